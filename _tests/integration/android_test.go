@@ -42,12 +42,10 @@ var monoRepoVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
-	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
-	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -62,10 +60,12 @@ var monoRepoVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
+	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
+	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.CacheSaveGradleVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// android-config-kts
@@ -73,12 +73,10 @@ var monoRepoVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
-	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
-	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -93,10 +91,12 @@ var monoRepoVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
+	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
+	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.CacheSaveGradleVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -128,6 +128,15 @@ var monoRepoResultYML = fmt.Sprintf(`options:
             value_map:
               "":
                 config: android-config
+            default_value: debug
+            value_infos:
+              debug:
+                recommended: true
+                reason: every Android project has a debug build type, which needs
+                  no signing config
+            validation:
+              pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+              hint: variant names in separate lines, like debug or freeRelease
       ./KotlinResponsiveViewsActivity:
         title: Module
         summary: Modules provide a container for your Android project's source code,
@@ -146,6 +155,15 @@ var monoRepoResultYML = fmt.Sprintf(`options:
             value_map:
               "":
                 config: android-config-kts
+            default_value: debug
+            value_infos:
+              debug:
+                recommended: true
+                reason: every Android project has a debug build type, which needs
+                  no signing config
+            validation:
+              pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+              hint: variant names in separate lines, like debug or freeRelease
 configs:
   android:
     android-config: |
@@ -155,45 +173,37 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_apk
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
             run_instrumented_tests:
               parallel: $TEST_SHARD_COUNT
       workflows:
-        build_apk:
-          summary: Run your Android unit tests and create an APK file to install your app
-            on a device or share it with your team.
-          description: The workflow will first clone your Git repository, install Android
-            tools, set the project's version code based on the build number, run Android
-            lint and unit tests, build the project's APK file and save it.
+        run_tests:
+          summary: Run your Android unit tests and get the test report.
+          description: The workflow will first clone your Git repository, cache your Gradle
+            dependencies, install Android tools, run your Android unit tests and save the
+            test report.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
-          - change-android-versioncode-and-versionname@%s:
-              inputs:
-              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle
-          - android-lint@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - variant: $VARIANT
-              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-              - cache_level: none
-          - sign-apk@%s:
-              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         run_instrumented_tests:
           summary: Run your Android instrumented tests and get the test report.
@@ -218,24 +228,39 @@ configs:
                     -Pandroid.testInstrumentationRunnerArguments.shardIndex=$BITRISE_IO_PARALLEL_INDEX
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
-          summary: Run your Android unit tests and get the test report.
-          description: The workflow will first clone your Git repository, cache your Gradle
-            dependencies, install Android tools, run your Android unit tests and save the
-            test report.
+        build_apk:
+          summary: Run your Android unit tests and create an APK file to install your app
+            on a device or share it with your team.
+          description: The workflow will first clone your Git repository, install Android
+            tools, set the project's version code based on the build number, run Android
+            lint and unit tests, build the project's APK file and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
+          - change-android-versioncode-and-versionname@%s:
+              inputs:
+              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle
+          - android-lint@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - variant: $VARIANT
+              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - save-gradle-cache@%s: {}
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+              - cache_level: none
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
     android-config-kts: |
       format_version: "%s"
@@ -244,45 +269,37 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_apk
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
             run_instrumented_tests:
               parallel: $TEST_SHARD_COUNT
       workflows:
-        build_apk:
-          summary: Run your Android unit tests and create an APK file to install your app
-            on a device or share it with your team.
-          description: The workflow will first clone your Git repository, install Android
-            tools, set the project's version code based on the build number, run Android
-            lint and unit tests, build the project's APK file and save it.
+        run_tests:
+          summary: Run your Android unit tests and get the test report.
+          description: The workflow will first clone your Git repository, cache your Gradle
+            dependencies, install Android tools, run your Android unit tests and save the
+            test report.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
-          - change-android-versioncode-and-versionname@%s:
-              inputs:
-              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle.kts
-          - android-lint@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - variant: $VARIANT
-              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-              - cache_level: none
-          - sign-apk@%s:
-              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         run_instrumented_tests:
           summary: Run your Android instrumented tests and get the test report.
@@ -307,24 +324,39 @@ configs:
                     -Pandroid.testInstrumentationRunnerArguments.shardIndex=$BITRISE_IO_PARALLEL_INDEX
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
-          summary: Run your Android unit tests and get the test report.
-          description: The workflow will first clone your Git repository, cache your Gradle
-            dependencies, install Android tools, run your Android unit tests and save the
-            test report.
+        build_apk:
+          summary: Run your Android unit tests and create an APK file to install your app
+            on a device or share it with your team.
+          description: The workflow will first clone your Git repository, install Android
+            tools, set the project's version code based on the build number, run Android
+            lint and unit tests, build the project's APK file and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
+          - change-android-versioncode-and-versionname@%s:
+              inputs:
+              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle.kts
+          - android-lint@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - variant: $VARIANT
+              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - save-gradle-cache@%s: {}
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+              - cache_level: none
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
 warnings:
   android: []
@@ -338,12 +370,10 @@ var sampleAppsAndroid22Versions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
-	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
-	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -358,10 +388,12 @@ var sampleAppsAndroid22Versions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
+	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
+	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.CacheSaveGradleVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -395,6 +427,15 @@ var sampleAppsAndroid22ResultYML = fmt.Sprintf(`options:
                 config: android-config
                 icons:
                 - 81af22c35b03b30a1931a6283349eae094463aa69c52af3afe804b40dbe6dc12.png
+            default_value: debug
+            value_infos:
+              debug:
+                recommended: true
+                reason: every Android project has a debug build type, which needs
+                  no signing config
+            validation:
+              pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+              hint: variant names in separate lines, like debug or freeRelease
 configs:
   android:
     android-config: |
@@ -404,45 +445,37 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_apk
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
             run_instrumented_tests:
               parallel: $TEST_SHARD_COUNT
       workflows:
-        build_apk:
-          summary: Run your Android unit tests and create an APK file to install your app
-            on a device or share it with your team.
-          description: The workflow will first clone your Git repository, install Android
-            tools, set the project's version code based on the build number, run Android
-            lint and unit tests, build the project's APK file and save it.
+        run_tests:
+          summary: Run your Android unit tests and get the test report.
+          description: The workflow will first clone your Git repository, cache your Gradle
+            dependencies, install Android tools, run your Android unit tests and save the
+            test report.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
-          - change-android-versioncode-and-versionname@%s:
-              inputs:
-              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle
-          - android-lint@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - variant: $VARIANT
-              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-              - cache_level: none
-          - sign-apk@%s:
-              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         run_instrumented_tests:
           summary: Run your Android instrumented tests and get the test report.
@@ -467,24 +500,39 @@ configs:
                     -Pandroid.testInstrumentationRunnerArguments.shardIndex=$BITRISE_IO_PARALLEL_INDEX
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
-          summary: Run your Android unit tests and get the test report.
-          description: The workflow will first clone your Git repository, cache your Gradle
-            dependencies, install Android tools, run your Android unit tests and save the
-            test report.
+        build_apk:
+          summary: Run your Android unit tests and create an APK file to install your app
+            on a device or share it with your team.
+          description: The workflow will first clone your Git repository, install Android
+            tools, set the project's version code based on the build number, run Android
+            lint and unit tests, build the project's APK file and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
+          - change-android-versioncode-and-versionname@%s:
+              inputs:
+              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle
+          - android-lint@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - variant: $VARIANT
+              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - save-gradle-cache@%s: {}
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+              - cache_level: none
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
 warnings:
   android: []
@@ -498,12 +546,10 @@ var sampleAppsKotlinDSLVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
-	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
-	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -518,10 +564,12 @@ var sampleAppsKotlinDSLVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
+	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
+	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.CacheSaveGradleVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -556,6 +604,15 @@ var sampleAppsKotlinDSLResultYML = fmt.Sprintf(`options:
                 icons:
                 - 81af22c35b03b30a1931a6283349eae094463aa69c52af3afe804b40dbe6dc12.png
                 - 3a50cbe24812ec6ef995f7142267bf67059d3e73e6b042873043b00354dbfde0.png
+            default_value: debug
+            value_infos:
+              debug:
+                recommended: true
+                reason: every Android project has a debug build type, which needs
+                  no signing config
+            validation:
+              pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+              hint: variant names in separate lines, like debug or freeRelease
 configs:
   android:
     android-config-kts: |
@@ -565,45 +622,37 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_apk
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
             run_instrumented_tests:
               parallel: $TEST_SHARD_COUNT
       workflows:
-        build_apk:
-          summary: Run your Android unit tests and create an APK file to install your app
-            on a device or share it with your team.
-          description: The workflow will first clone your Git repository, install Android
-            tools, set the project's version code based on the build number, run Android
-            lint and unit tests, build the project's APK file and save it.
+        run_tests:
+          summary: Run your Android unit tests and get the test report.
+          description: The workflow will first clone your Git repository, cache your Gradle
+            dependencies, install Android tools, run your Android unit tests and save the
+            test report.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
-          - change-android-versioncode-and-versionname@%s:
-              inputs:
-              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle.kts
-          - android-lint@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - variant: $VARIANT
-              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-              - cache_level: none
-          - sign-apk@%s:
-              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         run_instrumented_tests:
           summary: Run your Android instrumented tests and get the test report.
@@ -628,28 +677,42 @@ configs:
                     -Pandroid.testInstrumentationRunnerArguments.shardIndex=$BITRISE_IO_PARALLEL_INDEX
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
-          summary: Run your Android unit tests and get the test report.
-          description: The workflow will first clone your Git repository, cache your Gradle
-            dependencies, install Android tools, run your Android unit tests and save the
-            test report.
+        build_apk:
+          summary: Run your Android unit tests and create an APK file to install your app
+            on a device or share it with your team.
+          description: The workflow will first clone your Git repository, install Android
+            tools, set the project's version code based on the build number, run Android
+            lint and unit tests, build the project's APK file and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
+          - change-android-versioncode-and-versionname@%s:
+              inputs:
+              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle.kts
+          - android-lint@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - variant: $VARIANT
+              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - save-gradle-cache@%s: {}
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+              - cache_level: none
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
 warnings:
   android: []
 warnings_with_recommendations:
   android: []
-
 `, sampleAppsKotlinDSLVersions...)
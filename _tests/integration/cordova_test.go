@@ -32,21 +32,22 @@ func TestCordova(t *testing.T) {
 
 var sampleAppsCordovaWithJasmineVersions = []interface{}{
 	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CertificateAndProfileInstallerVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
 	steps.JasmineTestRunnerVersion,
-	steps.GenerateCordovaBuildConfigVersion,
-	steps.CordovaArchiveVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
+	steps.CertificateAndProfileInstallerVersion,
 	steps.NpmVersion,
 	steps.JasmineTestRunnerVersion,
-	steps.CacheSaveNPMVersion,
+	steps.GenerateCordovaBuildConfigVersion,
+	steps.CordovaArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -71,34 +72,41 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: cordova
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - certificate-and-profile-installer@%s: {}
+          - restore-npm-cache@%s: {}
           - npm@%s:
               title: npm install
               inputs:
               - command: install
           - jasmine-runner@%s: {}
-          - generate-cordova-build-configuration@%s: {}
-          - cordova-archive@%s:
-              inputs:
-              - platform: $CORDOVA_PLATFORM
-              - target: emulator
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
+          - certificate-and-profile-installer@%s: {}
           - npm@%s:
               title: npm install
               inputs:
               - command: install
           - jasmine-runner@%s: {}
-          - save-npm-cache@%s: {}
+          - generate-cordova-build-configuration@%s: {}
+          - cordova-archive@%s:
+              inputs:
+              - platform: $CORDOVA_PLATFORM
+              - target: emulator
           - deploy-to-bitrise-io@%s: {}
 warnings:
   cordova: []
@@ -108,21 +116,22 @@ warnings_with_recommendations:
 
 var sampleAppsCordovaWithKarmaJasmineVersions = []interface{}{
 	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CertificateAndProfileInstallerVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
 	steps.KarmaJasmineTestRunnerVersion,
-	steps.GenerateCordovaBuildConfigVersion,
-	steps.CordovaArchiveVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
+	steps.CertificateAndProfileInstallerVersion,
 	steps.NpmVersion,
 	steps.KarmaJasmineTestRunnerVersion,
-	steps.CacheSaveNPMVersion,
+	steps.GenerateCordovaBuildConfigVersion,
+	steps.CordovaArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -147,37 +156,43 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: cordova
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - certificate-and-profile-installer@%s: {}
+          - restore-npm-cache@%s: {}
           - npm@%s:
               title: npm install
               inputs:
               - command: install
           - karma-jasmine-runner@%s: {}
-          - generate-cordova-build-configuration@%s: {}
-          - cordova-archive@%s:
-              inputs:
-              - platform: $CORDOVA_PLATFORM
-              - target: emulator
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
+          - certificate-and-profile-installer@%s: {}
           - npm@%s:
               title: npm install
               inputs:
               - command: install
           - karma-jasmine-runner@%s: {}
-          - save-npm-cache@%s: {}
+          - generate-cordova-build-configuration@%s: {}
+          - cordova-archive@%s:
+              inputs:
+              - platform: $CORDOVA_PLATFORM
+              - target: emulator
           - deploy-to-bitrise-io@%s: {}
 warnings:
   cordova: []
 warnings_with_recommendations:
-  cordova: []
-  `, sampleAppsCordovaWithKarmaJasmineVersions...)
+  cordova: []`, sampleAppsCordovaWithKarmaJasmineVersions...)
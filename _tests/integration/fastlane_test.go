@@ -35,10 +35,10 @@ var fastlaneVersions = []interface{}{
 
 	// ios
 	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -47,13 +47,14 @@ var fastlaneVersions = []interface{}{
 	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
+	steps.PullIntermediateFilesVersion,
+	steps.XcodeTestWithoutBuildingVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeTestVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
-
-	steps.PullIntermediateFilesVersion,
-	steps.XcodeTestWithoutBuildingVersion,
 }
 
 var fastlaneResultYML = fmt.Sprintf(`options:
@@ -122,6 +123,13 @@ configs:
       app:
         envs:
         - FASTLANE_XCODE_LIST_TIMEOUT: "120"
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: <default-branch>
       workflows:
         primary:
           steps:
@@ -142,6 +150,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -151,12 +166,10 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
-        archive_and_export_app:
-          summary: Run your Xcode tests and create an IPA file to install your app on a
-            device or share it with your team.
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests, export an IPA file
-            from the project and save it.
+            your project's dependencies if any, run your Xcode tests and save the test results.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
@@ -166,12 +179,6 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
@@ -192,10 +199,19 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
+        test_without_building:
+          steps:
+          - pull-intermediate-files@%s: {}
+          - xcode-test-without-building@%s:
+              inputs:
+              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
+              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+        archive_and_export_app:
+          summary: Run your Xcode tests and create an IPA file to install your app on a
+            device or share it with your team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
+            your project's dependencies if any, run your Xcode tests, export an IPA file
+            from the project and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
@@ -205,14 +221,13 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - deploy-to-bitrise-io@%s: {}
-        test_without_building:
-          steps:
-          - pull-intermediate-files@%s: {}
-          - xcode-test-without-building@%s:
+          - xcode-archive@%s:
               inputs:
-              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
-              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
 warnings:
   fastlane: []
   ios: []
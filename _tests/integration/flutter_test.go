@@ -61,22 +61,22 @@ func TestFlutter(t *testing.T) {
 var flutterIosAndroidVersions = []interface{}{
 	// flutter-config-test-ios-android-0
 	models.FormatVersion,
-	// build_app workflow
+	// run_tests workflow
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CertificateAndProfileInstallerVersion,
 	steps.FlutterInstallVersion,
-	steps.FlutterAnalyzeVersion,
+	steps.CacheRestoreDartVersion,
 	steps.FlutterTestVersion,
-	steps.FlutterBuildVersion,
+	steps.CacheSaveDartVersion,
 	steps.DeployToBitriseIoVersion,
-	// run_tests workflow
+	// build_app workflow
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CertificateAndProfileInstallerVersion,
 	steps.FlutterInstallVersion,
-	steps.CacheRestoreDartVersion,
+	steps.FlutterAnalyzeVersion,
 	steps.FlutterTestVersion,
-	steps.CacheSaveDartVersion,
+	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -97,54 +97,61 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_app
+        push_branch: <default-branch>
       workflows:
-        build_app:
+        run_tests:
           description: |
-            Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
+            Runs tests or analysis.
 
-            If you build for iOS, make sure to set up code signing secrets on Bitrise for a successful build.
+            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
 
             Next steps:
-            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html) for signing and deployment options.
-            - Check out the Code signing guide for [iOS](https://docs.bitrise.io/en/bitrise-ci/code-signing/ios-code-signing.html) and [Android](https://docs.bitrise.io/en/bitrise-ci/code-signing/android-code-signing.html).
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s:
               inputs:
               - version: 3.41.7
-          - flutter-analyze@%s:
-              inputs:
-              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - restore-dart-cache@%s: {}
           - flutter-test@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-build@%s:
-              inputs:
-              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: both
-              - ios_output_type: archive
+          - save-dart-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
+        build_app:
           description: |
-            Runs tests or analysis.
+            Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
 
-            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
+            If you build for iOS, make sure to set up code signing secrets on Bitrise for a successful build.
 
             Next steps:
-            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html) for signing and deployment options.
+            - Check out the Code signing guide for [iOS](https://docs.bitrise.io/en/bitrise-ci/code-signing/ios-code-signing.html) and [Android](https://docs.bitrise.io/en/bitrise-ci/code-signing/android-code-signing.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s:
               inputs:
               - version: 3.41.7
-          - restore-dart-cache@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - flutter-test@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - save-dart-cache@%s: {}
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: both
+              - ios_output_type: archive
           - deploy-to-bitrise-io@%s: {}
 warnings:
   flutter: []
@@ -182,6 +189,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           description: |
@@ -212,6 +226,14 @@ warnings_with_recommendations:
 var flutterPluginVersions = []interface{}{
 	// flutter-config-test-android-0 (root plugin: Android only, has tests)
 	models.FormatVersion,
+	// run_tests workflow
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.FlutterInstallVersion,
+	steps.CacheRestoreDartVersion,
+	steps.FlutterTestVersion,
+	steps.CacheSaveDartVersion,
+	steps.DeployToBitriseIoVersion,
 	// build_app workflow
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -220,6 +242,9 @@ var flutterPluginVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
+
+	// flutter-config-test-ios-android-1 (example app: iOS + Android, has tests)
+	models.FormatVersion,
 	// run_tests workflow
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -228,9 +253,6 @@ var flutterPluginVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.CacheSaveDartVersion,
 	steps.DeployToBitriseIoVersion,
-
-	// flutter-config-test-ios-android-1 (example app: iOS + Android, has tests)
-	models.FormatVersion,
 	// build_app workflow
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -240,36 +262,94 @@ var flutterPluginVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
-	// run_tests workflow
+
+	// flutter-monorepo-config
+	models.FormatVersion,
+	// _setup workflow
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	// run_tests workflow
 	steps.FlutterInstallVersion,
 	steps.CacheRestoreDartVersion,
 	steps.FlutterTestVersion,
 	steps.CacheSaveDartVersion,
 	steps.DeployToBitriseIoVersion,
+	// build_app workflow
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
+	// run_tests_example workflow
+	steps.FlutterInstallVersion,
+	steps.CacheRestoreDartVersion,
+	steps.FlutterTestVersion,
+	steps.CacheSaveDartVersion,
+	steps.DeployToBitriseIoVersion,
+	// build_app_example workflow
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
 }
 
 var flutterPluginResultYML = fmt.Sprintf(`options:
   flutter:
-    title: Project location
-    summary: The path to your Flutter project, stored as an Environment Variable.
-      In your Workflows, you can specify paths relative to this path. You can change
-      this at any time.
-    env_key: BITRISE_FLUTTER_PROJECT_LOCATION
+    title: Projects
+    summary: Generate the config of a single project, or the monorepo config covering
+      every project
     type: selector
     value_map:
-      .:
-        config: flutter-config-test-android-0
-      example:
-        config: flutter-config-test-ios-android-1
+      all projects:
+        config: flutter-monorepo-config
+      single project:
+        title: Project location
+        summary: The path to your Flutter project, stored as an Environment Variable.
+          In your Workflows, you can specify paths relative to this path. You can
+          change this at any time.
+        env_key: BITRISE_FLUTTER_PROJECT_LOCATION
+        type: selector
+        value_map:
+          .:
+            config: flutter-config-test-android-0
+          example:
+            config: flutter-config-test-ios-android-1
 configs:
   flutter:
     flutter-config-test-android-0: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_app
+        push_branch: <default-branch>
       workflows:
+        run_tests:
+          description: |
+            Runs tests or analysis.
+
+            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+          - flutter-installer@%s:
+              inputs:
+              - version: 3.41.7
+          - restore-dart-cache@%s: {}
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - save-dart-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
         build_app:
           description: |
             Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
@@ -296,6 +376,18 @@ configs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
               - platform: android
           - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-ios-android-1: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_app
+        push_branch: <default-branch>
+      workflows:
         run_tests:
           description: |
             Runs tests or analysis.
@@ -316,11 +408,6 @@ configs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - save-dart-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-test-ios-android-1: |
-      format_version: "%s"
-      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: flutter
-      workflows:
         build_app:
           description: |
             Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
@@ -349,6 +436,15 @@ configs:
               - platform: both
               - ios_output_type: archive
           - deploy-to-bitrise-io@%s: {}
+    flutter-monorepo-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      workflows:
+        _setup:
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
         run_tests:
           description: |
             Runs tests or analysis.
@@ -357,18 +453,108 @@ configs:
 
             Next steps:
             - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
+          triggers:
+            pull_request:
+            - source_branch: '*'
+              changed_files: '**'
+          before_run:
+          - _setup
           steps:
-          - activate-ssh-key@%s: {}
-          - git-clone@%s: {}
           - flutter-installer@%s:
               inputs:
               - version: 3.41.7
           - restore-dart-cache@%s: {}
           - flutter-test@%s:
               inputs:
-              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - project_location: .
           - save-dart-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
+        build_app:
+          description: |
+            Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
+
+            If you build for iOS, make sure to set up code signing secrets on Bitrise for a successful build.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html) for signing and deployment options.
+            - Check out the Code signing guide for [iOS](https://docs.bitrise.io/en/bitrise-ci/code-signing/ios-code-signing.html) and [Android](https://docs.bitrise.io/en/bitrise-ci/code-signing/android-code-signing.html).
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: '**'
+          before_run:
+          - _setup
+          steps:
+          - flutter-installer@%s:
+              inputs:
+              - version: 3.41.7
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: .
+          - flutter-test@%s:
+              inputs:
+              - project_location: .
+          - flutter-build@%s:
+              inputs:
+              - project_location: .
+              - platform: android
+          - deploy-to-bitrise-io@%s: {}
+        run_tests_example:
+          description: |
+            Runs tests or analysis.
+
+            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
+          triggers:
+            pull_request:
+            - source_branch: '*'
+              changed_files: example/**
+          before_run:
+          - _setup
+          steps:
+          - flutter-installer@%s:
+              inputs:
+              - version: 3.41.7
+          - restore-dart-cache@%s: {}
+          - flutter-test@%s:
+              inputs:
+              - project_location: example
+          - save-dart-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
+        build_app_example:
+          description: |
+            Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
+
+            If you build for iOS, make sure to set up code signing secrets on Bitrise for a successful build.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html) for signing and deployment options.
+            - Check out the Code signing guide for [iOS](https://docs.bitrise.io/en/bitrise-ci/code-signing/ios-code-signing.html) and [Android](https://docs.bitrise.io/en/bitrise-ci/code-signing/android-code-signing.html).
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: example/**
+          before_run:
+          - _setup
+          steps:
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s:
+              inputs:
+              - version: 3.41.7
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: example
+          - flutter-test@%s:
+              inputs:
+              - project_location: example
+          - flutter-build@%s:
+              inputs:
+              - project_location: example
+              - platform: both
+              - ios_output_type: archive
+          - deploy-to-bitrise-io@%s: {}
 warnings:
   flutter: []
 warnings_with_recommendations:
@@ -405,6 +591,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           description: |
@@ -471,54 +664,61 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_app
+        push_branch: <default-branch>
       workflows:
-        build_app:
+        run_tests:
           description: |
-            Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
+            Runs tests or analysis.
 
-            If you build for iOS, make sure to set up code signing secrets on Bitrise for a successful build.
+            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
 
             Next steps:
-            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html) for signing and deployment options.
-            - Check out the Code signing guide for [iOS](https://docs.bitrise.io/en/bitrise-ci/code-signing/ios-code-signing.html) and [Android](https://docs.bitrise.io/en/bitrise-ci/code-signing/android-code-signing.html).
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s:
               inputs:
               - version: 3.41.7
-          - flutter-analyze@%s:
-              inputs:
-              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - restore-dart-cache@%s: {}
           - flutter-test@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-build@%s:
-              inputs:
-              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: both
-              - ios_output_type: archive
+          - save-dart-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
+        build_app:
           description: |
-            Runs tests or analysis.
+            Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
 
-            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
+            If you build for iOS, make sure to set up code signing secrets on Bitrise for a successful build.
 
             Next steps:
-            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html) for signing and deployment options.
+            - Check out the Code signing guide for [iOS](https://docs.bitrise.io/en/bitrise-ci/code-signing/ios-code-signing.html) and [Android](https://docs.bitrise.io/en/bitrise-ci/code-signing/android-code-signing.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s:
               inputs:
               - version: 3.41.7
-          - restore-dart-cache@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - flutter-test@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - save-dart-cache@%s: {}
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: both
+              - ios_output_type: archive
           - deploy-to-bitrise-io@%s: {}
 warnings:
   flutter: []
@@ -5,12 +5,16 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/output"
 	"github.com/bitrise-io/bitrise-init/scanner"
 	"github.com/bitrise-io/go-utils/fileutil"
 	"github.com/stretchr/testify/require"
 )
 
+// DefaultBranchPlaceholder stands for the default branch of the cloned repository in the expected results.
+const DefaultBranchPlaceholder = "<default-branch>"
+
 type TestCase struct {
 	Name              string
 	RepoURL           string
@@ -69,7 +73,14 @@ func Execute(t *testing.T, testCases []TestCase) {
 			result, err := fileutil.ReadStringFromFile(scanResultPth)
 			require.NoError(t, err)
 
-			ValidateConfigExpectation(t, testCase.Name, strings.TrimSpace(testCase.ExpectedResult), strings.TrimSpace(result), testCase.ExpectedVersions)
+			// The default branch is only read from the .git directory of the search dir.
+			defaultBranch := models.DefaultBranch
+			if testCase.RelativeSearchDir == "" {
+				defaultBranch = GitDefaultBranch(t, sampleAppDir)
+			}
+			expected := strings.ReplaceAll(testCase.ExpectedResult, DefaultBranchPlaceholder, defaultBranch)
+
+			ValidateConfigExpectation(t, testCase.Name, strings.TrimSpace(expected), strings.TrimSpace(result), testCase.ExpectedVersions)
 		})
 
 	}
@@ -5,6 +5,8 @@ import (
 	"github.com/bitrise-io/go-utils/command"
 	"github.com/bitrise-io/go-utils/command/git"
 	"github.com/stretchr/testify/require"
+	"os/exec"
+	"strings"
 	"testing"
 )
 
@@ -39,3 +41,15 @@ func addShallowOption(command *command.Model) {
 
 	command.GetCmd().Args = append(firstPart, append([]string{"--depth=1"}, secondPart...)...)
 }
+
+// GitDefaultBranch returns the branch of the origin remote's HEAD, or the checked out branch of the repository.
+func GitDefaultBranch(t *testing.T, dir string) string {
+	out, err := exec.Command("git", "-C", dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD").Output()
+	if err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/")
+	}
+
+	out, err = exec.Command("git", "-C", dir, "symbolic-ref", "--short", "HEAD").Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
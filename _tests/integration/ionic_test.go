@@ -66,6 +66,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ionic
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: <default-branch>
       workflows:
         primary:
           steps:
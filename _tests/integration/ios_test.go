@@ -102,11 +102,10 @@ func TestIOS(t *testing.T) {
 var iosNoSharedSchemesVersions = []interface{}{
 	models.FormatVersion,
 
-	// ios-no-shared-schemes/archive_and_export_app
+	// ios-no-shared-schemes/run_tests
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// ios-no-shared-schemes/build_for_testing
@@ -116,15 +115,16 @@ var iosNoSharedSchemesVersions = []interface{}{
 	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-no-shared-schemes/run_tests
+	// ios-no-shared-schemes/test_without_building
+	steps.PullIntermediateFilesVersion,
+	steps.XcodeTestWithoutBuildingVersion,
+
+	// ios-no-shared-schemes/archive_and_export_app
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeTestVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
-
-	// ios-no-shared-schemes/test_without_building
-	steps.PullIntermediateFilesVersion,
-	steps.XcodeTestWithoutBuildingVersion,
 }
 
 var iosNoSharedSchemesResultYML = fmt.Sprintf(`options:
@@ -171,6 +171,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -180,12 +187,10 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
-        archive_and_export_app:
-          summary: Run your Xcode tests and create an IPA file to install your app on a
-            device or share it with your team.
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests, export an IPA file
-            from the project and save it.
+            your project's dependencies if any, run your Xcode tests and save the test results.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
@@ -195,12 +200,6 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
@@ -221,10 +220,19 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
+        test_without_building:
+          steps:
+          - pull-intermediate-files@%s: {}
+          - xcode-test-without-building@%s:
+              inputs:
+              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
+              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+        archive_and_export_app:
+          summary: Run your Xcode tests and create an IPA file to install your app on a
+            device or share it with your team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
+            your project's dependencies if any, run your Xcode tests, export an IPA file
+            from the project and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
@@ -234,14 +242,13 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - deploy-to-bitrise-io@%s: {}
-        test_without_building:
-          steps:
-          - pull-intermediate-files@%s: {}
-          - xcode-test-without-building@%s:
+          - xcode-archive@%s:
               inputs:
-              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
-              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
 warnings:
   ios: []
 warnings_with_recommendations:
@@ -251,12 +258,13 @@ warnings_with_recommendations:
 var iosCocoapodsAtRootVersions = []interface{}{
 	models.FormatVersion,
 
-	// ios-cocoapods-at-root/archive_and_export_app
+	// ios-cocoapods-at-root/run_tests
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreCocoapodsVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveCocoapodsVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// ios-cocoapods-at-root/build_for_testing
@@ -269,18 +277,17 @@ var iosCocoapodsAtRootVersions = []interface{}{
 	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-cocoapods-at-root/run_tests
+	// ios-cocoapods-at-root/test_without_building
+	steps.PullIntermediateFilesVersion,
+	steps.XcodeTestWithoutBuildingVersion,
+
+	// ios-cocoapods-at-root/archive_and_export_app
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreCocoapodsVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestVersion,
-	steps.CacheSaveCocoapodsVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
-
-	// ios-cocoapods-at-root/test_without_building
-	steps.PullIntermediateFilesVersion,
-	steps.XcodeTestWithoutBuildingVersion,
 }
 
 var iosCocoapodsAtRootResultYML = fmt.Sprintf(`options:
@@ -327,6 +334,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -336,15 +350,14 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
-        archive_and_export_app:
-          summary: Run your Xcode tests and create an IPA file to install your app on a
-            device or share it with your team.
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests, export an IPA file
-            from the project and save it.
+            your project's dependencies if any, run your Xcode tests and save the test results.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-cocoapods-cache@%s: {}
           - cocoapods-install@%s:
               inputs:
               - is_cache_disabled: "true"
@@ -354,12 +367,7 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
+          - save-cocoapods-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
@@ -385,14 +393,22 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
+        test_without_building:
+          steps:
+          - pull-intermediate-files@%s: {}
+          - xcode-test-without-building@%s:
+              inputs:
+              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
+              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+        archive_and_export_app:
+          summary: Run your Xcode tests and create an IPA file to install your app on a
+            device or share it with your team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
+            your project's dependencies if any, run your Xcode tests, export an IPA file
+            from the project and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-cocoapods-cache@%s: {}
           - cocoapods-install@%s:
               inputs:
               - is_cache_disabled: "true"
@@ -402,15 +418,13 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - save-cocoapods-cache@%s: {}
-          - deploy-to-bitrise-io@%s: {}
-        test_without_building:
-          steps:
-          - pull-intermediate-files@%s: {}
-          - xcode-test-without-building@%s:
+          - xcode-archive@%s:
               inputs:
-              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
-              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
 warnings:
   ios: []
 warnings_with_recommendations:
@@ -421,26 +435,25 @@ var sampleAppsIosWatchkitVersions = []interface{}{
 	// ios-config
 	models.FormatVersion,
 
-	// ios-app-clip-ad-hoc-config/ios-config/archive_and_export_app
+	// ios-app-clip-ad-hoc-config/ios-config/build
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.XcodeArchiveVersion,
+	steps.XcodeBuildForTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-app-clip-ad-hoc-config/ios-config/build
+	// ios-app-clip-ad-hoc-config/ios-config/archive_and_export_app
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.XcodeBuildForTestVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// ios-test-config
 	models.FormatVersion,
 
-	// ios-app-clip-ad-hoc-config/ios-test-config/archive_and_export_app
+	// ios-app-clip-ad-hoc-config/ios-test-config/run_tests
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// ios-app-clip-ad-hoc-config/ios-test-config/build_for_testing
@@ -450,15 +463,16 @@ var sampleAppsIosWatchkitVersions = []interface{}{
 	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-app-clip-ad-hoc-config/ios-test-config/run_tests
+	// ios-app-clip-ad-hoc-config/ios-test-config/test_without_building
+	steps.PullIntermediateFilesVersion,
+	steps.XcodeTestWithoutBuildingVersion,
+
+	// ios-app-clip-ad-hoc-config/ios-test-config/archive_and_export_app
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeTestVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
-
-	// ios-app-clip-ad-hoc-config/ios-test-config/test_without_building
-	steps.PullIntermediateFilesVersion,
-	steps.XcodeTestWithoutBuildingVersion,
 }
 
 var sampleAppsIosWatchkitResultYML = fmt.Sprintf(`options:
@@ -564,42 +578,55 @@ var sampleAppsIosWatchkitResultYML = fmt.Sprintf(`options:
                 config: ios-config
               enterprise:
                 config: ios-config
+        default_value: watch-test
+        value_infos:
+          watch-test:
+            recommended: true
+            reason: the scheme has test targets
+            detected_from: watch-test.xcodeproj
 configs:
   ios:
     ios-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ios
+      trigger_map:
+      - type: pull_request
+        workflow: build
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       workflows:
-        archive_and_export_app:
-          summary: Create an IPA file to install your app on a device or share it with your
-            team.
+        build:
+          summary: Build your Xcode project.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, export an IPA file from the project and
-            save it.
+            your project's dependencies if any and build your project.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - xcode-archive@%s:
+          - xcode-build-for-test@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
+              - destination: platform=iOS Simulator,name=iPhone 8 Plus,OS=latest
+              - cache_level: none
           - deploy-to-bitrise-io@%s: {}
-        build:
-          summary: Build your Xcode project.
+        archive_and_export_app:
+          summary: Create an IPA file to install your app on a device or share it with your
+            team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any and build your project.
+            your project's dependencies if any, export an IPA file from the project and
+            save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - xcode-build-for-test@%s:
+          - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - destination: platform=iOS Simulator,name=iPhone 8 Plus,OS=latest
-              - cache_level: none
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
     ios-test-config: |
       format_version: "%s"
@@ -608,6 +635,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -617,12 +651,10 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
-        archive_and_export_app:
-          summary: Run your Xcode tests and create an IPA file to install your app on a
-            device or share it with your team.
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests, export an IPA file
-            from the project and save it.
+            your project's dependencies if any, run your Xcode tests and save the test results.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
@@ -632,12 +664,6 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
@@ -658,10 +684,19 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
+        test_without_building:
+          steps:
+          - pull-intermediate-files@%s: {}
+          - xcode-test-without-building@%s:
+              inputs:
+              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
+              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+        archive_and_export_app:
+          summary: Run your Xcode tests and create an IPA file to install your app on a
+            device or share it with your team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
+            your project's dependencies if any, run your Xcode tests, export an IPA file
+            from the project and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
@@ -671,14 +706,13 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - deploy-to-bitrise-io@%s: {}
-        test_without_building:
-          steps:
-          - pull-intermediate-files@%s: {}
-          - xcode-test-without-building@%s:
+          - xcode-archive@%s:
               inputs:
-              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
-              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
 warnings:
   ios: []
 warnings_with_recommendations:
@@ -688,12 +722,13 @@ warnings_with_recommendations:
 var sampleAppsCarthageVersions = []interface{}{
 	models.FormatVersion,
 
-	// ios-carthage-test-config/archive_and_export_app
+	// ios-carthage-test-config/run_tests
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreCarthageVersion,
 	steps.CarthageVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveCarthageVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// ios-carthage-test-config/build_for_testing
@@ -706,18 +741,17 @@ var sampleAppsCarthageVersions = []interface{}{
 	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-carthage-test-config/run_tests
+	// ios-carthage-test-config/test_without_building
+	steps.PullIntermediateFilesVersion,
+	steps.XcodeTestWithoutBuildingVersion,
+
+	// ios-carthage-test-config/archive_and_export_app
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreCarthageVersion,
 	steps.CarthageVersion,
 	steps.XcodeTestVersion,
-	steps.CacheSaveCarthageVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
-
-	// ios-carthage-test-config/test_without_building
-	steps.PullIntermediateFilesVersion,
-	steps.XcodeTestWithoutBuildingVersion,
 }
 
 var sampleAppsCarthageResultYML = fmt.Sprintf(`options:
@@ -764,6 +798,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -773,15 +814,14 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
-        archive_and_export_app:
-          summary: Run your Xcode tests and create an IPA file to install your app on a
-            device or share it with your team.
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests, export an IPA file
-            from the project and save it.
+            your project's dependencies if any, run your Xcode tests and save the test results.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-carthage-cache@%s: {}
           - carthage@%s:
               inputs:
               - carthage_command: bootstrap
@@ -791,12 +831,7 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
+          - save-carthage-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
@@ -822,14 +857,22 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
+        test_without_building:
+          steps:
+          - pull-intermediate-files@%s: {}
+          - xcode-test-without-building@%s:
+              inputs:
+              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
+              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+        archive_and_export_app:
+          summary: Run your Xcode tests and create an IPA file to install your app on a
+            device or share it with your team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
+            your project's dependencies if any, run your Xcode tests, export an IPA file
+            from the project and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-carthage-cache@%s: {}
           - carthage@%s:
               inputs:
               - carthage_command: bootstrap
@@ -839,15 +882,13 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - save-carthage-cache@%s: {}
-          - deploy-to-bitrise-io@%s: {}
-        test_without_building:
-          steps:
-          - pull-intermediate-files@%s: {}
-          - xcode-test-without-building@%s:
+          - xcode-archive@%s:
               inputs:
-              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
-              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
 warnings:
   ios: []
 warnings_with_recommendations:
@@ -857,11 +898,6 @@ warnings_with_recommendations:
 var sampleAppClipVersions = []interface{}{
 	// ios-app-clip-ad-hoc-config/deploy
 	models.FormatVersion,
-	steps.ActivateSSHKeyVersion,
-	steps.GitCloneVersion,
-	steps.XcodeArchiveVersion,
-	steps.ExportXCArchiveVersion,
-	steps.DeployToBitriseIoVersion,
 
 	// ios-app-clip-ad-hoc-config/primary
 	steps.ActivateSSHKeyVersion,
@@ -869,45 +905,54 @@ var sampleAppClipVersions = []interface{}{
 	steps.XcodeBuildForTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-app-clip-app-store-config/deploy
-	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeArchiveVersion,
+	steps.ExportXCArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
+	// ios-app-clip-app-store-config/deploy
+	models.FormatVersion,
+
 	// ios-app-clip-app-store-config/primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeBuildForTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-app-clip-development-config/deploy
-	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeArchiveVersion,
-	steps.ExportXCArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
+	// ios-app-clip-development-config/deploy
+	models.FormatVersion,
+
 	// ios-app-clip-development-config/primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeBuildForTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-app-clip-enterprise-config/deploy
-	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeArchiveVersion,
+	steps.ExportXCArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
+	// ios-app-clip-enterprise-config/deploy
+	models.FormatVersion,
+
 	// ios-app-clip-enterprise-config/primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeBuildForTestVersion,
 	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
 }
 
 var sampleAppClipResultYML = fmt.Sprintf(`options:
@@ -951,7 +996,28 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ios
+      trigger_map:
+      - type: pull_request
+        workflow: build
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       workflows:
+        build:
+          summary: Build your Xcode project.
+          description: The workflow will first clone your Git repository, cache and install
+            your project's dependencies if any and build your project.
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+          - xcode-build-for-test@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - destination: platform=iOS Simulator,name=iPhone 8 Plus,OS=latest
+              - cache_level: none
+          - deploy-to-bitrise-io@%s: {}
         archive_and_export_app:
           summary: Create an IPA file to install your app on a device or share it with your
             team.
@@ -975,6 +1041,18 @@ configs:
               - distribution_method: $BITRISE_DISTRIBUTION_METHOD
               - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
+    ios-app-clip-app-store-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: ios
+      trigger_map:
+      - type: pull_request
+        workflow: build
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
+      workflows:
         build:
           summary: Build your Xcode project.
           description: The workflow will first clone your Git repository, cache and install
@@ -989,11 +1067,6 @@ configs:
               - destination: platform=iOS Simulator,name=iPhone 8 Plus,OS=latest
               - cache_level: none
           - deploy-to-bitrise-io@%s: {}
-    ios-app-clip-app-store-config: |
-      format_version: "%s"
-      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: ios
-      workflows:
         archive_and_export_app:
           summary: Create an IPA file to install your app on a device or share it with your
             team.
@@ -1010,6 +1083,18 @@ configs:
               - distribution_method: $BITRISE_DISTRIBUTION_METHOD
               - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
+    ios-app-clip-development-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: ios
+      trigger_map:
+      - type: pull_request
+        workflow: build
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
+      workflows:
         build:
           summary: Build your Xcode project.
           description: The workflow will first clone your Git repository, cache and install
@@ -1024,11 +1109,6 @@ configs:
               - destination: platform=iOS Simulator,name=iPhone 8 Plus,OS=latest
               - cache_level: none
           - deploy-to-bitrise-io@%s: {}
-    ios-app-clip-development-config: |
-      format_version: "%s"
-      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: ios
-      workflows:
         archive_and_export_app:
           summary: Create an IPA file to install your app on a device or share it with your
             team.
@@ -1052,6 +1132,18 @@ configs:
               - distribution_method: $BITRISE_DISTRIBUTION_METHOD
               - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
+    ios-app-clip-enterprise-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: ios
+      trigger_map:
+      - type: pull_request
+        workflow: build
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
+      workflows:
         build:
           summary: Build your Xcode project.
           description: The workflow will first clone your Git repository, cache and install
@@ -1066,11 +1158,6 @@ configs:
               - destination: platform=iOS Simulator,name=iPhone 8 Plus,OS=latest
               - cache_level: none
           - deploy-to-bitrise-io@%s: {}
-    ios-app-clip-enterprise-config: |
-      format_version: "%s"
-      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: ios
-      workflows:
         archive_and_export_app:
           summary: Create an IPA file to install your app on a device or share it with your
             team.
@@ -1087,20 +1174,6 @@ configs:
               - distribution_method: $BITRISE_DISTRIBUTION_METHOD
               - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
-        build:
-          summary: Build your Xcode project.
-          description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any and build your project.
-          steps:
-          - activate-ssh-key@%s: {}
-          - git-clone@%s: {}
-          - xcode-build-for-test@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - destination: platform=iOS Simulator,name=iPhone 8 Plus,OS=latest
-              - cache_level: none
-          - deploy-to-bitrise-io@%s: {}
 warnings:
   ios: []
 warnings_with_recommendations:
@@ -1111,11 +1184,10 @@ var appleMultiplatformAppVersions = []interface{}{
 	// iOS
 	models.FormatVersion,
 
-	// ios-test-config/archive_and_export_app
+	// ios-test-config/run_tests
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// ios-test-config/build_for_testing
@@ -1125,31 +1197,32 @@ var appleMultiplatformAppVersions = []interface{}{
 	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-test-config/run_tests
+	// ios-test-config/test_without_building
+	steps.PullIntermediateFilesVersion,
+	steps.XcodeTestWithoutBuildingVersion,
+
+	// ios-test-config/archive_and_export_app
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.XcodeTestVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-test-config/test_without_building
-	steps.PullIntermediateFilesVersion,
-	steps.XcodeTestWithoutBuildingVersion,
-
 	// macOS
 	models.FormatVersion,
 
-	// ios-test-config/deploy
+	// ios-test-config/primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeTestMacVersion,
-	steps.XcodeArchiveMacVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-test-config/primary
+	// ios-test-config/deploy
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeTestMacVersion,
+	steps.XcodeArchiveMacVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -1233,6 +1306,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -1242,12 +1322,10 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
-        archive_and_export_app:
-          summary: Run your Xcode tests and create an IPA file to install your app on a
-            device or share it with your team.
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests, export an IPA file
-            from the project and save it.
+            your project's dependencies if any, run your Xcode tests and save the test results.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
@@ -1257,12 +1335,6 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
@@ -1283,10 +1355,19 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
+        test_without_building:
+          steps:
+          - pull-intermediate-files@%s: {}
+          - xcode-test-without-building@%s:
+              inputs:
+              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
+              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+        archive_and_export_app:
+          summary: Run your Xcode tests and create an IPA file to install your app on a
+            device or share it with your team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
+            your project's dependencies if any, run your Xcode tests, export an IPA file
+            from the project and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
@@ -1296,43 +1377,49 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - deploy-to-bitrise-io@%s: {}
-        test_without_building:
-          steps:
-          - pull-intermediate-files@%s: {}
-          - xcode-test-without-building@%s:
+          - xcode-archive@%s:
               inputs:
-              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
-              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
   macos:
     macos-test-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: macos
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - certificate-and-profile-installer@%s: {}
           - xcode-test-mac@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-          - xcode-archive-mac@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - certificate-and-profile-installer@%s: {}
           - xcode-test-mac@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+          - xcode-archive-mac@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
 warnings:
   ios: []
@@ -1346,11 +1433,12 @@ var sampleSPMVersions = []interface{}{
 	// iOS
 	models.FormatVersion,
 
-	// ios-spm-test-config/archive_and_export_app
+	// ios-spm-test-config/run_tests
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreSPMVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveSPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// ios-spm-test-config/build_for_testing
@@ -1362,17 +1450,16 @@ var sampleSPMVersions = []interface{}{
 	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-spm-test-config/run_tests
+	// ios-spm-test-config/test_without_building
+	steps.PullIntermediateFilesVersion,
+	steps.XcodeTestWithoutBuildingVersion,
+
+	// ios-spm-test-config/archive_and_export_app
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreSPMVersion,
 	steps.XcodeTestVersion,
-	steps.CacheSaveSPMVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
-
-	// ios-spm-test-config/test_without_building
-	steps.PullIntermediateFilesVersion,
-	steps.XcodeTestWithoutBuildingVersion,
 }
 var sampleSPMResultYML = fmt.Sprintf(`options:
   ios:
@@ -1418,6 +1505,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -1427,27 +1521,21 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
-        archive_and_export_app:
-          summary: Run your Xcode tests and create an IPA file to install your app on a
-            device or share it with your team.
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests, export an IPA file
-            from the project and save it.
+            your project's dependencies if any, run your Xcode tests and save the test results.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-spm-cache@%s: {}
           - xcode-test@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
+          - save-spm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
@@ -1470,29 +1558,35 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
+        test_without_building:
+          steps:
+          - pull-intermediate-files@%s: {}
+          - xcode-test-without-building@%s:
+              inputs:
+              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
+              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+        archive_and_export_app:
+          summary: Run your Xcode tests and create an IPA file to install your app on a
+            device or share it with your team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
+            your project's dependencies if any, run your Xcode tests, export an IPA file
+            from the project and save it.
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-spm-cache@%s: {}
           - xcode-test@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - save-spm-cache@%s: {}
-          - deploy-to-bitrise-io@%s: {}
-        test_without_building:
-          steps:
-          - pull-intermediate-files@%s: {}
-          - xcode-test-without-building@%s:
+          - xcode-archive@%s:
               inputs:
-              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
-              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
 warnings:
   ios: []
 warnings_with_recommendations:
@@ -1503,17 +1597,17 @@ var sampleSPMProjectVersions = []interface{}{
 	// iOS
 	models.FormatVersion,
 
-	// ios-spm-project-test-config/build_for_testing
+	// ios-spm-project-test-config/run_tests
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.XcodeBuildForTestVersion,
-	steps.XcodeTestShardCalculationVersion,
+	steps.XcodeTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios-spm-project-test-config/run_tests
+	// ios-spm-project-test-config/build_for_testing
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.XcodeTestVersion,
+	steps.XcodeBuildForTestVersion,
+	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// ios-spm-project-test-config/test_without_building
@@ -1577,6 +1671,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -1586,6 +1687,20 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
+          description: The workflow will first clone your Git repository, cache and install
+            your project's dependencies if any, run your Xcode tests and save the test results.
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+          - xcode-test@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - test_repetition_mode: retry_on_failure
+              - cache_level: none
+          - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
           - activate-ssh-key@%s: {}
@@ -1605,20 +1720,6 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
-          description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
-          steps:
-          - activate-ssh-key@%s: {}
-          - git-clone@%s: {}
-          - xcode-test@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - test_repetition_mode: retry_on_failure
-              - cache_level: none
-          - deploy-to-bitrise-io@%s: {}
         test_without_building:
           steps:
           - pull-intermediate-files@%s: {}
@@ -1631,6 +1732,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: macos
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: <default-branch>
       workflows:
         primary:
           steps:
@@ -1646,5 +1754,4 @@ warnings:
   macos: []
 warnings_with_recommendations:
   ios: []
-  macos: []
- `, sampleSPMProjectVersions...)
+  macos: []`, sampleSPMProjectVersions...)
@@ -81,6 +81,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: java
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -121,6 +128,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: java
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
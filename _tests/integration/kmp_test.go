@@ -72,18 +72,45 @@ var kmpTaskmanResultYaml = fmt.Sprintf(`options:
                             config: kotlin-multiplatform-config
                           enterprise:
                             config: kotlin-multiplatform-config
+            default_value: debug
+            value_infos:
+              debug:
+                recommended: true
+                reason: every Android project has a debug build type, which needs
+                  no signing config
+            validation:
+              pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+              hint: variant names in separate lines, like debug or freeRelease
 configs:
   kotlin-multiplatform:
     kotlin-multiplatform-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: kotlin-multiplatform
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        pipeline: build
+        push_branch: <default-branch>
       pipelines:
         build:
           workflows:
             android_build: {}
             ios_build: {}
       workflows:
+        run_tests:
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+          - restore-gradle-cache@%s: {}
+          - activate-build-cache-for-gradle@%s: {}
+          - gradle-unit-test@%s:
+              inputs:
+              - project_root_dir: $PROJECT_ROOT_DIR
+          - save-gradle-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
         android_build:
           steps:
           - activate-ssh-key@%s: {}
@@ -114,17 +141,6 @@ configs:
               - automatic_code_signing: api-key
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
-          steps:
-          - activate-ssh-key@%s: {}
-          - git-clone@%s: {}
-          - restore-gradle-cache@%s: {}
-          - activate-build-cache-for-gradle@%s: {}
-          - gradle-unit-test@%s:
-              inputs:
-              - project_root_dir: $PROJECT_ROOT_DIR
-          - save-gradle-cache@%s: {}
-          - deploy-to-bitrise-io@%s: {}
 warnings:
   kotlin-multiplatform: []
 warnings_with_recommendations:
@@ -133,29 +149,29 @@ warnings_with_recommendations:
 
 var kmpTaskmanResultVersions = []interface{}{
 	models.FormatVersion,
-	// android_build
+	// run_tests
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.GradleUnitTestVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
-	// ios_build
+	// android_build
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.XcodeArchiveVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
-	// run_tests
+	// ios_build
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.GradleUnitTestVersion,
+	steps.XcodeArchiveVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 }
@@ -32,16 +32,17 @@ func TestMacOS(t *testing.T) {
 
 var sampleAppsOSX1011Versions = []interface{}{
 	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeTestMacVersion,
-	steps.XcodeArchiveMacVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeTestMacVersion,
+	steps.XcodeArchiveMacVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -88,30 +89,37 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: macos
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - certificate-and-profile-installer@%s: {}
           - xcode-test-mac@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-          - xcode-archive-mac@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - certificate-and-profile-installer@%s: {}
           - xcode-test-mac@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+          - xcode-archive-mac@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
 warnings:
   macos: []
@@ -123,16 +131,16 @@ var sampleSPMMacProjectVersions = []interface{}{
 	// iOS
 	models.FormatVersion,
 
-	// ios-spm-project-test-config
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.XcodeBuildForTestVersion,
-	steps.XcodeTestShardCalculationVersion,
+	steps.XcodeTestVersion,
 	steps.DeployToBitriseIoVersion,
 
+	// ios-spm-project-test-config
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.XcodeTestVersion,
+	steps.XcodeBuildForTestVersion,
+	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.PullIntermediateFilesVersion,
@@ -195,6 +203,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       pipelines:
         run_tests:
           workflows:
@@ -204,6 +219,20 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
+          description: The workflow will first clone your Git repository, cache and install
+            your project's dependencies if any, run your Xcode tests and save the test results.
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+          - xcode-test@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - test_repetition_mode: retry_on_failure
+              - cache_level: none
+          - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
           - activate-ssh-key@%s: {}
@@ -223,20 +252,6 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
-          description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
-          steps:
-          - activate-ssh-key@%s: {}
-          - git-clone@%s: {}
-          - xcode-test@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - test_repetition_mode: retry_on_failure
-              - cache_level: none
-          - deploy-to-bitrise-io@%s: {}
         test_without_building:
           steps:
           - pull-intermediate-files@%s: {}
@@ -249,6 +264,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: macos
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: <default-branch>
       workflows:
         primary:
           steps:
@@ -264,5 +286,4 @@ warnings:
   macos: []
 warnings_with_recommendations:
   ios: []
-  macos: []
- `, sampleSPMMacProjectVersions...)
+  macos: []`, sampleSPMMacProjectVersions...)
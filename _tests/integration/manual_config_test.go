@@ -40,14 +40,13 @@ func TestManualConfig(t *testing.T) {
 var customConfigVersions = []interface{}{
 	// default-android-config
 	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
-	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
-	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -62,22 +61,23 @@ var customConfigVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
+	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
+	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.CacheSaveGradleVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// default-android-config-kts
 	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
-	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
-	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -92,10 +92,12 @@ var customConfigVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreGradleVersion,
 	steps.InstallMissingAndroidToolsVersion,
+	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
+	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
-	steps.CacheSaveGradleVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// cordova
@@ -125,8 +127,56 @@ var customConfigVersions = []interface{}{
 	steps.DeployToBitriseIoVersion,
 
 	// flutter
-	// flutter-config-test-both-0
+	// flutter-config-test-android-web-0
+	models.FormatVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.FlutterInstallVersion,
+	steps.CacheRestoreDartVersion,
+	steps.FlutterTestVersion,
+	steps.CacheSaveDartVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
+
+	// flutter-config-test-ios-android-web-0
+	models.FormatVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.FlutterInstallVersion,
+	steps.CacheRestoreDartVersion,
+	steps.FlutterTestVersion,
+	steps.CacheSaveDartVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
+
+	// flutter-config-test-ios-web-0
 	models.FormatVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.FlutterInstallVersion,
+	steps.CacheRestoreDartVersion,
+	steps.FlutterTestVersion,
+	steps.CacheSaveDartVersion,
+	steps.DeployToBitriseIoVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CertificateAndProfileInstallerVersion,
@@ -136,6 +186,9 @@ var customConfigVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
 
+	// flutter-config-test-web-0
+	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.FlutterInstallVersion,
@@ -158,11 +211,15 @@ var customConfigVersions = []interface{}{
 
 	// ios
 	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreCocoapodsVersion,
+	steps.CacheRestoreSPMVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveCocoapodsVersion,
+	steps.CacheSaveSPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -176,19 +233,16 @@ var customConfigVersions = []interface{}{
 	steps.XcodeTestShardCalculationVersion,
 	steps.DeployToBitriseIoVersion,
 
+	steps.PullIntermediateFilesVersion,
+	steps.XcodeTestWithoutBuildingVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreCocoapodsVersion,
-	steps.CacheRestoreSPMVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestVersion,
-	steps.CacheSaveCocoapodsVersion,
-	steps.CacheSaveSPMVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
-	steps.PullIntermediateFilesVersion,
-	steps.XcodeTestWithoutBuildingVersion,
-
 	// java
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
@@ -218,15 +272,15 @@ var customConfigVersions = []interface{}{
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.GradleUnitTestVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.GradleUnitTestVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 	// default-kotlin-multiplatform-config-android-ios
@@ -235,22 +289,22 @@ var customConfigVersions = []interface{}{
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.AndroidBuildVersion,
-	steps.SignAPKVersion,
+	steps.GradleUnitTestVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.XcodeArchiveVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.GradleUnitTestVersion,
+	steps.XcodeArchiveVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 	// default-kotlin-multiplatform-config-ios
@@ -259,35 +313,36 @@ var customConfigVersions = []interface{}{
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.XcodeArchiveVersion,
+	steps.GradleUnitTestVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CacheRestoreGradleVersion,
 	steps.ActivateBuildCacheForGradleVersion,
-	steps.GradleUnitTestVersion,
+	steps.XcodeArchiveVersion,
 	steps.CacheSaveGradleVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// macos
 	models.FormatVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CertificateAndProfileInstallerVersion,
+	steps.CacheRestoreCocoapodsVersion,
+	steps.CacheRestoreSPMVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestMacVersion,
-	steps.XcodeArchiveMacVersion,
+	steps.CacheSaveCocoapodsVersion,
+	steps.CacheSaveSPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreCocoapodsVersion,
-	steps.CacheRestoreSPMVersion,
+	steps.CertificateAndProfileInstallerVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestMacVersion,
-	steps.CacheSaveCocoapodsVersion,
-	steps.CacheSaveSPMVersion,
+	steps.XcodeArchiveMacVersion,
 	steps.DeployToBitriseIoVersion,
 
 	// node-js
@@ -353,17 +408,28 @@ var customConfigVersions = []interface{}{
 	steps.DeployToBitriseIoVersion,
 
 	// react native
-	// default-react-native-config/deploy
 	models.FormatVersion,
+
+	// default-react-native-android-config/primary
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
+	steps.YarnVersion,
+	steps.YarnVersion,
+	steps.CacheSaveNPMVersion,
+	steps.DeployToBitriseIoVersion,
+
+	// default-react-native-android-config/deploy
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.YarnVersion,
 	steps.YarnVersion,
 	steps.InstallMissingAndroidToolsVersion,
 	steps.AndroidBuildVersion,
-	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
+	models.FormatVersion,
+
 	// default-react-native-config/primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -373,8 +439,28 @@ var customConfigVersions = []interface{}{
 	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// default-react-native-expo-config/deploy
+	// default-react-native-config/deploy
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.YarnVersion,
+	steps.YarnVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+
 	models.FormatVersion,
+
+	// default-react-native-expo-config/primary
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
+	steps.YarnVersion,
+	steps.YarnVersion,
+	steps.CacheSaveNPMVersion,
+	steps.DeployToBitriseIoVersion,
+
+	// default-react-native-expo-config/deploy
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.YarnVersion,
@@ -382,7 +468,9 @@ var customConfigVersions = []interface{}{
 	steps.RunEASBuildVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// default-react-native-expo-config/primary
+	models.FormatVersion,
+
+	// default-react-native-ios-config/primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CacheRestoreNPMVersion,
@@ -391,6 +479,14 @@ var customConfigVersions = []interface{}{
 	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
+	// default-react-native-ios-config/deploy
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.YarnVersion,
+	steps.YarnVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+
 	// ruby
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
@@ -439,6 +535,15 @@ var customConfigResultYML = fmt.Sprintf(`options:
                     config: default-android-config
                   "yes":
                     config: default-android-config-kts
+            default_value: debug
+            value_infos:
+              debug:
+                recommended: true
+                reason: every Android project has a debug build type, which needs
+                  no signing config
+            validation:
+              pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+              hint: variant names in separate lines, like debug or freeRelease
   cordova:
     title: Directory of the Cordova config.xml file
     summary: The working directory of your Cordova project is where you store your
@@ -483,6 +588,10 @@ var customConfigResultYML = fmt.Sprintf(`options:
                 config: default-fastlane-android-config
               ios:
                 config: default-fastlane-ios-config
+        validation:
+          pattern: ^([a-z]+ )?[A-Za-z_][A-Za-z0-9_]*( +[A-Za-z_][A-Za-z0-9_]*:\S*)*$
+          hint: a lane name, optionally prefixed with its platform and followed by
+            its options, like beta or ios beta version:1.2
   flutter:
     title: Project location
     summary: The path to your Flutter project, stored as an Environment Variable.
@@ -492,7 +601,22 @@ var customConfigResultYML = fmt.Sprintf(`options:
     type: user_input
     value_map:
       "":
-        config: flutter-config-test-ios-android-web-0
+        title: Platforms to build
+        summary: The platforms of the project built by the build_app workflow, the
+          project is tested or analysed in any case.
+        type: multi_selector
+        value_map:
+          "":
+            config: flutter-config-test-web-0
+          android:
+            config: flutter-config-test-android-web-0
+          android,ios:
+            config: flutter-config-test-ios-android-web-0
+          ios:
+            config: flutter-config-test-ios-web-0
+        values:
+        - android
+        - ios
   ionic:
     title: Directory of the Ionic config.xml file
     summary: The working directory of your Ionic project is where you store your config.xml
@@ -583,46 +707,37 @@ var customConfigResultYML = fmt.Sprintf(`options:
     type: user_input
     value_map:
       "":
-        title: Has Android Application target?
-        summary: Indicates whether the project contains an Android Application target.
-        type: selector
+        title: Application targets
+        summary: The application targets of the project to build, besides testing
+          the shared Kotlin code.
+        type: multi_selector
         value_map:
-          "no":
-            title: Has iOS Application target?
-            summary: Indicates whether the project contains an iOS Application target.
-            type: selector
+          "":
+            config: default-kotlin-multiplatform-config
+          android:
+            title: Android Application Module
+            summary: The name of the Android application module to build.
+            env_key: MODULE
+            type: user_input
             value_map:
-              "no":
-                config: default-kotlin-multiplatform-config
-              "yes":
-                title: iOS Application Project or Workspace path
-                summary: The path of iOS application Xcode project or workspace to
-                  build.
-                env_key: BITRISE_PROJECT_PATH
-                type: user_input
+              "":
+                title: Android Application Variant
+                summary: The name of the Android application variant to build.
+                env_key: VARIANT
+                type: user_input_optional
                 value_map:
                   "":
-                    title: iOS Application Scheme
-                    summary: The name of the iOS application scheme to build.
-                    env_key: BITRISE_SCHEME
-                    type: user_input
-                    value_map:
-                      "":
-                        title: iOS Application Distribution method
-                        summary: The export method to use to build the iOS application
-                          IPA file.
-                        env_key: BITRISE_DISTRIBUTION_METHOD
-                        type: selector
-                        value_map:
-                          ad-hoc:
-                            config: default-kotlin-multiplatform-config-ios
-                          app-store:
-                            config: default-kotlin-multiplatform-config-ios
-                          development:
-                            config: default-kotlin-multiplatform-config-ios
-                          enterprise:
-                            config: default-kotlin-multiplatform-config-ios
-          "yes":
+                    config: default-kotlin-multiplatform-config-android
+                default_value: debug
+                value_infos:
+                  debug:
+                    recommended: true
+                    reason: every Android project has a debug build type, which needs
+                      no signing config
+                validation:
+                  pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                  hint: variant names in separate lines, like debug or freeRelease
+          android,ios:
             title: Android Application Module
             summary: The name of the Android application module to build.
             env_key: MODULE
@@ -635,41 +750,72 @@ var customConfigResultYML = fmt.Sprintf(`options:
                 type: user_input_optional
                 value_map:
                   "":
-                    title: Has iOS Application target?
-                    summary: Indicates whether the project contains an iOS Application
-                      target.
-                    type: selector
+                    title: iOS Application Project or Workspace path
+                    summary: The path of iOS application Xcode project or workspace
+                      to build.
+                    env_key: BITRISE_PROJECT_PATH
+                    type: user_input
                     value_map:
-                      "no":
-                        config: default-kotlin-multiplatform-config-android
-                      "yes":
-                        title: iOS Application Project or Workspace path
-                        summary: The path of iOS application Xcode project or workspace
-                          to build.
-                        env_key: BITRISE_PROJECT_PATH
+                      "":
+                        title: iOS Application Scheme
+                        summary: The name of the iOS application scheme to build.
+                        env_key: BITRISE_SCHEME
                         type: user_input
                         value_map:
                           "":
-                            title: iOS Application Scheme
-                            summary: The name of the iOS application scheme to build.
-                            env_key: BITRISE_SCHEME
-                            type: user_input
+                            title: iOS Application Distribution method
+                            summary: The export method to use to build the iOS application
+                              IPA file.
+                            env_key: BITRISE_DISTRIBUTION_METHOD
+                            type: selector
                             value_map:
-                              "":
-                                title: iOS Application Distribution method
-                                summary: The export method to use to build the iOS
-                                  application IPA file.
-                                env_key: BITRISE_DISTRIBUTION_METHOD
-                                type: selector
-                                value_map:
-                                  ad-hoc:
-                                    config: default-kotlin-multiplatform-config-android-ios
-                                  app-store:
-                                    config: default-kotlin-multiplatform-config-android-ios
-                                  development:
-                                    config: default-kotlin-multiplatform-config-android-ios
-                                  enterprise:
-                                    config: default-kotlin-multiplatform-config-android-ios
+                              ad-hoc:
+                                config: default-kotlin-multiplatform-config-android-ios
+                              app-store:
+                                config: default-kotlin-multiplatform-config-android-ios
+                              development:
+                                config: default-kotlin-multiplatform-config-android-ios
+                              enterprise:
+                                config: default-kotlin-multiplatform-config-android-ios
+                default_value: debug
+                value_infos:
+                  debug:
+                    recommended: true
+                    reason: every Android project has a debug build type, which needs
+                      no signing config
+                validation:
+                  pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                  hint: variant names in separate lines, like debug or freeRelease
+          ios:
+            title: iOS Application Project or Workspace path
+            summary: The path of iOS application Xcode project or workspace to build.
+            env_key: BITRISE_PROJECT_PATH
+            type: user_input
+            value_map:
+              "":
+                title: iOS Application Scheme
+                summary: The name of the iOS application scheme to build.
+                env_key: BITRISE_SCHEME
+                type: user_input
+                value_map:
+                  "":
+                    title: iOS Application Distribution method
+                    summary: The export method to use to build the iOS application
+                      IPA file.
+                    env_key: BITRISE_DISTRIBUTION_METHOD
+                    type: selector
+                    value_map:
+                      ad-hoc:
+                        config: default-kotlin-multiplatform-config-ios
+                      app-store:
+                        config: default-kotlin-multiplatform-config-ios
+                      development:
+                        config: default-kotlin-multiplatform-config-ios
+                      enterprise:
+                        config: default-kotlin-multiplatform-config-ios
+        values:
+        - android
+        - ios
   macos:
     title: Project or Workspace path
     summary: The location of your Xcode project, Xcode workspace or SPM project files
@@ -728,6 +874,9 @@ var customConfigResultYML = fmt.Sprintf(`options:
                 config: default-node-js-npm-config
               yarn:
                 config: default-node-js-yarn-config
+        validation:
+          pattern: ^\d+(\.\d+){0,2}(:(latest|installed))?$
+          hint: an exact (3.2.0) or partial (3:latest, 3:installed) version
   python:
     title: Python Project Directory
     summary: The directory containing the Python project files (requirements.txt,
@@ -753,6 +902,9 @@ var customConfigResultYML = fmt.Sprintf(`options:
                 config: default-python-poetry-config
               uv:
                 config: default-python-uv-config
+        validation:
+          pattern: ^\d+(\.\d+){0,2}(:(latest|installed))?$
+          hint: an exact (3.2.0) or partial (3:latest, 3:installed) version
   react-native:
     title: Is this an [Expo](https://expo.dev)-based React Native project?
     summary: |-
@@ -761,67 +913,158 @@ var customConfigResultYML = fmt.Sprintf(`options:
     type: selector
     value_map:
       "no":
-        title: The root directory of your Android project
-        summary: The root directory of your Android project where the gradlew or gradlew.bat
-          wrapper is located. This is stored as an Environment Variable (PROJECT_LOCATION)
-          and you can specify paths relative to this path in your Workflows. It can
-          be changed any time.
-        env_key: PROJECT_LOCATION
-        type: user_input
+        title: Platforms to build
+        summary: The native apps of the project built by the deploy workflow.
+        type: multi_selector
         value_map:
           android:
-            title: Module
-            summary: Modules provide a container for your Android project's source
-              code, resource files, and app level settings, such as the module-level
-              build file and Android manifest file. Each module can be independently
-              built, tested, and debugged. You can add new modules to your Bitrise
-              builds at any time.
-            env_key: MODULE
+            title: The root directory of your Android project
+            summary: The root directory of your Android project where the gradlew
+              or gradlew.bat wrapper is located. This is stored as an Environment
+              Variable (PROJECT_LOCATION) and you can specify paths relative to this
+              path in your Workflows. It can be changed any time.
+            env_key: PROJECT_LOCATION
             type: user_input
             value_map:
-              app:
-                title: Variant
-                summary: Your Android build variant. You can add variants at any time,
-                  as well as further configure your existing variants later.
-                env_key: VARIANT
-                type: user_input_optional
+              android:
+                title: Module
+                summary: Modules provide a container for your Android project's source
+                  code, resource files, and app level settings, such as the module-level
+                  build file and Android manifest file. Each module can be independently
+                  built, tested, and debugged. You can add new modules to your Bitrise
+                  builds at any time.
+                env_key: MODULE
+                type: user_input
                 value_map:
-                  Debug:
-                    title: Project or Workspace path
-                    summary: The location of your Xcode project, Xcode workspace or
-                      SPM project files stored as an Environment Variable. In your
-                      Workflows, you can specify paths relative to this path.
-                    env_key: BITRISE_PROJECT_PATH
-                    type: user_input
+                  app:
+                    title: Variant
+                    summary: Your Android build variant. You can add variants at any
+                      time, as well as further configure your existing variants later.
+                    env_key: VARIANT
+                    type: user_input_optional
                     value_map:
-                      "":
-                        title: Scheme name
-                        summary: An Xcode scheme defines a collection of targets to
-                          build, a configuration to use when building, and a collection
-                          of tests to execute. Only shared schemes are detected automatically
-                          but you can use any scheme as a target on Bitrise. You can
-                          change the scheme at any time in your Env Vars.
-                        env_key: BITRISE_SCHEME
+                      Debug:
+                        config: default-react-native-android-config
+                    default_value: debug
+                    value_infos:
+                      debug:
+                        recommended: true
+                        reason: every Android project has a debug build type, which
+                          needs no signing config
+                    validation:
+                      pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                      hint: variant names in separate lines, like debug or freeRelease
+          android,ios:
+            title: The root directory of your Android project
+            summary: The root directory of your Android project where the gradlew
+              or gradlew.bat wrapper is located. This is stored as an Environment
+              Variable (PROJECT_LOCATION) and you can specify paths relative to this
+              path in your Workflows. It can be changed any time.
+            env_key: PROJECT_LOCATION
+            type: user_input
+            value_map:
+              android:
+                title: Module
+                summary: Modules provide a container for your Android project's source
+                  code, resource files, and app level settings, such as the module-level
+                  build file and Android manifest file. Each module can be independently
+                  built, tested, and debugged. You can add new modules to your Bitrise
+                  builds at any time.
+                env_key: MODULE
+                type: user_input
+                value_map:
+                  app:
+                    title: Variant
+                    summary: Your Android build variant. You can add variants at any
+                      time, as well as further configure your existing variants later.
+                    env_key: VARIANT
+                    type: user_input_optional
+                    value_map:
+                      Debug:
+                        title: Project or Workspace path
+                        summary: The location of your Xcode project, Xcode workspace
+                          or SPM project files stored as an Environment Variable.
+                          In your Workflows, you can specify paths relative to this
+                          path.
+                        env_key: BITRISE_PROJECT_PATH
                         type: user_input
                         value_map:
                           "":
-                            title: Distribution method
-                            summary: The export method used to create an .ipa file
-                              in your builds, stored as an Environment Variable. You
-                              can change this at any time, or even create several
-                              .ipa files with different export methods in the same
-                              build.
-                            env_key: BITRISE_DISTRIBUTION_METHOD
-                            type: selector
+                            title: Scheme name
+                            summary: An Xcode scheme defines a collection of targets
+                              to build, a configuration to use when building, and
+                              a collection of tests to execute. Only shared schemes
+                              are detected automatically but you can use any scheme
+                              as a target on Bitrise. You can change the scheme at
+                              any time in your Env Vars.
+                            env_key: BITRISE_SCHEME
+                            type: user_input
                             value_map:
-                              ad-hoc:
-                                config: default-react-native-config
-                              app-store:
-                                config: default-react-native-config
-                              development:
-                                config: default-react-native-config
-                              enterprise:
-                                config: default-react-native-config
+                              "":
+                                title: Distribution method
+                                summary: The export method used to create an .ipa
+                                  file in your builds, stored as an Environment Variable.
+                                  You can change this at any time, or even create
+                                  several .ipa files with different export methods
+                                  in the same build.
+                                env_key: BITRISE_DISTRIBUTION_METHOD
+                                type: selector
+                                value_map:
+                                  ad-hoc:
+                                    config: default-react-native-config
+                                  app-store:
+                                    config: default-react-native-config
+                                  development:
+                                    config: default-react-native-config
+                                  enterprise:
+                                    config: default-react-native-config
+                    default_value: debug
+                    value_infos:
+                      debug:
+                        recommended: true
+                        reason: every Android project has a debug build type, which
+                          needs no signing config
+                    validation:
+                      pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                      hint: variant names in separate lines, like debug or freeRelease
+          ios:
+            title: Project or Workspace path
+            summary: The location of your Xcode project, Xcode workspace or SPM project
+              files stored as an Environment Variable. In your Workflows, you can
+              specify paths relative to this path.
+            env_key: BITRISE_PROJECT_PATH
+            type: user_input
+            value_map:
+              "":
+                title: Scheme name
+                summary: An Xcode scheme defines a collection of targets to build,
+                  a configuration to use when building, and a collection of tests
+                  to execute. Only shared schemes are detected automatically but you
+                  can use any scheme as a target on Bitrise. You can change the scheme
+                  at any time in your Env Vars.
+                env_key: BITRISE_SCHEME
+                type: user_input
+                value_map:
+                  "":
+                    title: Distribution method
+                    summary: The export method used to create an .ipa file in your
+                      builds, stored as an Environment Variable. You can change this
+                      at any time, or even create several .ipa files with different
+                      export methods in the same build.
+                    env_key: BITRISE_DISTRIBUTION_METHOD
+                    type: selector
+                    value_map:
+                      ad-hoc:
+                        config: default-react-native-ios-config
+                      app-store:
+                        config: default-react-native-ios-config
+                      development:
+                        config: default-react-native-ios-config
+                      enterprise:
+                        config: default-react-native-ios-config
+        values:
+        - android
+        - ios
       "yes":
         title: Expo project directory
         summary: Path of the directory containing the project's  `+"`package.json`"+` and
@@ -856,6 +1099,9 @@ var customConfigResultYML = fmt.Sprintf(`options:
         value_map:
           "":
             config: default-ruby-config
+        validation:
+          pattern: ^\d+(\.\d+){0,2}(:(latest|installed))?$
+          hint: an exact (3.2.0) or partial (3:latest, 3:installed) version
 configs:
   android:
     default-android-config: |
@@ -865,46 +1111,38 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_apk
+        push_branch: main
       pipelines:
         run_tests:
           workflows:
             run_instrumented_tests:
               parallel: $TEST_SHARD_COUNT
       workflows:
-        build_apk:
-          summary: Run your Android unit tests and create an APK file to install your app
-            on a device or share it with your team.
-          description: The workflow will first clone your Git repository, install Android
-            tools, set the project's version code based on the build number, run Android
-            lint and unit tests, build the project's APK file and save it.
+        run_tests:
+          summary: Run your Android unit tests and get the test report.
+          description: The workflow will first clone your Git repository, cache your Gradle
+            dependencies, install Android tools, run your Android unit tests and save the
+            test report.
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
+          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
-          - change-android-versioncode-and-versionname@%s:
-              inputs:
-              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle
-          - android-lint@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - variant: $VARIANT
-              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-              - cache_level: none
-          - sign-apk@%s:
-              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         run_instrumented_tests:
           summary: Run your Android instrumented tests and get the test report.
@@ -930,25 +1168,40 @@ configs:
                     -Pandroid.testInstrumentationRunnerArguments.shardIndex=$BITRISE_IO_PARALLEL_INDEX
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
-          summary: Run your Android unit tests and get the test report.
-          description: The workflow will first clone your Git repository, cache your Gradle
-            dependencies, install Android tools, run your Android unit tests and save the
-            test report.
+        build_apk:
+          summary: Run your Android unit tests and create an APK file to install your app
+            on a device or share it with your team.
+          description: The workflow will first clone your Git repository, install Android
+            tools, set the project's version code based on the build number, run Android
+            lint and unit tests, build the project's APK file and save it.
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
-          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
+          - change-android-versioncode-and-versionname@%s:
+              inputs:
+              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle
+          - android-lint@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - variant: $VARIANT
+              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - save-gradle-cache@%s: {}
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+              - cache_level: none
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
     default-android-config-kts: |
       format_version: "%s"
@@ -957,46 +1210,38 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_apk
+        push_branch: main
       pipelines:
         run_tests:
           workflows:
             run_instrumented_tests:
               parallel: $TEST_SHARD_COUNT
       workflows:
-        build_apk:
-          summary: Run your Android unit tests and create an APK file to install your app
-            on a device or share it with your team.
-          description: The workflow will first clone your Git repository, install Android
-            tools, set the project's version code based on the build number, run Android
-            lint and unit tests, build the project's APK file and save it.
+        run_tests:
+          summary: Run your Android unit tests and get the test report.
+          description: The workflow will first clone your Git repository, cache your Gradle
+            dependencies, install Android tools, run your Android unit tests and save the
+            test report.
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
+          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
-          - change-android-versioncode-and-versionname@%s:
-              inputs:
-              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle.kts
-          - android-lint@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - variant: $VARIANT
-              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-              - cache_level: none
-          - sign-apk@%s:
-              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         run_instrumented_tests:
           summary: Run your Android instrumented tests and get the test report.
@@ -1022,31 +1267,53 @@ configs:
                     -Pandroid.testInstrumentationRunnerArguments.shardIndex=$BITRISE_IO_PARALLEL_INDEX
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
-          summary: Run your Android unit tests and get the test report.
-          description: The workflow will first clone your Git repository, cache your Gradle
-            dependencies, install Android tools, run your Android unit tests and save the
-            test report.
+        build_apk:
+          summary: Run your Android unit tests and create an APK file to install your app
+            on a device or share it with your team.
+          description: The workflow will first clone your Git repository, install Android
+            tools, set the project's version code based on the build number, run Android
+            lint and unit tests, build the project's APK file and save it.
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
-          - restore-gradle-cache@%s: {}
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
+          - change-android-versioncode-and-versionname@%s:
+              inputs:
+              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle.kts
+          - android-lint@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - variant: $VARIANT
+              - cache_level: none
           - android-unit-test@%s:
               inputs:
               - project_location: $PROJECT_LOCATION
               - variant: $VARIANT
               - cache_level: none
-          - save-gradle-cache@%s: {}
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+              - cache_level: none
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - deploy-to-bitrise-io@%s: {}
   cordova:
     default-cordova-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: cordova
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: main
       workflows:
         primary:
           steps:
@@ -1076,6 +1343,13 @@ configs:
       app:
         envs:
         - FASTLANE_XCODE_LIST_TIMEOUT: "120"
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: main
       workflows:
         primary:
           steps:
@@ -1095,6 +1369,13 @@ configs:
       app:
         envs:
         - FASTLANE_XCODE_LIST_TIMEOUT: "120"
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: main
       workflows:
         primary:
           steps:
@@ -1109,11 +1390,151 @@ configs:
               - enable_cache: "no"
           - deploy-to-bitrise-io@%s: {}
   flutter:
+    flutter-config-test-android-web-0: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_app
+        push_branch: main
+      workflows:
+        run_tests:
+          description: |
+            Runs tests or analysis.
+
+            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - flutter-installer@%s: {}
+          - restore-dart-cache@%s: {}
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - save-dart-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
+        build_app:
+          description: |
+            Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
+
+            If you build for iOS, make sure to set up code signing secrets on Bitrise for a successful build.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html) for signing and deployment options.
+            - Check out the Code signing guide for [iOS](https://docs.bitrise.io/en/bitrise-ci/code-signing/ios-code-signing.html) and [Android](https://docs.bitrise.io/en/bitrise-ci/code-signing/android-code-signing.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: android
+          - deploy-to-bitrise-io@%s: {}
     flutter-config-test-ios-android-web-0: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_app
+        push_branch: main
       workflows:
+        run_tests:
+          description: |
+            Runs tests or analysis.
+
+            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - flutter-installer@%s: {}
+          - restore-dart-cache@%s: {}
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - save-dart-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
+        build_app:
+          description: |
+            Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
+
+            If you build for iOS, make sure to set up code signing secrets on Bitrise for a successful build.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html) for signing and deployment options.
+            - Check out the Code signing guide for [iOS](https://docs.bitrise.io/en/bitrise-ci/code-signing/ios-code-signing.html) and [Android](https://docs.bitrise.io/en/bitrise-ci/code-signing/android-code-signing.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: both
+              - ios_output_type: archive
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-ios-web-0: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: build_app
+        push_branch: main
+      workflows:
+        run_tests:
+          description: |
+            Runs tests or analysis.
+
+            Runs flutter-test if a test directory is present, otherwise runs flutter-analyze.
+
+            Next steps:
+            - Check out [Getting started with Flutter apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - flutter-installer@%s: {}
+          - restore-dart-cache@%s: {}
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - save-dart-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
         build_app:
           description: |
             Builds and deploys app using [Deploy to bitrise.io Step](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-flutter-projects.html#deploying-a-flutter-app).
@@ -1138,9 +1559,21 @@ configs:
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: both
+              - platform: ios
               - ios_output_type: archive
           - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-web-0: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
+      workflows:
         run_tests:
           description: |
             Runs tests or analysis.
@@ -1165,6 +1598,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ionic
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: main
       workflows:
         primary:
           steps:
@@ -1194,6 +1634,13 @@ configs:
       app:
         envs:
         - TEST_SHARD_COUNT: 2
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: archive_and_export_app
+        push_branch: main
       pipelines:
         run_tests:
           workflows:
@@ -1203,16 +1650,16 @@ configs:
               - build_for_testing
               parallel: $TEST_SHARD_COUNT
       workflows:
-        archive_and_export_app:
-          summary: Run your Xcode tests and create an IPA file to install your app on a
-            device or share it with your team.
+        run_tests:
+          summary: Run your Xcode tests and get the test report.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests, export an IPA file
-            from the project and save it.
+            your project's dependencies if any, run your Xcode tests and save the test results.
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
+          - restore-cocoapods-cache@%s: {}
+          - restore-spm-cache@%s: {}
           - cocoapods-install@%s:
               inputs:
               - is_cache_disabled: "true"
@@ -1222,12 +1669,8 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - automatic_code_signing: api-key
+          - save-cocoapods-cache@%s: {}
+          - save-spm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
         build_for_testing:
           steps:
@@ -1256,16 +1699,23 @@ configs:
               - pipeline_intermediate_files: |-
                   BITRISE_TEST_SHARDS_PATH
                   BITRISE_TEST_BUNDLE_PATH
-        run_tests:
-          summary: Run your Xcode tests and get the test report.
+        test_without_building:
+          steps:
+          - pull-intermediate-files@%s: {}
+          - xcode-test-without-building@%s:
+              inputs:
+              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
+              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+        archive_and_export_app:
+          summary: Run your Xcode tests and create an IPA file to install your app on a
+            device or share it with your team.
           description: The workflow will first clone your Git repository, cache and install
-            your project's dependencies if any, run your Xcode tests and save the test results.
+            your project's dependencies if any, run your Xcode tests, export an IPA file
+            from the project and save it.
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
-          - restore-cocoapods-cache@%s: {}
-          - restore-spm-cache@%s: {}
           - cocoapods-install@%s:
               inputs:
               - is_cache_disabled: "true"
@@ -1275,21 +1725,25 @@ configs:
               - scheme: $BITRISE_SCHEME
               - test_repetition_mode: retry_on_failure
               - cache_level: none
-          - save-cocoapods-cache@%s: {}
-          - save-spm-cache@%s: {}
-          - deploy-to-bitrise-io@%s: {}
-        test_without_building:
-          steps:
-          - pull-intermediate-files@%s: {}
-          - xcode-test-without-building@%s:
+          - xcode-archive@%s:
               inputs:
-              - only_testing: $BITRISE_TEST_SHARDS_PATH/$BITRISE_IO_PARALLEL_INDEX
-              - xctestrun: $BITRISE_TEST_BUNDLE_PATH/all_tests.xctestrun
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
   java:
     default-java-gradle-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: java
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
@@ -1304,6 +1758,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: java
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
@@ -1325,6 +1786,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: kotlin-multiplatform
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
@@ -1342,45 +1810,71 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: kotlin-multiplatform
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: android_build
+        push_branch: main
       workflows:
-        android_build:
+        run_tests:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
           - restore-gradle-cache@%s: {}
           - activate-build-cache-for-gradle@%s: {}
-          - android-build@%s:
+          - gradle-unit-test@%s:
               inputs:
-              - project_location: $PROJECT_ROOT_DIR
-              - module: $MODULE
-              - variant: $VARIANT
-          - sign-apk@%s:
-              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+              - project_root_dir: $PROJECT_ROOT_DIR
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
+        android_build:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
           - restore-gradle-cache@%s: {}
           - activate-build-cache-for-gradle@%s: {}
-          - gradle-unit-test@%s:
+          - android-build@%s:
               inputs:
-              - project_root_dir: $PROJECT_ROOT_DIR
+              - project_location: $PROJECT_ROOT_DIR
+              - module: $MODULE
+              - variant: $VARIANT
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
     default-kotlin-multiplatform-config-android-ios: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: kotlin-multiplatform
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        pipeline: build
+        push_branch: main
       pipelines:
         build:
           workflows:
             android_build: {}
             ios_build: {}
       workflows:
+        run_tests:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - restore-gradle-cache@%s: {}
+          - activate-build-cache-for-gradle@%s: {}
+          - gradle-unit-test@%s:
+              inputs:
+              - project_root_dir: $PROJECT_ROOT_DIR
+          - save-gradle-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
         android_build:
           steps:
           - activate-ssh-key@%s:
@@ -1413,6 +1907,18 @@ configs:
               - automatic_code_signing: api-key
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
+    default-kotlin-multiplatform-config-ios: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: kotlin-multiplatform
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: ios_build
+        push_branch: main
+      workflows:
         run_tests:
           steps:
           - activate-ssh-key@%s:
@@ -1425,11 +1931,6 @@ configs:
               - project_root_dir: $PROJECT_ROOT_DIR
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-    default-kotlin-multiplatform-config-ios: |
-      format_version: "%s"
-      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: kotlin-multiplatform
-      workflows:
         ios_build:
           steps:
           - activate-ssh-key@%s:
@@ -1446,30 +1947,26 @@ configs:
               - automatic_code_signing: api-key
           - save-gradle-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        run_tests:
-          steps:
-          - activate-ssh-key@%s:
-              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
-          - git-clone@%s: {}
-          - restore-gradle-cache@%s: {}
-          - activate-build-cache-for-gradle@%s: {}
-          - gradle-unit-test@%s:
-              inputs:
-              - project_root_dir: $PROJECT_ROOT_DIR
-          - save-gradle-cache@%s: {}
-          - deploy-to-bitrise-io@%s: {}
   macos:
     default-macos-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: macos
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: main
       workflows:
-        deploy:
+        primary:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
-          - certificate-and-profile-installer@%s: {}
+          - restore-cocoapods-cache@%s: {}
+          - restore-spm-cache@%s: {}
           - cocoapods-install@%s:
               inputs:
               - is_cache_disabled: "true"
@@ -1477,19 +1974,15 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-          - xcode-archive-mac@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
+          - save-cocoapods-cache@%s: {}
+          - save-spm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
-          - restore-cocoapods-cache@%s: {}
-          - restore-spm-cache@%s: {}
+          - certificate-and-profile-installer@%s: {}
           - cocoapods-install@%s:
               inputs:
               - is_cache_disabled: "true"
@@ -1497,14 +1990,24 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-          - save-cocoapods-cache@%s: {}
-          - save-spm-cache@%s: {}
+          - xcode-archive-mac@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
           - deploy-to-bitrise-io@%s: {}
   node-js:
     default-node-js-npm-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: node-js
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
@@ -1540,6 +2043,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: node-js
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
@@ -1576,6 +2086,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: other
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: primary
+        push_branch: main
       workflows:
         primary:
           steps:
@@ -1588,6 +2105,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: python
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
@@ -1633,6 +2157,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: python
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
@@ -1678,6 +2209,13 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: python
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
@@ -1720,11 +2258,100 @@ configs:
               - paths: ~/.cache/uv
           - deploy-to-bitrise-io@%s: {}
   react-native:
+    default-react-native-android-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: main
+      workflows:
+        primary:
+          description: |
+            Runs tests.
+
+            Next steps:
+            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
+          - yarn@%s:
+              title: yarn install
+              inputs:
+              - command: install
+          - yarn@%s:
+              title: yarn test
+              inputs:
+              - command: test
+          - save-npm-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
+        deploy:
+          description: |
+            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
+
+            Next steps:
+            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
+            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - yarn@%s:
+              title: yarn install
+              inputs:
+              - command: install
+          - yarn@%s:
+              title: yarn test
+              inputs:
+              - command: test
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - deploy-to-bitrise-io@%s: {}
     default-react-native-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: main
       workflows:
+        primary:
+          description: |
+            Runs tests.
+
+            Next steps:
+            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
+          - yarn@%s:
+              title: yarn install
+              inputs:
+              - command: install
+          - yarn@%s:
+              title: yarn test
+              inputs:
+              - command: test
+          - save-npm-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
         deploy:
           description: |
             Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
@@ -1760,12 +2387,24 @@ configs:
               - configuration: Release
               - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
+    default-react-native-expo-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: main
+      workflows:
         primary:
           description: |
             Runs tests.
 
             Next steps:
-            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
+            - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
@@ -1774,18 +2413,15 @@ configs:
           - yarn@%s:
               title: yarn install
               inputs:
+              - workdir: $WORKDIR
               - command: install
           - yarn@%s:
               title: yarn test
               inputs:
+              - workdir: $WORKDIR
               - command: test
           - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-    default-react-native-expo-config: |
-      format_version: "%s"
-      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: react-native
-      workflows:
         deploy:
           description: |
             Tests the app and runs a build on Expo Application Services (EAS).
@@ -1813,12 +2449,24 @@ configs:
               - platform: $PLATFORM
               - work_dir: $WORKDIR
           - deploy-to-bitrise-io@%s: {}
+    default-react-native-ios-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: main
+      workflows:
         primary:
           description: |
             Runs tests.
 
             Next steps:
-            - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
+            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
@@ -1827,20 +2475,52 @@ configs:
           - yarn@%s:
               title: yarn install
               inputs:
-              - workdir: $WORKDIR
               - command: install
           - yarn@%s:
               title: yarn test
               inputs:
-              - workdir: $WORKDIR
               - command: test
           - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
+        deploy:
+          description: |
+            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
+
+            Next steps:
+            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
+            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - yarn@%s:
+              title: yarn install
+              inputs:
+              - command: install
+          - yarn@%s:
+              title: yarn test
+              inputs:
+              - command: test
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - configuration: Release
+              - automatic_code_signing: api-key
+          - deploy-to-bitrise-io@%s: {}
   ruby:
     default-ruby-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ruby
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: main
       workflows:
         run_tests:
           steps:
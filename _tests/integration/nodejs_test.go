@@ -80,6 +80,15 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: node-js
+      tools:
+        node: "22"
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -99,8 +108,6 @@ configs:
               inputs:
               - command: run test
           - save-npm-cache@%s: {}
-      tools:
-        node: "22"
 warnings:
   node-js: []
 warnings_with_recommendations:
@@ -138,6 +145,15 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: node-js
+      tools:
+        node: 22.13.0
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -153,8 +169,6 @@ configs:
               inputs:
               - command: run lint
           - save-npm-cache@%s: {}
-      tools:
-        node: 22.13.0
 warnings:
   node-js: []
 warnings_with_recommendations:
@@ -193,6 +207,15 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: node-js
+      tools:
+        node: 22.14.0
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -212,8 +235,6 @@ configs:
               inputs:
               - command: run test
           - save-npm-cache@%s: {}
-      tools:
-        node: 22.14.0
 warnings:
   node-js: []
 warnings_with_recommendations:
@@ -224,6 +245,29 @@ warnings_with_recommendations:
 // the last-written config (nextjs-npm, node: "22") is what ends up in the configs map.
 
 var nodejsSamplesResultVersions = []interface{}{
+	// node-js-monorepo-config
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
+	steps.NpmVersion,
+	steps.NpmVersion,
+	steps.NpmVersion,
+	steps.CacheSaveNPMVersion,
+	steps.CacheRestoreNPMVersion,
+	steps.NpmVersion,
+	steps.NpmVersion,
+	steps.NpmVersion,
+	steps.CacheSaveNPMVersion,
+	steps.CacheRestoreNPMVersion,
+	steps.NpmVersion,
+	steps.NpmVersion,
+	steps.NpmVersion,
+	steps.CacheSaveNPMVersion,
+	steps.CacheRestoreNPMVersion,
+	steps.YarnVersion,
+	steps.YarnVersion,
+	steps.CacheSaveNPMVersion,
 	// node-js-npm-lint-test-config (nextjs-npm, node: "22")
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
@@ -245,45 +289,186 @@ var nodejsSamplesResultVersions = []interface{}{
 
 var nodejsSamplesResultYML = fmt.Sprintf(`options:
   node-js:
-    title: Project Directory
-    summary: The directory containing the package.json file
-    env_key: NODEJS_PROJECT_DIR
+    title: Projects
+    summary: Generate the config of a single project, or the monorepo config covering
+      every project
     type: selector
     value_map:
-      nestjs-cats-app:
-        title: Package Manager
-        summary: The package manager used in the project
-        type: selector
-        value_map:
-          npm:
-            config: node-js-npm-lint-test-config
-      nestjs-node-version:
-        title: Package Manager
-        summary: The package manager used in the project
+      all projects:
+        config: node-js-monorepo-config
+      single project:
+        title: Project Directory
+        summary: The directory containing the package.json file
+        env_key: NODEJS_PROJECT_DIR
         type: selector
         value_map:
-          npm:
-            config: node-js-npm-lint-test-config
-      nextjs-npm:
-        title: Package Manager
-        summary: The package manager used in the project
-        type: selector
-        value_map:
-          npm:
-            config: node-js-npm-lint-test-config
-      nextjs-yarn:
-        title: Package Manager
-        summary: The package manager used in the project
-        type: selector
-        value_map:
-          yarn:
-            config: node-js-yarn-lint-config
+          nestjs-cats-app:
+            title: Package Manager
+            summary: The package manager used in the project
+            type: selector
+            value_map:
+              npm:
+                config: node-js-npm-lint-test-config
+          nestjs-node-version:
+            title: Package Manager
+            summary: The package manager used in the project
+            type: selector
+            value_map:
+              npm:
+                config: node-js-npm-lint-test-config
+          nextjs-npm:
+            title: Package Manager
+            summary: The package manager used in the project
+            type: selector
+            value_map:
+              npm:
+                config: node-js-npm-lint-test-config
+          nextjs-yarn:
+            title: Package Manager
+            summary: The package manager used in the project
+            type: selector
+            value_map:
+              yarn:
+                config: node-js-yarn-lint-config
 configs:
   node-js:
+    node-js-monorepo-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: node-js
+      workflows:
+        _setup:
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+        run_tests_nestjs-cats-app:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: nestjs-cats-app/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: nestjs-cats-app/**
+          before_run:
+          - _setup
+          steps:
+          - restore-npm-cache@%s: {}
+          - npm@%s:
+              title: npm install
+              inputs:
+              - workdir: nestjs-cats-app
+              - command: install
+          - npm@%s:
+              title: npm run lint
+              inputs:
+              - workdir: nestjs-cats-app
+              - command: run lint
+          - npm@%s:
+              title: npm run test
+              inputs:
+              - workdir: nestjs-cats-app
+              - command: run test
+          - save-npm-cache@%s: {}
+          tools:
+            node: 22.14.0
+        run_tests_nestjs-node-version:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: nestjs-node-version/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: nestjs-node-version/**
+          before_run:
+          - _setup
+          steps:
+          - restore-npm-cache@%s: {}
+          - npm@%s:
+              title: npm install
+              inputs:
+              - workdir: nestjs-node-version
+              - command: install
+          - npm@%s:
+              title: npm run lint
+              inputs:
+              - workdir: nestjs-node-version
+              - command: run lint
+          - npm@%s:
+              title: npm run test
+              inputs:
+              - workdir: nestjs-node-version
+              - command: run test
+          - save-npm-cache@%s: {}
+          tools:
+            node: "22"
+        run_tests_nextjs-npm:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: nextjs-npm/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: nextjs-npm/**
+          before_run:
+          - _setup
+          steps:
+          - restore-npm-cache@%s: {}
+          - npm@%s:
+              title: npm install
+              inputs:
+              - workdir: nextjs-npm
+              - command: install
+          - npm@%s:
+              title: npm run lint
+              inputs:
+              - workdir: nextjs-npm
+              - command: run lint
+          - npm@%s:
+              title: npm run test
+              inputs:
+              - workdir: nextjs-npm
+              - command: run test
+          - save-npm-cache@%s: {}
+          tools:
+            node: "22"
+        run_tests_nextjs-yarn:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: nextjs-yarn/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: nextjs-yarn/**
+          before_run:
+          - _setup
+          steps:
+          - restore-npm-cache@%s: {}
+          - yarn@%s:
+              title: yarn install
+              inputs:
+              - workdir: nextjs-yarn
+              - command: install
+          - yarn@%s:
+              title: yarn run lint
+              inputs:
+              - workdir: nextjs-yarn
+              - command: run lint
+          - save-npm-cache@%s: {}
+          tools:
+            node: 22.13.0
     node-js-npm-lint-test-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: node-js
+      tools:
+        node: "22"
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -306,12 +491,19 @@ configs:
               - workdir: $NODEJS_PROJECT_DIR
               - command: run test
           - save-npm-cache@%s: {}
-      tools:
-        node: "22"
     node-js-yarn-lint-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: node-js
+      tools:
+        node: 22.13.0
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -329,8 +521,6 @@ configs:
               - workdir: $NODEJS_PROJECT_DIR
               - command: run lint
           - save-npm-cache@%s: {}
-      tools:
-        node: 22.13.0
 warnings:
   node-js: []
 warnings_with_recommendations:
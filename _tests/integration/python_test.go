@@ -24,6 +24,25 @@ func TestPython(t *testing.T) {
 }
 
 var pythonFastapiResultVersions = []interface{}{
+	// python-monorepo-config
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CacheRestoreVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.CacheSaveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CacheRestoreVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.CacheSaveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CacheRestoreVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.CacheSaveVersion,
+	steps.DeployToBitriseIoVersion,
 	// python-pip-pytest-config
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
@@ -55,24 +74,171 @@ var pythonFastapiResultVersions = []interface{}{
 
 var pythonFastapiResultYML = fmt.Sprintf(`options:
   python:
-    title: Python Project Directory
-    summary: The directory containing the Python project files (requirements.txt,
-      pyproject.toml, etc.)
-    env_key: PYTHON_PROJECT_DIR
+    title: Projects
+    summary: Generate the config of a single project, or the monorepo config covering
+      every project
     type: selector
     value_map:
-      fastapi-pip-sample:
-        config: python-pip-pytest-config
-      fastapi-poetry-sample:
-        config: python-poetry-pytest-config
-      fastapi-uv-sample:
-        config: python-uv-pytest-config
+      all projects:
+        config: python-monorepo-config
+      single project:
+        title: Python Project Directory
+        summary: The directory containing the Python project files (requirements.txt,
+          pyproject.toml, etc.)
+        env_key: PYTHON_PROJECT_DIR
+        type: selector
+        value_map:
+          fastapi-pip-sample:
+            config: python-pip-pytest-config
+          fastapi-poetry-sample:
+            config: python-poetry-pytest-config
+          fastapi-uv-sample:
+            config: python-uv-pytest-config
 configs:
   python:
+    python-monorepo-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: python
+      workflows:
+        _setup:
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+        run_tests_fastapi-pip-sample:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: fastapi-pip-sample/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: fastapi-pip-sample/**
+          before_run:
+          - _setup
+          steps:
+          - restore-cache@%s:
+              inputs:
+              - key: pip-{{ checksum "fastapi-pip-sample/requirements.txt" }}
+          - script@%s:
+              title: Install dependencies
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  pip install --upgrade pip
+                  pip install -r requirements.txt
+                  pip install -r requirements-dev.txt
+              - working_dir: fastapi-pip-sample
+          - script@%s:
+              title: Run tests
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  pytest
+              - working_dir: fastapi-pip-sample
+          - save-cache@%s:
+              inputs:
+              - key: pip-{{ checksum "fastapi-pip-sample/requirements.txt" }}
+              - paths: ~/.cache/pip
+          - deploy-to-bitrise-io@%s: {}
+          tools:
+            python: "3.14"
+        run_tests_fastapi-poetry-sample:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: fastapi-poetry-sample/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: fastapi-poetry-sample/**
+          before_run:
+          - _setup
+          steps:
+          - restore-cache@%s:
+              inputs:
+              - key: poetry-{{ checksum "fastapi-poetry-sample/poetry.lock" }}
+          - script@%s:
+              title: Install dependencies
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  pip install poetry
+                  poetry install --no-root
+              - working_dir: fastapi-poetry-sample
+          - script@%s:
+              title: Run tests
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  poetry run pytest
+              - working_dir: fastapi-poetry-sample
+          - save-cache@%s:
+              inputs:
+              - key: poetry-{{ checksum "fastapi-poetry-sample/poetry.lock" }}
+              - paths: ~/.cache/pypoetry
+          - deploy-to-bitrise-io@%s: {}
+          tools:
+            python: "3.12"
+        run_tests_fastapi-uv-sample:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: fastapi-uv-sample/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: fastapi-uv-sample/**
+          before_run:
+          - _setup
+          steps:
+          - restore-cache@%s:
+              inputs:
+              - key: uv-{{ checksum "fastapi-uv-sample/uv.lock" }}
+          - script@%s:
+              title: Install dependencies
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  pip install uv
+                  uv sync
+              - working_dir: fastapi-uv-sample
+          - script@%s:
+              title: Run tests
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  uv run pytest
+              - working_dir: fastapi-uv-sample
+          - save-cache@%s:
+              inputs:
+              - key: uv-{{ checksum "fastapi-uv-sample/uv.lock" }}
+              - paths: ~/.cache/uv
+          - deploy-to-bitrise-io@%s: {}
+          tools:
+            python: "3.12"
     python-pip-pytest-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: python
+      tools:
+        python: "3.14"
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -106,12 +272,19 @@ configs:
               - key: pip-{{ checksum "requirements.txt" }}
               - paths: ~/.cache/pip
           - deploy-to-bitrise-io@%s: {}
-      tools:
-        python: "3.14"
     python-poetry-pytest-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: python
+      tools:
+        python: "3.12"
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -144,12 +317,19 @@ configs:
               - key: poetry-{{ checksum "poetry.lock" }}
               - paths: ~/.cache/pypoetry
           - deploy-to-bitrise-io@%s: {}
-      tools:
-        python: "3.12"
     python-uv-pytest-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: python
+      tools:
+        python: "3.12"
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -182,8 +362,6 @@ configs:
               - key: uv-{{ checksum "uv.lock" }}
               - paths: ~/.cache/uv
           - deploy-to-bitrise-io@%s: {}
-      tools:
-        python: "3.12"
 warnings:
   python: []
 warnings_with_recommendations:
@@ -41,18 +41,18 @@ func TestReactNativeExpo(t *testing.T) {
 
 var managedExpoVersions = []interface{}{
 	models.FormatVersion,
-	// deploy
+	// primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.YarnVersion,
-	steps.RunEASBuildVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
-	// primary
+	// deploy
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.YarnVersion,
-	steps.CacheSaveNPMVersion,
+	steps.RunEASBuildVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -75,42 +75,49 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           description: |
-            Runs a build on Expo Application Services (EAS).
+            Installs dependencies.
 
             Next steps:
-            - Configure the `+"`Run Expo Application Services (EAS) build`"+` Step's `+"`Access Token`"+` input.
+            - Add tests to your project and configure the workflow to run them.
             - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
-            - For an alternative deploy workflow checkout the [(React Native) Expo: Build using Turtle CLI recipe](https://github.com/bitrise-io/workflow-recipes/blob/main/recipes/rn-expo-turtle-build.md).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
           - yarn@%s:
               title: yarn install
               inputs:
               - command: install
-          - run-eas-build@%s:
-              inputs:
-              - platform: $PLATFORM
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           description: |
-            Installs dependencies.
+            Runs a build on Expo Application Services (EAS).
 
             Next steps:
-            - Add tests to your project and configure the workflow to run them.
+            - Configure the `+"`Run Expo Application Services (EAS) build`"+` Step's `+"`Access Token`"+` input.
             - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
+            - For an alternative deploy workflow checkout the [(React Native) Expo: Build using Turtle CLI recipe](https://github.com/bitrise-io/workflow-recipes/blob/main/recipes/rn-expo-turtle-build.md).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
           - yarn@%s:
               title: yarn install
               inputs:
               - command: install
-          - save-npm-cache@%s: {}
+          - run-eas-build@%s:
+              inputs:
+              - platform: $PLATFORM
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
@@ -120,20 +127,20 @@ warnings_with_recommendations:
 
 var managedExpo2Versions = []interface{}{
 	models.FormatVersion,
-	// deploy
+	// primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.YarnVersion,
 	steps.YarnVersion,
-	steps.RunEASBuildVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
-	// primary
+	// deploy
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.YarnVersion,
 	steps.YarnVersion,
-	steps.CacheSaveNPMVersion,
+	steps.RunEASBuildVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -156,18 +163,24 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           description: |
-            Tests the app and runs a build on Expo Application Services (EAS).
+            Runs tests.
 
             Next steps:
-            - Configure the `+"`Run Expo Application Services (EAS) build`"+` Step's `+"`Access Token`"+` input.
             - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
-            - For an alternative deploy workflow checkout the [(React Native) Expo: Build using Turtle CLI recipe](https://github.com/bitrise-io/workflow-recipes/blob/main/recipes/rn-expo-turtle-build.md).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
           - yarn@%s:
               title: yarn install
               inputs:
@@ -176,20 +189,19 @@ configs:
               title: yarn test
               inputs:
               - command: test
-          - run-eas-build@%s:
-              inputs:
-              - platform: $PLATFORM
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           description: |
-            Runs tests.
+            Tests the app and runs a build on Expo Application Services (EAS).
 
             Next steps:
+            - Configure the `+"`Run Expo Application Services (EAS) build`"+` Step's `+"`Access Token`"+` input.
             - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
+            - For an alternative deploy workflow checkout the [(React Native) Expo: Build using Turtle CLI recipe](https://github.com/bitrise-io/workflow-recipes/blob/main/recipes/rn-expo-turtle-build.md).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
           - yarn@%s:
               title: yarn install
               inputs:
@@ -198,7 +210,9 @@ configs:
               title: yarn test
               inputs:
               - command: test
-          - save-npm-cache@%s: {}
+          - run-eas-build@%s:
+              inputs:
+              - platform: $PLATFORM
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
@@ -209,20 +223,20 @@ warnings_with_recommendations:
 // Bare workflow is the same as react-native with native projects
 var sampleAppsExpoBareVersions = []interface{}{
 	models.FormatVersion,
-	// deploy
+	// primary
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.YarnVersion,
 	steps.YarnVersion,
-	steps.RunEASBuildVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
-	// primary
+	// deploy
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.YarnVersion,
 	steps.YarnVersion,
-	steps.CacheSaveNPMVersion,
+	steps.RunEASBuildVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -245,18 +259,24 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           description: |
-            Tests the app and runs a build on Expo Application Services (EAS).
+            Runs tests.
 
             Next steps:
-            - Configure the `+"`Run Expo Application Services (EAS) build`"+` Step's `+"`Access Token`"+` input.
             - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
-            - For an alternative deploy workflow checkout the [(React Native) Expo: Build using Turtle CLI recipe](https://github.com/bitrise-io/workflow-recipes/blob/main/recipes/rn-expo-turtle-build.md).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
           - yarn@%s:
               title: yarn install
               inputs:
@@ -265,20 +285,19 @@ configs:
               title: yarn test
               inputs:
               - command: test
-          - run-eas-build@%s:
-              inputs:
-              - platform: $PLATFORM
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           description: |
-            Runs tests.
+            Tests the app and runs a build on Expo Application Services (EAS).
 
             Next steps:
+            - Configure the `+"`Run Expo Application Services (EAS) build`"+` Step's `+"`Access Token`"+` input.
             - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
+            - For an alternative deploy workflow checkout the [(React Native) Expo: Build using Turtle CLI recipe](https://github.com/bitrise-io/workflow-recipes/blob/main/recipes/rn-expo-turtle-build.md).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
           - yarn@%s:
               title: yarn install
               inputs:
@@ -287,7 +306,9 @@ configs:
               title: yarn test
               inputs:
               - command: test
-          - save-npm-cache@%s: {}
+          - run-eas-build@%s:
+              inputs:
+              - platform: $PLATFORM
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
@@ -126,19 +126,19 @@ var sampleAppsReactNativeSubdirVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.InstallMissingAndroidToolsVersion,
-	steps.AndroidBuildVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.CacheSaveNPMVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidBuildVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -229,23 +229,39 @@ var sampleAppsReactNativeSubdirResultYML = fmt.Sprintf(`options:
                                 config: react-native-android-ios-test-config
                               enterprise:
                                 config: react-native-android-ios-test-config
+                default_value: debug
+                value_infos:
+                  debug:
+                    recommended: true
+                    reason: every Android project has a debug build type, which needs
+                      no signing config
+                validation:
+                  pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                  hint: variant names in separate lines, like debug or freeRelease
 configs:
   react-native:
     react-native-android-ios-test-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           description: |
-            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
+            Runs tests.
 
             Next steps:
-            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
             - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
           - npm@%s:
               title: npm install
               inputs:
@@ -256,32 +272,18 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - install-missing-android-tools@%s:
-              inputs:
-              - gradlew_path: $PROJECT_LOCATION/gradlew
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - configuration: Release
-              - automatic_code_signing: api-key
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           description: |
-            Runs tests.
+            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
 
             Next steps:
+            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
             - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
           - npm@%s:
               title: npm install
               inputs:
@@ -292,7 +294,21 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - save-npm-cache@%s: {}
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - configuration: Release
+              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
@@ -305,19 +321,19 @@ var sampleAppsReactNativeIosAndAndroidVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.InstallMissingAndroidToolsVersion,
-	steps.AndroidBuildVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.CacheSaveNPMVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidBuildVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -408,23 +424,39 @@ var sampleAppsReactNativeIosAndAndroidResultYML = fmt.Sprintf(`options:
                                 config: react-native-android-ios-test-config
                               enterprise:
                                 config: react-native-android-ios-test-config
+                default_value: debug
+                value_infos:
+                  debug:
+                    recommended: true
+                    reason: every Android project has a debug build type, which needs
+                      no signing config
+                validation:
+                  pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                  hint: variant names in separate lines, like debug or freeRelease
 configs:
   react-native:
     react-native-android-ios-test-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           description: |
-            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
+            Runs tests.
 
             Next steps:
-            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
             - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
           - npm@%s:
               title: npm install
               inputs:
@@ -435,32 +467,18 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - install-missing-android-tools@%s:
-              inputs:
-              - gradlew_path: $PROJECT_LOCATION/gradlew
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - configuration: Release
-              - automatic_code_signing: api-key
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           description: |
-            Runs tests.
+            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
 
             Next steps:
+            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
             - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
           - npm@%s:
               title: npm install
               inputs:
@@ -471,7 +489,21 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - save-npm-cache@%s: {}
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - configuration: Release
+              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
@@ -484,17 +516,17 @@ var sampleAppsReactNativeIosAndAndroidNoTestVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
-	steps.InstallMissingAndroidToolsVersion,
-	steps.AndroidBuildVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
-	steps.CacheSaveNPMVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidBuildVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -585,13 +617,47 @@ var sampleAppsReactNativeIosAndAndroidNoTestResultYML = fmt.Sprintf(`options:
                                 config: react-native-android-ios-config
                               enterprise:
                                 config: react-native-android-ios-config
+                default_value: debug
+                value_infos:
+                  debug:
+                    recommended: true
+                    reason: every Android project has a debug build type, which needs
+                      no signing config
+                validation:
+                  pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                  hint: variant names in separate lines, like debug or freeRelease
 configs:
   react-native:
     react-native-android-ios-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
+        primary:
+          description: |
+            Installs dependencies.
+
+            Next steps:
+            - Add tests to your project and configure the workflow to run them.
+            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
+          - npm@%s:
+              title: npm install
+              inputs:
+              - workdir: $WORKDIR
+              - command: install
+          - save-npm-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
         deploy:
           description: |
             Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
@@ -623,24 +689,6 @@ configs:
               - configuration: Release
               - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
-        primary:
-          description: |
-            Installs dependencies.
-
-            Next steps:
-            - Add tests to your project and configure the workflow to run them.
-            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
-          steps:
-          - activate-ssh-key@%s: {}
-          - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
-          - npm@%s:
-              title: npm install
-              inputs:
-              - workdir: $WORKDIR
-              - command: install
-          - save-npm-cache@%s: {}
-          - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
 warnings_with_recommendations:
@@ -652,19 +700,19 @@ var sampleAppsReactNativeIosAndAndroidYarnVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.YarnVersion,
 	steps.YarnVersion,
-	steps.InstallMissingAndroidToolsVersion,
-	steps.AndroidBuildVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.YarnVersion,
 	steps.YarnVersion,
-	steps.CacheSaveNPMVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidBuildVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -755,23 +803,39 @@ var sampleAppsReactNativeIosAndAndroidYarnResultYML = fmt.Sprintf(`options:
                                 config: react-native-android-ios-test-yarn-config
                               enterprise:
                                 config: react-native-android-ios-test-yarn-config
+                default_value: debug
+                value_infos:
+                  debug:
+                    recommended: true
+                    reason: every Android project has a debug build type, which needs
+                      no signing config
+                validation:
+                  pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                  hint: variant names in separate lines, like debug or freeRelease
 configs:
   react-native:
     react-native-android-ios-test-yarn-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           description: |
-            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
+            Runs tests.
 
             Next steps:
-            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
             - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
           - yarn@%s:
               title: yarn install
               inputs:
@@ -782,32 +846,18 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - install-missing-android-tools@%s:
-              inputs:
-              - gradlew_path: $PROJECT_LOCATION/gradlew
-          - android-build@%s:
-              inputs:
-              - project_location: $PROJECT_LOCATION
-              - module: $MODULE
-              - variant: $VARIANT
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - configuration: Release
-              - automatic_code_signing: api-key
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           description: |
-            Runs tests.
+            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
 
             Next steps:
+            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
             - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
           - yarn@%s:
               title: yarn install
               inputs:
@@ -818,7 +868,21 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - save-npm-cache@%s: {}
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - configuration: Release
+              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
@@ -831,18 +895,18 @@ var sampleAppsReactNativeJoplinVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
-	steps.InstallMissingAndroidToolsVersion,
-	steps.AndroidBuildVersion,
-	steps.CocoapodsInstallVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
-	steps.CacheSaveNPMVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidBuildVersion,
+	steps.CocoapodsInstallVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -951,13 +1015,47 @@ var sampleAppsReactNativeJoplinResultYML = fmt.Sprintf(`options:
                                 config: react-native-android-ios-pod-config
                               enterprise:
                                 config: react-native-android-ios-pod-config
+                default_value: debug
+                value_infos:
+                  debug:
+                    recommended: true
+                    reason: every Android project has a debug build type, which needs
+                      no signing config
+                validation:
+                  pattern: ^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$
+                  hint: variant names in separate lines, like debug or freeRelease
 configs:
   react-native:
     react-native-android-ios-pod-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
+        primary:
+          description: |
+            Installs dependencies.
+
+            Next steps:
+            - Add tests to your project and configure the workflow to run them.
+            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
+          - npm@%s:
+              title: npm install
+              inputs:
+              - workdir: $WORKDIR
+              - command: install
+          - save-npm-cache@%s: {}
+          - deploy-to-bitrise-io@%s: {}
         deploy:
           description: |
             Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
@@ -992,24 +1090,6 @@ configs:
               - configuration: Release
               - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
-        primary:
-          description: |
-            Installs dependencies.
-
-            Next steps:
-            - Add tests to your project and configure the workflow to run them.
-            - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
-          steps:
-          - activate-ssh-key@%s: {}
-          - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
-          - npm@%s:
-              title: npm install
-              inputs:
-              - workdir: $WORKDIR
-              - command: install
-          - save-npm-cache@%s: {}
-          - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
 warnings_with_recommendations:
@@ -1021,17 +1101,17 @@ var sampleAppsReactNativeIosAndNoAndroidVersions = []interface{}{
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.XcodeArchiveVersion,
+	steps.CacheSaveNPMVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
-	steps.CacheRestoreNPMVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.CacheSaveNPMVersion,
+	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 }
 
@@ -1099,17 +1179,24 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: react-native
+      trigger_map:
+      - type: pull_request
+        workflow: primary
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: deploy
+        push_branch: <default-branch>
       workflows:
-        deploy:
+        primary:
           description: |
-            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
+            Runs tests.
 
             Next steps:
-            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
             - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
+          - restore-npm-cache@%s: {}
           - npm@%s:
               title: npm install
               inputs:
@@ -1120,24 +1207,18 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - xcode-archive@%s:
-              inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
-              - configuration: Release
-              - automatic_code_signing: api-key
+          - save-npm-cache@%s: {}
           - deploy-to-bitrise-io@%s: {}
-        primary:
+        deploy:
           description: |
-            Runs tests.
+            Tests, builds and deploys the app using *Deploy to bitrise.io* Step.
 
             Next steps:
+            - Set up an [Apple service with API key](https://docs.bitrise.io/en/bitrise-platform/integrations/apple-services-connection/connecting-to-an-apple-service-with-api-key.html).
             - Check out [Getting started with React Native apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-react-native-projects.html).
           steps:
           - activate-ssh-key@%s: {}
           - git-clone@%s: {}
-          - restore-npm-cache@%s: {}
           - npm@%s:
               title: npm install
               inputs:
@@ -1148,7 +1229,13 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - save-npm-cache@%s: {}
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - distribution_method: $BITRISE_DISTRIBUTION_METHOD
+              - configuration: Release
+              - automatic_code_signing: api-key
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
@@ -70,12 +70,38 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ruby
+      tools:
+        ruby: 3.3.9
       app:
         envs:
         - DB_HOST: localhost
         - DB_USERNAME: postgres
         - DB_PASSWORD: password
         - REDIS_URL: redis://localhost:6379/0
+      containers:
+        postgres:
+          type: service
+          image: postgres:18
+          ports:
+          - 5432:5432
+          envs:
+          - POSTGRES_PASSWORD: $DB_PASSWORD
+          options: --health-cmd "pg_isready" --health-interval 10s --health-timeout 5s --health-retries
+            5
+        redis:
+          type: service
+          image: redis:8
+          ports:
+          - 6379:6379
+          options: --health-cmd "redis-cli ping" --health-interval 10s --health-timeout
+            5s --health-retries 5
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -119,25 +145,6 @@ configs:
               - key: gem-{{ checksum "Gemfile.lock" }}
               - paths: vendor/bundle
           - deploy-to-bitrise-io@%s: {}
-      tools:
-        ruby: 3.3.9
-      containers:
-        postgres:
-          type: service
-          image: postgres:18
-          ports:
-          - 5432:5432
-          envs:
-          - POSTGRES_PASSWORD: $DB_PASSWORD
-          options: --health-cmd "pg_isready" --health-interval 10s --health-timeout 5s --health-retries
-            5
-        redis:
-          type: service
-          image: redis:8
-          ports:
-          - 6379:6379
-          options: --health-cmd "redis-cli ping" --health-interval 10s --health-timeout
-            5s --health-retries 5
 warnings:
   ruby: []
 warnings_with_recommendations:
@@ -171,9 +178,26 @@ configs:
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ruby
+      tools:
+        ruby: 3.3.9
       app:
         envs:
         - MONGODB_URL: mongodb://localhost:27017/sample_ruby_on_rails_minitest_sqlite_mongodb_test
+      containers:
+        mongodb:
+          type: service
+          image: mongo:8
+          ports:
+          - 27017:27017
+          options: --health-cmd "mongosh --eval 'db.runCommand({ping:1})'" --health-interval
+            10s --health-timeout 5s --health-retries 5
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -214,16 +238,6 @@ configs:
               - key: gem-{{ checksum "Gemfile.lock" }}
               - paths: vendor/bundle
           - deploy-to-bitrise-io@%s: {}
-      tools:
-        ruby: 3.3.9
-      containers:
-        mongodb:
-          type: service
-          image: mongo:8
-          ports:
-          - 27017:27017
-          options: --health-cmd "mongosh --eval 'db.runCommand({ping:1})'" --health-interval
-            10s --health-timeout 5s --health-retries 5
 warnings:
   ruby: []
 warnings_with_recommendations:
@@ -262,30 +276,78 @@ var rubyMonorepoResultVersions = []interface{}{
 	steps.ScriptVersion, // Run tests
 	steps.CacheSaveVersion,
 	steps.DeployToBitriseIoVersion,
+	// ruby-monorepo-config
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CacheRestoreVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.CacheSaveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CacheRestoreVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.CacheSaveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CacheRestoreVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.CacheSaveVersion,
+	steps.DeployToBitriseIoVersion,
 }
 
 var rubyMonorepoResultYML = fmt.Sprintf(`options:
   ruby:
-    title: Project Directory
-    summary: The directory containing the Gemfile
-    env_key: RUBY_PROJECT_DIR
+    title: Projects
+    summary: Generate the config of a single project, or the monorepo config covering
+      every project
     type: selector
     value_map:
-      sample-ruby-on-rails-minitest-sqlite-mongodb:
-        config: ruby-bundler-minitest-mongodb-config
-      sample-ruby-on-rails-rspec-mysql-redis:
-        config: ruby-bundler-rspec-mysql-redis-config
-      sample-ruby-on-rails-rspec-postgres-redis:
-        config: ruby-bundler-rspec-postgres-redis-config
+      all projects:
+        config: ruby-monorepo-config
+      single project:
+        title: Project Directory
+        summary: The directory containing the Gemfile
+        env_key: RUBY_PROJECT_DIR
+        type: selector
+        value_map:
+          sample-ruby-on-rails-minitest-sqlite-mongodb:
+            config: ruby-bundler-minitest-mongodb-config
+          sample-ruby-on-rails-rspec-mysql-redis:
+            config: ruby-bundler-rspec-mysql-redis-config
+          sample-ruby-on-rails-rspec-postgres-redis:
+            config: ruby-bundler-rspec-postgres-redis-config
 configs:
   ruby:
     ruby-bundler-minitest-mongodb-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ruby
+      tools:
+        ruby: 3.3.9
       app:
         envs:
         - MONGODB_URL: mongodb://localhost:27017/sample_ruby_on_rails_minitest_sqlite_mongodb_test
+      containers:
+        mongodb:
+          type: service
+          image: mongo:8
+          ports:
+          - 27017:27017
+          options: --health-cmd "mongosh --eval 'db.runCommand({ping:1})'" --health-interval
+            10s --health-timeout 5s --health-retries 5
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -329,26 +391,42 @@ configs:
               - key: gem-{{ checksum "Gemfile.lock" }}
               - paths: vendor/bundle
           - deploy-to-bitrise-io@%s: {}
-      tools:
-        ruby: 3.3.9
-      containers:
-        mongodb:
-          type: service
-          image: mongo:8
-          ports:
-          - 27017:27017
-          options: --health-cmd "mongosh --eval 'db.runCommand({ping:1})'" --health-interval
-            10s --health-timeout 5s --health-retries 5
     ruby-bundler-rspec-mysql-redis-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ruby
+      tools:
+        ruby: 3.3.9
       app:
         envs:
         - DB_HOST: 127.0.0.1
         - DB_USERNAME: root
         - DB_PASSWORD: password
         - REDIS_URL: redis://localhost:6379/0
+      containers:
+        mysql:
+          type: service
+          image: mysql:9
+          ports:
+          - 3306:3306
+          envs:
+          - MYSQL_ROOT_PASSWORD: $DB_PASSWORD
+          options: --health-cmd "mysqladmin ping -h 127.0.0.1 -u root --password=$$MYSQL_ROOT_PASSWORD"
+            --health-interval 10s --health-timeout 5s --health-retries 5
+        redis:
+          type: service
+          image: redis:8
+          ports:
+          - 6379:6379
+          options: --health-cmd "redis-cli ping" --health-interval 10s --health-timeout
+            5s --health-retries 5
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -404,18 +482,28 @@ configs:
               - key: gem-{{ checksum "Gemfile.lock" }}
               - paths: vendor/bundle
           - deploy-to-bitrise-io@%s: {}
+    ruby-bundler-rspec-postgres-redis-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: ruby
       tools:
         ruby: 3.3.9
+      app:
+        envs:
+        - DB_HOST: localhost
+        - DB_USERNAME: postgres
+        - DB_PASSWORD: password
+        - REDIS_URL: redis://localhost:6379/0
       containers:
-        mysql:
+        postgres:
           type: service
-          image: mysql:9
+          image: postgres:18
           ports:
-          - 3306:3306
+          - 5432:5432
           envs:
-          - MYSQL_ROOT_PASSWORD: $DB_PASSWORD
-          options: --health-cmd "mysqladmin ping -h 127.0.0.1 -u root --password=$$MYSQL_ROOT_PASSWORD"
-            --health-interval 10s --health-timeout 5s --health-retries 5
+          - POSTGRES_PASSWORD: $DB_PASSWORD
+          options: --health-cmd "pg_isready" --health-interval 10s --health-timeout 5s --health-retries
+            5
         redis:
           type: service
           image: redis:8
@@ -423,16 +511,13 @@ configs:
           - 6379:6379
           options: --health-cmd "redis-cli ping" --health-interval 10s --health-timeout
             5s --health-retries 5
-    ruby-bundler-rspec-postgres-redis-config: |
-      format_version: "%s"
-      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: ruby
-      app:
-        envs:
-        - DB_HOST: localhost
-        - DB_USERNAME: postgres
-        - DB_PASSWORD: password
-        - REDIS_URL: redis://localhost:6379/0
+      trigger_map:
+      - type: pull_request
+        workflow: run_tests
+        pull_request_source_branch: '*'
+      - type: push
+        workflow: run_tests
+        push_branch: <default-branch>
       workflows:
         run_tests:
           steps:
@@ -479,9 +564,27 @@ configs:
               - key: gem-{{ checksum "Gemfile.lock" }}
               - paths: vendor/bundle
           - deploy-to-bitrise-io@%s: {}
-      tools:
-        ruby: 3.3.9
+    ruby-monorepo-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: ruby
       containers:
+        mongodb:
+          type: service
+          image: mongo:8
+          ports:
+          - 27017:27017
+          options: --health-cmd "mongosh --eval 'db.runCommand({ping:1})'" --health-interval
+            10s --health-timeout 5s --health-retries 5
+        mysql:
+          type: service
+          image: mysql:9
+          ports:
+          - 3306:3306
+          envs:
+          - MYSQL_ROOT_PASSWORD: $DB_PASSWORD
+          options: --health-cmd "mysqladmin ping -h 127.0.0.1 -u root --password=$$MYSQL_ROOT_PASSWORD"
+            --health-interval 10s --health-timeout 5s --health-retries 5
         postgres:
           type: service
           image: postgres:18
@@ -498,6 +601,197 @@ configs:
           - 6379:6379
           options: --health-cmd "redis-cli ping" --health-interval 10s --health-timeout
             5s --health-retries 5
+      workflows:
+        _setup:
+          steps:
+          - activate-ssh-key@%s: {}
+          - git-clone@%s: {}
+        run_tests_sample-ruby-on-rails-minitest-sqlite-mongodb:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: sample-ruby-on-rails-minitest-sqlite-mongodb/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: sample-ruby-on-rails-minitest-sqlite-mongodb/**
+          before_run:
+          - _setup
+          envs:
+          - MONGODB_URL: mongodb://localhost:27017/sample_ruby_on_rails_minitest_sqlite_mongodb_test
+          steps:
+          - restore-cache@%s:
+              inputs:
+              - key: gem-{{ checksum "sample-ruby-on-rails-minitest-sqlite-mongodb/Gemfile.lock"
+                  }}
+          - script@%s:
+              title: Install dependencies
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle config set --local path vendor/bundle
+                  bundle install
+              - working_dir: sample-ruby-on-rails-minitest-sqlite-mongodb
+          - script@%s:
+              title: Database setup
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle exec rails db:create db:schema:load
+              - working_dir: sample-ruby-on-rails-minitest-sqlite-mongodb
+          - script@%s:
+              title: Run tests
+              service_containers:
+              - mongodb
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle exec rails test
+              - working_dir: sample-ruby-on-rails-minitest-sqlite-mongodb
+          - save-cache@%s:
+              inputs:
+              - key: gem-{{ checksum "sample-ruby-on-rails-minitest-sqlite-mongodb/Gemfile.lock"
+                  }}
+              - paths: sample-ruby-on-rails-minitest-sqlite-mongodb/vendor/bundle
+          - deploy-to-bitrise-io@%s: {}
+          tools:
+            ruby: 3.3.9
+        run_tests_sample-ruby-on-rails-rspec-mysql-redis:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: sample-ruby-on-rails-rspec-mysql-redis/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: sample-ruby-on-rails-rspec-mysql-redis/**
+          before_run:
+          - _setup
+          envs:
+          - DB_HOST: 127.0.0.1
+          - DB_USERNAME: root
+          - DB_PASSWORD: password
+          - REDIS_URL: redis://localhost:6379/0
+          steps:
+          - restore-cache@%s:
+              inputs:
+              - key: gem-{{ checksum "sample-ruby-on-rails-rspec-mysql-redis/Gemfile.lock"
+                  }}
+          - script@%s:
+              title: Install system dependencies
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  apt-get update
+                  apt-get install -y libmariadb-dev
+          - script@%s:
+              title: Install dependencies
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle config set --local path vendor/bundle
+                  bundle install
+              - working_dir: sample-ruby-on-rails-rspec-mysql-redis
+          - script@%s:
+              title: Database setup
+              service_containers:
+              - mysql
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle exec rails db:create db:schema:load
+              - working_dir: sample-ruby-on-rails-rspec-mysql-redis
+          - script@%s:
+              title: Run tests
+              service_containers:
+              - mysql
+              - redis
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle exec rspec
+              - working_dir: sample-ruby-on-rails-rspec-mysql-redis
+          - save-cache@%s:
+              inputs:
+              - key: gem-{{ checksum "sample-ruby-on-rails-rspec-mysql-redis/Gemfile.lock"
+                  }}
+              - paths: sample-ruby-on-rails-rspec-mysql-redis/vendor/bundle
+          - deploy-to-bitrise-io@%s: {}
+          tools:
+            ruby: 3.3.9
+        run_tests_sample-ruby-on-rails-rspec-postgres-redis:
+          triggers:
+            push:
+            - branch: <default-branch>
+              changed_files: sample-ruby-on-rails-rspec-postgres-redis/**
+            pull_request:
+            - source_branch: '*'
+              changed_files: sample-ruby-on-rails-rspec-postgres-redis/**
+          before_run:
+          - _setup
+          envs:
+          - DB_HOST: localhost
+          - DB_USERNAME: postgres
+          - DB_PASSWORD: password
+          - REDIS_URL: redis://localhost:6379/0
+          steps:
+          - restore-cache@%s:
+              inputs:
+              - key: gem-{{ checksum "sample-ruby-on-rails-rspec-postgres-redis/Gemfile.lock"
+                  }}
+          - script@%s:
+              title: Install dependencies
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle config set --local path vendor/bundle
+                  bundle install
+              - working_dir: sample-ruby-on-rails-rspec-postgres-redis
+          - script@%s:
+              title: Database setup
+              service_containers:
+              - postgres
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle exec rails db:create db:schema:load
+              - working_dir: sample-ruby-on-rails-rspec-postgres-redis
+          - script@%s:
+              title: Run tests
+              service_containers:
+              - postgres
+              - redis
+              inputs:
+              - content: |-
+                  #!/usr/bin/env bash
+                  set -euxo pipefail
+
+                  bundle exec rspec
+              - working_dir: sample-ruby-on-rails-rspec-postgres-redis
+          - save-cache@%s:
+              inputs:
+              - key: gem-{{ checksum "sample-ruby-on-rails-rspec-postgres-redis/Gemfile.lock"
+                  }}
+              - paths: sample-ruby-on-rails-rspec-postgres-redis/vendor/bundle
+          - deploy-to-bitrise-io@%s: {}
+          tools:
+            ruby: 3.3.9
 warnings:
   ruby: []
 warnings_with_recommendations:
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/bitrise-init/steps/catalog"
+)
+
+type stepCatalogOutput struct {
+	CatalogPath string `json:"catalog_path"`
+}
+
+func runStepCatalog(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("step-catalog", stderr)
+	steplibDir := flags.String("steplib", "", "local checkout of the steplib (https://github.com/bitrise-io/bitrise-steplib)")
+	outputPth := flags.String("output", "steps/catalog/catalog.yml", "path of the step catalog")
+	if exitCode, ok := parseFlags(flags, args, stdout); !ok {
+		return exitCode
+	}
+	if *steplibDir == "" {
+		return writeError(stdout, exitCodeUsage, fmt.Errorf("-steplib is required"))
+	}
+
+	stepCatalog, err := catalog.Build(*steplibDir, steps.LatestVersions)
+	if err != nil {
+		return writeError(stdout, exitCodeFailed, err)
+	}
+	content, err := stepCatalog.Marshal()
+	if err != nil {
+		return writeError(stdout, exitCodeFailed, err)
+	}
+	if err := os.WriteFile(*outputPth, content, 0644); err != nil {
+		return writeError(stdout, exitCodeFailed, fmt.Errorf("failed to write step catalog: %w", err))
+	}
+	return writeJSON(stdout, exitCodeOK, stepCatalogOutput{CatalogPath: *outputPth})
+}
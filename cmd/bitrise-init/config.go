@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanner"
+)
+
+// answerFlags collects the repeated -answer KEY=VALUE flags.
+type answerFlags scanner.Answers
+
+func (answers answerFlags) String() string {
+	return fmt.Sprint(map[string]string(answers))
+}
+
+func (answers answerFlags) Set(value string) error {
+	key, value, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("answer has to be in KEY=VALUE format")
+	}
+	answers[key] = value
+	return nil
+}
+
+type configOutput struct {
+	ConfigPath string                `json:"config_path,omitempty"`
+	Platforms  []string              `json:"platforms,omitempty"`
+	Error      string                `json:"error,omitempty"`
+	Answer     *scanner.AnswerError  `json:"answer,omitempty"`
+	Augment    *models.AugmentReport `json:"augment,omitempty"`
+}
+
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("config", stderr)
+	searchDir := flags.String("dir", ".", "directory to scan")
+	scanResultPth := flags.String("scan-result", "", "scan result file (yaml or json) to use instead of scanning -dir")
+	answersPth := flags.String("answers", "", "YAML answers file, a mapping of option env keys or titles to values")
+	cliAnswers := answerFlags{}
+	flags.Var(cliAnswers, "answer", "option value in KEY=VALUE format, overrides the answers file, can be repeated")
+	hasSSHKey := flags.Bool("ssh-key", true, "the repository is cloned with an SSH key")
+	outputPth := flags.String("output", "bitrise.yml", "path of the generated config")
+	withComments := flags.Bool("comments", false, "explain the workflows and the non-obvious steps in YAML comments, the reasons are not kept in -scan-result files, enabled by default with -augment")
+	augmentPth := flags.String("augment", "", "existing bitrise.yml to add the missing generated workflows to, its workflows are not changed")
+	if exitCode, ok := parseFlags(flags, args, stdout); !ok {
+		return exitCode
+	}
+	if *augmentPth != "" && !isFlagSet(flags, "comments") {
+		// the comments of the existing workflows and steps are kept, the added ones are explained the same way
+		*withComments = true
+	}
+
+	answers := scanner.Answers{}
+	if *answersPth != "" {
+		fileAnswers, err := scanner.ReadAnswersFile(*answersPth)
+		if err != nil {
+			return writeError(stdout, exitCodeFailed, err)
+		}
+		for key, value := range fileAnswers {
+			answers[key] = value
+		}
+	}
+	for key, value := range cliAnswers {
+		answers[key] = value
+	}
+
+	var result models.ScanResultModel
+	if *scanResultPth != "" {
+		var err error
+		if result, err = readScanResult(*scanResultPth); err != nil {
+			return writeError(stdout, exitCodeFailed, err)
+		}
+	} else {
+		result = scanner.ConfigWithContext(context.Background(), *searchDir, scanner.ConfigOptions{HasSSHKey: *hasSSHKey})
+	}
+
+	out := configOutput{Platforms: detectedPlatforms(result)}
+	if len(out.Platforms) == 0 {
+		out.Error = "No known platform detected"
+		return writeJSON(stdout, exitCodeNoPlatformDetected, out)
+	}
+
+	config, err := scanner.ResolveConfig(result, answers)
+	if err != nil {
+		out.Error = err.Error()
+		if errors.As(err, &out.Answer) {
+			return writeJSON(stdout, exitCodeInvalidAnswer, out)
+		}
+		return writeJSON(stdout, exitCodeFailed, out)
+	}
+
+	if *augmentPth == "" {
+		if err := output.WriteConfigToFile(config, *outputPth, *withComments); err != nil {
+			out.Error = fmt.Sprintf("failed to write config: %s", err)
+			return writeJSON(stdout, exitCodeFailed, out)
+		}
+	} else {
+		existing, err := os.ReadFile(*augmentPth)
+		if err != nil {
+			out.Error = err.Error()
+			return writeJSON(stdout, exitCodeFailed, out)
+		}
+
+		content, report, err := models.AugmentConfig(existing, config, *withComments)
+		if err != nil {
+			out.Error = fmt.Sprintf("failed to augment config (%s): %s", *augmentPth, err)
+			return writeJSON(stdout, exitCodeFailed, out)
+		}
+		out.Augment = &report
+
+		if err := os.WriteFile(*outputPth, content, 0644); err != nil {
+			out.Error = fmt.Sprintf("failed to write config: %s", err)
+			return writeJSON(stdout, exitCodeFailed, out)
+		}
+	}
+
+	out.ConfigPath = *outputPth
+	return writeJSON(stdout, exitCodeOK, out)
+}
+
+// readScanResult reads a scan result written by the scan command in yaml or json format.
+func readScanResult(pth string) (models.ScanResultModel, error) {
+	content, err := os.ReadFile(pth)
+	if err != nil {
+		return models.ScanResultModel{}, err
+	}
+
+	var result output.ScanResult
+	if filepath.Ext(pth) == ".json" {
+		err = json.Unmarshal(content, &result)
+	} else {
+		err = yaml.Unmarshal(content, &result)
+	}
+	if err != nil {
+		return models.ScanResultModel{}, fmt.Errorf("failed to parse scan result (%s): %w", pth, err)
+	}
+
+	scanResult, err := result.ScanResultModel()
+	if err != nil {
+		return models.ScanResultModel{}, fmt.Errorf("failed to parse scan result (%s): %w", pth, err)
+	}
+	return scanResult, nil
+}
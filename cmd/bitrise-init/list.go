@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io"
+
+	"github.com/bitrise-io/bitrise-init/scanners"
+)
+
+type scannerOutput struct {
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	Priority         int      `json:"priority"`
+	ExcludedScanners []string `json:"excluded_scanners,omitempty"`
+}
+
+type listScannersOutput struct {
+	Scanners []scannerOutput `json:"scanners"`
+}
+
+func runListScanners(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("list-scanners", stderr)
+	if exitCode, ok := parseFlags(flags, args, stdout); !ok {
+		return exitCode
+	}
+
+	out := listScannersOutput{Scanners: []scannerOutput{}}
+	for _, scannerType := range []scanners.ScannerType{scanners.ProjectScannerType, scanners.AutomationToolScannerType} {
+		for _, registration := range scanners.Registrations(scannerType) {
+			out.Scanners = append(out.Scanners, scannerOutput{
+				Name:             registration.Name,
+				Type:             string(registration.Type),
+				Priority:         registration.Priority,
+				ExcludedScanners: scanners.ExcludedScannerNames(registration.New()),
+			})
+		}
+	}
+
+	return writeJSON(stdout, exitCodeOK, out)
+}
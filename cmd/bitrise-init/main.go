@@ -0,0 +1,162 @@
+// The bitrise-init command scans a repository and generates bitrise.yml configs for the detected projects.
+//
+// Usage:
+//
+//	bitrise-init scan [-dir DIR] [-output-dir DIR] [-format yaml|json|raw]
+//	bitrise-init config [-dir DIR | -scan-result FILE] [-answers FILE] [-answer KEY=VALUE]... [-output FILE]
+//	bitrise-init manual [-output-dir DIR] [-format yaml|json|raw]
+//	bitrise-init upgrade-steps [-config FILE] [-write] [-output FILE]
+//	bitrise-init step-catalog -steplib DIR [-output FILE]
+//	bitrise-init list-scanners
+//	bitrise-init serve [-addr ADDR] [-local-root DIR] [-max-concurrent-scans N] [-max-upload-size BYTES]
+//
+// The serve command serves the scanner over HTTP, see the server package for the API.
+// The scanner plugins found in the BITRISE_INIT_PLUGIN_PATH directories are registered for every command.
+// Every other command prints a single JSON object to the standard output, logs are printed to the standard error.
+// Failed commands print the error in the JSON object's "error" field, and exit with one of these exit codes:
+//
+//	1: the command failed
+//	2: invalid command line arguments
+//	3: no known platform detected
+//	4: an answer is missing or invalid (config)
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bitrise-io/bitrise-init/scanners/plugin"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+// Exit codes
+const (
+	exitCodeOK = iota
+	exitCodeFailed
+	exitCodeUsage
+	exitCodeNoPlatformDetected
+	exitCodeInvalidAnswer
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{name: "scan", summary: "Scan a directory and write the scan result", run: runScan},
+	{name: "config", summary: "Scan a directory and generate a bitrise.yml from the answers", run: runConfig},
+	{name: "manual", summary: "Write the default configs of every scanner", run: runManual},
+	{name: "upgrade-steps", summary: "Report the outdated and deprecated steps of a bitrise.yml", run: runUpgradeSteps},
+	{name: "step-catalog", summary: "Write the step input catalog from a local steplib checkout", run: runStepCatalog},
+	{name: "list-scanners", summary: "List the available scanners", run: runListScanners},
+	{name: "serve", summary: "Serve the scanner over HTTP", run: runServe},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	log.SetOutWriter(stderr)
+
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitCodeUsage
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			if err := registerPlugins(); err != nil {
+				return writeError(stdout, exitCodeFailed, err)
+			}
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return exitCodeOK
+	}
+
+	printUsage(stderr)
+	return writeError(stdout, exitCodeUsage, fmt.Errorf("unknown command: %s", args[0]))
+}
+
+// registerPlugins registers the scanner plugins found in the BITRISE_INIT_PLUGIN_PATH directories.
+func registerPlugins() error {
+	names, err := plugin.RegisterPlugins(context.Background(), plugin.DirsFromEnv()...)
+	if err != nil {
+		return fmt.Errorf("failed to register scanner plugins: %w", err)
+	}
+	if len(names) > 0 {
+		log.TInfof("Registered scanner plugins: %v", names)
+	}
+	return nil
+}
+
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Usage: bitrise-init <command> [flags]")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// newFlagSet returns a flag set printing its errors and usage to stderr.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+// isFlagSet reports whether the flag was set on the command line.
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// parseFlags parses the command's flags, returns false and the exit code if the command should not run.
+func parseFlags(flags *flag.FlagSet, args []string, stdout io.Writer) (int, bool) {
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitCodeOK, false
+	} else if err != nil {
+		return writeError(stdout, exitCodeUsage, err), false
+	}
+
+	if flags.NArg() > 0 {
+		return writeError(stdout, exitCodeUsage, fmt.Errorf("unexpected arguments: %v", flags.Args())), false
+	}
+	return exitCodeOK, true
+}
+
+type errorOutput struct {
+	Error string `json:"error"`
+}
+
+// writeJSON prints the command's output, returns exitCode.
+func writeJSON(stdout io.Writer, exitCode int, v interface{}) int {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return exitCodeFailed
+	}
+	return exitCode
+}
+
+func writeError(stdout io.Writer, exitCode int, err error) int {
+	return writeJSON(stdout, exitCode, errorOutput{Error: err.Error()})
+}
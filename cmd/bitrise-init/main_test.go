@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanners"
+	"github.com/bitrise-io/bitrise-init/scanners/plugin"
+	"github.com/bitrise-io/bitrise-init/steps"
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+)
+
+// runCommand runs the command with the args, and decodes its JSON output into out.
+func runCommand(t *testing.T, out interface{}, args ...string) int {
+	var stdout, stderr bytes.Buffer
+	exitCode := run(args, &stdout, &stderr)
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), out), "stdout: %s\nstderr: %s", stdout.String(), stderr.String())
+	return exitCode
+}
+
+func TestRun_usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, exitCodeUsage, run(nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "Usage: bitrise-init <command> [flags]")
+
+	var out errorOutput
+	require.Equal(t, exitCodeUsage, runCommand(t, &out, "unknown"))
+	require.Equal(t, "unknown command: unknown", out.Error)
+
+	require.Equal(t, exitCodeUsage, runCommand(t, &out, "scan", "-format", "xml"))
+	require.Equal(t, "not a valid format: xml", out.Error)
+
+	require.Equal(t, exitCodeUsage, runCommand(t, &out, "config", "-answer", "no-value"))
+	require.Contains(t, out.Error, "answer has to be in KEY=VALUE format")
+}
+
+func TestRunScan(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "result")
+
+	var out scanOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "scan", "-dir", filepath.Join("testdata", "node-js"), "-output-dir", outputDir, "-format", "json"))
+	require.Equal(t, scanOutput{ResultPath: filepath.Join(outputDir, "result.json"), Platforms: []string{"node-js"}}, out)
+
+	content, err := os.ReadFile(out.ResultPath)
+	require.NoError(t, err)
+	var result output.ScanResult
+	require.NoError(t, json.Unmarshal(content, &result))
+	require.Contains(t, result.Configs["node-js"], "node-js-npm-root-test-config")
+
+	out = scanOutput{}
+	require.Equal(t, exitCodeNoPlatformDetected, runCommand(t, &out, "scan", "-dir", filepath.Join("testdata", "no-platform"), "-output-dir", outputDir))
+	require.Equal(t, filepath.Join(outputDir, "result.yml"), out.ResultPath)
+	require.Empty(t, out.Platforms)
+	require.Equal(t, "No known platform detected", out.Error)
+}
+
+func TestRunConfig(t *testing.T) {
+	configPth := filepath.Join(t.TempDir(), "bitrise.yml")
+
+	var out configOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "config", "-dir", filepath.Join("testdata", "node-js"), "-output", configPth))
+	require.Equal(t, configOutput{ConfigPath: configPth, Platforms: []string{"node-js"}}, out)
+
+	content, err := os.ReadFile(configPth)
+	require.NoError(t, err)
+	var config bitriseModels.BitriseDataModel
+	require.NoError(t, yaml.Unmarshal(content, &config))
+	require.Contains(t, config.Workflows, "run_tests")
+	require.Equal(t, []envmanModels.EnvironmentItemModel{{"NODEJS_PROJECT_DIR": "."}}, config.App.Environments)
+	require.NotContains(t, string(content), "#")
+
+	t.Run("comments", func(t *testing.T) {
+		var out configOutput
+		require.Equal(t, exitCodeOK, runCommand(t, &out, "config", "-dir", filepath.Join("testdata", "node-js"), "-output", configPth, "-comments"))
+
+		content, err := os.ReadFile(configPth)
+		require.NoError(t, err)
+		require.Contains(t, string(content), "  # Installs the dependencies, then runs the lint and test scripts of the package.json.\n  run_tests:\n")
+		require.Contains(t, string(content), "    # Restores the node_modules of a previous build to speed up this one.\n    - restore-npm-cache@")
+	})
+
+	t.Run("augment", func(t *testing.T) {
+		existingPth := filepath.Join(t.TempDir(), "bitrise.yml")
+		existing := "format_version: \"11\"\nworkflows:\n  # Deploys the web app.\n  deploy:\n    steps:\n    - script@1: {}\n"
+		require.NoError(t, os.WriteFile(existingPth, []byte(existing), 0600))
+
+		var out configOutput
+		require.Equal(t, exitCodeOK, runCommand(t, &out, "config", "-dir", filepath.Join("testdata", "node-js"), "-augment", existingPth, "-output", configPth))
+		require.Equal(t, &models.AugmentReport{
+			AddedWorkflows:  []string{"run_tests"},
+			AddedAppEnvs:    []string{"NODEJS_PROJECT_DIR"},
+			AddedTriggerMap: true,
+		}, out.Augment)
+
+		content, err := os.ReadFile(configPth)
+		require.NoError(t, err)
+		require.Contains(t, string(content), "format_version: \"11\"\n")
+		require.Contains(t, string(content), "  # Deploys the web app.\n  deploy:\n")
+		require.Contains(t, string(content), "  # Installs the dependencies, then runs the lint and test scripts of the package.json.\n  run_tests:\n")
+
+		// -comments=false is kept
+		require.Equal(t, exitCodeOK, runCommand(t, &out, "config", "-dir", filepath.Join("testdata", "node-js"), "-augment", existingPth, "-output", configPth, "-comments=false"))
+		content, err = os.ReadFile(configPth)
+		require.NoError(t, err)
+		require.Contains(t, string(content), "  # Deploys the web app.\n  deploy:\n")
+		require.Contains(t, string(content), "  run_tests:\n")
+		require.NotContains(t, string(content), "# Installs the dependencies")
+	})
+
+	t.Run("invalid answer", func(t *testing.T) {
+		answersPth := filepath.Join(t.TempDir(), "answers.yml")
+		require.NoError(t, os.WriteFile(answersPth, []byte("NODEJS_PROJECT_DIR: web\nPackage Manager: npm\n"), 0600))
+
+		var out configOutput
+		exitCode := runCommand(t, &out, "config", "-dir", filepath.Join("testdata", "node-js"), "-answers", answersPth, "-output", configPth)
+		require.Equal(t, exitCodeInvalidAnswer, exitCode)
+		require.Equal(t, `invalid answer for "Project Directory" (NODEJS_PROJECT_DIR): web, available values: .`, out.Error)
+		require.Equal(t, "web", out.Answer.Value)
+
+		// -answer overrides the answers file
+		out = configOutput{}
+		exitCode = runCommand(t, &out, "config", "-dir", filepath.Join("testdata", "node-js"), "-answers", answersPth, "-answer", "NODEJS_PROJECT_DIR=.", "-output", configPth)
+		require.Equal(t, exitCodeOK, exitCode)
+	})
+
+	t.Run("no platform detected", func(t *testing.T) {
+		var out configOutput
+		require.Equal(t, exitCodeNoPlatformDetected, runCommand(t, &out, "config", "-dir", filepath.Join("testdata", "no-platform"), "-output", configPth))
+		require.Equal(t, "No known platform detected", out.Error)
+	})
+}
+
+func TestRunConfig_scanResult(t *testing.T) {
+	outputDir := t.TempDir()
+	var scanOut scanOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &scanOut, "scan", "-dir", filepath.Join("testdata", "node-js"), "-output-dir", outputDir))
+
+	configPth := filepath.Join(outputDir, "bitrise.yml")
+	var out configOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "config", "-scan-result", scanOut.ResultPath, "-output", configPth))
+	require.FileExists(t, configPth)
+}
+
+func TestRunUpgradeSteps(t *testing.T) {
+	configPth := filepath.Join(t.TempDir(), "bitrise.yml")
+	require.NoError(t, os.WriteFile(configPth, []byte("workflows:\n  primary:\n    steps:\n    - git-clone@6: {}\n    - cache-push@2: {}\n"), 0600))
+
+	var out upgradeStepsOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "upgrade-steps", "-config", configPth))
+	require.Empty(t, out.ConfigPath)
+	require.Len(t, out.Steps, 2)
+	require.Equal(t, "cache-push@2", out.Steps[1].Step)
+	require.Equal(t, steps.CacheSaveID, out.Steps[1].ReplacedBy)
+
+	upgradedPth := filepath.Join(t.TempDir(), "upgraded.yml")
+	out = upgradeStepsOutput{}
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "upgrade-steps", "-config", configPth, "-write", "-output", upgradedPth))
+	require.Equal(t, upgradedPth, out.ConfigPath)
+	content, err := os.ReadFile(upgradedPth)
+	require.NoError(t, err)
+	require.Equal(t, "workflows:\n  primary:\n    steps:\n    - git-clone@"+steps.GitCloneVersion+": {}\n    - cache-push@2: {}\n", string(content))
+}
+
+func TestRunStepCatalog(t *testing.T) {
+	steplibDir := t.TempDir()
+	for id, version := range steps.LatestVersions {
+		stepDir := filepath.Join(steplibDir, "steps", id, version+".0.0")
+		require.NoError(t, os.MkdirAll(stepDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(stepDir, "step.yml"), []byte("inputs:\n- workdir: \"\"\n"), 0600))
+	}
+
+	catalogPth := filepath.Join(t.TempDir(), "catalog.yml")
+	var out stepCatalogOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "step-catalog", "-steplib", steplibDir, "-output", catalogPth))
+	require.Equal(t, catalogPth, out.CatalogPath)
+	content, err := os.ReadFile(catalogPth)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "  git-clone:\n    version: "+steps.GitCloneVersion+".0.0\n    inputs:\n    - key: workdir\n")
+
+	var errOut errorOutput
+	require.Equal(t, exitCodeUsage, runCommand(t, &errOut, "step-catalog"))
+	require.Equal(t, "-steplib is required", errOut.Error)
+}
+
+func TestRunManual(t *testing.T) {
+	outputDir := t.TempDir()
+
+	var out manualOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "manual", "-output-dir", outputDir))
+	require.Equal(t, filepath.Join(outputDir, "result.yml"), out.ResultPath)
+	require.FileExists(t, out.ResultPath)
+}
+
+func TestRunListScanners(t *testing.T) {
+	var out listScannersOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "list-scanners"))
+
+	var names []string
+	for _, scanner := range out.Scanners {
+		names = append(names, scanner.Name)
+	}
+	require.Equal(t, "kotlin-multiplatform", names[0])
+	require.Contains(t, names, "node-js")
+	require.Equal(t, scannerOutput{Name: "fastlane", Type: "automation_tool", Priority: 100}, out.Scanners[len(out.Scanners)-1])
+}
+
+func TestRunListScanners_plugin(t *testing.T) {
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$(cat)" in
+*default_options*) echo '{"protocol_version": 1, "options": {"title": "Title", "type": "selector", "value_map": {"value": {"config": "shell-config"}}}}' ;;
+*) echo '{"protocol_version": 1, "name": "shell-plugin", "priority": 5}' ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, plugin.ExecutablePrefix+"shell"), []byte(script), 0755))
+	t.Setenv(plugin.PathEnvKey, dir)
+	defer func() {
+		require.NoError(t, scanners.Unregister("shell-plugin"))
+	}()
+
+	var out listScannersOutput
+	require.Equal(t, exitCodeOK, runCommand(t, &out, "list-scanners"))
+	require.Contains(t, out.Scanners, scannerOutput{Name: "shell-plugin", Type: "project", Priority: 5})
+}
+
+func TestRunServe_invalidAddress(t *testing.T) {
+	var out errorOutput
+	require.Equal(t, exitCodeFailed, runCommand(t, &out, "serve", "-addr", "invalid-address"))
+	require.Contains(t, out.Error, "server failed")
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanner"
+)
+
+type manualOutput struct {
+	ResultPath string `json:"result_path"`
+}
+
+func runManual(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("manual", stderr)
+	outputDir := flags.String("output-dir", "_manual_config", "directory of the manual config result")
+	formatFlag := flags.String("format", output.YAMLFormat.String(), "format of the result: yaml, json or raw")
+	if exitCode, ok := parseFlags(flags, args, stdout); !ok {
+		return exitCode
+	}
+
+	format, err := output.ParseFormat(*formatFlag)
+	if err != nil {
+		return writeError(stdout, exitCodeUsage, err)
+	}
+
+	result, err := scanner.ManualConfig()
+	if err != nil {
+		return writeError(stdout, exitCodeFailed, err)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return writeError(stdout, exitCodeFailed, fmt.Errorf("failed to create output dir: %w", err))
+	}
+	pth, err := output.WriteToFile(result, format, filepath.Join(*outputDir, "result"))
+	if err != nil {
+		return writeError(stdout, exitCodeFailed, fmt.Errorf("failed to write result: %w", err))
+	}
+
+	return writeJSON(stdout, exitCodeOK, manualOutput{ResultPath: pth})
+}
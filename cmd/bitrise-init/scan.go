@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanner"
+)
+
+type scanOutput struct {
+	ResultPath string   `json:"result_path,omitempty"`
+	Platforms  []string `json:"platforms"`
+	Error      string   `json:"error,omitempty"`
+}
+
+func runScan(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("scan", stderr)
+	searchDir := flags.String("dir", ".", "directory to scan")
+	outputDir := flags.String("output-dir", "_scan_result", "directory of the scan result")
+	formatFlag := flags.String("format", output.YAMLFormat.String(), "format of the scan result: yaml, json or raw")
+	if exitCode, ok := parseFlags(flags, args, stdout); !ok {
+		return exitCode
+	}
+
+	format, err := output.ParseFormat(*formatFlag)
+	if err != nil {
+		return writeError(stdout, exitCodeUsage, err)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return writeError(stdout, exitCodeFailed, fmt.Errorf("failed to create output dir: %w", err))
+	}
+
+	result, err := scanner.GenerateAndWriteResults(*searchDir, *outputDir, format)
+	out := scanOutput{
+		ResultPath: resultPath(*outputDir, format),
+		Platforms:  detectedPlatforms(result),
+	}
+	if err == nil {
+		return writeJSON(stdout, exitCodeOK, out)
+	}
+
+	out.Error = err.Error()
+	// the scan result is written even if no platform is detected
+	if _, statErr := os.Stat(out.ResultPath); statErr == nil && len(out.Platforms) == 0 {
+		return writeJSON(stdout, exitCodeNoPlatformDetected, out)
+	}
+	out.ResultPath = ""
+	return writeJSON(stdout, exitCodeFailed, out)
+}
+
+// resultPath returns the path of the scan result written by output.WriteToFile.
+func resultPath(outputDir string, format output.Format) string {
+	ext := map[output.Format]string{
+		output.RawFormat:  ".txt",
+		output.JSONFormat: ".json",
+		output.YAMLFormat: ".yml",
+	}[format]
+	return filepath.Join(outputDir, "result"+ext)
+}
+
+func detectedPlatforms(result models.ScanResultModel) []string {
+	platforms := []string{}
+	for platform := range result.ScannerToOptionRoot {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bitrise-io/bitrise-init/server"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+func runServe(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("serve", stderr)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	localRoot := flags.String("local-root", "", "enables scanning the server-local directories inside this directory")
+	maxConcurrentScans := flags.Int("max-concurrent-scans", server.DefaultMaxConcurrentScans, "maximum number of scans running at the same time")
+	maxUploadSize := flags.Int64("max-upload-size", server.DefaultMaxUploadSize, "maximum size of the uploaded archives in bytes")
+	scannerTimeout := flags.Duration("scanner-timeout", server.DefaultScannerTimeout, "maximum time a single scanner can spend on a scan")
+	if exitCode, ok := parseFlags(flags, args, stdout); !ok {
+		return exitCode
+	}
+
+	handler, err := server.New(server.Options{
+		LocalRoot:          *localRoot,
+		MaxConcurrentScans: *maxConcurrentScans,
+		MaxUploadSize:      *maxUploadSize,
+		ScannerTimeout:     *scannerTimeout,
+	})
+	if err != nil {
+		return writeError(stdout, exitCodeUsage, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	httpServer := &http.Server{Addr: *addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Warnf("Failed to shut down the server: %s", err)
+		}
+	}()
+
+	log.Infof("Listening on %s", *addr)
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return writeError(stdout, exitCodeFailed, fmt.Errorf("server failed: %w", err))
+	}
+	return exitCodeOK
+}
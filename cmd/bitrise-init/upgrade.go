@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bitrise-io/bitrise-init/steps"
+)
+
+type upgradeStepsOutput struct {
+	ConfigPath string             `json:"config_path,omitempty"`
+	Steps      []steps.StepAdvice `json:"steps,omitempty"`
+}
+
+func runUpgradeSteps(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("upgrade-steps", stderr)
+	configPth := flags.String("config", "bitrise.yml", "bitrise.yml to check")
+	write := flags.Bool("write", false, "upgrade the outdated step versions in the config")
+	outputPth := flags.String("output", "", "path of the upgraded config, defaults to -config")
+	if exitCode, ok := parseFlags(flags, args, stdout); !ok {
+		return exitCode
+	}
+
+	content, err := os.ReadFile(*configPth)
+	if err != nil {
+		return writeError(stdout, exitCodeFailed, err)
+	}
+
+	if !*write {
+		advices, err := steps.AdviseStepUpgrades(content)
+		if err != nil {
+			return writeError(stdout, exitCodeFailed, err)
+		}
+		return writeJSON(stdout, exitCodeOK, upgradeStepsOutput{Steps: advices})
+	}
+
+	upgraded, advices, err := steps.UpgradeSteps(content)
+	if err != nil {
+		return writeError(stdout, exitCodeFailed, err)
+	}
+	if *outputPth == "" {
+		*outputPth = *configPth
+	}
+	if err := os.WriteFile(*outputPth, upgraded, 0644); err != nil {
+		return writeError(stdout, exitCodeFailed, fmt.Errorf("failed to write config: %w", err))
+	}
+	return writeJSON(stdout, exitCodeOK, upgradeStepsOutput{ConfigPath: *outputPth, Steps: advices})
+}
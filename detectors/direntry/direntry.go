@@ -1,10 +1,12 @@
 package direntry
 
 import (
-	"os"
+	"io/fs"
 	"path/filepath"
 	"slices"
 	"strings"
+
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 )
 
 var ignoreDirs = []string{".git", ".github", ".gradle", ".idea", "build", ".kotlin", ".fleet", "CordovaLib", "node_modules"}
@@ -16,14 +18,28 @@ type DirEntry struct {
 	IsDir   bool
 	parent  *DirEntry
 	entries []DirEntry
+	// index is used to read the entry's contents, it can be nil
+	index *fileindex.Index
 }
 
 // WalkDir walks the directory tree starting from rootDir and returns a DirEntry representing the root directory.
+// The AbsPath of the returned entries are absolute even if rootDir is relative.
 func WalkDir(rootDir string, depth uint) (*DirEntry, error) {
+	return WalkDirWithIndex(nil, rootDir, depth)
+}
+
+// WalkDirWithIndex is like WalkDir, but reads the directory tree from the given file index (if rootDir is indexed),
+// instead of walking the file system again.
+func WalkDirWithIndex(index *fileindex.Index, rootDir string, depth uint) (*DirEntry, error) {
 	if depth == 0 {
 		return nil, nil
 	}
 
+	rootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
 	parent := DirEntry{
 		AbsPath: rootDir,
 		RelPath: "./",
@@ -31,9 +47,10 @@ func WalkDir(rootDir string, depth uint) (*DirEntry, error) {
 		IsDir:   true,
 		parent:  nil,
 		entries: nil,
+		index:   index,
 	}
 
-	if err := recursiveWalkDir(rootDir, &parent, 0, depth); err != nil {
+	if err := recursiveWalkDir(index, rootDir, &parent, 0, depth); err != nil {
 		return nil, err
 	}
 
@@ -45,6 +62,21 @@ func (e DirEntry) Parent() *DirEntry {
 	return e.parent
 }
 
+// Index returns the file index the entry was walked from, it can be nil.
+func (e DirEntry) Index() *fileindex.Index {
+	return e.index
+}
+
+// Open opens the file of the entry for reading, from the file index the entry was walked from.
+func (e DirEntry) Open() (fs.File, error) {
+	return e.index.Open(e.AbsPath)
+}
+
+// ReadFile reads the file of the entry, from the file index the entry was walked from.
+func (e DirEntry) ReadFile() ([]byte, error) {
+	return e.index.ReadFile(e.AbsPath)
+}
+
 // FindFirstEntryByName returns the first entry (shortest file path) with the specified name and directory status.
 func (e DirEntry) FindFirstEntryByName(name string, isDir bool) *DirEntry {
 	return recursiveFindFirstEntryByName([]DirEntry{e}, name, isDir)
@@ -150,34 +182,35 @@ func recursiveFindAllEntriesByName(dirEntries []DirEntry, matchingDirEntries []D
 	return recursiveFindAllEntriesByName(nextDirEntries, matchingDirEntries, name, isDir)
 }
 
-func recursiveWalkDir(rootDir string, parent *DirEntry, currentDepth, maxDepth uint) error {
+func recursiveWalkDir(index *fileindex.Index, rootDir string, parent *DirEntry, currentDepth, maxDepth uint) error {
 	if currentDepth >= maxDepth {
 		return nil
 	}
 
-	entries, err := os.ReadDir(parent.AbsPath)
+	entries, err := index.ReadDir(parent.AbsPath)
 	if err != nil {
 		return err
 	}
 
 	parent.entries = make([]DirEntry, 0, len(entries))
 	for _, entry := range entries {
-		if slices.Contains(ignoreDirs, entry.Name()) {
+		if slices.Contains(ignoreDirs, entry.Name) {
 			continue
 		}
 
-		entryAbsPath := filepath.Join(parent.AbsPath, entry.Name())
+		entryAbsPath := filepath.Join(parent.AbsPath, entry.Name)
 		dirEntry := DirEntry{
 			AbsPath: entryAbsPath,
 			RelPath: "./" + filepath.Join("./", strings.TrimPrefix(entryAbsPath, rootDir)),
-			Name:    entry.Name(),
-			IsDir:   entry.IsDir(),
+			Name:    entry.Name,
+			IsDir:   entry.IsDir,
 			parent:  parent,
 			entries: nil,
+			index:   index,
 		}
 
 		if dirEntry.IsDir {
-			if err := recursiveWalkDir(rootDir, &dirEntry, currentDepth+1, maxDepth); err != nil {
+			if err := recursiveWalkDir(index, rootDir, &dirEntry, currentDepth+1, maxDepth); err != nil {
 				return err
 			}
 		}
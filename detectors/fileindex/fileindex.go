@@ -0,0 +1,352 @@
+package fileindex
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+// DefaultIgnoredDirs are the directory names not indexed by default.
+var DefaultIgnoredDirs = []string{".git"}
+
+// Options configures which part of the directory tree gets indexed.
+type Options struct {
+	// MaxDepth limits the depth of the indexed entries, relative to the root dir (the root dir's children are on depth 1).
+	// No limit is applied if it is 0.
+	// The contents of the directories on MaxDepth are not indexed, the queries of these directories fall back to walking them
+	// (see TruncatedDirs).
+	MaxDepth int
+	// IgnoredDirs are directory names, which are not indexed (neither the directory nor its contents).
+	// DefaultIgnoredDirs is used if it is nil.
+	IgnoredDirs []string
+	// IgnoredPaths are slash separated path patterns (see path.Match), relative to the root dir, which are not indexed.
+	// A pattern matching a directory excludes the directory's contents too.
+	IgnoredPaths []string
+}
+
+// Entry is a file or directory in the index.
+type Entry struct {
+	AbsPath string
+	// RelPath is relative to the index root dir.
+	RelPath string
+	Name    string
+	IsDir   bool
+	// Depth is the number of path components in RelPath.
+	Depth int
+}
+
+// Index is a snapshot of a directory tree, built with a single walk and shared by the scanners,
+// so that the scanners do not need to walk the same directory tree again and again.
+//
+// The query methods can be called on a nil Index, in this case they fall back to walking the file system.
+type Index struct {
+	fsys   fs.FS
+	onDisk bool
+
+	rootDir string
+	// entries in lexical walk order, the root dir is not included
+	entries []Entry
+	// sortedPaths contains the absolute path of the root dir and of the entries, sorted by components
+	sortedPaths   []string
+	dirToChildren map[string][]Entry
+	// truncatedDirs are the indexed directories with contents deeper than Options.MaxDepth, in walk order
+	truncatedDirs []string
+}
+
+// New walks rootDir and builds its index.
+func New(rootDir string, opts Options) (*Index, error) {
+	absRootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := newIndex(os.DirFS(absRootDir), absRootDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	index.onDisk = true
+	return index, nil
+}
+
+// NewFS walks fsys and builds its index, to scan a directory tree without checking it out to the disk
+// (like an archive or a git commit).
+// The entries of fsys are indexed as if fsys was mounted at rootDir, the file system is not accessed at rootDir:
+// every file access of the index (including the fall back for not indexed paths under rootDir) reads fsys.
+func NewFS(fsys fs.FS, rootDir string, opts Options) (*Index, error) {
+	absRootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return newIndex(fsys, absRootDir, opts)
+}
+
+func newIndex(fsys fs.FS, absRootDir string, opts Options) (*Index, error) {
+	ignoredDirs := opts.IgnoredDirs
+	if ignoredDirs == nil {
+		ignoredDirs = DefaultIgnoredDirs
+	}
+	for _, pattern := range opts.IgnoredPaths {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid ignored path pattern (%s): %w", pattern, err)
+		}
+	}
+
+	index := Index{
+		fsys:          fsys,
+		rootDir:       absRootDir,
+		dirToChildren: map[string][]Entry{absRootDir: nil},
+	}
+
+	if err := fs.WalkDir(fsys, ".", func(fsPth string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if fsPth == "." {
+			return nil
+		}
+
+		if d.IsDir() && slices.Contains(ignoredDirs, d.Name()) {
+			return filepath.SkipDir
+		}
+
+		if isIgnoredPath(opts.IgnoredPaths, fsPth) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath := filepath.FromSlash(fsPth)
+		depth := strings.Count(relPath, string(filepath.Separator)) + 1
+		if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+			// the parent's children are not indexed, so that its queries fall back to walking it
+			parentDir := filepath.Join(absRootDir, filepath.Dir(relPath))
+			if _, ok := index.dirToChildren[parentDir]; ok {
+				delete(index.dirToChildren, parentDir)
+				index.truncatedDirs = append(index.truncatedDirs, parentDir)
+			}
+			// skips the directory, or the rest of the parent dir in case of a file
+			return filepath.SkipDir
+		}
+
+		pth := filepath.Join(absRootDir, relPath)
+		entry := Entry{
+			AbsPath: pth,
+			RelPath: relPath,
+			Name:    d.Name(),
+			IsDir:   d.IsDir(),
+			Depth:   depth,
+		}
+
+		index.entries = append(index.entries, entry)
+		parentDir := filepath.Dir(pth)
+		index.dirToChildren[parentDir] = append(index.dirToChildren[parentDir], entry)
+		if entry.IsDir {
+			index.dirToChildren[pth] = nil
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to index %s: %w", absRootDir, err)
+	}
+
+	index.sortedPaths = make([]string, 0, len(index.entries)+1)
+	index.sortedPaths = append(index.sortedPaths, absRootDir)
+	for _, entry := range index.entries {
+		index.sortedPaths = append(index.sortedPaths, entry.AbsPath)
+	}
+	sortPathsByComponents(index.sortedPaths)
+
+	return &index, nil
+}
+
+func isIgnoredPath(patterns []string, fsPth string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, fsPth); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// OnDisk returns true if the indexed directory tree is on the disk at RootDir (the index is not created by NewFS),
+// in this case external tools (like ruby scripts) can also work with the indexed files.
+// A nil Index is considered as on disk, as its queries fall back to the file system.
+func (index *Index) OnDisk() bool {
+	return index == nil || index.onDisk
+}
+
+// RootDir returns the absolute path of the indexed directory.
+func (index *Index) RootDir() string {
+	if index == nil {
+		return ""
+	}
+	return index.rootDir
+}
+
+// Entries returns every indexed entry in lexical walk order (as visited by filepath.WalkDir).
+func (index *Index) Entries() []Entry {
+	if index == nil {
+		return nil
+	}
+	return index.entries
+}
+
+// TruncatedDirs returns the indexed directories with contents deeper than Options.MaxDepth.
+// Their contents are not indexed, the queries of these directories (and of their parents) walk the file system instead.
+func (index *Index) TruncatedDirs() []string {
+	if index == nil {
+		return nil
+	}
+	return index.truncatedDirs
+}
+
+// Contains returns true if dir is the root dir or an indexed directory, with all of its contents indexed.
+func (index *Index) Contains(dir string) bool {
+	if index == nil {
+		return false
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+
+	_, ok := index.dirToChildren[absDir]
+	return ok
+}
+
+// ReadDir returns the indexed children of dir sorted by name, like os.ReadDir.
+// It falls back to reading the file system if dir is not indexed.
+func (index *Index) ReadDir(dir string) ([]Entry, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if index.Contains(absDir) {
+		return index.dirToChildren[absDir], nil
+	}
+
+	var dirEntries []fs.DirEntry
+	if fsPth, ok := index.fsPath(absDir); ok && !index.OnDisk() {
+		dirEntries, err = fs.ReadDir(index.fsys, fsPth)
+	} else {
+		dirEntries, err = os.ReadDir(absDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, dirEntry := range dirEntries {
+		entries = append(entries, Entry{
+			AbsPath: filepath.Join(absDir, dirEntry.Name()),
+			RelPath: dirEntry.Name(),
+			Name:    dirEntry.Name(),
+			IsDir:   dirEntry.IsDir(),
+			Depth:   1,
+		})
+	}
+	return entries, nil
+}
+
+// ListPathInDirSortedByComponents is the indexed variant of pathutil.ListPathInDirSortedByComponents:
+// it returns dir and every indexed path in it, sorted by the number of path components.
+// It falls back to walking the file system (like pathutil.ListPathInDirSortedByComponents) if dir is not indexed,
+// or some of its contents are not indexed because of Options.MaxDepth.
+func (index *Index) ListPathInDirSortedByComponents(dir string, relPath bool) ([]string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return []string{}, err
+	}
+
+	if !index.Contains(absDir) || index.isTruncated(absDir) {
+		if fsPth, ok := index.fsPath(absDir); ok && !index.OnDisk() {
+			return listFSPathSortedByComponents(index.fsys, fsPth, absDir, relPath)
+		}
+		return pathutil.ListPathInDirSortedByComponents(absDir, relPath)
+	}
+
+	var paths []string
+	for _, pth := range index.sortedPaths {
+		if pth != absDir && !strings.HasPrefix(pth, absDir+string(filepath.Separator)) {
+			continue
+		}
+
+		if relPath {
+			rel, err := filepath.Rel(absDir, pth)
+			if err != nil {
+				return []string{}, err
+			}
+			pth = rel
+		}
+
+		paths = append(paths, pth)
+	}
+
+	return paths, nil
+}
+
+// isTruncated returns true if dir has contents not indexed because of Options.MaxDepth.
+func (index *Index) isTruncated(dir string) bool {
+	for _, truncatedDir := range index.truncatedDirs {
+		if truncatedDir == dir || strings.HasPrefix(truncatedDir, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func listFSPathSortedByComponents(fsys fs.FS, fsDir, absDir string, relPath bool) ([]string, error) {
+	var paths []string
+	if err := fs.WalkDir(fsys, fsDir, func(fsPth string, _ fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		rel, err := filepath.Rel(filepath.FromSlash(fsDir), filepath.FromSlash(fsPth))
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.Join(absDir, rel))
+		return nil
+	}); err != nil {
+		return []string{}, err
+	}
+
+	sortPathsByComponents(paths)
+
+	if relPath {
+		for i, pth := range paths {
+			rel, err := filepath.Rel(absDir, pth)
+			if err != nil {
+				return []string{}, err
+			}
+			paths[i] = rel
+		}
+	}
+	return paths, nil
+}
+
+// sortPathsByComponents sorts absolute paths like pathutil.SortPathsByComponents,
+// but keeps the walk order of the paths with the same number of components and base name.
+func sortPathsByComponents(paths []string) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		d1 := strings.Count(paths[i], string(filepath.Separator))
+		d2 := strings.Count(paths[j], string(filepath.Separator))
+		if d1 != d2 {
+			return d1 < d2
+		}
+		return filepath.Base(paths[i]) < filepath.Base(paths[j])
+	})
+}
@@ -0,0 +1,346 @@
+package fileindex
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestTree(t testing.TB, dir string, files []string) {
+	for _, file := range files {
+		pth := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, os.WriteFile(pth, []byte{}, 0644))
+	}
+}
+
+var testTreeFiles = []string{
+	"package.json",
+	"Gemfile",
+	".git/HEAD",
+	"android/build.gradle",
+	"android/app/build.gradle",
+	"ios/Podfile",
+	"ios/App.xcodeproj/project.pbxproj",
+	"ios/App/AppDelegate.swift",
+	"node_modules/lib/package.json",
+}
+
+func TestIndex_ListPathInDirSortedByComponents(t *testing.T) {
+	dir := t.TempDir()
+	createTestTree(t, dir, testTreeFiles)
+
+	index, err := New(dir, Options{IgnoredDirs: []string{}})
+	require.NoError(t, err)
+
+	for _, searchDir := range []string{dir, filepath.Join(dir, "ios"), filepath.Join(dir, "android")} {
+		for _, relPath := range []bool{true, false} {
+			want, err := pathutil.ListPathInDirSortedByComponents(searchDir, relPath)
+			require.NoError(t, err)
+
+			got, err := index.ListPathInDirSortedByComponents(searchDir, relPath)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		}
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	dir := t.TempDir()
+	createTestTree(t, dir, testTreeFiles)
+
+	tests := []struct {
+		name string
+		opts Options
+		// sorted by the number of components, then by base name
+		want []string
+	}{
+		{
+			name: "default options ignore .git",
+			opts: Options{},
+			want: []string{
+				".",
+				"Gemfile",
+				"android",
+				"ios",
+				"node_modules",
+				"package.json",
+				"ios/App",
+				"ios/App.xcodeproj",
+				"ios/Podfile",
+				"android/app",
+				"android/build.gradle",
+				"node_modules/lib",
+				"ios/App/AppDelegate.swift",
+				"android/app/build.gradle",
+				"node_modules/lib/package.json",
+				"ios/App.xcodeproj/project.pbxproj",
+			},
+		},
+		{
+			name: "ignored dirs",
+			opts: Options{IgnoredDirs: []string{"node_modules", "android"}},
+			want: []string{
+				".",
+				".git",
+				"Gemfile",
+				"ios",
+				"package.json",
+				"ios/App",
+				"ios/App.xcodeproj",
+				".git/HEAD",
+				"ios/Podfile",
+				"ios/App/AppDelegate.swift",
+				"ios/App.xcodeproj/project.pbxproj",
+			},
+		},
+		{
+			name: "ignored paths",
+			opts: Options{IgnoredPaths: []string{"ios/App*", "node_modules"}},
+			want: []string{
+				".",
+				"Gemfile",
+				"android",
+				"ios",
+				"package.json",
+				"ios/Podfile",
+				"android/app",
+				"android/build.gradle",
+				"android/app/build.gradle",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := New(dir, tt.opts)
+			require.NoError(t, err)
+
+			got, err := index.ListPathInDirSortedByComponents(dir, true)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNew_MaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	createTestTree(t, dir, testTreeFiles)
+
+	index, err := New(dir, Options{MaxDepth: 2, IgnoredDirs: []string{"node_modules", "android"}})
+	require.NoError(t, err)
+
+	var relPaths []string
+	for _, entry := range index.Entries() {
+		relPaths = append(relPaths, filepath.ToSlash(entry.RelPath))
+	}
+	require.Equal(t, []string{".git", ".git/HEAD", "Gemfile", "ios", "ios/App", "ios/App.xcodeproj", "ios/Podfile", "package.json"}, relPaths)
+	require.Equal(t, []string{filepath.Join(dir, "ios", "App"), filepath.Join(dir, "ios", "App.xcodeproj")}, index.TruncatedDirs())
+
+	// the directories with not indexed contents are walked
+	require.True(t, index.Contains(filepath.Join(dir, "ios")))
+	require.False(t, index.Contains(filepath.Join(dir, "ios", "App")))
+	entries, err := index.ReadDir(filepath.Join(dir, "ios", "App"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "AppDelegate.swift", entries[0].Name)
+
+	want, err := pathutil.ListPathInDirSortedByComponents(filepath.Join(dir, "ios"), true)
+	require.NoError(t, err)
+	got, err := index.ListPathInDirSortedByComponents(filepath.Join(dir, "ios"), true)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	// the fully indexed directories are not walked
+	got, err = index.ListPathInDirSortedByComponents(filepath.Join(dir, ".git"), true)
+	require.NoError(t, err)
+	require.Equal(t, []string{".", "HEAD"}, got)
+}
+
+func TestIndex_ReadDir(t *testing.T) {
+	dir := t.TempDir()
+	createTestTree(t, dir, testTreeFiles)
+
+	index, err := New(dir, Options{})
+	require.NoError(t, err)
+
+	entries, err := index.ReadDir(filepath.Join(dir, "ios"))
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	require.Equal(t, []string{"App", "App.xcodeproj", "Podfile"}, names)
+	require.True(t, entries[0].IsDir)
+	require.Equal(t, filepath.Join("ios", "App"), entries[0].RelPath)
+	require.Equal(t, 2, entries[0].Depth)
+}
+
+func TestIndex_nilAndNotIndexedFallback(t *testing.T) {
+	dir := t.TempDir()
+	createTestTree(t, dir, testTreeFiles)
+
+	want, err := pathutil.ListPathInDirSortedByComponents(dir, false)
+	require.NoError(t, err)
+
+	var nilIndex *Index
+	got, err := nilIndex.ListPathInDirSortedByComponents(dir, false)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Nil(t, nilIndex.Entries())
+
+	// .git is not indexed by default, so it is listed from the file system
+	index, err := New(dir, Options{})
+	require.NoError(t, err)
+	require.False(t, index.Contains(filepath.Join(dir, ".git")))
+
+	entries, err := index.ReadDir(filepath.Join(dir, ".git"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "HEAD", entries[0].Name)
+}
+
+func newTestMapFS(files []string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for _, file := range files {
+		fsys[file] = &fstest.MapFile{Data: []byte(file)}
+	}
+	return fsys
+}
+
+func TestNewFS(t *testing.T) {
+	dir := t.TempDir()
+	createTestTree(t, dir, testTreeFiles)
+	diskIndex, err := New(dir, Options{})
+	require.NoError(t, err)
+
+	const rootDir = "/virtual"
+	index, err := NewFS(newTestMapFS(testTreeFiles), rootDir, Options{})
+	require.NoError(t, err)
+	require.False(t, index.OnDisk())
+	require.True(t, diskIndex.OnDisk())
+
+	for _, relPath := range []bool{true, false} {
+		want, err := diskIndex.ListPathInDirSortedByComponents(dir, relPath)
+		require.NoError(t, err)
+		got, err := index.ListPathInDirSortedByComponents(rootDir, relPath)
+		require.NoError(t, err)
+
+		for i := range want {
+			want[i] = strings.Replace(want[i], dir, rootDir, 1)
+		}
+		require.Equal(t, want, got)
+	}
+
+	// .git is not indexed, but it is read from the fs.FS instead of the disk
+	entries, err := index.ReadDir(filepath.Join(rootDir, ".git"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "HEAD", entries[0].Name)
+}
+
+func TestIndex_readFilesFS(t *testing.T) {
+	const rootDir = "/virtual"
+	index, err := NewFS(newTestMapFS(testTreeFiles), rootDir, Options{})
+	require.NoError(t, err)
+
+	content, err := index.ReadStringFromFile(filepath.Join(rootDir, "ios", "Podfile"))
+	require.NoError(t, err)
+	require.Equal(t, "ios/Podfile", content)
+
+	require.True(t, index.FileExists(filepath.Join(rootDir, "Gemfile")))
+	require.False(t, index.FileExists(filepath.Join(rootDir, "ios")))
+	exists, err := index.IsDirExists(filepath.Join(rootDir, "ios"))
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = index.IsPathExists(filepath.Join(rootDir, "missing"))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = index.ReadFile(filepath.Join(rootDir, "missing"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestIndex_Materialize(t *testing.T) {
+	index, err := NewFS(newTestMapFS(testTreeFiles), "/virtual", Options{})
+	require.NoError(t, err)
+
+	dir, cleanup, err := index.Materialize(func(entry Entry) bool {
+		return strings.HasPrefix(entry.RelPath, "ios"+string(filepath.Separator))
+	})
+	require.NoError(t, err)
+
+	got, err := pathutil.ListPathInDirSortedByComponents(dir, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{".", "ios", "ios/App", "ios/App.xcodeproj", "ios/Podfile", "ios/App/AppDelegate.swift", "ios/App.xcodeproj/project.pbxproj"}, got)
+
+	content, err := os.ReadFile(filepath.Join(dir, "ios", "Podfile"))
+	require.NoError(t, err)
+	require.Equal(t, "ios/Podfile", string(content))
+
+	cleanup()
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+
+	// indexes on the disk are not copied
+	diskDir := t.TempDir()
+	diskIndex, err := New(diskDir, Options{})
+	require.NoError(t, err)
+	dir, cleanup, err = diskIndex.Materialize(func(Entry) bool { return true })
+	require.NoError(t, err)
+	defer cleanup()
+	require.Equal(t, diskIndex.RootDir(), dir)
+}
+
+// benchmarkScannerCount is the number of scanners listing the search dir during a scan.
+const benchmarkScannerCount = 13
+
+func createBenchmarkTree(b *testing.B) string {
+	dir := b.TempDir()
+	var files []string
+	for i := 0; i < 20; i++ {
+		for _, file := range testTreeFiles {
+			files = append(files, filepath.Join("module"+string(rune('a'+i)), file))
+		}
+	}
+	createTestTree(b, dir, files)
+	return dir
+}
+
+// BenchmarkListPath_pathutilPerScanner is the baseline of BenchmarkListPath_sharedIndex: every scanner walking the search dir itself,
+// as before the shared index. Compare them with: go test -run ^$ -bench ListPath -benchmem ./detectors/fileindex
+func BenchmarkListPath_pathutilPerScanner(b *testing.B) {
+	dir := createBenchmarkTree(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchmarkScannerCount; j++ {
+			if _, err := pathutil.ListPathInDirSortedByComponents(dir, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkListPath_sharedIndex(b *testing.B) {
+	dir := createBenchmarkTree(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		index, err := New(dir, Options{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < benchmarkScannerCount; j++ {
+			if _, err := index.ListPathInDirSortedByComponents(dir, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
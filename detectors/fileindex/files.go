@@ -0,0 +1,167 @@
+package fileindex
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+)
+
+// fsPath converts an absolute path under the index root dir to a path in the index's fs.FS.
+func (index *Index) fsPath(pth string) (string, bool) {
+	if index == nil {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(index.rootDir, pth)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// readsFS returns the fs.FS path of pth, if it needs to be read from the index's fs.FS instead of the disk.
+func (index *Index) readsFS(pth string) (string, bool) {
+	if index.OnDisk() {
+		return "", false
+	}
+
+	absPth, err := filepath.Abs(pth)
+	if err != nil {
+		return "", false
+	}
+	return index.fsPath(absPth)
+}
+
+// Open opens the file at pth (an absolute path, like the indexed AbsPaths) for reading, like os.Open.
+func (index *Index) Open(pth string) (fs.File, error) {
+	if fsPth, ok := index.readsFS(pth); ok {
+		return index.fsys.Open(fsPth)
+	}
+	return os.Open(pth)
+}
+
+// ReadFile reads the file at pth, like os.ReadFile.
+func (index *Index) ReadFile(pth string) ([]byte, error) {
+	if fsPth, ok := index.readsFS(pth); ok {
+		return fs.ReadFile(index.fsys, fsPth)
+	}
+	return os.ReadFile(pth)
+}
+
+// ReadStringFromFile reads the file at pth as a string, like fileutil.ReadStringFromFile.
+func (index *Index) ReadStringFromFile(pth string) (string, error) {
+	content, err := index.ReadFile(pth)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Stat returns the FileInfo of the file at pth, like os.Stat.
+func (index *Index) Stat(pth string) (fs.FileInfo, error) {
+	if fsPth, ok := index.readsFS(pth); ok {
+		return fs.Stat(index.fsys, fsPth)
+	}
+	return os.Stat(pth)
+}
+
+// IsPathExists is like pathutil.IsPathExists, but checks the indexed file system.
+func (index *Index) IsPathExists(pth string) (bool, error) {
+	if pth == "" {
+		return false, errors.New("no path provided")
+	}
+
+	if _, err := index.Stat(pth); errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// IsDirExists is like pathutil.IsDirExists, but checks the indexed file system.
+func (index *Index) IsDirExists(pth string) (bool, error) {
+	if pth == "" {
+		return false, errors.New("no path provided")
+	}
+
+	info, err := index.Stat(pth)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return info.IsDir(), nil
+}
+
+// FileExists reports whether pth exists and is not a directory, like utility.FileExists.
+func (index *Index) FileExists(pth string) bool {
+	info, err := index.Stat(pth)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
+}
+
+// Materialize writes the indexed files matching the filter to a temporary directory, keeping their relative paths,
+// so that they can be processed by tools not able to read the index (like libraries opening files by path or external tools).
+// Returns the directory corresponding to RootDir and a function removing the temporary directory.
+// If the index is on the disk, RootDir is returned as is and nothing is copied.
+func (index *Index) Materialize(filter func(entry Entry) bool) (string, func(), error) {
+	if index.OnDisk() {
+		return index.RootDir(), func() {}, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "bitrise-init-fs")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.TWarnf("Failed to remove %s: %s", tmpDir, err)
+		}
+	}
+
+	for _, entry := range index.entries {
+		if entry.IsDir || !filter(entry) {
+			continue
+		}
+
+		if err := index.copyEntry(entry, filepath.Join(tmpDir, entry.RelPath)); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("failed to materialize %s: %w", entry.RelPath, err)
+		}
+	}
+
+	return tmpDir, cleanup, nil
+}
+
+func (index *Index) copyEntry(entry Entry, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	src, err := index.fsys.Open(filepath.ToSlash(entry.RelPath))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dstFile, src); err != nil {
+		_ = dstFile.Close()
+		return err
+	}
+	return dstFile.Close()
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+const (
+	originHeadPath = ".git/refs/remotes/origin/HEAD"
+	headPath       = ".git/HEAD"
+
+	symbolicRefPrefix = "ref: "
+)
+
+// DefaultBranch returns the default branch of the repository at the root of fsys, read from its .git directory.
+// The branch of the origin remote's HEAD is preferred (set by git clone), then the checked out branch.
+// Returns an empty string if fsys is not a git repository, or the branch is unknown (for example HEAD is detached).
+func DefaultBranch(fsys fs.FS) (string, error) {
+	branch, err := readSymbolicRef(fsys, originHeadPath, "refs/remotes/origin/")
+	if err != nil || branch != "" {
+		return branch, err
+	}
+	return readSymbolicRef(fsys, headPath, "refs/heads/")
+}
+
+// readSymbolicRef reads the ref the symbolic ref file points to, and returns it without the prefix.
+func readSymbolicRef(fsys fs.FS, pth, prefix string) (string, error) {
+	content, err := fs.ReadFile(fsys, pth)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(content)), symbolicRefPrefix)
+	if !ok {
+		return "", nil
+	}
+	branch, ok := strings.CutPrefix(ref, prefix)
+	if !ok {
+		return "", nil
+	}
+	return branch, nil
+}
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "origin HEAD",
+			files: map[string]string{originHeadPath: "ref: refs/remotes/origin/develop\n", headPath: "ref: refs/heads/feature\n"},
+			want:  "develop",
+		},
+		{
+			name:  "checked out branch",
+			files: map[string]string{headPath: "ref: refs/heads/release/1.0\n"},
+			want:  "release/1.0",
+		},
+		{
+			name:  "detached HEAD",
+			files: map[string]string{headPath: "9fceb02d0ae598e95dc970b74767f19372d61af8\n"},
+			want:  "",
+		},
+		{
+			name:  "not a git repository",
+			files: map[string]string{"package.json": "{}"},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for pth, content := range tt.files {
+				fsys[pth] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			got, err := DefaultBranch(fsys)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,7 +3,6 @@ package gradle
 import (
 	"fmt"
 	"io"
-	"os"
 	"sort"
 	"strings"
 
@@ -31,7 +30,7 @@ type SubProject struct {
 }
 
 func (proj SubProject) DetectAnyDependenciesInBuildScript(dependencies []string) (bool, error) {
-	return detectAnyDependencies(proj.BuildScriptFileEntry.AbsPath, dependencies)
+	return detectAnyDependencies(proj.BuildScriptFileEntry, dependencies)
 }
 
 type Project struct {
@@ -111,7 +110,7 @@ func (proj Project) GetPluginAliasFromVersionCatalog(pluginID string) (string, e
 		return "", nil
 	}
 
-	file, err := os.Open(proj.VersionCatalogFileEntry.AbsPath)
+	file, err := proj.VersionCatalogFileEntry.Open()
 	if err != nil {
 		return "", err
 	}
@@ -156,12 +155,12 @@ func (proj Project) detectAnyDependenciesInVersionCatalogFile(dependencies []str
 	if proj.VersionCatalogFileEntry == nil {
 		return false, nil
 	}
-	return detectAnyDependencies(proj.VersionCatalogFileEntry.AbsPath, dependencies)
+	return detectAnyDependencies(*proj.VersionCatalogFileEntry, dependencies)
 }
 
 func (proj Project) detectAnyDependenciesInIncludedProjectBuildScripts(dependencies []string) (bool, error) {
 	for _, includedProject := range proj.IncludedProjects {
-		detected, err := detectAnyDependencies(includedProject.BuildScriptFileEntry.AbsPath, dependencies)
+		detected, err := detectAnyDependencies(includedProject.BuildScriptFileEntry, dependencies)
 		if err != nil {
 			return false, err
 		}
@@ -174,7 +173,7 @@ func (proj Project) detectAnyDependenciesInIncludedProjectBuildScripts(dependenc
 
 func (proj Project) detectAnyDependenciesInBuildScripts(dependencies []string) (bool, error) {
 	for _, BuildScriptFileEntry := range proj.AllBuildScriptFileEntries {
-		detected, err := detectAnyDependencies(BuildScriptFileEntry.AbsPath, dependencies)
+		detected, err := detectAnyDependencies(BuildScriptFileEntry, dependencies)
 		if err != nil {
 			return false, err
 		}
@@ -185,14 +184,14 @@ func (proj Project) detectAnyDependenciesInBuildScripts(dependencies []string) (
 	return false, nil
 }
 
-func detectAnyDependencies(pth string, dependencies []string) (bool, error) {
-	file, err := os.Open(pth)
+func detectAnyDependencies(buildScriptFileEntry direntry.DirEntry, dependencies []string) (bool, error) {
+	file, err := buildScriptFileEntry.Open()
 	if err != nil {
 		return false, err
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			log.TWarnf("Unable to close file %s: %s", pth, err)
+			log.TWarnf("Unable to close file %s: %s", buildScriptFileEntry.AbsPath, err)
 		}
 	}()
 
@@ -319,7 +318,7 @@ func detectIncludedProjects(projectRootEntry gradleProjectRootEntry) (*includedP
 }
 
 func detectProjectIncludes(settingGradleFile direntry.DirEntry) ([]string, error) {
-	file, err := os.Open(settingGradleFile.AbsPath)
+	file, err := settingGradleFile.Open()
 	if err != nil {
 		return nil, err
 	}
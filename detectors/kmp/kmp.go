@@ -2,14 +2,13 @@ package kmp
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/bitrise-io/bitrise-init/detectors/gradle"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/scanners/android"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 type Project struct {
@@ -18,8 +17,8 @@ type Project struct {
 	AndroidAppDetectResult *android.DetectResult
 }
 
-func ScanProject(gradleProject gradle.Project) (*Project, error) {
-	log.TInfof("Searching for Kotlin Multiplatform dependencies...")
+func ScanProject(logger *logger.Logger, gradleProject gradle.Project) (*Project, error) {
+	logger.TInfof("Searching for Kotlin Multiplatform dependencies...")
 	kotlinMultiplatformDetected, err := gradleProject.DetectAnyDependencies([]string{
 		"org.jetbrains.kotlin.multiplatform",
 		`kotlin("multiplatform")`,
@@ -28,20 +27,20 @@ func ScanProject(gradleProject gradle.Project) (*Project, error) {
 		return nil, err
 	}
 
-	log.TDonef("Kotlin Multiplatform dependencies found: %v", kotlinMultiplatformDetected)
+	logger.TDonef("Kotlin Multiplatform dependencies found: %v", kotlinMultiplatformDetected)
 	if !kotlinMultiplatformDetected {
 		return nil, nil
 	}
 
-	log.TInfof("Scanning Kotlin Multiplatform targets...")
-	iosAppDetectResult, err := scanIOSAppProject(gradleProject)
+	logger.TInfof("Scanning Kotlin Multiplatform targets...")
+	iosAppDetectResult, err := scanIOSAppProject(logger, gradleProject)
 	if err != nil {
-		log.TWarnf("Failed to scan iOS project: %s", err)
+		logger.TWarnf("Failed to scan iOS project: %s", err)
 	}
 
-	androidAppDetectResult, err := scanAndroidAppProject(gradleProject)
+	androidAppDetectResult, err := scanAndroidAppProject(logger, gradleProject)
 	if err != nil {
-		log.TWarnf("Failed to scan Android project: %s", err)
+		logger.TWarnf("Failed to scan Android project: %s", err)
 	}
 
 	return &Project{
@@ -51,13 +50,15 @@ func ScanProject(gradleProject gradle.Project) (*Project, error) {
 	}, nil
 }
 
-func scanIOSAppProject(gradleProject gradle.Project) (*ios.DetectResult, error) {
+func scanIOSAppProject(logger *logger.Logger, gradleProject gradle.Project) (*ios.DetectResult, error) {
 	xcodeProjectFile := gradleProject.RootDirEntry.FindFirstFileEntryByExtension(".xcodeproj")
 	if xcodeProjectFile == nil {
 		return nil, nil
 	}
 
 	iosScanner := ios.NewScanner()
+	iosScanner.SetFileIndex(xcodeProjectFile.Index())
+	iosScanner.SetLogger(logger)
 	detected, err := iosScanner.DetectPlatform(filepath.Dir(xcodeProjectFile.AbsPath))
 	if err != nil {
 		return nil, err
@@ -66,7 +67,7 @@ func scanIOSAppProject(gradleProject gradle.Project) (*ios.DetectResult, error)
 	if detected && len(iosScanner.DetectResult.Projects) > 0 {
 		result := iosScanner.DetectResult
 		if len(result.Projects) > 1 {
-			log.TWarnf("%d iOS projects found in the Gradle project, using the first one: %s", len(result.Projects), result.Projects[0].RelPath)
+			logger.TWarnf("%d iOS projects found in the Gradle project, using the first one: %s", len(result.Projects), result.Projects[0].RelPath)
 		}
 
 		// Keep the first project only and update the iOS project path to be relative to the root of the Gradle project
@@ -88,7 +89,7 @@ func scanIOSAppProject(gradleProject gradle.Project) (*ios.DetectResult, error)
 	return nil, nil
 }
 
-func scanAndroidAppProject(gradleProject gradle.Project) (*android.DetectResult, error) {
+func scanAndroidAppProject(logger *logger.Logger, gradleProject gradle.Project) (*android.DetectResult, error) {
 	androidApplicationPluginAlias, err := gradleProject.GetPluginAliasFromVersionCatalog(`com.android.application`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Android application plugin ID: %w", err)
@@ -118,7 +119,7 @@ func scanAndroidAppProject(gradleProject gradle.Project) (*android.DetectResult,
 			isWearApp := false
 			if len(manifestFiles) > 0 {
 				for _, manifestFile := range manifestFiles {
-					manifestContent, err := os.ReadFile(manifestFile.AbsPath)
+					manifestContent, err := manifestFile.ReadFile()
 					if err != nil {
 						return nil, fmt.Errorf("failed to read AndroidManifest.xml file: %w", err)
 					}
@@ -141,7 +142,7 @@ func scanAndroidAppProject(gradleProject gradle.Project) (*android.DetectResult,
 		androidAppProject := &androidAppProjects[0]
 		androidAppProjectDir := filepath.Dir(androidAppProject.BuildScriptFileEntry.RelPath)
 		if len(androidAppProjects) > 1 {
-			log.TWarnf("%d Android targets found in the Gradle project, using the first one: %s", len(androidAppProjects), androidAppProjectDir)
+			logger.TWarnf("%d Android targets found in the Gradle project, using the first one: %s", len(androidAppProjects), androidAppProjectDir)
 		}
 
 		return &android.DetectResult{
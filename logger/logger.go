@@ -0,0 +1,175 @@
+// Package logger provides a logger writing the messages in the format of the go-utils log package to its own writer,
+// so that the logs of the scanners running in parallel can be collected separately.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+const timestampLayout = "15:04:05"
+
+// Logger writes the log messages to its writer, it is safe for concurrent use.
+// A nil Logger prints with the go-utils log package.
+type Logger struct {
+	mu    sync.Mutex
+	out   io.Writer
+	debug bool
+}
+
+// New returns a logger writing to out.
+func New(out io.Writer) *Logger {
+	return &Logger{out: out}
+}
+
+// EnableDebugLog enables the Debugf and TDebugf messages.
+func (l *Logger) EnableDebugLog(enable bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debug = enable
+}
+
+func (l *Logger) printf(colorFunc colorstring.ColorfFunc, withTime, debug bool, format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if debug && !l.debug {
+		return
+	}
+
+	message := colorFunc(format, v...)
+	if withTime {
+		message = fmt.Sprintf("[%s] %s", time.Now().Format(timestampLayout), message)
+	}
+	if _, err := fmt.Fprintln(l.out, message); err != nil {
+		fmt.Printf("failed to print message: %s, error: %s\n", message, err)
+	}
+}
+
+// Println writes an empty line.
+func (l *Logger) Println() {
+	l.Printf("")
+}
+
+// Successf ...
+func (l *Logger) Successf(format string, v ...interface{}) {
+	if l == nil {
+		log.Successf(format, v...)
+		return
+	}
+	l.printf(colorstring.Greenf, false, false, format, v...)
+}
+
+// Donef ...
+func (l *Logger) Donef(format string, v ...interface{}) {
+	l.Successf(format, v...)
+}
+
+// Infof ...
+func (l *Logger) Infof(format string, v ...interface{}) {
+	if l == nil {
+		log.Infof(format, v...)
+		return
+	}
+	l.printf(colorstring.Bluef, false, false, format, v...)
+}
+
+// Printf ...
+func (l *Logger) Printf(format string, v ...interface{}) {
+	if l == nil {
+		log.Printf(format, v...)
+		return
+	}
+	l.printf(colorstring.NoColorf, false, false, format, v...)
+}
+
+// Debugf ...
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	if l == nil {
+		log.Debugf(format, v...)
+		return
+	}
+	l.printf(colorstring.Magentaf, false, true, format, v...)
+}
+
+// Warnf ...
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	if l == nil {
+		log.Warnf(format, v...)
+		return
+	}
+	l.printf(colorstring.Yellowf, false, false, format, v...)
+}
+
+// Errorf ...
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	if l == nil {
+		log.Errorf(format, v...)
+		return
+	}
+	l.printf(colorstring.Redf, false, false, format, v...)
+}
+
+// TSuccessf ...
+func (l *Logger) TSuccessf(format string, v ...interface{}) {
+	if l == nil {
+		log.TSuccessf(format, v...)
+		return
+	}
+	l.printf(colorstring.Greenf, true, false, format, v...)
+}
+
+// TDonef ...
+func (l *Logger) TDonef(format string, v ...interface{}) {
+	l.TSuccessf(format, v...)
+}
+
+// TInfof ...
+func (l *Logger) TInfof(format string, v ...interface{}) {
+	if l == nil {
+		log.TInfof(format, v...)
+		return
+	}
+	l.printf(colorstring.Bluef, true, false, format, v...)
+}
+
+// TPrintf ...
+func (l *Logger) TPrintf(format string, v ...interface{}) {
+	if l == nil {
+		log.TPrintf(format, v...)
+		return
+	}
+	l.printf(colorstring.NoColorf, true, false, format, v...)
+}
+
+// TDebugf ...
+func (l *Logger) TDebugf(format string, v ...interface{}) {
+	if l == nil {
+		log.TDebugf(format, v...)
+		return
+	}
+	l.printf(colorstring.Magentaf, true, true, format, v...)
+}
+
+// TWarnf ...
+func (l *Logger) TWarnf(format string, v ...interface{}) {
+	if l == nil {
+		log.TWarnf(format, v...)
+		return
+	}
+	l.printf(colorstring.Yellowf, true, false, format, v...)
+}
+
+// TErrorf ...
+func (l *Logger) TErrorf(format string, v ...interface{}) {
+	if l == nil {
+		log.TErrorf(format, v...)
+		return
+	}
+	l.printf(colorstring.Redf, true, false, format, v...)
+}
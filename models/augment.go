@@ -0,0 +1,393 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+)
+
+// Kinds of the AugmentConflict items.
+const (
+	AugmentConflictWorkflow   = "workflow"
+	AugmentConflictPipeline   = "pipeline"
+	AugmentConflictAppEnv     = "app_env"
+	AugmentConflictTool       = "tool"
+	AugmentConflictContainer  = "container"
+	AugmentConflictTriggerMap = "trigger_map"
+)
+
+// generatedWorkflowSuffix is appended to the ID of a generated workflow, which is added next to the existing workflow with the same ID.
+const generatedWorkflowSuffix = "_generated"
+
+// AugmentReport lists what AugmentConfig added to the existing config, and the generated parts conflicting with it.
+type AugmentReport struct {
+	AddedWorkflows  []string          `json:"added_workflows,omitempty" yaml:"added_workflows,omitempty"`
+	AddedPipelines  []string          `json:"added_pipelines,omitempty" yaml:"added_pipelines,omitempty"`
+	AddedAppEnvs    []string          `json:"added_app_envs,omitempty" yaml:"added_app_envs,omitempty"`
+	AddedTools      []string          `json:"added_tools,omitempty" yaml:"added_tools,omitempty"`
+	AddedContainers []string          `json:"added_containers,omitempty" yaml:"added_containers,omitempty"`
+	AddedTriggerMap bool              `json:"added_trigger_map,omitempty" yaml:"added_trigger_map,omitempty"`
+	Conflicts       []AugmentConflict `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+}
+
+// AugmentConflict is a generated part of the config, which is defined differently in the existing config.
+// The existing definition is always kept.
+type AugmentConflict struct {
+	Kind    string `json:"kind" yaml:"kind"`
+	ID      string `json:"id" yaml:"id"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// AugmentConfig adds the parts of the generated config missing from the existing (usually hand-written) bitrise.yml:
+// the workflows, pipelines, app envs, tools and containers not defined in the existing config,
+// and the trigger map if the existing config has no triggers.
+//
+// The existing workflows are never changed. If an existing workflow differs from the generated workflow with the same ID,
+// and it misses the cache or test steps of the generated workflow, the generated workflow is added with the _generated suffix,
+// so that the steps can be compared and moved by hand. The triggers and pipelines of the generated workflows are added with the new IDs.
+// The existing parts differing from the generated ones are reported as conflicts.
+//
+// The added parts are inserted into the existing bitrise.yml at the end of their sections,
+// the existing content (comments, anchors, unknown keys and formatting) is kept as it is.
+// If withComments is set, the added workflows and steps are explained in YAML comments.
+func AugmentConfig(existing []byte, generated Config, withComments bool) ([]byte, AugmentReport, error) {
+	var existingModel bitriseModels.BitriseDataModel
+	if err := yaml.Unmarshal(existing, &existingModel); err != nil {
+		return nil, AugmentReport{}, fmt.Errorf("failed to parse the existing config: %w", err)
+	}
+
+	augmenter := configAugmenter{
+		config:            existingModel,
+		renamedWorkflows:  map[string]string{},
+		conflictWorkflows: map[string]bool{},
+		conflictPipelines: map[string]bool{},
+	}
+	augmenter.config.Workflows = maps.Clone(existingModel.Workflows)
+	augmenter.config.Pipelines = maps.Clone(existingModel.Pipelines)
+	augmenter.config.Containers = maps.Clone(existingModel.Containers)
+	augmenter.config.Tools = maps.Clone(existingModel.Tools)
+	augmenter.config.App.Environments = slices.Clone(existingModel.App.Environments)
+
+	if augmenter.config.ProjectType == "" {
+		augmenter.added.ProjectType = generated.ProjectType
+	}
+
+	augmenter.addAppEnvs(generated.App.Environments)
+	augmenter.addTools(generated.Tools)
+	augmenter.addContainers(generated.Containers)
+	augmenter.addWorkflows(generated)
+	augmenter.addPipelines(generated)
+	augmenter.addTriggerMap(generated.TriggerMap)
+
+	added := NewConfig(augmenter.added)
+	added.setLayout(augmenter.layout)
+	content, err := insertConfig(existing, added, withComments)
+	if err != nil {
+		return nil, AugmentReport{}, err
+	}
+	return content, augmenter.report, nil
+}
+
+// configAugmenter collects the parts of the generated config to add to the existing config.
+// config is the existing config with the parts added so far, added and layout hold the added parts only.
+type configAugmenter struct {
+	config bitriseModels.BitriseDataModel
+	added  bitriseModels.BitriseDataModel
+	layout configLayout
+	report AugmentReport
+
+	// renamedWorkflows maps the generated workflow IDs to the IDs they are added with (<id>_generated)
+	renamedWorkflows map[string]string
+	// conflictWorkflows and conflictPipelines are the generated workflows and pipelines not added due to a conflict,
+	// the references to them would run the differing existing ones
+	conflictWorkflows map[string]bool
+	conflictPipelines map[string]bool
+}
+
+func (augmenter *configAugmenter) conflict(kind, id, format string, args ...interface{}) {
+	augmenter.report.Conflicts = append(augmenter.report.Conflicts, AugmentConflict{Kind: kind, ID: id, Message: fmt.Sprintf(format, args...)})
+}
+
+func (augmenter *configAugmenter) addAppEnvs(envs []envmanModels.EnvironmentItemModel) {
+	for _, env := range envs {
+		key := envKey(env)
+		idx := slices.IndexFunc(augmenter.config.App.Environments, func(existing envmanModels.EnvironmentItemModel) bool {
+			return envKey(existing) == key
+		})
+		if idx == -1 {
+			augmenter.config.App.Environments = append(augmenter.config.App.Environments, env)
+			augmenter.added.App.Environments = append(augmenter.added.App.Environments, env)
+			augmenter.report.AddedAppEnvs = append(augmenter.report.AddedAppEnvs, key)
+		} else if !sameYAML(augmenter.config.App.Environments[idx], env) {
+			augmenter.conflict(AugmentConflictAppEnv, key, "app env %s is set to %v, the generated config sets it to %v", key, augmenter.config.App.Environments[idx][key], env[key])
+		}
+	}
+}
+
+func (augmenter *configAugmenter) addTools(tools bitriseModels.ToolsModel) {
+	ids := slices.Sorted(maps.Keys(tools))
+	for _, id := range ids {
+		version := tools[id]
+		existing, ok := augmenter.config.Tools[id]
+		if !ok {
+			if augmenter.added.Tools == nil {
+				augmenter.added.Tools = bitriseModels.ToolsModel{}
+			}
+			augmenter.added.Tools[id] = version
+			augmenter.report.AddedTools = append(augmenter.report.AddedTools, string(id))
+		} else if existing != version {
+			augmenter.conflict(AugmentConflictTool, string(id), "tool %s is set to %s, the generated config sets it to %s", id, existing, version)
+		}
+	}
+}
+
+func (augmenter *configAugmenter) addContainers(containers map[string]bitriseModels.Container) {
+	for _, name := range sortedKeys(containers) {
+		existing, ok := augmenter.config.Containers[name]
+		if !ok {
+			if augmenter.added.Containers == nil {
+				augmenter.added.Containers = map[string]bitriseModels.Container{}
+			}
+			augmenter.added.Containers[name] = containers[name]
+			augmenter.report.AddedContainers = append(augmenter.report.AddedContainers, name)
+		} else if !sameYAML(existing, containers[name]) {
+			augmenter.conflict(AugmentConflictContainer, name, "container %s differs from the generated container", name)
+		}
+	}
+}
+
+func (augmenter *configAugmenter) addWorkflows(generated Config) {
+	generatedLayout := generated.layout
+	for _, workflowID := range orderedKeys(generated.Workflows, generatedLayout.Workflows) {
+		workflow := generated.Workflows[workflowID]
+		existing, ok := augmenter.config.Workflows[workflowID]
+		if ok && sameYAML(existing, workflow) {
+			continue
+		}
+
+		addedID := workflowID
+		if ok {
+			missingSteps := missingCacheAndTestSteps(existing, workflow)
+			addedID = workflowID + generatedWorkflowSuffix
+			if _, taken := augmenter.config.Workflows[addedID]; len(missingSteps) == 0 || taken {
+				augmenter.conflict(AugmentConflictWorkflow, workflowID, "workflow %s differs from the generated workflow", workflowID)
+				augmenter.conflictWorkflows[workflowID] = true
+				continue
+			}
+			augmenter.conflict(AugmentConflictWorkflow, workflowID, "workflow %s misses the %s steps of the generated workflow, added as %s",
+				workflowID, strings.Join(missingSteps, ", "), addedID)
+			augmenter.renamedWorkflows[workflowID] = addedID
+		}
+
+		if augmenter.config.Workflows == nil {
+			augmenter.config.Workflows = map[string]bitriseModels.WorkflowModel{}
+		}
+		if augmenter.added.Workflows == nil {
+			augmenter.added.Workflows = map[string]bitriseModels.WorkflowModel{}
+		}
+		augmenter.config.Workflows[addedID] = workflow
+		augmenter.added.Workflows[addedID] = workflow
+		augmenter.layout.Workflows = append(augmenter.layout.Workflows, addedID)
+		augmenter.report.AddedWorkflows = append(augmenter.report.AddedWorkflows, addedID)
+		if reason := generatedLayout.WorkflowReasons[workflowID]; reason != "" {
+			setWorkflowReason(&augmenter.layout, addedID, reason)
+		}
+		for idx, reason := range generatedLayout.StepReasons[workflowID] {
+			setStepReason(&augmenter.layout, addedID, idx, reason)
+		}
+	}
+}
+
+func (augmenter *configAugmenter) addPipelines(generated Config) {
+	generatedLayout := generated.layout
+	for _, pipelineID := range orderedKeys(generated.Pipelines, generatedLayout.Pipelines) {
+		pipeline := generated.Pipelines[pipelineID]
+		if existing, ok := augmenter.config.Pipelines[pipelineID]; ok {
+			if !sameYAML(existing, pipeline) {
+				augmenter.conflict(AugmentConflictPipeline, pipelineID, "pipeline %s differs from the generated pipeline", pipelineID)
+				augmenter.conflictPipelines[pipelineID] = true
+			}
+			continue
+		}
+
+		pipeline, workflowOrder, conflictWorkflow := augmenter.renamePipelineWorkflows(pipeline, generatedLayout.PipelineWorkflows[pipelineID])
+		if conflictWorkflow != "" {
+			augmenter.conflict(AugmentConflictPipeline, pipelineID, "pipeline %s is not added, it runs workflow %s, which differs from the generated workflow", pipelineID, conflictWorkflow)
+			augmenter.conflictPipelines[pipelineID] = true
+			continue
+		}
+
+		if augmenter.config.Pipelines == nil {
+			augmenter.config.Pipelines = map[string]bitriseModels.PipelineModel{}
+		}
+		if augmenter.added.Pipelines == nil {
+			augmenter.added.Pipelines = map[string]bitriseModels.PipelineModel{}
+		}
+		augmenter.config.Pipelines[pipelineID] = pipeline
+		augmenter.added.Pipelines[pipelineID] = pipeline
+		augmenter.layout.Pipelines = append(augmenter.layout.Pipelines, pipelineID)
+		if augmenter.layout.PipelineWorkflows == nil {
+			augmenter.layout.PipelineWorkflows = map[string][]string{}
+		}
+		augmenter.layout.PipelineWorkflows[pipelineID] = orderedKeys(pipeline.Workflows, workflowOrder)
+		augmenter.report.AddedPipelines = append(augmenter.report.AddedPipelines, pipelineID)
+	}
+}
+
+// renamePipelineWorkflows points the workflows of the generated pipeline to the IDs the generated workflows are added with.
+// It returns the ID of a workflow of the pipeline, which is not added due to a conflict, if any.
+func (augmenter *configAugmenter) renamePipelineWorkflows(pipeline bitriseModels.PipelineModel, order []string) (bitriseModels.PipelineModel, []string, string) {
+	rename := func(id string) string {
+		if renamed, ok := augmenter.renamedWorkflows[id]; ok {
+			return renamed
+		}
+		return id
+	}
+
+	workflows := bitriseModels.GraphPipelineWorkflowListItemModel{}
+	for _, name := range sortedKeys(pipeline.Workflows) {
+		workflow := pipeline.Workflows[name]
+		workflowID := name
+		if workflow.Uses != "" {
+			workflowID = workflow.Uses
+		}
+		if augmenter.conflictWorkflows[workflowID] {
+			return pipeline, nil, workflowID
+		}
+
+		if workflow.Uses != "" {
+			workflow.Uses = rename(workflow.Uses)
+		} else {
+			name = rename(name)
+		}
+		if len(workflow.DependsOn) > 0 {
+			dependsOn := make([]string, 0, len(workflow.DependsOn))
+			for _, dependency := range workflow.DependsOn {
+				if pipeline.Workflows[dependency].Uses == "" {
+					dependency = rename(dependency)
+				}
+				dependsOn = append(dependsOn, dependency)
+			}
+			workflow.DependsOn = dependsOn
+		}
+		workflows[name] = workflow
+	}
+
+	var renamedOrder []string
+	for _, name := range order {
+		if workflow, ok := pipeline.Workflows[name]; ok && workflow.Uses == "" {
+			name = rename(name)
+		}
+		renamedOrder = append(renamedOrder, name)
+	}
+
+	pipeline.Workflows = workflows
+	return pipeline, renamedOrder, ""
+}
+
+// addTriggerMap sets the generated trigger map, if the existing config has no triggers.
+// The items triggering generated workflows added with a new ID are pointed to the new ID,
+// the items triggering generated workflows or pipelines not added due to a conflict are left out.
+func (augmenter *configAugmenter) addTriggerMap(triggerMap bitriseModels.TriggerMapModel) {
+	if len(triggerMap) == 0 {
+		return
+	}
+	if hasTriggers(augmenter.config) {
+		if !sameYAML(augmenter.config.TriggerMap, triggerMap) {
+			augmenter.conflict(AugmentConflictTriggerMap, "trigger_map", "the existing triggers are kept, the generated trigger map is not added")
+		}
+		return
+	}
+
+	var added bitriseModels.TriggerMapModel
+	for _, item := range triggerMap {
+		if augmenter.conflictWorkflows[item.WorkflowID] {
+			augmenter.conflict(AugmentConflictTriggerMap, item.WorkflowID, "the trigger of workflow %s is not added, the workflow differs from the generated workflow", item.WorkflowID)
+			continue
+		}
+		if augmenter.conflictPipelines[item.PipelineID] {
+			augmenter.conflict(AugmentConflictTriggerMap, item.PipelineID, "the trigger of pipeline %s is not added, the pipeline differs from the generated pipeline", item.PipelineID)
+			continue
+		}
+		if renamed, ok := augmenter.renamedWorkflows[item.WorkflowID]; ok {
+			item.WorkflowID = renamed
+		}
+		added = append(added, item)
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	augmenter.added.TriggerMap = added
+	augmenter.report.AddedTriggerMap = true
+}
+
+// hasTriggers reports whether the config has a trigger map or triggers on its workflows or pipelines.
+func hasTriggers(config bitriseModels.BitriseDataModel) bool {
+	if len(config.TriggerMap) > 0 {
+		return true
+	}
+	for _, workflow := range config.Workflows {
+		if !sameYAML(workflow.Triggers, bitriseModels.Triggers{}) {
+			return true
+		}
+	}
+	for _, pipeline := range config.Pipelines {
+		if !sameYAML(pipeline.Triggers, bitriseModels.Triggers{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingCacheAndTestSteps returns the IDs of the cache and test steps of the generated workflow, which are not in the existing workflow.
+func missingCacheAndTestSteps(existing, generated bitriseModels.WorkflowModel) []string {
+	var existingIDs []string
+	for _, item := range existing.Steps {
+		existingIDs = append(existingIDs, stepID(item))
+	}
+
+	var missing []string
+	for _, item := range generated.Steps {
+		id := stepID(item)
+		if !isCacheStep(id) && !isTestStep(item) {
+			continue
+		}
+		if !slices.Contains(existingIDs, id) && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+func isCacheStep(id string) bool {
+	return strings.Contains(id, "cache")
+}
+
+// isTestStep reports whether the step runs tests, based on its ID (xcode-test) or its title (npm run test).
+func isTestStep(item bitriseModels.StepListItemModel) bool {
+	if strings.Contains(stepID(item), "test") {
+		return true
+	}
+	for _, value := range item {
+		if step, ok := value.(stepmanModels.StepModel); ok && step.Title != nil {
+			return strings.Contains(strings.ToLower(*step.Title), "test")
+		}
+	}
+	return false
+}
+
+// sameYAML reports whether a and b are serialized the same way,
+// the models parsed from a bitrise.yml can differ from the generated ones in the empty values.
+func sameYAML(a, b interface{}) bool {
+	aData, aErr := yaml.Marshal(a)
+	bData, bErr := yaml.Marshal(b)
+	return aErr == nil && bErr == nil && string(aData) == string(bData)
+}
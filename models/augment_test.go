@@ -0,0 +1,206 @@
+package models
+
+import (
+	"testing"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+// augmentTestConfig is written by hand: with comments, an anchor, an unknown key and indented sequences.
+const augmentTestConfig = `format_version: "11"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+project_type: node-js
+x-clone: &clone
+  git-clone@8: {}
+app:
+  envs:
+    - NODEJS_PROJECT_DIR: web # the web app
+workflows:
+  # Deploys the web app.
+  deploy:
+    steps:
+      - *clone
+      # Uploads the build.
+      - script@1:
+          inputs:
+            - content: |
+                npm run deploy
+
+  run_tests:
+    steps:
+      - *clone
+      - npm@1:
+          inputs:
+            - command: test
+# the end
+`
+
+const augmentedTestConfig = `format_version: "11"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+project_type: node-js
+tools:
+  nodejs: "22"
+x-clone: &clone
+  git-clone@8: {}
+app:
+  envs:
+    - NODEJS_PROJECT_DIR: web # the web app
+    - NODE_ENV: test
+trigger_map:
+- type: pull_request
+  pipeline: ci
+  pull_request_source_branch: '*'
+- type: push
+  workflow: run_tests_generated
+  push_branch: main
+pipelines:
+  ci:
+    workflows:
+      run_tests_generated: {}
+      build:
+        depends_on:
+        - run_tests_generated
+workflows:
+  # Deploys the web app.
+  deploy:
+    steps:
+      - *clone
+      # Uploads the build.
+      - script@1:
+          inputs:
+            - content: |
+                npm run deploy
+
+  run_tests:
+    steps:
+      - *clone
+      - npm@1:
+          inputs:
+            - command: test
+  # Runs the tests.
+  run_tests_generated:
+    steps:
+    - git-clone@8: {}
+    - restore-npm-cache@2: {}
+    - npm@1: {}
+    - save-npm-cache@1: {}
+  # Builds the app.
+  build:
+    steps:
+    - git-clone@8: {}
+    - npm@1: {}
+# the end
+`
+
+func newAugmentTestConfig(t *testing.T) Config {
+	builder := NewDefaultConfigBuilder()
+	builder.SetWorkflowReasonTo("run_tests", "Runs the tests.")
+	builder.AppendStepListItemsTo("run_tests", mergeTestStep("git-clone@8"), mergeTestStep("restore-npm-cache@2"), mergeTestStep("npm@1"), mergeTestStep("save-npm-cache@1"))
+	builder.SetWorkflowReasonTo("build", "Builds the app.")
+	builder.AppendStepListItemsTo("build", mergeTestStep("git-clone@8"), mergeTestStep("npm@1"))
+	builder.AppendStepListItemsTo("deploy", mergeTestStep("git-clone@8"), mergeTestStep("deploy-to-bitrise-io@2"))
+	builder.SetGraphPipelineWorkflowTo("ci", "run_tests", bitriseModels.GraphPipelineWorkflowModel{})
+	builder.SetGraphPipelineWorkflowTo("ci", "build", bitriseModels.GraphPipelineWorkflowModel{DependsOn: []string{"run_tests"}})
+	builder.SetGraphPipelineWorkflowTo("release", "deploy", bitriseModels.GraphPipelineWorkflowModel{})
+	builder.AddTool("nodejs", "22")
+	builder.AddPullRequestTriggerToPipeline("ci")
+	builder.AddPushTriggerToWorkflow("deploy")
+	builder.AddPushTriggerToWorkflow("run_tests")
+	generated, err := builder.Generate("node-js", envmanModels.EnvironmentItemModel{"NODEJS_PROJECT_DIR": "."}, envmanModels.EnvironmentItemModel{"NODE_ENV": "test"})
+	require.NoError(t, err)
+	return generated
+}
+
+func TestAugmentConfig(t *testing.T) {
+	generated := newAugmentTestConfig(t)
+
+	content, report, err := AugmentConfig([]byte(augmentTestConfig), generated, true)
+	require.NoError(t, err)
+	require.Equal(t, AugmentReport{
+		AddedWorkflows:  []string{"run_tests_generated", "build"},
+		AddedPipelines:  []string{"ci"},
+		AddedAppEnvs:    []string{"NODE_ENV"},
+		AddedTools:      []string{"nodejs"},
+		AddedTriggerMap: true,
+		Conflicts: []AugmentConflict{
+			{Kind: AugmentConflictAppEnv, ID: "NODEJS_PROJECT_DIR", Message: "app env NODEJS_PROJECT_DIR is set to web, the generated config sets it to ."},
+			{Kind: AugmentConflictWorkflow, ID: "run_tests", Message: "workflow run_tests misses the restore-npm-cache, save-npm-cache steps of the generated workflow, added as run_tests_generated"},
+			{Kind: AugmentConflictWorkflow, ID: "deploy", Message: "workflow deploy differs from the generated workflow"},
+			{Kind: AugmentConflictPipeline, ID: "release", Message: "pipeline release is not added, it runs workflow deploy, which differs from the generated workflow"},
+			{Kind: AugmentConflictTriggerMap, ID: "deploy", Message: "the trigger of workflow deploy is not added, the workflow differs from the generated workflow"},
+		},
+	}, report)
+	// the existing lines are kept as they are, the added parts are inserted at the end of their sections
+	require.Equal(t, augmentedTestConfig, string(content))
+
+	augmented, err := UnmarshalConfig(content)
+	require.NoError(t, err)
+	_, err = augmented.Validate()
+	require.NoError(t, err)
+	require.Equal(t, generated.Workflows["run_tests"], augmented.Workflows["run_tests_generated"])
+
+	// augmenting with the same config again adds nothing
+	content, report, err = AugmentConfig(content, generated, true)
+	require.NoError(t, err)
+	require.Equal(t, augmentedTestConfig, string(content))
+	require.Empty(t, report.AddedWorkflows)
+	require.Empty(t, report.AddedAppEnvs)
+	require.False(t, report.AddedTriggerMap)
+}
+
+func TestAugmentConfig_missingSections(t *testing.T) {
+	builder := NewDefaultConfigBuilder()
+	builder.AppendStepListItemsTo("run_tests", mergeTestStep("npm@1"))
+	builder.AddTool("nodejs", "22")
+	generated, err := builder.Generate("node-js", envmanModels.EnvironmentItemModel{"NODE_ENV": "test"})
+	require.NoError(t, err)
+
+	// the empty sections are replaced, the missing ones are inserted in the conventional order
+	existing := "# Hand-written config\nformat_version: \"11\"\nx-unknown: value\nworkflows: {}\n"
+	content, _, err := AugmentConfig([]byte(existing), generated, false)
+	require.NoError(t, err)
+	require.Equal(t, `# Hand-written config
+format_version: "11"
+project_type: node-js
+tools:
+  nodejs: "22"
+app:
+  envs:
+  - NODE_ENV: test
+x-unknown: value
+workflows:
+  run_tests:
+    steps:
+    - npm@1: {}
+`, string(content))
+
+	// the sections written in flow style are not changed
+	_, _, err = AugmentConfig([]byte("format_version: \"11\"\nworkflows: {primary: {}}\n"), generated, false)
+	require.EqualError(t, err, "failed to add the generated workflows to the existing config (line 2), it is not written in block style")
+}
+
+func TestAugmentConfig_existingTriggers(t *testing.T) {
+	existing := `format_version: "11"
+trigger_map:
+- push_branch: main
+  workflow: primary
+workflows:
+  primary: {}
+`
+
+	builder := NewDefaultConfigBuilder()
+	builder.AppendStepListItemsTo("run_tests", mergeTestStep("npm@1"))
+	builder.AddPullRequestTriggerToWorkflow("run_tests")
+	generated, err := builder.Generate("node-js")
+	require.NoError(t, err)
+
+	content, report, err := AugmentConfig([]byte(existing), generated, false)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "trigger_map:\n- push_branch: main\n  workflow: primary\nworkflows:\n")
+	require.False(t, report.AddedTriggerMap)
+	require.Equal(t, []AugmentConflict{
+		{Kind: AugmentConflictTriggerMap, ID: "trigger_map", Message: "the existing triggers are kept, the generated trigger map is not added"},
+	}, report.Conflicts)
+}
@@ -0,0 +1,279 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// insertConfig inserts the parts of the added config into the existing bitrise.yml, at the end of their sections.
+// The lines of the existing config are kept as they are, only the empty sections (like workflows: {}) are replaced.
+func insertConfig(existing []byte, added Config, withComments bool) ([]byte, error) {
+	addedContent, err := marshalConfig(added, withComments)
+	if err != nil {
+		return nil, err
+	}
+
+	existingRoot, err := documentRoot(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the existing config: %w", err)
+	}
+	if existingRoot == nil || existingRoot.Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("the existing config is not a mapping")
+	}
+	addedRoot, err := documentRoot(addedContent)
+	if err != nil {
+		return nil, err
+	}
+
+	editor := configEditor{
+		lines:      splitLines(string(existing)),
+		addedLines: splitLines(string(addedContent)),
+	}
+	for i := 0; i+1 < len(addedRoot.Content); i += 2 {
+		key, value := addedRoot.Content[i], addedRoot.Content[i+1]
+		if isEmptyNode(value) {
+			continue
+		}
+
+		existingKey, existingValue := mappingPair(existingRoot, key.Value)
+		if existingKey == nil {
+			at := editor.topLevelInsertLine(existingRoot, key.Value)
+			editor.insert(at, at, editor.addedBlock(key, value, 0))
+			continue
+		}
+		if err := editor.addTo(existingKey, existingValue, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(editor.apply()), nil
+}
+
+// configEditor collects the line edits of the existing bitrise.yml.
+type configEditor struct {
+	lines      []string
+	addedLines []string
+	edits      []lineEdit
+}
+
+// lineEdit replaces the lines[start:end] of the existing config, the lines are inserted if start == end.
+type lineEdit struct {
+	start, end int
+	lines      []string
+}
+
+func (editor *configEditor) insert(start, end int, lines []string) {
+	editor.edits = append(editor.edits, lineEdit{start: start, end: end, lines: lines})
+}
+
+// addTo adds the added value to the value of the same key in the existing config:
+// the missing entries of a mapping and the items of a sequence are inserted at the end of the existing block,
+// an empty value is replaced.
+func (editor *configEditor) addTo(existingKey, existingValue, addedKey, addedValue *yamlv3.Node) error {
+	isBlock := existingValue.Style&yamlv3.FlowStyle == 0
+
+	switch {
+	case isEmptyNode(existingValue):
+		editor.insert(existingKey.Line-1, blockEnd(editor.lines, existingKey, existingValue), editor.addedBlock(addedKey, addedValue, indentation(editor.lines, existingKey)))
+	case existingValue.Kind == yamlv3.MappingNode && addedValue.Kind == yamlv3.MappingNode && isBlock:
+		at := blockEnd(editor.lines, existingKey, existingValue)
+		childIndent := indentation(editor.lines, existingValue.Content[0])
+
+		var lines []string
+		for i := 0; i+1 < len(addedValue.Content); i += 2 {
+			key, value := addedValue.Content[i], addedValue.Content[i+1]
+			if existingChildKey, existingChildValue := mappingPair(existingValue, key.Value); existingChildKey != nil {
+				if err := editor.addTo(existingChildKey, existingChildValue, key, value); err != nil {
+					return err
+				}
+				continue
+			}
+			lines = append(lines, editor.addedBlock(key, value, childIndent)...)
+		}
+		if len(lines) > 0 {
+			editor.insert(at, at, lines)
+		}
+	case existingValue.Kind == yamlv3.SequenceNode && addedValue.Kind == yamlv3.SequenceNode && isBlock:
+		at := blockEnd(editor.lines, existingKey, existingValue)
+		first := addedValue.Content[0].Line - 1
+		delta := indentation(editor.lines, existingValue.Content[0]) - leadingSpaces(editor.addedLines[first])
+		editor.insert(at, at, reindent(editor.addedLines[first:blockEnd(editor.addedLines, addedKey, addedValue)], delta))
+	default:
+		return fmt.Errorf("failed to add the generated %s to the existing config (line %d), it is not written in block style", addedKey.Value, existingKey.Line)
+	}
+	return nil
+}
+
+// addedBlock returns the lines of the key and its value in the added config, with the comments above the key,
+// indented to indent.
+func (editor *configEditor) addedBlock(key, value *yamlv3.Node, indent int) []string {
+	keyIndent := indentation(editor.addedLines, key)
+	start := key.Line - 1
+	for start > 0 {
+		line := editor.addedLines[start-1]
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") || leadingSpaces(line) != keyIndent {
+			break
+		}
+		start--
+	}
+	return reindent(editor.addedLines[start:blockEnd(editor.addedLines, key, value)], indent-keyIndent)
+}
+
+// topLevelInsertLine returns the line where the missing top-level key is inserted, following the conventional key order:
+// after the last key ordered before it, or before the first key ordered after it, or at the end of the config.
+func (editor *configEditor) topLevelInsertLine(root *yamlv3.Node, key string) int {
+	rank := slices.Index(topLevelKeyOrder, key)
+
+	var previous, next *yamlv3.Node
+	var previousValue *yamlv3.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		existingKey := root.Content[i]
+		existingRank := slices.Index(topLevelKeyOrder, existingKey.Value)
+		switch {
+		case existingRank == -1:
+		case existingRank < rank:
+			previous, previousValue = existingKey, root.Content[i+1]
+		case existingRank > rank && next == nil:
+			next = existingKey
+		}
+	}
+
+	if previous != nil {
+		return blockEnd(editor.lines, previous, previousValue)
+	}
+	if next != nil {
+		// the comments above the key belong to the key
+		at := next.Line - 1
+		for at > 0 && strings.HasPrefix(editor.lines[at-1], "#") {
+			at--
+		}
+		return at
+	}
+	return len(editor.lines)
+}
+
+// apply returns the existing config with the edits applied.
+func (editor *configEditor) apply() string {
+	if len(editor.edits) == 0 {
+		return strings.Join(editor.lines, "")
+	}
+
+	edits := slices.Clone(editor.edits)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	lines := slices.Clone(editor.lines)
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		lines[len(lines)-1] += "\n"
+	}
+
+	var content strings.Builder
+	next := 0
+	for _, edit := range edits {
+		for ; next < edit.start; next++ {
+			content.WriteString(lines[next])
+		}
+		for _, line := range edit.lines {
+			content.WriteString(line)
+		}
+		next = max(next, edit.end)
+	}
+	for ; next < len(lines); next++ {
+		content.WriteString(lines[next])
+	}
+	return content.String()
+}
+
+// blockEnd returns the index of the line after the last line of the key and its value.
+// The block lasts until the first line indented at most as the key, except for the items of a sequence written at the key's indentation;
+// the blank lines and the less indented comments at the end of the block belong to the next block.
+func blockEnd(lines []string, key, value *yamlv3.Node) int {
+	indent := indentation(lines, key)
+	isSequence := value.Kind == yamlv3.SequenceNode
+
+	end := key.Line
+	for i := key.Line; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+		lineIndent := leadingSpaces(lines[i])
+		if lineIndent > indent || (isSequence && lineIndent == indent && (trimmed == "-" || strings.HasPrefix(trimmed, "- "))) {
+			end = i + 1
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		break
+	}
+	return end
+}
+
+// indentation returns the indentation of the line of the node.
+func indentation(lines []string, node *yamlv3.Node) int {
+	return leadingSpaces(lines[node.Line-1])
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// reindent shifts the non-blank lines by delta spaces.
+func reindent(lines []string, delta int) []string {
+	shifted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		switch {
+		case strings.TrimSpace(line) == "":
+		case delta > 0:
+			line = strings.Repeat(" ", delta) + line
+		case delta < 0:
+			line = line[min(-delta, leadingSpaces(line)):]
+		}
+		shifted = append(shifted, line)
+	}
+	return shifted
+}
+
+// splitLines splits the content into lines, keeping the line endings.
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func documentRoot(content []byte) (*yamlv3.Node, error) {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	return document.Content[0], nil
+}
+
+// mappingPair returns the key and value nodes of the key, or nils if the key is missing.
+func mappingPair(node *yamlv3.Node, key string) (*yamlv3.Node, *yamlv3.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// isEmptyNode reports whether the node is null, an empty string or an empty collection.
+func isEmptyNode(node *yamlv3.Node) bool {
+	switch node.Kind {
+	case yamlv3.ScalarNode:
+		return node.Value == "" || node.Tag == "!!null"
+	case yamlv3.MappingNode, yamlv3.SequenceNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
@@ -1,8 +1,13 @@
 package models
 
 import (
+	"fmt"
+	"maps"
+
+	"github.com/bitrise-io/bitrise-init/steps/catalog"
 	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
 )
 
 // WorkflowID ...
@@ -20,15 +25,23 @@ const (
 	// FormatVersion ...
 	FormatVersion = bitriseModels.FormatVersion
 
+	// DefaultBranch is the branch of the push triggers,
+	// replaced by the default branch of the scanned repository if it is known, see SetDefaultBranch.
+	DefaultBranch = "main"
+
 	defaultSteplibSource = "https://github.com/bitrise-io/bitrise-steplib.git"
 )
 
 // ConfigBuilderModel ...
 type ConfigBuilderModel struct {
 	workflowBuilderMap   map[WorkflowID]*workflowBuilderModel
+	workflowOrder        []WorkflowID
 	pipelineBuilderMap   map[PipelineID]*pipelineBuilderModel
+	pipelineOrder        []PipelineID
 	containerDefinitions map[string]bitriseModels.Container
 	tools                bitriseModels.ToolsModel
+	triggerMap           bitriseModels.TriggerMapModel
+	stepReasons          map[string]string
 }
 
 // NewDefaultConfigBuilder ...
@@ -39,14 +52,22 @@ func NewDefaultConfigBuilder() *ConfigBuilderModel {
 	}
 }
 
+// SetStepReasons sets the reasons explaining why the steps are in the generated config, by step ID.
+// The reason of a step is used when the step is appended without a reason.
+func (builder *ConfigBuilderModel) SetStepReasons(reasons map[string]string) {
+	builder.stepReasons = reasons
+}
+
 // AppendStepListItemsTo ...
 func (builder *ConfigBuilderModel) AppendStepListItemsTo(workflow WorkflowID, items ...bitriseModels.StepListItemModel) {
-	workflowBuilder := builder.workflowBuilderMap[workflow]
-	if workflowBuilder == nil {
-		workflowBuilder = newDefaultWorkflowBuilder()
-		builder.workflowBuilderMap[workflow] = workflowBuilder
-	}
-	workflowBuilder.appendStepListItems(items...)
+	workflowBuilder := builder.workflowBuilder(workflow)
+	workflowBuilder.appendStepListItems(builder.stepReasons, items...)
+}
+
+// AppendStepListItemsWithReasonTo appends the steps to the workflow, the reason explains why the first of them is needed.
+func (builder *ConfigBuilderModel) AppendStepListItemsWithReasonTo(workflow WorkflowID, reason string, items ...bitriseModels.StepListItemModel) {
+	workflowBuilder := builder.workflowBuilder(workflow)
+	workflowBuilder.appendStepListItemsWithReason(builder.stepReasons, reason, items...)
 }
 
 // SetGraphPipelineWorkflowTo ...
@@ -55,30 +76,108 @@ func (builder *ConfigBuilderModel) SetGraphPipelineWorkflowTo(pipeline PipelineI
 	if pipelineBuilder == nil {
 		pipelineBuilder = newDefaultPipelineBuilder()
 		builder.pipelineBuilderMap[pipeline] = pipelineBuilder
+		builder.pipelineOrder = append(builder.pipelineOrder, pipeline)
 	}
 	pipelineBuilder.setGraphPipelineWorkflow(workflow, item)
 }
 
 // SetWorkflowDescriptionTo ...
 func (builder *ConfigBuilderModel) SetWorkflowDescriptionTo(workflow WorkflowID, description string) {
-	workflowBuilder := builder.workflowBuilderMap[workflow]
-	if workflowBuilder == nil {
-		workflowBuilder = newDefaultWorkflowBuilder()
-		builder.workflowBuilderMap[workflow] = workflowBuilder
-	}
+	workflowBuilder := builder.workflowBuilder(workflow)
 	workflowBuilder.Description = description
 }
 
 // SetWorkflowSummaryTo ...
 func (builder *ConfigBuilderModel) SetWorkflowSummaryTo(workflow WorkflowID, summary string) {
-	workflowBuilder := builder.workflowBuilderMap[workflow]
-	if workflowBuilder == nil {
-		workflowBuilder = newDefaultWorkflowBuilder()
-		builder.workflowBuilderMap[workflow] = workflowBuilder
-	}
+	workflowBuilder := builder.workflowBuilder(workflow)
 	workflowBuilder.Summary = summary
 }
 
+// SetWorkflowReasonTo sets the reason explaining what the workflow is for.
+func (builder *ConfigBuilderModel) SetWorkflowReasonTo(workflow WorkflowID, reason string) {
+	workflowBuilder := builder.workflowBuilder(workflow)
+	workflowBuilder.Reason = reason
+}
+
+// SetWorkflowBeforeRunTo sets the workflows running before the workflow.
+func (builder *ConfigBuilderModel) SetWorkflowBeforeRunTo(workflow WorkflowID, beforeRun ...WorkflowID) {
+	workflowBuilder := builder.workflowBuilder(workflow)
+	workflowBuilder.BeforeRun = nil
+	for _, beforeRunWorkflow := range beforeRun {
+		workflowBuilder.BeforeRun = append(workflowBuilder.BeforeRun, string(beforeRunWorkflow))
+	}
+}
+
+// AppendWorkflowEnvsTo appends envs to the workflow's envs.
+func (builder *ConfigBuilderModel) AppendWorkflowEnvsTo(workflow WorkflowID, envs ...envmanModels.EnvironmentItemModel) {
+	workflowBuilder := builder.workflowBuilder(workflow)
+	workflowBuilder.Envs = append(workflowBuilder.Envs, envs...)
+}
+
+// AddWorkflowToolTo adds a tool with its version to the workflow's tools, overriding the tools of the config.
+func (builder *ConfigBuilderModel) AddWorkflowToolTo(workflow WorkflowID, id bitriseModels.ToolID, version string) {
+	workflowBuilder := builder.workflowBuilder(workflow)
+	if workflowBuilder.Tools == nil {
+		workflowBuilder.Tools = bitriseModels.ToolsModel{}
+	}
+	workflowBuilder.Tools[id] = version
+}
+
+// AddPushTriggerToWorkflow triggers the workflow on pushes to the default branch.
+func (builder *ConfigBuilderModel) AddPushTriggerToWorkflow(workflow WorkflowID) {
+	builder.triggerMap = append(builder.triggerMap, bitriseModels.TriggerMapItemModel{
+		Type:       bitriseModels.CodePushType,
+		PushBranch: DefaultBranch,
+		WorkflowID: string(workflow),
+	})
+}
+
+// AddPushTriggerToPipeline triggers the pipeline on pushes to the default branch.
+func (builder *ConfigBuilderModel) AddPushTriggerToPipeline(pipeline PipelineID) {
+	builder.triggerMap = append(builder.triggerMap, bitriseModels.TriggerMapItemModel{
+		Type:       bitriseModels.CodePushType,
+		PushBranch: DefaultBranch,
+		PipelineID: string(pipeline),
+	})
+}
+
+// AddPullRequestTriggerToWorkflow triggers the workflow on every pull request.
+func (builder *ConfigBuilderModel) AddPullRequestTriggerToWorkflow(workflow WorkflowID) {
+	builder.triggerMap = append(builder.triggerMap, bitriseModels.TriggerMapItemModel{
+		Type:                    bitriseModels.PullRequestType,
+		PullRequestSourceBranch: "*",
+		WorkflowID:              string(workflow),
+	})
+}
+
+// AddPullRequestTriggerToPipeline triggers the pipeline on every pull request.
+func (builder *ConfigBuilderModel) AddPullRequestTriggerToPipeline(pipeline PipelineID) {
+	builder.triggerMap = append(builder.triggerMap, bitriseModels.TriggerMapItemModel{
+		Type:                    bitriseModels.PullRequestType,
+		PullRequestSourceBranch: "*",
+		PipelineID:              string(pipeline),
+	})
+}
+
+// AddChangedFilesPushTriggerTo triggers the workflow on pushes to the default branch changing files matching the changedFiles glob.
+// Unlike the trigger map items, the triggers of every workflow are checked, so more workflows can run for the same push.
+func (builder *ConfigBuilderModel) AddChangedFilesPushTriggerTo(workflow WorkflowID, changedFiles string) {
+	workflowBuilder := builder.workflowBuilder(workflow)
+	workflowBuilder.Triggers.PushTriggers = append(workflowBuilder.Triggers.PushTriggers, bitriseModels.PushGitEventTriggerItem{
+		Branch:       DefaultBranch,
+		ChangedFiles: changedFiles,
+	})
+}
+
+// AddChangedFilesPullRequestTriggerTo triggers the workflow on pull requests changing files matching the changedFiles glob.
+func (builder *ConfigBuilderModel) AddChangedFilesPullRequestTriggerTo(workflow WorkflowID, changedFiles string) {
+	workflowBuilder := builder.workflowBuilder(workflow)
+	workflowBuilder.Triggers.PullRequestTriggers = append(workflowBuilder.Triggers.PullRequestTriggers, bitriseModels.PullRequestGitEventTriggerItem{
+		SourceBranch: "*",
+		ChangedFiles: changedFiles,
+	})
+}
+
 // SetContainerDefinitions ...
 func (builder *ConfigBuilderModel) SetContainerDefinitions(containers map[string]bitriseModels.Container) {
 	builder.containerDefinitions = containers
@@ -92,30 +191,130 @@ func (builder *ConfigBuilderModel) AddTool(id bitriseModels.ToolID, version stri
 	builder.tools[id] = version
 }
 
+func (builder *ConfigBuilderModel) workflowBuilder(workflow WorkflowID) *workflowBuilderModel {
+	workflowBuilder := builder.workflowBuilderMap[workflow]
+	if workflowBuilder == nil {
+		workflowBuilder = newDefaultWorkflowBuilder()
+		builder.workflowBuilderMap[workflow] = workflowBuilder
+		builder.workflowOrder = append(builder.workflowOrder, workflow)
+	}
+	return workflowBuilder
+}
+
 // Generate ...
-func (builder *ConfigBuilderModel) Generate(projectType string, appEnvs ...envmanModels.EnvironmentItemModel) (bitriseModels.BitriseDataModel, error) {
+func (builder *ConfigBuilderModel) Generate(projectType string, appEnvs ...envmanModels.EnvironmentItemModel) (Config, error) {
 	pipelines := map[string]bitriseModels.PipelineModel{}
-	for pipelineID, pipelineBuilder := range builder.pipelineBuilderMap {
+	for _, pipelineID := range builder.pipelineOrder {
+		pipelineBuilder := builder.pipelineBuilderMap[pipelineID]
+		if err := pipelineBuilder.validate(builder.workflowBuilderMap); err != nil {
+			return Config{}, fmt.Errorf("invalid pipeline %s: %w", pipelineID, err)
+		}
 		pipelines[string(pipelineID)] = pipelineBuilder.generate()
 	}
 
 	workflows := map[string]bitriseModels.WorkflowModel{}
-	for workflowID, workflowBuilder := range builder.workflowBuilderMap {
-		workflows[string(workflowID)] = workflowBuilder.generate()
+	for _, workflowID := range builder.workflowOrder {
+		workflow := builder.workflowBuilderMap[workflowID].generate()
+		if err := validateSteps(workflow.Steps); err != nil {
+			return Config{}, fmt.Errorf("invalid step in workflow %s: %w", workflowID, err)
+		}
+		workflows[string(workflowID)] = workflow
 	}
 
 	app := bitriseModels.AppModel{
 		Environments: appEnvs,
 	}
 
-	return bitriseModels.BitriseDataModel{
+	config := NewConfig(bitriseModels.BitriseDataModel{
 		FormatVersion:        FormatVersion,
 		DefaultStepLibSource: defaultSteplibSource,
 		ProjectType:          projectType,
 		Tools:                builder.tools,
 		Containers:           builder.containerDefinitions,
+		TriggerMap:           builder.triggerMap,
 		Pipelines:            pipelines,
 		Workflows:            workflows,
 		App:                  app,
-	}, nil
+	})
+	config.setLayout(builder.layout())
+
+	return config, nil
+}
+
+// validateSteps checks the inputs of the steps against the step catalog.
+func validateSteps(items []bitriseModels.StepListItemModel) error {
+	for _, item := range items {
+		for reference, value := range item {
+			if step, ok := value.(stepmanModels.StepModel); ok {
+				if err := catalog.Default().ValidateStep(reference, step); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (builder *ConfigBuilderModel) layout() configLayout {
+	layout := configLayout{PipelineWorkflows: map[string][]string{}}
+	for _, workflowID := range builder.workflowOrder {
+		layout.Workflows = append(layout.Workflows, string(workflowID))
+
+		workflowBuilder := builder.workflowBuilderMap[workflowID]
+		if workflowBuilder.Reason != "" {
+			setWorkflowReason(&layout, string(workflowID), workflowBuilder.Reason)
+		}
+		for idx, reason := range workflowBuilder.StepReasons {
+			setStepReason(&layout, string(workflowID), idx, reason)
+		}
+	}
+	for _, pipelineID := range builder.pipelineOrder {
+		layout.Pipelines = append(layout.Pipelines, string(pipelineID))
+		for _, workflowID := range builder.pipelineBuilderMap[pipelineID].workflowOrder {
+			layout.PipelineWorkflows[string(pipelineID)] = append(layout.PipelineWorkflows[string(pipelineID)], string(workflowID))
+		}
+	}
+	return layout
+}
+
+// SetDefaultBranch replaces DefaultBranch with branch in the push triggers of the config and of its workflows.
+func SetDefaultBranch(config *bitriseModels.BitriseDataModel, branch string) {
+	if branch == "" || branch == DefaultBranch {
+		return
+	}
+
+	// the trigger map and the workflows can be shared by the copies of a config
+	if len(config.TriggerMap) > 0 {
+		triggerMap := make(bitriseModels.TriggerMapModel, len(config.TriggerMap))
+		for idx, item := range config.TriggerMap {
+			if item.Type == bitriseModels.CodePushType && item.PushBranch == DefaultBranch {
+				item.PushBranch = branch
+			}
+			triggerMap[idx] = item
+		}
+		config.TriggerMap = triggerMap
+	}
+
+	var workflows map[string]bitriseModels.WorkflowModel
+	for workflowID, workflow := range config.Workflows {
+		if len(workflow.Triggers.PushTriggers) == 0 {
+			continue
+		}
+		if workflows == nil {
+			workflows = maps.Clone(config.Workflows)
+		}
+
+		pushTriggers := make([]bitriseModels.PushGitEventTriggerItem, len(workflow.Triggers.PushTriggers))
+		for idx, item := range workflow.Triggers.PushTriggers {
+			if item.Branch == DefaultBranch {
+				item.Branch = branch
+			}
+			pushTriggers[idx] = item
+		}
+		workflow.Triggers.PushTriggers = pushTriggers
+		workflows[workflowID] = workflow
+	}
+	if workflows != nil {
+		config.Workflows = workflows
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
 	stepmanModels "github.com/bitrise-io/stepman/models"
 	"github.com/stretchr/testify/require"
 )
@@ -20,6 +21,56 @@ func TestConfigGenerateHaveProjectType(t *testing.T) {
 	require.Equal(t, "iOS", model.ProjectType)
 }
 
+func TestConfigGenerateValidatesStepInputs(t *testing.T) {
+	config := NewDefaultConfigBuilder()
+	config.AppendStepListItemsTo("primary", bitriseModels.StepListItemModel{
+		"android-build@1": stepmanModels.StepModel{Inputs: []envmanModels.EnvironmentItemModel{{"project_locaton": "."}}},
+	})
+
+	_, err := config.Generate("android")
+	require.EqualError(t, err, "invalid step in workflow primary: step android-build@1 has no input project_locaton")
+
+	config = NewDefaultConfigBuilder()
+	config.AppendStepListItemsTo("primary", bitriseModels.StepListItemModel{
+		"android-build@1": stepmanModels.StepModel{Inputs: []envmanModels.EnvironmentItemModel{{"build_type": "ipa"}}},
+	})
+
+	_, err = config.Generate("android")
+	require.ErrorContains(t, err, "invalid value of input build_type of step android-build@1: ipa")
+}
+
+func TestConfigGenerateValidatesPipelines(t *testing.T) {
+	newConfig := func() *ConfigBuilderModel {
+		config := NewDefaultConfigBuilder()
+		config.AppendStepListItemsTo("build", bitriseModels.StepListItemModel{"script@1": stepmanModels.StepModel{}})
+		config.AppendStepListItemsTo("test", bitriseModels.StepListItemModel{"script@1": stepmanModels.StepModel{}})
+		return config
+	}
+
+	config := newConfig()
+	config.SetGraphPipelineWorkflowTo("ci", "build", bitriseModels.GraphPipelineWorkflowModel{})
+	config.SetGraphPipelineWorkflowTo("ci", "test", bitriseModels.GraphPipelineWorkflowModel{DependsOn: []string{"build"}})
+	config.SetGraphPipelineWorkflowTo("ci", "test_2", bitriseModels.GraphPipelineWorkflowModel{Uses: "test"})
+	_, err := config.Generate("android")
+	require.NoError(t, err)
+
+	config = newConfig()
+	config.SetGraphPipelineWorkflowTo("ci", "deploy", bitriseModels.GraphPipelineWorkflowModel{})
+	_, err = config.Generate("android")
+	require.EqualError(t, err, "invalid pipeline ci: workflow deploy is not defined")
+
+	config = newConfig()
+	config.SetGraphPipelineWorkflowTo("ci", "test", bitriseModels.GraphPipelineWorkflowModel{DependsOn: []string{"build"}})
+	_, err = config.Generate("android")
+	require.EqualError(t, err, "invalid pipeline ci: workflow test depends on build, which is not part of the pipeline")
+
+	config = newConfig()
+	config.SetGraphPipelineWorkflowTo("ci", "build", bitriseModels.GraphPipelineWorkflowModel{DependsOn: []string{"test"}})
+	config.SetGraphPipelineWorkflowTo("ci", "test", bitriseModels.GraphPipelineWorkflowModel{DependsOn: []string{"build"}})
+	_, err = config.Generate("android")
+	require.EqualError(t, err, "invalid pipeline ci: dependency cycle: build -> test -> build")
+}
+
 func TestConfigDoesNotGenerateTriggerMap(t *testing.T) {
 	config := NewDefaultConfigBuilder()
 	config.AppendStepListItemsTo("primary", []bitriseModels.StepListItemModel{
@@ -31,3 +82,55 @@ func TestConfigDoesNotGenerateTriggerMap(t *testing.T) {
 	require.Nil(t, err)
 	require.Nil(t, model.TriggerMap)
 }
+
+func TestConfigGenerateTriggerMap(t *testing.T) {
+	config := NewDefaultConfigBuilder()
+	config.AppendStepListItemsTo("run_tests", bitriseModels.StepListItemModel{"step-id": stepmanModels.StepModel{}})
+	config.SetGraphPipelineWorkflowTo("build", "run_tests", bitriseModels.GraphPipelineWorkflowModel{})
+	config.AddPullRequestTriggerToWorkflow("run_tests")
+	config.AddPushTriggerToPipeline("build")
+
+	model, err := config.Generate("iOS")
+	require.NoError(t, err)
+	require.Equal(t, bitriseModels.TriggerMapModel{
+		{Type: bitriseModels.PullRequestType, WorkflowID: "run_tests", PullRequestSourceBranch: "*"},
+		{Type: bitriseModels.CodePushType, PipelineID: "build", PushBranch: DefaultBranch},
+	}, model.TriggerMap)
+
+	copied := model
+	SetDefaultBranch(&copied.BitriseDataModel, "develop")
+	require.Equal(t, "develop", copied.TriggerMap[1].PushBranch)
+	require.Equal(t, "*", copied.TriggerMap[0].PullRequestSourceBranch)
+	// the original config is not changed
+	require.Equal(t, DefaultBranch, model.TriggerMap[1].PushBranch)
+}
+
+func TestConfigGenerateWorkflowTriggers(t *testing.T) {
+	config := NewDefaultConfigBuilder()
+	config.AppendStepListItemsTo(SetupWorkflowID, bitriseModels.StepListItemModel{"git-clone": stepmanModels.StepModel{}})
+	config.AppendStepListItemsTo("run_tests_web", bitriseModels.StepListItemModel{"npm": stepmanModels.StepModel{}})
+	config.SetWorkflowBeforeRunTo("run_tests_web", SetupWorkflowID)
+	config.AddWorkflowToolTo("run_tests_web", "node", "22")
+	config.AddChangedFilesPullRequestTriggerTo("run_tests_web", "web/**")
+	config.AddChangedFilesPushTriggerTo("run_tests_web", "web/**")
+
+	model, err := config.Generate("node-js")
+	require.NoError(t, err)
+	_, err = model.Validate()
+	require.NoError(t, err)
+	require.Nil(t, model.TriggerMap)
+
+	workflow := model.Workflows["run_tests_web"]
+	require.Equal(t, []string{"_setup"}, workflow.BeforeRun)
+	require.Equal(t, bitriseModels.ToolsModel{"node": "22"}, workflow.Tools)
+	require.Equal(t, bitriseModels.Triggers{
+		PushTriggers:        []bitriseModels.PushGitEventTriggerItem{{Branch: DefaultBranch, ChangedFiles: "web/**"}},
+		PullRequestTriggers: []bitriseModels.PullRequestGitEventTriggerItem{{SourceBranch: "*", ChangedFiles: "web/**"}},
+	}, workflow.Triggers)
+
+	copied := model
+	SetDefaultBranch(&copied.BitriseDataModel, "develop")
+	require.Equal(t, "develop", copied.Workflows["run_tests_web"].Triggers.PushTriggers[0].Branch)
+	// the original config is not changed
+	require.Equal(t, DefaultBranch, model.Workflows["run_tests_web"].Triggers.PushTriggers[0].Branch)
+}
@@ -0,0 +1,56 @@
+package models
+
+import "encoding/json"
+
+// BitriseConfigMap maps the config names to the generated configs.
+// The configs are serialized as bitrise.yml strings, both in JSON and in YAML:
+//
+//	ios-test-config: |
+//	  format_version: "13"
+//	  ...
+type BitriseConfigMap map[string]Config
+
+// MarshalJSON serializes the config as a bitrise.yml string.
+func (config Config) MarshalJSON() ([]byte, error) {
+	data, err := MarshalConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(data))
+}
+
+// UnmarshalJSON parses the config from a bitrise.yml string.
+func (config *Config) UnmarshalJSON(data []byte) error {
+	var content string
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+	return config.unmarshalContent(content)
+}
+
+// MarshalYAML serializes the config as a bitrise.yml string.
+func (config Config) MarshalYAML() (interface{}, error) {
+	data, err := MarshalConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// UnmarshalYAML parses the config from a bitrise.yml string.
+func (config *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var content string
+	if err := unmarshal(&content); err != nil {
+		return err
+	}
+	return config.unmarshalContent(content)
+}
+
+func (config *Config) unmarshalContent(content string) error {
+	parsed, err := UnmarshalConfig([]byte(content))
+	if err != nil {
+		return err
+	}
+	*config = parsed
+	return nil
+}
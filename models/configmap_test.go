@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanResultModel_configsAsStrings(t *testing.T) {
+	configYML := "format_version: \"13\"\nproject_type: ios\nworkflows:\n  primary: {}\n"
+	result := ScanResultModel{
+		ScannerToBitriseConfigMap: map[string]BitriseConfigMap{
+			"ios": {
+				"ios-config": NewConfig(bitriseModels.BitriseDataModel{
+					FormatVersion: "13",
+					ProjectType:   "ios",
+					Workflows:     map[string]bitriseModels.WorkflowModel{"primary": {}},
+				}),
+			},
+		},
+	}
+
+	jsonData, err := json.Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"configs": {"ios": {"ios-config": "format_version: \"13\"\nproject_type: ios\nworkflows:\n  primary: {}\n"}}}`, string(jsonData))
+
+	var fromJSON ScanResultModel
+	require.NoError(t, json.Unmarshal(jsonData, &fromJSON))
+	require.Equal(t, result.ScannerToBitriseConfigMap, fromJSON.ScannerToBitriseConfigMap)
+
+	yamlData, err := yaml.Marshal(result)
+	require.NoError(t, err)
+	var configStrs struct {
+		Configs map[string]map[string]string `yaml:"configs"`
+	}
+	require.NoError(t, yaml.Unmarshal(yamlData, &configStrs))
+	require.Equal(t, configYML, configStrs.Configs["ios"]["ios-config"])
+
+	var fromYAML ScanResultModel
+	require.NoError(t, yaml.Unmarshal(yamlData, &fromYAML))
+	require.Equal(t, result.ScannerToBitriseConfigMap, fromYAML.ScannerToBitriseConfigMap)
+
+	var fromYAMLv3 ScanResultModel
+	require.NoError(t, yamlv3.Unmarshal(yamlData, &fromYAMLv3))
+	require.Equal(t, result.ScannerToBitriseConfigMap, fromYAMLv3.ScannerToBitriseConfigMap)
+
+	require.Error(t, json.Unmarshal([]byte(`{"configs": {"ios": {"ios-config": "workflows: ["}}}`), &fromJSON))
+}
@@ -0,0 +1,365 @@
+package models
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+)
+
+// topLevelKeyOrder is the conventional order of the bitrise.yml top-level keys.
+var topLevelKeyOrder = []string{
+	"format_version",
+	"default_step_lib_source",
+	"project_type",
+	"title",
+	"summary",
+	"description",
+	"tools",
+	"tool_config",
+	"app",
+	"meta",
+	"containers",
+	"services",
+	"trigger_map",
+	"pipelines",
+	"stages",
+	"step_bundles",
+	"workflows",
+}
+
+// configLayout is the creation order of the workflows, the pipelines and the workflows of each pipeline,
+// and the reasons explaining the workflows and the steps (by workflow and step index).
+type configLayout struct {
+	Workflows         []string
+	Pipelines         []string
+	PipelineWorkflows map[string][]string
+	WorkflowReasons   map[string]string
+	StepReasons       map[string]map[int]string
+}
+
+// isDefault reports whether the layout matches the alphabetical order without comments, used when no layout is recorded.
+func (layout configLayout) isDefault() bool {
+	if !slices.IsSorted(layout.Workflows) || !slices.IsSorted(layout.Pipelines) {
+		return false
+	}
+	for _, workflows := range layout.PipelineWorkflows {
+		if !slices.IsSorted(workflows) {
+			return false
+		}
+	}
+	return len(layout.WorkflowReasons) == 0 && len(layout.StepReasons) == 0
+}
+
+// Config is a generated bitrise.yml: the data model and its layout.
+// The layout is only read by the emitter, it is never part of the data model.
+type Config struct {
+	bitriseModels.BitriseDataModel `yaml:",inline"`
+
+	layout configLayout
+}
+
+// NewConfig returns the config of the data model, its workflows and pipelines are written in alphabetical order.
+func NewConfig(model bitriseModels.BitriseDataModel) Config {
+	return Config{BitriseDataModel: model}
+}
+
+// setLayout records the layout of the config, the default layout is kept as the zero value.
+func (config *Config) setLayout(layout configLayout) {
+	if layout.isDefault() {
+		layout = configLayout{}
+	}
+	config.layout = layout
+}
+
+// MarshalConfig serializes the config as a bitrise.yml.
+//
+// The top-level keys are written in the conventional order (format_version, default_step_lib_source, project_type,
+// tools, app, containers, pipelines, workflows), the workflows and the pipelines in their creation order.
+// The output is the same for the same config.
+func MarshalConfig(config Config) ([]byte, error) {
+	return marshalConfig(config, false)
+}
+
+// MarshalConfigWithComments is MarshalConfig, with comments explaining the workflows and steps which have a reason attached.
+func MarshalConfigWithComments(config Config) ([]byte, error) {
+	return marshalConfig(config, true)
+}
+
+func marshalConfig(config Config, withComments bool) ([]byte, error) {
+	layout := config.layout
+
+	var root yamlv3.Node
+	if err := root.Encode(config.BitriseDataModel); err != nil {
+		return nil, err
+	}
+
+	reorderMapping(&root, topLevelKeyOrder)
+	if pipelines := mappingValue(&root, "pipelines"); pipelines != nil {
+		reorderMapping(pipelines, layout.Pipelines)
+		for i := 0; i+1 < len(pipelines.Content); i += 2 {
+			if workflows := mappingValue(pipelines.Content[i+1], "workflows"); workflows != nil {
+				reorderMapping(workflows, layout.PipelineWorkflows[pipelines.Content[i].Value])
+			}
+		}
+	}
+	if workflows := mappingValue(&root, "workflows"); workflows != nil {
+		reorderMapping(workflows, layout.Workflows)
+	}
+
+	// The node tree is written with yaml.v2, yaml.v3 can't write the sequences in the compact style:
+	//
+	//	steps:
+	//	- git-clone@8: {}
+	value, err := yamlV2Value(&root)
+	if err != nil {
+		return nil, err
+	}
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if withComments {
+		return addComments(data, layout)
+	}
+	return data, nil
+}
+
+// yamlV2Value converts the yaml.v3 node to a value written by yaml.v2 the same way, keeping the order of the mappings.
+func yamlV2Value(node *yamlv3.Node) (interface{}, error) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlV2Value(node.Content[0])
+	case yamlv3.MappingNode:
+		mapping := yaml.MapSlice{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, err := yamlV2Value(node.Content[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := yamlV2Value(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			mapping = append(mapping, yaml.MapItem{Key: key, Value: value})
+		}
+		return mapping, nil
+	case yamlv3.SequenceNode:
+		sequence := []interface{}{}
+		for _, item := range node.Content {
+			value, err := yamlV2Value(item)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+		}
+		return sequence, nil
+	case yamlv3.AliasNode:
+		return yamlV2Value(node.Alias)
+	default:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+// addComments inserts the workflow and step reasons as comment lines above the workflow keys and step list items.
+func addComments(data []byte, layout configLayout) ([]byte, error) {
+	if len(layout.WorkflowReasons) == 0 && len(layout.StepReasons) == 0 {
+		return data, nil
+	}
+
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return data, nil
+	}
+	workflows := mappingValue(document.Content[0], "workflows")
+	if workflows == nil {
+		return data, nil
+	}
+
+	// comments maps the line numbers to the comment lines written above them
+	comments := map[int]string{}
+	for i := 0; i+1 < len(workflows.Content); i += 2 {
+		workflowKey := workflows.Content[i]
+		if reason := layout.WorkflowReasons[workflowKey.Value]; reason != "" {
+			comments[workflowKey.Line] = commentLines(reason, workflowKey.Column-1)
+		}
+
+		stepList := mappingValue(workflows.Content[i+1], "steps")
+		if stepList == nil || stepList.Kind != yamlv3.SequenceNode {
+			continue
+		}
+		for idx, step := range stepList.Content {
+			if reason := layout.StepReasons[workflowKey.Value][idx]; reason != "" {
+				// The column of the step is after the "- " of the list item
+				comments[step.Line] = commentLines(reason, step.Column-3)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	for i, line := range bytes.SplitAfter(data, []byte("\n")) {
+		buf.WriteString(comments[i+1])
+		buf.Write(line)
+	}
+	return buf.Bytes(), nil
+}
+
+// commentLines returns the lines of the text as YAML comment lines, indented by indent spaces.
+func commentLines(text string, indent int) string {
+	var lines strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		lines.WriteString(strings.Repeat(" ", indent) + "# " + line + "\n")
+	}
+	return lines.String()
+}
+
+// UnmarshalConfig parses a bitrise.yml, keeping the order of its workflows and pipelines,
+// and the comments of its workflows and steps.
+func UnmarshalConfig(data []byte) (Config, error) {
+	var model bitriseModels.BitriseDataModel
+	if err := yaml.Unmarshal(data, &model); err != nil {
+		return Config{}, err
+	}
+	config := NewConfig(model)
+
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		return Config{}, err
+	}
+	if len(document.Content) == 0 {
+		return config, nil
+	}
+	root := document.Content[0]
+
+	layout := configLayout{PipelineWorkflows: map[string][]string{}}
+	if pipelines := mappingValue(root, "pipelines"); pipelines != nil {
+		for i := 0; i+1 < len(pipelines.Content); i += 2 {
+			pipelineID := pipelines.Content[i].Value
+			layout.Pipelines = append(layout.Pipelines, pipelineID)
+			layout.PipelineWorkflows[pipelineID] = mappingKeys(mappingValue(pipelines.Content[i+1], "workflows"))
+		}
+	}
+	if workflows := mappingValue(root, "workflows"); workflows != nil {
+		layout.Workflows = mappingKeys(workflows)
+		readComments(workflows, &layout)
+	}
+	config.setLayout(layout)
+
+	return config, nil
+}
+
+// readComments reads the head comments of the workflow keys and step list items as reasons.
+func readComments(workflows *yamlv3.Node, layout *configLayout) {
+	for i := 0; i+1 < len(workflows.Content); i += 2 {
+		workflowID := workflows.Content[i].Value
+		if reason := commentText(workflows.Content[i].HeadComment); reason != "" {
+			setWorkflowReason(layout, workflowID, reason)
+		}
+
+		stepList := mappingValue(workflows.Content[i+1], "steps")
+		if stepList == nil || stepList.Kind != yamlv3.SequenceNode {
+			continue
+		}
+		for idx, step := range stepList.Content {
+			if reason := commentText(step.HeadComment); reason != "" {
+				setStepReason(layout, workflowID, idx, reason)
+			}
+		}
+	}
+}
+
+// commentText strips the comment markers from a yaml.v3 comment.
+func commentText(comment string) string {
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func setWorkflowReason(layout *configLayout, workflowID, reason string) {
+	if layout.WorkflowReasons == nil {
+		layout.WorkflowReasons = map[string]string{}
+	}
+	layout.WorkflowReasons[workflowID] = reason
+}
+
+func setStepReason(layout *configLayout, workflowID string, idx int, reason string) {
+	if layout.StepReasons == nil {
+		layout.StepReasons = map[string]map[int]string{}
+	}
+	if layout.StepReasons[workflowID] == nil {
+		layout.StepReasons[workflowID] = map[int]string{}
+	}
+	layout.StepReasons[workflowID][idx] = reason
+}
+
+// mappingValue returns the value node of the key, or nil if the node is not a mapping or the key is missing.
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func mappingKeys(node *yamlv3.Node) []string {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+// reorderMapping moves the keys listed in order to the front of the mapping, the rest keep their relative order.
+func reorderMapping(node *yamlv3.Node, order []string) {
+	if node.Kind != yamlv3.MappingNode {
+		return
+	}
+
+	rank := func(key string) int {
+		if i := slices.Index(order, key); i >= 0 {
+			return i
+		}
+		return len(order)
+	}
+
+	type pair struct{ key, value *yamlv3.Node }
+	var pairs []pair
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+	}
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		return rank(a.key.Value) - rank(b.key.Value)
+	})
+
+	content := make([]*yamlv3.Node, 0, len(node.Content))
+	for _, p := range pairs {
+		content = append(content, p.key, p.value)
+	}
+	node.Content = content
+}
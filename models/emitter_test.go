@@ -0,0 +1,163 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+const orderedConfigYML = `format_version: "%s"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+project_type: android
+tools:
+  java: "17"
+app:
+  envs:
+  - PROJECT_LOCATION: .
+pipelines:
+  run_tests:
+    workflows:
+      unit_tests: {}
+      instrumented_tests:
+        depends_on:
+        - unit_tests
+workflows:
+  unit_tests:
+    steps:
+    - git-clone@8: {}
+  instrumented_tests:
+    steps:
+    - git-clone@8: {}
+  deploy:
+    summary: Build and deploy
+    steps:
+    - git-clone@8: {}
+`
+
+func newOrderedConfig(t *testing.T) Config {
+	builder := NewDefaultConfigBuilder()
+	for _, workflow := range []WorkflowID{"unit_tests", "instrumented_tests", DeployWorkflowID} {
+		builder.AppendStepListItemsTo(workflow, bitriseModels.StepListItemModel{"git-clone@8": stepmanModels.StepModel{}})
+	}
+	builder.SetWorkflowSummaryTo(DeployWorkflowID, "Build and deploy")
+	builder.SetGraphPipelineWorkflowTo("run_tests", "unit_tests", bitriseModels.GraphPipelineWorkflowModel{})
+	builder.SetGraphPipelineWorkflowTo("run_tests", "instrumented_tests", bitriseModels.GraphPipelineWorkflowModel{DependsOn: []string{"unit_tests"}})
+	builder.AddTool("java", "17")
+
+	config, err := builder.Generate("android", envmanModels.EnvironmentItemModel{"PROJECT_LOCATION": "."})
+	require.NoError(t, err)
+	return config
+}
+
+func TestMarshalConfig(t *testing.T) {
+	expected := fmt.Sprintf(orderedConfigYML, FormatVersion)
+
+	content, err := MarshalConfig(newOrderedConfig(t))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(content))
+
+	// the order survives parsing the bitrise.yml
+	config, err := UnmarshalConfig(content)
+	require.NoError(t, err)
+	content, err = MarshalConfig(config)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(content))
+}
+
+func TestMarshalConfig_defaultOrder(t *testing.T) {
+	config := NewConfig(bitriseModels.BitriseDataModel{
+		FormatVersion: "13",
+		Workflows: map[string]bitriseModels.WorkflowModel{
+			"primary": {},
+			"deploy":  {},
+		},
+		Meta: map[string]interface{}{"stack": "linux-ubuntu-22.04"},
+	})
+
+	content, err := MarshalConfig(config)
+	require.NoError(t, err)
+	require.Equal(t, `format_version: "13"
+project_type: ""
+meta:
+  stack: linux-ubuntu-22.04
+workflows:
+  deploy: {}
+  primary: {}
+`, string(content))
+
+	parsed, err := UnmarshalConfig(content)
+	require.NoError(t, err)
+	require.Equal(t, config, parsed)
+}
+
+func TestMarshalConfigWithComments(t *testing.T) {
+	builder := NewDefaultConfigBuilder()
+	builder.SetStepReasons(map[string]string{"restore-cache": "Restores the cache."})
+	builder.SetWorkflowReasonTo(PrimaryWorkflowID, "Runs the tests.")
+	builder.AppendStepListItemsTo(PrimaryWorkflowID,
+		bitriseModels.StepListItemModel{"git-clone@8": stepmanModels.StepModel{}},
+		bitriseModels.StepListItemModel{"restore-cache@2": stepmanModels.StepModel{}},
+	)
+	builder.AppendStepListItemsWithReasonTo(PrimaryWorkflowID, "Runs the test script.", bitriseModels.StepListItemModel{"script@1": stepmanModels.StepModel{}})
+	builder.AppendStepListItemsTo(DeployWorkflowID, bitriseModels.StepListItemModel{"git-clone@8": stepmanModels.StepModel{}})
+
+	config, err := builder.Generate("other")
+	require.NoError(t, err)
+
+	expected := fmt.Sprintf(`format_version: "%s"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+project_type: other
+workflows:
+  # Runs the tests.
+  primary:
+    steps:
+    - git-clone@8: {}
+    # Restores the cache.
+    - restore-cache@2: {}
+    # Runs the test script.
+    - script@1: {}
+  deploy:
+    steps:
+    - git-clone@8: {}
+`, FormatVersion)
+
+	content, err := MarshalConfigWithComments(config)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(content))
+
+	// the comments survive parsing the bitrise.yml
+	parsed, err := UnmarshalConfig(content)
+	require.NoError(t, err)
+	content, err = MarshalConfigWithComments(parsed)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(content))
+
+	content, err = MarshalConfig(config)
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "#")
+}
+
+func TestConfig_layoutNotInDataModel(t *testing.T) {
+	config := newOrderedConfig(t)
+	require.Nil(t, config.Meta)
+
+	// the config is serialized as the bitrise.yml of its data model
+	data, err := yaml.Marshal(config)
+	require.NoError(t, err)
+	var content string
+	require.NoError(t, yaml.Unmarshal(data, &content))
+	var parsed bitriseModels.BitriseDataModel
+	require.NoError(t, yaml.Unmarshal([]byte(content), &parsed))
+
+	parsedData, err := yaml.Marshal(parsed)
+	require.NoError(t, err)
+	modelData, err := yaml.Marshal(config.BitriseDataModel)
+	require.NoError(t, err)
+	require.Equal(t, string(modelData), string(parsedData))
+}
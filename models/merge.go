@@ -0,0 +1,544 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+)
+
+const (
+	// MergedProjectType is the project type of the configs merged from the configs of several platforms.
+	MergedProjectType = "other"
+	// MergedTestPipelineID is the pipeline of a merged config running the pull request workflows of every platform in parallel.
+	MergedTestPipelineID PipelineID = "run_all_tests"
+	// MergedBuildPipelineID is the pipeline of a merged config running the default branch workflows of every platform in parallel.
+	MergedBuildPipelineID PipelineID = "build_all"
+
+	mergedTestPipelineDescription  = "Runs the tests of every platform in parallel."
+	mergedBuildPipelineDescription = "Runs the builds of every platform in parallel."
+)
+
+var (
+	envNamespaceInvalidCharsRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	envReferenceRegexp             = regexp.MustCompile(`\$(\{[A-Za-z_][A-Za-z0-9_]*\}|[A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// ConfigPart is a config merged by MergeConfigs, its Namespace (usually the scanner name) prefixes the workflow and pipeline IDs.
+type ConfigPart struct {
+	Namespace string
+	Config    Config
+}
+
+// MergeConfigs combines the configs of several platforms into one config.
+//
+// The workflow and pipeline IDs are prefixed with the namespace of their part (run_tests of android becomes android_run_tests),
+// the app envs defined differently by more parts too (PROJECT_ROOT_DIR of java becomes JAVA_PROJECT_ROOT_DIR),
+// the references to the renamed envs are updated in the step inputs and the envs.
+// The tools of the parts move to their workflows, the steps starting every workflow (like git-clone) move to the shared
+// SetupWorkflowID utility workflow.
+// The trigger maps of the parts are replaced by the MergedTestPipelineID and MergedBuildPipelineID pipelines,
+// running the workflows triggered by pull requests and by pushes in the parts in parallel.
+func MergeConfigs(parts []ConfigPart) (Config, error) {
+	if len(parts) == 0 {
+		return Config{}, errors.New("no configs to merge")
+	}
+
+	var namespaces []string
+	for _, part := range parts {
+		if part.Namespace == "" {
+			return Config{}, errors.New("config namespace not set")
+		}
+		if slices.Contains(namespaces, part.Namespace) {
+			return Config{}, fmt.Errorf("config namespace (%s) is not unique", part.Namespace)
+		}
+		namespaces = append(namespaces, part.Namespace)
+	}
+
+	merger := newConfigMerger(parts)
+	for _, part := range parts {
+		if err := merger.addPart(part); err != nil {
+			return Config{}, fmt.Errorf("failed to merge %s config: %w", part.Namespace, err)
+		}
+	}
+	return merger.generate(), nil
+}
+
+// configMerger collects the renamed contents of the merged configs.
+type configMerger struct {
+	config          bitriseModels.BitriseDataModel
+	layout          configLayout
+	conflictingEnvs map[string]bool
+	containerParts  map[string]string
+	testWorkflows   pipelineWorkflows
+	buildWorkflows  pipelineWorkflows
+	pushBranch      interface{}
+}
+
+// pipelineWorkflows are the workflows of a merged pipeline in their order.
+type pipelineWorkflows struct {
+	order     []string
+	workflows bitriseModels.GraphPipelineWorkflowListItemModel
+}
+
+func (p *pipelineWorkflows) add(workflowID string, workflow bitriseModels.GraphPipelineWorkflowModel) {
+	if p.workflows == nil {
+		p.workflows = bitriseModels.GraphPipelineWorkflowListItemModel{}
+	}
+	if _, ok := p.workflows[workflowID]; ok {
+		return
+	}
+	p.order = append(p.order, workflowID)
+	p.workflows[workflowID] = workflow
+}
+
+func newConfigMerger(parts []ConfigPart) *configMerger {
+	return &configMerger{
+		config: bitriseModels.BitriseDataModel{
+			FormatVersion:        parts[0].Config.FormatVersion,
+			DefaultStepLibSource: parts[0].Config.DefaultStepLibSource,
+			ProjectType:          MergedProjectType,
+			Workflows:            map[string]bitriseModels.WorkflowModel{},
+			Pipelines:            map[string]bitriseModels.PipelineModel{},
+		},
+		layout:          configLayout{PipelineWorkflows: map[string][]string{}},
+		conflictingEnvs: conflictingAppEnvKeys(parts),
+		containerParts:  map[string]string{},
+		pushBranch:      DefaultBranch,
+	}
+}
+
+// conflictingAppEnvKeys returns the keys of the app envs defined differently by more configs.
+func conflictingAppEnvKeys(parts []ConfigPart) map[string]bool {
+	envsByKey := map[string][]envmanModels.EnvironmentItemModel{}
+	for _, part := range parts {
+		for _, env := range part.Config.App.Environments {
+			if key := envKey(env); key != "" {
+				envsByKey[key] = append(envsByKey[key], env)
+			}
+		}
+	}
+
+	conflicting := map[string]bool{}
+	for key, envs := range envsByKey {
+		for _, env := range envs[1:] {
+			if !reflect.DeepEqual(env, envs[0]) {
+				conflicting[key] = true
+			}
+		}
+	}
+	return conflicting
+}
+
+func (merger *configMerger) addPart(part ConfigPart) error {
+	renamer := newPartRenamer(part, merger.conflictingEnvs)
+
+	for _, env := range part.Config.App.Environments {
+		env = renamer.env(env)
+		if slices.ContainsFunc(merger.config.App.Environments, func(existing envmanModels.EnvironmentItemModel) bool {
+			return reflect.DeepEqual(existing, env)
+		}) {
+			continue
+		}
+		merger.config.App.Environments = append(merger.config.App.Environments, env)
+	}
+
+	for _, name := range sortedKeys(part.Config.Containers) {
+		container := renamer.container(part.Config.Containers[name])
+		if existing, ok := merger.config.Containers[name]; ok {
+			if !reflect.DeepEqual(existing, container) {
+				return fmt.Errorf("container %s is defined differently in the %s config", name, merger.containerParts[name])
+			}
+			continue
+		}
+		if merger.config.Containers == nil {
+			merger.config.Containers = map[string]bitriseModels.Container{}
+		}
+		merger.config.Containers[name] = container
+		merger.containerParts[name] = part.Namespace
+	}
+
+	partLayout := part.Config.layout
+	for _, workflowID := range orderedKeys(part.Config.Workflows, partLayout.Workflows) {
+		renamedID := renamer.workflowID(workflowID)
+		merger.config.Workflows[renamedID] = renamer.workflow(part.Config.Workflows[workflowID], part.Config.Tools)
+		merger.layout.Workflows = append(merger.layout.Workflows, renamedID)
+
+		if reason := partLayout.WorkflowReasons[workflowID]; reason != "" {
+			setWorkflowReason(&merger.layout, renamedID, reason)
+		}
+		for idx, reason := range partLayout.StepReasons[workflowID] {
+			setStepReason(&merger.layout, renamedID, idx, reason)
+		}
+	}
+
+	for _, pipelineID := range orderedKeys(part.Config.Pipelines, partLayout.Pipelines) {
+		pipeline := part.Config.Pipelines[pipelineID]
+		renamedID := renamer.pipelineID(pipelineID)
+
+		workflows := bitriseModels.GraphPipelineWorkflowListItemModel{}
+		for workflowID, workflow := range pipeline.Workflows {
+			workflows[renamer.workflowID(workflowID)] = renamer.graphPipelineWorkflow(workflow)
+		}
+		pipeline.Workflows = workflows
+		merger.config.Pipelines[renamedID] = pipeline
+
+		merger.layout.Pipelines = append(merger.layout.Pipelines, renamedID)
+		for _, workflowID := range orderedKeys(part.Config.Pipelines[pipelineID].Workflows, partLayout.PipelineWorkflows[pipelineID]) {
+			merger.layout.PipelineWorkflows[renamedID] = append(merger.layout.PipelineWorkflows[renamedID], renamer.workflowID(workflowID))
+		}
+	}
+
+	for _, item := range part.Config.TriggerMap {
+		var target *pipelineWorkflows
+		switch item.Type {
+		case bitriseModels.PullRequestType:
+			target = &merger.testWorkflows
+		case bitriseModels.CodePushType:
+			target = &merger.buildWorkflows
+			if len(merger.buildWorkflows.order) == 0 {
+				merger.pushBranch = item.PushBranch
+			}
+		default:
+			continue
+		}
+
+		if item.WorkflowID != "" {
+			target.add(renamer.workflowID(item.WorkflowID), bitriseModels.GraphPipelineWorkflowModel{})
+		} else if pipeline, ok := part.Config.Pipelines[item.PipelineID]; ok {
+			for _, workflowID := range orderedKeys(pipeline.Workflows, partLayout.PipelineWorkflows[item.PipelineID]) {
+				target.add(renamer.workflowID(workflowID), renamer.graphPipelineWorkflow(pipeline.Workflows[workflowID]))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (merger *configMerger) generate() Config {
+	merger.extractSharedSetup()
+	merger.removeDuplicateUtilityWorkflows()
+
+	var pipelineOrder []string
+	if len(merger.testWorkflows.order) > 0 {
+		merger.addMergedPipeline(MergedTestPipelineID, mergedTestPipelineDescription, merger.testWorkflows)
+		pipelineOrder = append(pipelineOrder, string(MergedTestPipelineID))
+		merger.config.TriggerMap = append(merger.config.TriggerMap, bitriseModels.TriggerMapItemModel{
+			Type:                    bitriseModels.PullRequestType,
+			PullRequestSourceBranch: "*",
+			PipelineID:              string(MergedTestPipelineID),
+		})
+	}
+	if len(merger.buildWorkflows.order) > 0 {
+		merger.addMergedPipeline(MergedBuildPipelineID, mergedBuildPipelineDescription, merger.buildWorkflows)
+		pipelineOrder = append(pipelineOrder, string(MergedBuildPipelineID))
+		merger.config.TriggerMap = append(merger.config.TriggerMap, bitriseModels.TriggerMapItemModel{
+			Type:       bitriseModels.CodePushType,
+			PushBranch: merger.pushBranch,
+			PipelineID: string(MergedBuildPipelineID),
+		})
+	}
+	merger.layout.Pipelines = append(pipelineOrder, merger.layout.Pipelines...)
+
+	if len(merger.config.Pipelines) == 0 {
+		merger.config.Pipelines = nil
+	}
+
+	config := NewConfig(merger.config)
+	config.setLayout(merger.layout)
+	return config
+}
+
+func (merger *configMerger) addMergedPipeline(pipelineID PipelineID, description string, workflows pipelineWorkflows) {
+	merger.config.Pipelines[string(pipelineID)] = bitriseModels.PipelineModel{
+		Description: description,
+		Workflows:   workflows.workflows,
+	}
+	merger.layout.PipelineWorkflows[string(pipelineID)] = workflows.order
+}
+
+// extractSharedSetup moves the steps starting every workflow, which has no before run workflows, into the SetupWorkflowID workflow.
+func (merger *configMerger) extractSharedSetup() {
+	var workflowIDs []string
+	for _, workflowID := range merger.layout.Workflows {
+		workflow := merger.config.Workflows[workflowID]
+		if !strings.HasPrefix(workflowID, "_") && len(workflow.BeforeRun) == 0 {
+			workflowIDs = append(workflowIDs, workflowID)
+		}
+	}
+	if len(workflowIDs) < 2 {
+		return
+	}
+	if _, ok := merger.config.Workflows[string(SetupWorkflowID)]; ok {
+		return
+	}
+
+	shared := merger.config.Workflows[workflowIDs[0]].Steps
+	for _, workflowID := range workflowIDs[1:] {
+		steps := merger.config.Workflows[workflowID].Steps
+		n := 0
+		for n < len(shared) && n < len(steps) && reflect.DeepEqual(shared[n], steps[n]) {
+			n++
+		}
+		shared = shared[:n]
+	}
+	if len(shared) == 0 {
+		return
+	}
+
+	setupID := string(SetupWorkflowID)
+	merger.config.Workflows[setupID] = bitriseModels.WorkflowModel{Steps: shared}
+	setWorkflowReason(&merger.layout, setupID, SetupWorkflowReason)
+	for idx, reason := range merger.layout.StepReasons[workflowIDs[0]] {
+		if idx < len(shared) {
+			setStepReason(&merger.layout, setupID, idx, reason)
+		}
+	}
+
+	for _, workflowID := range workflowIDs {
+		workflow := merger.config.Workflows[workflowID]
+		workflow.Steps = workflow.Steps[len(shared):]
+		workflow.BeforeRun = []string{setupID}
+		merger.config.Workflows[workflowID] = workflow
+
+		stepReasons := merger.layout.StepReasons[workflowID]
+		delete(merger.layout.StepReasons, workflowID)
+		for idx, reason := range stepReasons {
+			if idx >= len(shared) {
+				setStepReason(&merger.layout, workflowID, idx-len(shared), reason)
+			}
+		}
+	}
+	merger.layout.Workflows = append([]string{setupID}, merger.layout.Workflows...)
+}
+
+// removeDuplicateUtilityWorkflows replaces the utility workflows with the first utility workflow of the same content,
+// like the setup workflows of the merged configs.
+func (merger *configMerger) removeDuplicateUtilityWorkflows() {
+	replacements := map[string]string{}
+	var kept []string
+	for _, workflowID := range merger.layout.Workflows {
+		if !strings.HasPrefix(workflowID, "_") {
+			continue
+		}
+		workflow := merger.config.Workflows[workflowID]
+		idx := slices.IndexFunc(kept, func(keptID string) bool {
+			return reflect.DeepEqual(merger.config.Workflows[keptID], workflow)
+		})
+		if idx == -1 {
+			kept = append(kept, workflowID)
+			continue
+		}
+		replacements[workflowID] = kept[idx]
+	}
+	if len(replacements) == 0 {
+		return
+	}
+
+	replace := func(workflowIDs []string) []string {
+		var replaced []string
+		for _, workflowID := range workflowIDs {
+			if replacement, ok := replacements[workflowID]; ok {
+				workflowID = replacement
+			}
+			if !slices.Contains(replaced, workflowID) {
+				replaced = append(replaced, workflowID)
+			}
+		}
+		return replaced
+	}
+	for workflowID, workflow := range merger.config.Workflows {
+		workflow.BeforeRun = replace(workflow.BeforeRun)
+		workflow.AfterRun = replace(workflow.AfterRun)
+		merger.config.Workflows[workflowID] = workflow
+	}
+	for workflowID := range replacements {
+		delete(merger.config.Workflows, workflowID)
+		delete(merger.layout.WorkflowReasons, workflowID)
+		delete(merger.layout.StepReasons, workflowID)
+	}
+	merger.layout.Workflows = slices.DeleteFunc(merger.layout.Workflows, func(workflowID string) bool {
+		_, ok := replacements[workflowID]
+		return ok
+	})
+}
+
+// partRenamer renames the workflows, pipelines and conflicting app envs of a merged config.
+type partRenamer struct {
+	namespace    string
+	envNamespace string
+	renamedEnvs  map[string]string
+}
+
+func newPartRenamer(part ConfigPart, conflictingEnvs map[string]bool) partRenamer {
+	envNamespace := strings.Trim(envNamespaceInvalidCharsRegexp.ReplaceAllString(strings.ToUpper(part.Namespace), "_"), "_")
+	renamedEnvs := map[string]string{}
+	for _, env := range part.Config.App.Environments {
+		if key := envKey(env); conflictingEnvs[key] {
+			renamedEnvs[key] = envNamespace + "_" + key
+		}
+	}
+
+	return partRenamer{
+		namespace:    strings.Trim(workflowIDInvalidCharsRegexp.ReplaceAllString(part.Namespace, "_"), "_"),
+		envNamespace: envNamespace,
+		renamedEnvs:  renamedEnvs,
+	}
+}
+
+// workflowID prefixes the workflow ID with the namespace, utility workflows keep their leading underscore (_setup becomes _android_setup).
+func (renamer partRenamer) workflowID(workflowID string) string {
+	if strings.HasPrefix(workflowID, "_") {
+		return "_" + renamer.namespace + workflowID
+	}
+	return renamer.namespace + "_" + workflowID
+}
+
+func (renamer partRenamer) workflowIDs(workflowIDs []string) []string {
+	var renamed []string
+	for _, workflowID := range workflowIDs {
+		renamed = append(renamed, renamer.workflowID(workflowID))
+	}
+	return renamed
+}
+
+func (renamer partRenamer) pipelineID(pipelineID string) string {
+	return renamer.namespace + "_" + pipelineID
+}
+
+// workflow renames the references of the workflow, the tools of the config are added to the workflow's tools.
+func (renamer partRenamer) workflow(workflow bitriseModels.WorkflowModel, configTools bitriseModels.ToolsModel) bitriseModels.WorkflowModel {
+	workflow.BeforeRun = renamer.workflowIDs(workflow.BeforeRun)
+	workflow.AfterRun = renamer.workflowIDs(workflow.AfterRun)
+	workflow.Environments = renamer.envs(workflow.Environments)
+
+	var steps []bitriseModels.StepListItemModel
+	for _, item := range workflow.Steps {
+		steps = append(steps, renamer.stepListItem(item))
+	}
+	workflow.Steps = steps
+
+	if len(configTools) > 0 {
+		tools := bitriseModels.ToolsModel{}
+		for id, version := range configTools {
+			tools[id] = version
+		}
+		for id, version := range workflow.Tools {
+			tools[id] = version
+		}
+		workflow.Tools = tools
+	}
+	return workflow
+}
+
+func (renamer partRenamer) graphPipelineWorkflow(workflow bitriseModels.GraphPipelineWorkflowModel) bitriseModels.GraphPipelineWorkflowModel {
+	workflow.DependsOn = renamer.workflowIDs(workflow.DependsOn)
+	if workflow.Uses != "" {
+		workflow.Uses = renamer.workflowID(workflow.Uses)
+	}
+	return workflow
+}
+
+func (renamer partRenamer) stepListItem(item bitriseModels.StepListItemModel) bitriseModels.StepListItemModel {
+	renamed := bitriseModels.StepListItemModel{}
+	for key, value := range item {
+		if step, ok := value.(stepmanModels.StepModel); ok {
+			step.Inputs = renamer.envs(step.Inputs)
+			value = step
+		}
+		renamed[key] = value
+	}
+	return renamed
+}
+
+func (renamer partRenamer) container(container bitriseModels.Container) bitriseModels.Container {
+	container.Envs = renamer.envs(container.Envs)
+	return container
+}
+
+func (renamer partRenamer) envs(envs []envmanModels.EnvironmentItemModel) []envmanModels.EnvironmentItemModel {
+	if envs == nil {
+		return nil
+	}
+	renamed := make([]envmanModels.EnvironmentItemModel, 0, len(envs))
+	for _, env := range envs {
+		renamed = append(renamed, renamer.env(env))
+	}
+	return renamed
+}
+
+// env renames the conflicting env key and the references to the conflicting envs in the value.
+func (renamer partRenamer) env(env envmanModels.EnvironmentItemModel) envmanModels.EnvironmentItemModel {
+	renamed := envmanModels.EnvironmentItemModel{}
+	for key, value := range env {
+		if key != envmanModels.OptionsKey {
+			if renamedKey, ok := renamer.renamedEnvs[key]; ok {
+				key = renamedKey
+			}
+			if str, ok := value.(string); ok {
+				value = renamer.envReferences(str)
+			}
+		}
+		renamed[key] = value
+	}
+	return renamed
+}
+
+func (renamer partRenamer) envReferences(value string) string {
+	if len(renamer.renamedEnvs) == 0 {
+		return value
+	}
+	return envReferenceRegexp.ReplaceAllStringFunc(value, func(reference string) string {
+		name := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(reference, "$"), "{"), "}")
+		renamed, ok := renamer.renamedEnvs[name]
+		if !ok {
+			return reference
+		}
+		if strings.HasPrefix(reference, "${") {
+			return "${" + renamed + "}"
+		}
+		return "$" + renamed
+	})
+}
+
+// envKey returns the key of the env, or an empty string if the env is invalid.
+func envKey(env envmanModels.EnvironmentItemModel) string {
+	for key := range env {
+		if key != envmanModels.OptionsKey {
+			return key
+		}
+	}
+	return ""
+}
+
+// orderedKeys returns the keys of m in the recorded order, the keys without a recorded order are appended in alphabetical order.
+func orderedKeys[V any](m map[string]V, order []string) []string {
+	var keys []string
+	for _, key := range order {
+		if _, ok := m[key]; ok && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range sortedKeys(m) {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"testing"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func mergeTestStep(id string, inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	return bitriseModels.StepListItemModel{id: stepmanModels.StepModel{Inputs: inputs}}
+}
+
+func TestMergeConfigs(t *testing.T) {
+	gitClone := mergeTestStep("git-clone@8")
+
+	kmp := NewDefaultConfigBuilder()
+	kmp.AppendStepListItemsWithReasonTo("run_tests", "Clones the repository.", gitClone)
+	kmp.AppendStepListItemsWithReasonTo("run_tests", "Runs the tests.", mergeTestStep("gradle-unit-test@1", envmanModels.EnvironmentItemModel{"project_root_dir": "$PROJECT_ROOT_DIR"}))
+	kmp.AppendStepListItemsTo("android_build", gitClone, mergeTestStep("android-build@1", envmanModels.EnvironmentItemModel{"project_location": "${PROJECT_ROOT_DIR}/android"}))
+	kmp.AppendStepListItemsTo("ios_build", gitClone, mergeTestStep("xcode-archive@5"))
+	kmp.SetGraphPipelineWorkflowTo("build", "android_build", bitriseModels.GraphPipelineWorkflowModel{})
+	kmp.SetGraphPipelineWorkflowTo("build", "ios_build", bitriseModels.GraphPipelineWorkflowModel{})
+	kmp.AddPullRequestTriggerToWorkflow("run_tests")
+	kmp.AddPushTriggerToPipeline("build")
+	kmpConfig, err := kmp.Generate("kotlin-multiplatform", envmanModels.EnvironmentItemModel{"PROJECT_ROOT_DIR": "."}, envmanModels.EnvironmentItemModel{"GRADLEW_PATH": "./gradlew"})
+	require.NoError(t, err)
+	SetDefaultBranch(&kmpConfig.BitriseDataModel, "develop")
+
+	java := NewDefaultConfigBuilder()
+	java.AppendStepListItemsTo("run_tests", gitClone, mergeTestStep("script@1", envmanModels.EnvironmentItemModel{"content": "cd $PROJECT_ROOT_DIR && $GRADLEW_PATH test"}))
+	java.AddTool("java", "17")
+	java.AddPullRequestTriggerToWorkflow("run_tests")
+	java.AddPushTriggerToWorkflow("run_tests")
+	javaConfig, err := java.Generate("java", envmanModels.EnvironmentItemModel{"PROJECT_ROOT_DIR": "backend"}, envmanModels.EnvironmentItemModel{"GRADLEW_PATH": "./gradlew"})
+	require.NoError(t, err)
+
+	nodejs := NewDefaultConfigBuilder()
+	nodejs.AppendStepListItemsTo(SetupWorkflowID, gitClone)
+	nodejs.SetWorkflowReasonTo(SetupWorkflowID, SetupWorkflowReason)
+	nodejs.SetWorkflowBeforeRunTo("run_tests_web", SetupWorkflowID)
+	nodejs.AppendStepListItemsTo("run_tests_web", mergeTestStep("npm@1"))
+	nodejs.AddChangedFilesPullRequestTriggerTo("run_tests_web", "web/**")
+	nodejsConfig, err := nodejs.Generate("node-js")
+	require.NoError(t, err)
+
+	merged, err := MergeConfigs([]ConfigPart{
+		{Namespace: "kotlin-multiplatform", Config: kmpConfig},
+		{Namespace: "java", Config: javaConfig},
+		{Namespace: "node-js", Config: nodejsConfig},
+	})
+	require.NoError(t, err)
+	_, err = merged.Validate()
+	require.NoError(t, err)
+
+	require.Equal(t, MergedProjectType, merged.ProjectType)
+	require.Equal(t, []envmanModels.EnvironmentItemModel{
+		{"KOTLIN_MULTIPLATFORM_PROJECT_ROOT_DIR": "."},
+		{"GRADLEW_PATH": "./gradlew"},
+		{"JAVA_PROJECT_ROOT_DIR": "backend"},
+	}, merged.App.Environments)
+
+	// the shared git-clone step moved to the setup workflow, the setup workflow of the node-js config is the same
+	layout := merged.layout
+	require.Equal(t, []string{"_setup", "kotlin-multiplatform_run_tests", "kotlin-multiplatform_android_build", "kotlin-multiplatform_ios_build", "java_run_tests", "node-js_run_tests_web"}, layout.Workflows)
+	require.Equal(t, []bitriseModels.StepListItemModel{gitClone}, merged.Workflows["_setup"].Steps)
+	require.Equal(t, "Clones the repository.", layout.StepReasons["_setup"][0])
+	require.Equal(t, "Runs the tests.", layout.StepReasons["kotlin-multiplatform_run_tests"][0])
+	for _, workflowID := range layout.Workflows[1:] {
+		require.Equal(t, []string{"_setup"}, merged.Workflows[workflowID].BeforeRun, workflowID)
+	}
+
+	// the references of the renamed envs are updated
+	require.Equal(t, []bitriseModels.StepListItemModel{
+		mergeTestStep("android-build@1", envmanModels.EnvironmentItemModel{"project_location": "${KOTLIN_MULTIPLATFORM_PROJECT_ROOT_DIR}/android"}),
+	}, merged.Workflows["kotlin-multiplatform_android_build"].Steps)
+	require.Equal(t, []bitriseModels.StepListItemModel{
+		mergeTestStep("script@1", envmanModels.EnvironmentItemModel{"content": "cd $JAVA_PROJECT_ROOT_DIR && $GRADLEW_PATH test"}),
+	}, merged.Workflows["java_run_tests"].Steps)
+	require.Equal(t, bitriseModels.ToolsModel{"java": "17"}, merged.Workflows["java_run_tests"].Tools)
+	require.Equal(t, "web/**", merged.Workflows["node-js_run_tests_web"].Triggers.PullRequestTriggers[0].ChangedFiles)
+
+	require.Equal(t, []string{"run_all_tests", "build_all", "kotlin-multiplatform_build"}, layout.Pipelines)
+	require.Equal(t, []string{"kotlin-multiplatform_run_tests", "java_run_tests"}, layout.PipelineWorkflows["run_all_tests"])
+	require.Equal(t, []string{"kotlin-multiplatform_android_build", "kotlin-multiplatform_ios_build", "java_run_tests"}, layout.PipelineWorkflows["build_all"])
+	require.Equal(t, bitriseModels.TriggerMapModel{
+		{Type: bitriseModels.PullRequestType, PipelineID: "run_all_tests", PullRequestSourceBranch: "*"},
+		{Type: bitriseModels.CodePushType, PipelineID: "build_all", PushBranch: "develop"},
+	}, merged.TriggerMap)
+
+	// the merged configs are not changed
+	require.Equal(t, "$PROJECT_ROOT_DIR", kmpConfig.Workflows["run_tests"].Steps[1]["gradle-unit-test@1"].(stepmanModels.StepModel).Inputs[0]["project_root_dir"])
+	require.Len(t, javaConfig.Workflows["run_tests"].Steps, 2)
+}
+
+func TestMergeConfigs_errors(t *testing.T) {
+	config := func(container bitriseModels.Container) Config {
+		return NewConfig(bitriseModels.BitriseDataModel{
+			Containers: map[string]bitriseModels.Container{"postgres": container},
+			Workflows:  map[string]bitriseModels.WorkflowModel{"run_tests": {}},
+		})
+	}
+
+	_, err := MergeConfigs(nil)
+	require.EqualError(t, err, "no configs to merge")
+
+	_, err = MergeConfigs([]ConfigPart{{Namespace: "ruby", Config: config(bitriseModels.Container{})}, {Namespace: "ruby", Config: config(bitriseModels.Container{})}})
+	require.EqualError(t, err, "config namespace (ruby) is not unique")
+
+	_, err = MergeConfigs([]ConfigPart{
+		{Namespace: "ruby", Config: config(bitriseModels.Container{Image: "postgres:18"})},
+		{Namespace: "python", Config: config(bitriseModels.Container{Image: "postgres:17"})},
+	})
+	require.EqualError(t, err, "failed to merge python config: container postgres is defined differently in the ruby config")
+}
@@ -390,3 +390,102 @@ func TestRemoveConfigs(t *testing.T) {
 	}
 }`, option.String())
 }
+
+func TestWithoutConfigs(t *testing.T) {
+	optionJSON := `{
+	"title": "Project (or Workspace) path",
+	"env_key": "BITRISE_PROJECT_PATH",
+	"default_value": "BitriseTest.xcodeproj",
+	"value_map": {
+		"BitriseTest.xcodeproj": {
+			"title": "Scheme name",
+			"env_key": "BITRISE_SCHEME",
+			"value_map": {
+				"BitriseTest": {
+					"config": "ios-test-config"
+				}
+			}
+		},
+		"Other.xcodeproj": {
+			"title": "Scheme name",
+			"env_key": "BITRISE_SCHEME",
+			"value_map": {
+				"Other": {
+					"config": "ios-other-config"
+				}
+			}
+		}
+	},
+	"value_infos": {
+		"BitriseTest.xcodeproj": {
+			"recommended": true
+		}
+	}
+}`
+
+	var option OptionNode
+	require.NoError(t, json.Unmarshal([]byte(optionJSON), &option))
+
+	pruned := option.WithoutConfigs("ios-test-config")
+
+	require.Equal(t, `{
+	"title": "Project (or Workspace) path",
+	"env_key": "BITRISE_PROJECT_PATH",
+	"value_map": {
+		"Other.xcodeproj": {
+			"title": "Scheme name",
+			"env_key": "BITRISE_SCHEME",
+			"value_map": {
+				"Other": {
+					"config": "ios-other-config"
+				}
+			}
+		}
+	}
+}`, pruned.String())
+	// the original tree is kept
+	require.Len(t, option.ChildOptionMap, 2)
+
+	require.Nil(t, option.WithoutConfigs("ios-test-config", "ios-other-config"))
+}
+
+func TestMultiSelectKey(t *testing.T) {
+	require.Equal(t, "", MultiSelectKey())
+	require.Equal(t, "android,ios", MultiSelectKey("ios", "android"))
+	require.Equal(t, "android,ios,web", MultiSelectKey(" web, ios ", "android,ios", ""))
+}
+
+func TestMultiSelectorOption(t *testing.T) {
+	option := NewOption("Platforms", "", "PLATFORMS", TypeMultiSelector)
+	option.AddConfig("", NewConfigOption("test-config", nil))
+	option.AddConfig("ios, android", NewConfigOption("both-config", nil))
+	exportOption := NewOption("Export method", "", "EXPORT_METHOD", TypeSelector)
+	option.AddOption("ios", exportOption)
+	exportOption.AddConfig("app-store", NewConfigOption("ios-config", nil))
+
+	require.Equal(t, []string{"android", "ios"}, option.Values)
+	require.Equal(t, []string{"", "android,ios", "ios"}, sortedKeys(option.ChildOptionMap))
+	require.Equal(t, option.Values, option.Copy().Values)
+
+	// the option is a last option next to the branches of the combinations with more options
+	lastOptions := option.LastChilds()
+	require.Len(t, lastOptions, 2)
+	require.ElementsMatch(t, []*OptionNode{option, exportOption}, lastOptions)
+}
+
+func TestSetValueInfo(t *testing.T) {
+	option := NewOption("Scheme", "", "BITRISE_SCHEME", TypeSelector)
+	option.SetValueInfo("App", ValueInfo{Reason: "no tests"})
+	option.SetValueInfo("AppTests", ValueInfo{Recommended: true, Reason: "the scheme has test targets", DetectedFrom: "App.xcodeproj"})
+	option.SetValueInfo("Other", ValueInfo{Recommended: true})
+	require.Equal(t, "AppTests", option.DefaultValue)
+
+	bytes, err := json.Marshal(option)
+	require.NoError(t, err)
+	require.Contains(t, string(bytes), `"default_value":"AppTests","value_infos":{"App":{"reason":"no tests"},"AppTests":{"recommended":true,"reason":"the scheme has test targets","detected_from":"App.xcodeproj"}`)
+
+	platformsOption := NewOption("Platforms", "", "PLATFORMS", TypeMultiSelector)
+	platformsOption.SetValueInfo("ios", ValueInfo{Recommended: true})
+	platformsOption.SetValueInfo("android", ValueInfo{Recommended: true})
+	require.Equal(t, "android,ios", platformsOption.DefaultValue)
+}
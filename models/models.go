@@ -4,8 +4,6 @@ import (
 	"github.com/bitrise-io/go-steputils/step"
 )
 
-type BitriseConfigMap map[string]string
-
 type Warnings []string
 
 type Errors []string
@@ -26,6 +24,7 @@ type ErrorWithRecommendations struct {
 
 type ErrorsWithRecommendations []ErrorWithRecommendations
 
+// ScanResultModel is the result of the scan, its configs are serialized as bitrise.yml strings (see BitriseConfigMap).
 type ScanResultModel struct {
 	ScannerToOptionRoot                  map[string]OptionNode                `json:"options,omitempty" yaml:"options,omitempty"`
 	ScannerToBitriseConfigMap            map[string]BitriseConfigMap          `json:"configs,omitempty" yaml:"configs,omitempty"`
@@ -34,6 +33,26 @@ type ScanResultModel struct {
 	ScannerToErrorsWithRecommendations   map[string]ErrorsWithRecommendations `json:"errors_with_recommendations,omitempty" yaml:"errors_with_recommendations,omitempty"`
 	ScannerToWarningsWithRecommendations map[string]ErrorsWithRecommendations `json:"warnings_with_recommendations,omitempty" yaml:"warnings_with_recommendations,omitempty"`
 	Icons                                []Icon                               `json:"-" yaml:"-"`
+	// ProjectConfig is set if the scanned project has a project config file (.bitrise-init.yml).
+	ProjectConfig *ProjectConfigResult `json:"project_config,omitempty" yaml:"project_config,omitempty"`
+}
+
+// ProjectConfigAnswer is an option value pinned in the project config file.
+type ProjectConfigAnswer struct {
+	Title  string `json:"title,omitempty" yaml:"title,omitempty"`
+	EnvKey string `json:"env_key,omitempty" yaml:"env_key,omitempty"`
+	Value  string `json:"value" yaml:"value"`
+}
+
+// ProjectConfigResult lists the parts of the scan result, which came from the project config file.
+type ProjectConfigResult struct {
+	Path                     string                           `json:"path" yaml:"path"`
+	ExcludedPaths            []string                         `json:"excluded_paths,omitempty" yaml:"excluded_paths,omitempty"`
+	DisabledScanners         []string                         `json:"disabled_scanners,omitempty" yaml:"disabled_scanners,omitempty"`
+	ForcedScanners           []string                         `json:"forced_scanners,omitempty" yaml:"forced_scanners,omitempty"`
+	ScannerToAnswers         map[string][]ProjectConfigAnswer `json:"answers,omitempty" yaml:"answers,omitempty"`
+	ScannerToPreferredConfig map[string]string                `json:"preferred_configs,omitempty" yaml:"preferred_configs,omitempty"`
+	Warnings                 Warnings                         `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 }
 
 type SSHKeyActivation int
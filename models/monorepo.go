@@ -0,0 +1,58 @@
+package models
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MonorepoOptionTitle is the title of the option choosing between the config of a single project and the monorepo config.
+	MonorepoOptionTitle = "Projects"
+	// MonorepoOptionSummary ...
+	MonorepoOptionSummary = "Generate the config of a single project, or the monorepo config covering every project"
+	// SingleProjectOptionValue is the monorepo option value leading to the project directory option of the scanner.
+	SingleProjectOptionValue = "single project"
+	// AllProjectsOptionValue is the monorepo option value selecting the monorepo config of a scanner,
+	// which covers every detected project with its own workflows.
+	AllProjectsOptionValue = "all projects"
+
+	// SetupWorkflowID is the utility workflow of the monorepo configs, holding the steps shared by the project workflows.
+	SetupWorkflowID WorkflowID = "_setup"
+	// SetupWorkflowReason explains the setup workflow of the monorepo configs.
+	SetupWorkflowReason = "Prepares the repository, the project workflows run it before their own steps."
+)
+
+// NewMonorepoOption returns the root option of a scanner detecting several projects:
+// it selects either the monorepo config or a single project with projectOption.
+// The option has no env key, the monorepo configs do not depend on the project directory.
+func NewMonorepoOption(projectOption *OptionNode, monorepoConfigName string) *OptionNode {
+	option := NewOption(MonorepoOptionTitle, MonorepoOptionSummary, "", TypeSelector)
+	option.AddOption(SingleProjectOptionValue, projectOption)
+	option.AddConfig(AllProjectsOptionValue, NewConfigOption(monorepoConfigName, nil))
+	return option
+}
+
+var workflowIDInvalidCharsRegexp = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// ProjectWorkflowID returns the ID of the workflow for the project at projectRelDir in a monorepo config,
+// for example run_tests_packages_web for run_tests and packages/web.
+// The project in the root of the repository gets the workflow ID as is.
+func ProjectWorkflowID(workflow WorkflowID, projectRelDir string) WorkflowID {
+	projectRelDir = path.Clean(projectRelDir)
+	if projectRelDir == "." {
+		return workflow
+	}
+	suffix := strings.Trim(workflowIDInvalidCharsRegexp.ReplaceAllString(projectRelDir, "_"), "_")
+	return WorkflowID(string(workflow) + "_" + suffix)
+}
+
+// ProjectChangedFiles returns the changed files glob matching the files of the project at projectRelDir,
+// used as the trigger condition of the project's workflows in a monorepo config.
+func ProjectChangedFiles(projectRelDir string) string {
+	projectRelDir = path.Clean(projectRelDir)
+	if projectRelDir == "." {
+		return "**"
+	}
+	return projectRelDir + "/**"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectWorkflowID(t *testing.T) {
+	tests := []struct {
+		projectRelDir    string
+		wantWorkflowID   WorkflowID
+		wantChangedFiles string
+	}{
+		{projectRelDir: ".", wantWorkflowID: "run_tests", wantChangedFiles: "**"},
+		{projectRelDir: "web", wantWorkflowID: "run_tests_web", wantChangedFiles: "web/**"},
+		{projectRelDir: "packages/web/", wantWorkflowID: "run_tests_packages_web", wantChangedFiles: "packages/web/**"},
+		{projectRelDir: "apps/@scope/admin panel", wantWorkflowID: "run_tests_apps_scope_admin_panel", wantChangedFiles: "apps/@scope/admin panel/**"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.projectRelDir, func(t *testing.T) {
+			require.Equal(t, tt.wantWorkflowID, ProjectWorkflowID("run_tests", tt.projectRelDir))
+			require.Equal(t, tt.wantChangedFiles, ProjectChangedFiles(tt.projectRelDir))
+		})
+	}
+}
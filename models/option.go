@@ -3,6 +3,10 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
 )
 
 // Type is to select the user interaction type that is required to fill an option
@@ -34,9 +38,18 @@ const (
 	// Now, if this type is selected:
 	// - we must show an input field to the user and it is NOT required to be filled, can be empty, and any name for the key will be the placeholder value for the input field
 	TypeOptionalUserInput Type = "user_input_optional"
+	// If this type is selected:
+	// - the user can select any number of the values listed in Values, for example the platforms to build
+	// - the key of the next option is the selected values, sorted and joined with MultiSelectSeparator (see MultiSelectKey),
+	//   the env var of the option gets the same value
+	// - nothing can be selected only if there is a next option for the empty key
+	TypeMultiSelector Type = "multi_selector"
 
 	// UserInputOptionDefaultValue can be used as a value (forValue) for adding a default new option to a TypeUserInput and TypeOptionalUserInput OptionNode via AddOption or AddConfig.
 	UserInputOptionDefaultValue = ""
+
+	// MultiSelectSeparator separates the selected values of a TypeMultiSelector OptionNode.
+	MultiSelectSeparator = ","
 )
 
 // OptionNode ...
@@ -46,6 +59,14 @@ type OptionNode struct {
 	EnvKey         string                 `json:"env_key,omitempty" yaml:"env_key,omitempty"`
 	Type           Type                   `json:"type,omitempty" yaml:"type,omitempty"`
 	ChildOptionMap map[string]*OptionNode `json:"value_map,omitempty" yaml:"value_map,omitempty"`
+	// Values are the selectable values of a TypeMultiSelector option, in the order they are listed.
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+	// DefaultValue is the answer offered by default: a key of ChildOptionMap for selectors, the prefilled value of user inputs.
+	DefaultValue string `json:"default_value,omitempty" yaml:"default_value,omitempty"`
+	// ValueInfos describe the values the user can pick (the keys of selectors, the Values of multi-selectors), by value.
+	ValueInfos map[string]ValueInfo `json:"value_infos,omitempty" yaml:"value_infos,omitempty"`
+	// Validation restricts the values which can be typed in for user inputs (and optional selectors).
+	Validation *Validation `json:"validation,omitempty" yaml:"validation,omitempty"`
 	// Leafs only
 	Config string   `json:"config,omitempty" yaml:"config,omitempty"`
 	Icons  []string `json:"icons,omitempty" yaml:"icons,omitempty"`
@@ -54,6 +75,16 @@ type OptionNode struct {
 	Head       *OptionNode `json:"-" yaml:"-"`
 }
 
+// ValueInfo is the metadata of a value of an OptionNode.
+type ValueInfo struct {
+	// Recommended is set for the values the scanner suggests to pick.
+	Recommended bool `json:"recommended,omitempty" yaml:"recommended,omitempty"`
+	// Reason tells why the value is recommended.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// DetectedFrom is the path of the file the value was detected in, relative to the scanned directory.
+	DetectedFrom string `json:"detected_from,omitempty" yaml:"detected_from,omitempty"`
+}
+
 // NewOption ...
 func NewOption(title, summary, envKey string, optionType Type) *OptionNode {
 	return &OptionNode{
@@ -101,6 +132,7 @@ func (option *OptionNode) IsEmpty() bool {
 
 // AddOption ...
 func (option *OptionNode) AddOption(forValue string, newOption *OptionNode) {
+	forValue = option.childKey(forValue)
 	option.ChildOptionMap[forValue] = newOption
 
 	if newOption != nil {
@@ -117,6 +149,7 @@ func (option *OptionNode) AddOption(forValue string, newOption *OptionNode) {
 
 // AddConfig ...
 func (option *OptionNode) AddConfig(forValue string, newConfigOption *OptionNode) {
+	forValue = option.childKey(forValue)
 	option.ChildOptionMap[forValue] = newConfigOption
 
 	if newConfigOption != nil {
@@ -131,6 +164,57 @@ func (option *OptionNode) AddConfig(forValue string, newConfigOption *OptionNode
 	}
 }
 
+// MultiSelectKey returns the key of the next option of a TypeMultiSelector option for the selected values:
+// the values are trimmed, deduplicated, sorted and joined with MultiSelectSeparator.
+// A value can be a list of values separated by MultiSelectSeparator too, like an answer typed in by the user.
+func MultiSelectKey(values ...string) string {
+	var selected []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, MultiSelectSeparator) {
+			if item = strings.TrimSpace(item); item != "" && !slices.Contains(selected, item) {
+				selected = append(selected, item)
+			}
+		}
+	}
+	sort.Strings(selected)
+	return strings.Join(selected, MultiSelectSeparator)
+}
+
+// childKey returns the key of a next option: the key of a TypeMultiSelector option is normalized by MultiSelectKey,
+// and the values it selects are added to the Values of the option.
+func (option *OptionNode) childKey(forValue string) string {
+	if option.Type != TypeMultiSelector {
+		return forValue
+	}
+
+	key := MultiSelectKey(forValue)
+	for _, value := range strings.Split(key, MultiSelectSeparator) {
+		if value != "" && !slices.Contains(option.Values, value) {
+			option.Values = append(option.Values, value)
+		}
+	}
+	return key
+}
+
+// SetValueInfo sets the metadata of a value,
+// the first recommended value becomes the DefaultValue of the option unless it has one.
+// The recommended values of a TypeMultiSelector option are all selected by its DefaultValue.
+func (option *OptionNode) SetValueInfo(value string, info ValueInfo) {
+	if option.ValueInfos == nil {
+		option.ValueInfos = map[string]ValueInfo{}
+	}
+	option.ValueInfos[value] = info
+
+	if !info.Recommended {
+		return
+	}
+	if option.Type == TypeMultiSelector {
+		option.DefaultValue = MultiSelectKey(option.DefaultValue, value)
+	} else if option.DefaultValue == "" {
+		option.DefaultValue = value
+	}
+}
+
 // Parent ...
 func (option *OptionNode) Parent() (*OptionNode, string, bool) {
 	if option.Head == nil {
@@ -170,6 +254,22 @@ func (option *OptionNode) LastChilds() []*OptionNode {
 			return
 		}
 
+		if opt.Type == TypeMultiSelector {
+			// the branches of the selectable value combinations usually have different depths
+			collected := false
+			for _, childOption := range opt.ChildOptionMap {
+				if childOption == nil || childOption.IsConfigOption() || childOption.IsEmpty() {
+					if !collected {
+						lastOptions = append(lastOptions, opt)
+						collected = true
+					}
+					continue
+				}
+				walk(childOption)
+			}
+			return
+		}
+
 		for _, childOption := range opt.ChildOptionMap {
 			if childOption == nil {
 				lastOptions = append(lastOptions, opt)
@@ -205,6 +305,50 @@ func (option *OptionNode) RemoveConfigs() {
 	}
 }
 
+// WithoutConfigs returns a copy of the option tree without the leaves selecting the configs,
+// the options left without values are removed too, nil is returned if no config is left.
+// The default value and the value infos of the removed values are dropped.
+func (option *OptionNode) WithoutConfigs(configs ...string) *OptionNode {
+	if option.IsConfigOption() {
+		if slices.Contains(configs, option.Config) {
+			return nil
+		}
+		return option
+	}
+	if len(option.ChildOptionMap) == 0 {
+		return option
+	}
+
+	pruned := *option
+	pruned.ChildOptionMap = map[string]*OptionNode{}
+	for value, child := range option.ChildOptionMap {
+		if child == nil {
+			pruned.ChildOptionMap[value] = nil
+			continue
+		}
+		if prunedChild := child.WithoutConfigs(configs...); prunedChild != nil {
+			pruned.ChildOptionMap[value] = prunedChild
+		}
+	}
+	if len(pruned.ChildOptionMap) == 0 {
+		return nil
+	}
+
+	isRemoved := func(value string) bool {
+		_, wasValue := option.ChildOptionMap[value]
+		_, isValue := pruned.ChildOptionMap[value]
+		return wasValue && !isValue
+	}
+	if isRemoved(pruned.DefaultValue) {
+		pruned.DefaultValue = ""
+	}
+	if len(pruned.ValueInfos) > 0 {
+		pruned.ValueInfos = maps.Clone(pruned.ValueInfos)
+		maps.DeleteFunc(pruned.ValueInfos, func(value string, _ ValueInfo) bool { return isRemoved(value) })
+	}
+	return &pruned
+}
+
 // AttachToLastChilds ...
 func (option *OptionNode) AttachToLastChilds(opt *OptionNode) {
 	childs := option.LastChilds()
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CheckOptionTree checks that the option tree and the configs of a scanner match.
+// The issues breaking the tree are returned as errors: a leaf which selects no config or a config missing from the map,
+// a config which is not selected by any leaf.
+// The issues confusing the user are returned as warnings: selector values which look the same to the user (differing only in case or surrounding spaces),
+// default and described values which can't be selected, invalid validation rules,
+// and keys of the multi-select options which are not normalized lists of their listed values (see MultiSelectKey).
+func CheckOptionTree(options OptionNode, configs BitriseConfigMap) (errs []string, warnings []string) {
+	checker := optionTreeChecker{configs: configs, selected: map[string]bool{}}
+	checker.check(&options, nil)
+
+	for _, name := range sortedKeys(configs) {
+		if !checker.selected[name] {
+			checker.addError("config (%s) is not selected by any option", name)
+		}
+	}
+	return checker.errs, checker.warnings
+}
+
+type optionTreeChecker struct {
+	configs  BitriseConfigMap
+	selected map[string]bool
+	errs     []string
+	warnings []string
+}
+
+func (checker *optionTreeChecker) addError(format string, args ...any) {
+	checker.errs = append(checker.errs, fmt.Sprintf(format, args...))
+}
+
+func (checker *optionTreeChecker) addWarning(format string, args ...any) {
+	checker.warnings = append(checker.warnings, fmt.Sprintf(format, args...))
+}
+
+func (checker *optionTreeChecker) check(option *OptionNode, path []string) {
+	if option.IsConfigOption() {
+		checker.selected[option.Config] = true
+		if _, ok := checker.configs[option.Config]; !ok {
+			checker.addError("option (%s) selects config (%s), which is not generated", optionPath(path), option.Config)
+		}
+		return
+	}
+	if len(option.ChildOptionMap) == 0 {
+		checker.addError("option (%s) selects no config", optionPath(path))
+		return
+	}
+
+	values := make([]string, 0, len(option.ChildOptionMap))
+	for value := range option.ChildOptionMap {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	if option.Type == TypeSelector || option.Type == TypeOptionalSelector {
+		seen := map[string]string{}
+		for _, value := range values {
+			normalized := strings.ToLower(strings.TrimSpace(value))
+			if other, ok := seen[normalized]; ok {
+				checker.addWarning("option (%s) has duplicate values: %q and %q", optionPath(append(path, option.Title)), other, value)
+				continue
+			}
+			seen[normalized] = value
+		}
+	}
+	if option.Type == TypeMultiSelector {
+		for _, key := range values {
+			if MultiSelectKey(key) != key {
+				checker.addWarning("option (%s) has a key which is not a sorted list of its values: %q", optionPath(append(path, option.Title)), key)
+				continue
+			}
+			for _, value := range strings.Split(key, MultiSelectSeparator) {
+				if value != "" && !slices.Contains(option.Values, value) {
+					checker.addWarning("option (%s) has a key with a value which is not listed in its values: %q", optionPath(append(path, option.Title)), key)
+					break
+				}
+			}
+		}
+	}
+
+	checker.checkValueInfos(option, append(path, option.Title))
+	checker.checkValidation(option, append(path, option.Title))
+
+	for _, value := range values {
+		childPath := append(append(path[:len(path):len(path)], option.Title), value)
+		child := option.ChildOptionMap[value]
+		if child == nil {
+			checker.addError("option (%s) selects no config", optionPath(childPath))
+			continue
+		}
+		checker.check(child, childPath)
+	}
+}
+
+// checkValueInfos checks that the default value and the described values of a selector can be picked,
+// the values of user inputs and optional selectors are not limited.
+func (checker *optionTreeChecker) checkValueInfos(option *OptionNode, path []string) {
+	if option.Type != TypeSelector && option.Type != TypeMultiSelector {
+		return
+	}
+
+	if _, ok := option.ChildOptionMap[option.DefaultValue]; option.DefaultValue != "" && !ok {
+		checker.addWarning("option (%s) has a default value which can't be selected: %q", optionPath(path), option.DefaultValue)
+	}
+	for _, value := range sortedKeys(option.ValueInfos) {
+		_, ok := option.ChildOptionMap[value]
+		if option.Type == TypeMultiSelector {
+			ok = slices.Contains(option.Values, value)
+		}
+		if !ok {
+			checker.addWarning("option (%s) describes a value which can't be selected: %q", optionPath(path), value)
+		}
+	}
+}
+
+// checkValidation checks that the validation rules of the option can be parsed, and its default value follows them.
+func (checker *optionTreeChecker) checkValidation(option *OptionNode, path []string) {
+	if option.Validation == nil {
+		return
+	}
+
+	if err := option.Validation.checkRules(); err != nil {
+		checker.addWarning("option (%s) has an invalid validation: %s", optionPath(path), err)
+		return
+	}
+	if option.DefaultValue != "" {
+		if err := option.ValidateValue(option.DefaultValue); err != nil {
+			checker.addWarning("option (%s) has an invalid default value (%s): %s", optionPath(path), option.DefaultValue, err)
+		}
+	}
+}
+
+// optionPath joins the titles and the values leading to an option.
+func optionPath(path []string) string {
+	if len(path) == 0 {
+		return "root"
+	}
+	return strings.Join(path, " > ")
+}
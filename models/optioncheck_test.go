@@ -0,0 +1,103 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOptionTree(t *testing.T) {
+	newOptions := func() *OptionNode {
+		root := NewOption("Project type", "", "PROJECT_TYPE", TypeSelector)
+		app := NewOption("Build variant", "", "VARIANT", TypeOptionalSelector)
+		root.AddOption("app", app)
+		app.AddConfig("debug", NewConfigOption("debug-config", nil))
+		app.AddConfig("release", NewConfigOption("release-config", nil))
+		root.AddConfig("library", NewConfigOption("library-config", nil))
+		return root
+	}
+	configs := BitriseConfigMap{"debug-config": {}, "release-config": {}, "library-config": {}}
+
+	errs, warnings := CheckOptionTree(*newOptions(), configs)
+	require.Empty(t, errs)
+	require.Empty(t, warnings)
+
+	options := newOptions()
+	options.ChildOptionMap["app"].AddConfig(" Debug", NewConfigOption("debug-config", nil))
+	options.ChildOptionMap["library"].Config = "lib-config"
+	options.AddOption("plugin", nil)
+	errs, warnings = CheckOptionTree(*options, configs)
+	require.Equal(t, []string{
+		"option (Project type > library) selects config (lib-config), which is not generated",
+		"option (Project type > plugin) selects no config",
+		"config (library-config) is not selected by any option",
+	}, errs)
+	require.Equal(t, []string{
+		`option (Project type > app > Build variant) has duplicate values: " Debug" and "debug"`,
+	}, warnings)
+}
+
+func TestCheckOptionTree_multiSelector(t *testing.T) {
+	option := NewOption("Platforms", "", "", TypeMultiSelector)
+	option.AddConfig("ios, android", NewConfigOption("both-config", nil))
+	configs := BitriseConfigMap{"both-config": {}, "android-config": {}}
+	option.ChildOptionMap["ios,android"] = NewConfigOption("android-config", nil)
+	option.ChildOptionMap["web"] = NewConfigOption("android-config", nil)
+
+	errs, warnings := CheckOptionTree(*option, configs)
+	require.Empty(t, errs)
+	require.Equal(t, []string{
+		`option (Platforms) has a key which is not a sorted list of its values: "ios,android"`,
+		`option (Platforms) has a key with a value which is not listed in its values: "web"`,
+	}, warnings)
+}
+
+func TestCheckOptionTree_valueInfos(t *testing.T) {
+	option := NewOption("Scheme", "", "", TypeSelector)
+	option.AddConfig("App", NewConfigOption("app-config", nil))
+	option.SetValueInfo("App", ValueInfo{Recommended: true})
+	configs := BitriseConfigMap{"app-config": {}}
+	errs, warnings := CheckOptionTree(*option, configs)
+	require.Empty(t, errs)
+	require.Empty(t, warnings)
+
+	option.DefaultValue = "Other"
+	option.SetValueInfo("Other", ValueInfo{Reason: "missing"})
+	errs, warnings = CheckOptionTree(*option, configs)
+	require.Empty(t, errs)
+	require.Equal(t, []string{
+		`option (Scheme) has a default value which can't be selected: "Other"`,
+		`option (Scheme) describes a value which can't be selected: "Other"`,
+	}, warnings)
+
+	// the values of user inputs are not limited
+	variantOption := NewOption("Variant", "", "", TypeOptionalUserInput)
+	variantOption.AddConfig("", NewConfigOption("app-config", nil))
+	variantOption.SetValueInfo("debug", ValueInfo{Recommended: true})
+	errs, warnings = CheckOptionTree(*variantOption, configs)
+	require.Empty(t, errs)
+	require.Empty(t, warnings)
+}
+
+func TestCheckOptionTree_validation(t *testing.T) {
+	option := NewOption("Node.js version", "", "NODEJS_VERSION", TypeUserInput)
+	option.AddConfig("", NewConfigOption("node-config", nil))
+	option.Validation = ToolVersionValidation()
+	option.DefaultValue = "22"
+	configs := BitriseConfigMap{"node-config": {}}
+	errs, warnings := CheckOptionTree(*option, configs)
+	require.Empty(t, errs)
+	require.Empty(t, warnings)
+
+	option.DefaultValue = "lts"
+	_, warnings = CheckOptionTree(*option, configs)
+	require.Equal(t, []string{
+		`option (Node.js version) has an invalid default value (lts): the value doesn't match the pattern ^\d+(\.\d+){0,2}(:(latest|installed))?$, expected an exact (3.2.0) or partial (3:latest, 3:installed) version`,
+	}, warnings)
+
+	option.Validation = &Validation{SemverConstraint: ">>18"}
+	_, warnings = CheckOptionTree(*option, configs)
+	require.Equal(t, []string{
+		"option (Node.js version) has an invalid validation: invalid version constraint (>>18): improper constraint: >>18",
+	}, warnings)
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ToolVersionPattern matches the tool versions `bitrise tools install` accepts: exact (20.10.0) or partial (22:latest, 20:installed) versions.
+const ToolVersionPattern = `^\d+(\.\d+){0,2}(:(latest|installed))?$`
+
+// ToolVersionValidation returns the validation of the tool version options, see ToolVersionPattern.
+func ToolVersionValidation() *Validation {
+	return &Validation{Pattern: ToolVersionPattern, Hint: "an exact (3.2.0) or partial (3:latest, 3:installed) version"}
+}
+
+// Validation restricts the values which can be typed in for a user input option, the empty rules are not checked.
+type Validation struct {
+	// Pattern is a regular expression the value has to match.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// AllowedValues are the only values accepted.
+	AllowedValues []string `json:"allowed_values,omitempty" yaml:"allowed_values,omitempty"`
+	// SemverConstraint is a semantic version constraint (like >=18, <21) the value has to satisfy.
+	SemverConstraint string `json:"semver_constraint,omitempty" yaml:"semver_constraint,omitempty"`
+	// Hint describes the expected value, it is added to the error messages.
+	Hint string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+// Check returns an error describing why the value breaks the rules, nil if it follows them.
+func (validation Validation) Check(value string) error {
+	if err := validation.check(value); err != nil {
+		if validation.Hint != "" {
+			return fmt.Errorf("%w, expected %s", err, validation.Hint)
+		}
+		return err
+	}
+	return nil
+}
+
+func (validation Validation) check(value string) error {
+	if len(validation.AllowedValues) > 0 && !slices.Contains(validation.AllowedValues, value) {
+		return fmt.Errorf("the value is not one of the allowed values (%s)", strings.Join(validation.AllowedValues, ", "))
+	}
+
+	if validation.Pattern != "" {
+		pattern, err := regexp.Compile(validation.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern (%s): %w", validation.Pattern, err)
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("the value doesn't match the pattern %s", validation.Pattern)
+		}
+	}
+
+	if validation.SemverConstraint != "" {
+		constraint, err := semver.NewConstraint(validation.SemverConstraint)
+		if err != nil {
+			return fmt.Errorf("invalid version constraint (%s): %w", validation.SemverConstraint, err)
+		}
+		version, err := semver.NewVersion(value)
+		if err != nil {
+			return fmt.Errorf("the value is not a semantic version")
+		}
+		if !constraint.Check(version) {
+			return fmt.Errorf("the version doesn't satisfy the constraint %s", validation.SemverConstraint)
+		}
+	}
+
+	return nil
+}
+
+// checkRules returns an error if the pattern or the version constraint of the validation can't be parsed.
+func (validation Validation) checkRules() error {
+	if validation.Pattern != "" {
+		if _, err := regexp.Compile(validation.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern (%s): %w", validation.Pattern, err)
+		}
+	}
+	if validation.SemverConstraint != "" {
+		if _, err := semver.NewConstraint(validation.SemverConstraint); err != nil {
+			return fmt.Errorf("invalid version constraint (%s): %w", validation.SemverConstraint, err)
+		}
+	}
+	return nil
+}
+
+// ValidateValue checks a value typed in for the option against its Validation.
+// The values the option lists are always valid, so is the empty value of an optional user input.
+func (option *OptionNode) ValidateValue(value string) error {
+	if option.Validation == nil {
+		return nil
+	}
+	if _, ok := option.ChildOptionMap[value]; ok {
+		return nil
+	}
+	if value == "" && option.Type == TypeOptionalUserInput {
+		return nil
+	}
+	return option.Validation.Check(value)
+}
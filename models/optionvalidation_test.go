@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidation_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		validation Validation
+		value      string
+		wantErr    string
+	}{
+		{name: "pattern", validation: Validation{Pattern: `^[a-z]+$`}, value: "debug"},
+		{name: "not matching pattern", validation: Validation{Pattern: `^[a-z]+$`}, value: "free-debug", wantErr: "the value doesn't match the pattern ^[a-z]+$"},
+		{name: "invalid pattern", validation: Validation{Pattern: `^[a-z+$`}, value: "debug", wantErr: "invalid pattern (^[a-z+$): error parsing regexp: missing closing ]: `[a-z+$`"},
+		{name: "allowed value", validation: Validation{AllowedValues: []string{"apk", "aab"}}, value: "aab"},
+		{name: "not allowed value", validation: Validation{AllowedValues: []string{"apk", "aab"}}, value: "ipa", wantErr: "the value is not one of the allowed values (apk, aab)"},
+		{name: "version", validation: Validation{SemverConstraint: ">=18, <23"}, value: "22.1"},
+		{name: "old version", validation: Validation{SemverConstraint: ">=18, <23"}, value: "16.20.0", wantErr: "the version doesn't satisfy the constraint >=18, <23"},
+		{name: "not a version", validation: Validation{SemverConstraint: ">=18"}, value: "lts", wantErr: "the value is not a semantic version"},
+		{name: "hint", validation: Validation{Pattern: `^\d+$`, Hint: "a number"}, value: "one", wantErr: `the value doesn't match the pattern ^\d+$, expected a number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validation.Check(tt.value)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToolVersionPattern(t *testing.T) {
+	validation := ToolVersionValidation()
+	for _, version := range []string{"22", "20.10.0", "3.12", "22:latest", "20:installed"} {
+		require.NoError(t, validation.Check(version), version)
+	}
+	for _, version := range []string{"v22", "22.x", "latest", "22:newest", "1.2.3.4"} {
+		require.Error(t, validation.Check(version), version)
+	}
+}
+
+func TestOptionNode_ValidateValue(t *testing.T) {
+	option := NewOption("Variant", "", "VARIANT", TypeOptionalUserInput)
+	option.AddConfig("", NewConfigOption("config", nil))
+	require.NoError(t, option.ValidateValue("free-debug"))
+
+	option.Validation = &Validation{Pattern: `^[A-Za-z]+$`}
+	require.NoError(t, option.ValidateValue("freeDebug"))
+	require.NoError(t, option.ValidateValue(""))
+	require.EqualError(t, option.ValidateValue("free-debug"), "the value doesn't match the pattern ^[A-Za-z]+$")
+
+	// the listed values are valid
+	option.AddConfig("free-release", NewConfigOption("config", nil))
+	require.NoError(t, option.ValidateValue("free-release"))
+}
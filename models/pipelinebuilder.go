@@ -1,13 +1,17 @@
 package models
 
 import (
+	"fmt"
+	"strings"
+
 	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
 )
 
 type pipelineBuilderModel struct {
-	Workflows   map[WorkflowID]bitriseModels.GraphPipelineWorkflowModel
-	Description string
-	Summary     string
+	Workflows     map[WorkflowID]bitriseModels.GraphPipelineWorkflowModel
+	workflowOrder []WorkflowID
+	Description   string
+	Summary       string
 }
 
 func newDefaultPipelineBuilder() *pipelineBuilderModel {
@@ -17,6 +21,9 @@ func newDefaultPipelineBuilder() *pipelineBuilderModel {
 }
 
 func (builder *pipelineBuilderModel) setGraphPipelineWorkflow(workflow WorkflowID, item bitriseModels.GraphPipelineWorkflowModel) {
+	if _, ok := builder.Workflows[workflow]; !ok {
+		builder.workflowOrder = append(builder.workflowOrder, workflow)
+	}
 	builder.Workflows[workflow] = item
 }
 
@@ -32,3 +39,53 @@ func (builder *pipelineBuilderModel) generate() bitriseModels.PipelineModel {
 		Summary:     builder.Summary,
 	}
 }
+
+// validate checks the workflow graph of the pipeline: every workflow (or the workflow a variant uses) is defined,
+// the dependencies are part of the pipeline and they have no cycle.
+func (builder *pipelineBuilderModel) validate(workflows map[WorkflowID]*workflowBuilderModel) error {
+	for _, workflowID := range builder.workflowOrder {
+		definition := workflowID
+		if uses := builder.Workflows[workflowID].Uses; uses != "" {
+			definition = WorkflowID(uses)
+		}
+		if _, ok := workflows[definition]; !ok {
+			return fmt.Errorf("workflow %s is not defined", definition)
+		}
+		for _, dependency := range builder.Workflows[workflowID].DependsOn {
+			if _, ok := builder.Workflows[WorkflowID(dependency)]; !ok {
+				return fmt.Errorf("workflow %s depends on %s, which is not part of the pipeline", workflowID, dependency)
+			}
+		}
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := map[WorkflowID]int{}
+	var visit func(workflowID WorkflowID, path []string) error
+	visit = func(workflowID WorkflowID, path []string) error {
+		path = append(path, string(workflowID))
+		switch states[workflowID] {
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+
+		states[workflowID] = visiting
+		for _, dependency := range builder.Workflows[workflowID].DependsOn {
+			if err := visit(WorkflowID(dependency), path); err != nil {
+				return err
+			}
+		}
+		states[workflowID] = visited
+		return nil
+	}
+	for _, workflowID := range builder.workflowOrder {
+		if err := visit(workflowID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+)
+
+// ValidateConfig checks the config the way the bitrise CLI does when it runs the bitrise.yml:
+// the serialized config is parsed, normalized and validated by the bitrise models.
+// Workflows without steps are rejected too.
+func ValidateConfig(config Config) error {
+	data, err := MarshalConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var parsed bitriseModels.BitriseDataModel
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	if err := parsed.Normalize(); err != nil {
+		return err
+	}
+	if _, err := parsed.Validate(); err != nil {
+		return err
+	}
+
+	for _, workflowID := range sortedKeys(parsed.Workflows) {
+		workflow := parsed.Workflows[workflowID]
+		if len(workflow.Steps) == 0 && len(workflow.BeforeRun) == 0 && len(workflow.AfterRun) == 0 {
+			return fmt.Errorf("workflow (%s) has no steps", workflowID)
+		}
+	}
+	return nil
+}
+
+// InvalidConfigError is returned by BitriseConfigMap.Validate for a config which doesn't pass ValidateConfig.
+type InvalidConfigError struct {
+	Name string
+	Err  error
+}
+
+func (e *InvalidConfigError) Error() string {
+	return fmt.Sprintf("generated config (%s) is invalid: %s", e.Name, e.Err)
+}
+
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks every config of the map with ValidateConfig, returns an InvalidConfigError for every invalid config.
+func (configMap BitriseConfigMap) Validate() []error {
+	var errs []error
+	for _, name := range sortedKeys(configMap) {
+		if err := ValidateConfig(configMap[name]); err != nil {
+			errs = append(errs, &InvalidConfigError{Name: name, Err: err})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	newConfig := func() Config {
+		return NewConfig(bitriseModels.BitriseDataModel{
+			FormatVersion: FormatVersion,
+			Workflows: map[string]bitriseModels.WorkflowModel{
+				"build": {Steps: []bitriseModels.StepListItemModel{{"script@1": stepmanModels.StepModel{}}}},
+			},
+			Pipelines: map[string]bitriseModels.PipelineModel{
+				"ci": {Workflows: bitriseModels.GraphPipelineWorkflowListItemModel{"build": {}}},
+			},
+		})
+	}
+	require.NoError(t, ValidateConfig(newConfig()))
+
+	config := newConfig()
+	config.Pipelines["ci"].Workflows["test"] = bitriseModels.GraphPipelineWorkflowModel{DependsOn: []string{"build"}}
+	require.EqualError(t, ValidateConfig(config), "workflow (test) defined in pipeline (ci) is not found in the workflow definitions")
+
+	config = newConfig()
+	config.Workflows["test"] = bitriseModels.WorkflowModel{}
+	require.EqualError(t, ValidateConfig(config), "workflow (test) has no steps")
+
+	config = newConfig()
+	config.FormatVersion = ""
+	otherConfig := newConfig()
+	otherConfig.Workflows["test"] = bitriseModels.WorkflowModel{}
+	errs := BitriseConfigMap{"android-config": config, "other-config": otherConfig, "valid-config": newConfig()}.Validate()
+	require.Len(t, errs, 2)
+	require.EqualError(t, errs[0], "generated config (android-config) is invalid: missing format_version")
+	require.EqualError(t, errs[1], "generated config (other-config) is invalid: workflow (test) has no steps")
+}
@@ -1,27 +1,66 @@
 package models
 
-import bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+import (
+	"strings"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+)
 
 type workflowBuilderModel struct {
 	Steps       []bitriseModels.StepListItemModel
 	Description string
 	Summary     string
+	Reason      string
+	StepReasons map[int]string
+	BeforeRun   []string
+	Envs        []envmanModels.EnvironmentItemModel
+	Tools       bitriseModels.ToolsModel
+	Triggers    bitriseModels.Triggers
 }
 
 func newDefaultWorkflowBuilder() *workflowBuilderModel {
 	return &workflowBuilderModel{
-		Steps: []bitriseModels.StepListItemModel{},
+		Steps:       []bitriseModels.StepListItemModel{},
+		StepReasons: map[int]string{},
+	}
+}
+
+// appendStepListItems appends the steps, explained by their reasons in stepReasons (by step ID).
+func (builder *workflowBuilderModel) appendStepListItems(stepReasons map[string]string, items ...bitriseModels.StepListItemModel) {
+	for _, item := range items {
+		if reason := stepReasons[stepID(item)]; reason != "" {
+			builder.StepReasons[len(builder.Steps)] = reason
+		}
+		builder.Steps = append(builder.Steps, item)
 	}
 }
 
-func (builder *workflowBuilderModel) appendStepListItems(items ...bitriseModels.StepListItemModel) {
-	builder.Steps = append(builder.Steps, items...)
+func (builder *workflowBuilderModel) appendStepListItemsWithReason(stepReasons map[string]string, reason string, items ...bitriseModels.StepListItemModel) {
+	idx := len(builder.Steps)
+	builder.appendStepListItems(stepReasons, items...)
+	if len(items) > 0 {
+		builder.StepReasons[idx] = reason
+	}
 }
 
 func (builder *workflowBuilderModel) generate() bitriseModels.WorkflowModel {
 	return bitriseModels.WorkflowModel{
-		Steps:       builder.Steps,
-		Description: builder.Description,
-		Summary:     builder.Summary,
+		Steps:        builder.Steps,
+		Description:  builder.Description,
+		Summary:      builder.Summary,
+		Triggers:     builder.Triggers,
+		BeforeRun:    builder.BeforeRun,
+		Environments: builder.Envs,
+		Tools:        builder.Tools,
+	}
+}
+
+// stepID returns the ID of the step without its version, for example git-clone for git-clone@8.
+func stepID(item bitriseModels.StepListItemModel) string {
+	for key := range item {
+		id, _, _ := strings.Cut(key, "@")
+		return id
 	}
+	return ""
 }
@@ -8,6 +8,7 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/go-utils/fileutil"
 )
 
@@ -52,11 +53,19 @@ func (format Format) String() string {
 	return "unknown"
 }
 
-// WriteToFile ...
+// WriteToFile writes a to pth in the format, the extension of pth is replaced by the format's extension.
+// A models.ScanResultModel is written in its serialized form (ScanResult).
 func WriteToFile(a interface{}, format Format, pth string) (string, error) {
 	str := ""
 	ext := ""
 
+	if format != RawFormat {
+		var err error
+		if a, err = serializable(a); err != nil {
+			return "", err
+		}
+	}
+
 	switch format {
 	case RawFormat:
 		str = fmt.Sprint(a)
@@ -92,10 +101,17 @@ func WriteToFile(a interface{}, format Format, pth string) (string, error) {
 	return pth, nil
 }
 
-// Print ...
+// Print prints a in the format, a models.ScanResultModel is printed in its serialized form (ScanResult).
 func Print(a interface{}, format Format) error {
 	str := ""
 
+	if format != RawFormat {
+		var err error
+		if a, err = serializable(a); err != nil {
+			return err
+		}
+	}
+
 	switch format {
 	case RawFormat:
 		str = fmt.Sprint(a)
@@ -118,3 +134,21 @@ func Print(a interface{}, format Format) error {
 	fmt.Println(str)
 	return nil
 }
+
+// MarshalConfig serializes the config as a bitrise.yml.
+// If withComments is set, the workflows and the non-obvious steps are explained in YAML comments.
+func MarshalConfig(config models.Config, withComments bool) ([]byte, error) {
+	if withComments {
+		return models.MarshalConfigWithComments(config)
+	}
+	return models.MarshalConfig(config)
+}
+
+// WriteConfigToFile writes the config as a bitrise.yml to pth, see MarshalConfig.
+func WriteConfigToFile(config models.Config, pth string, withComments bool) error {
+	content, err := MarshalConfig(config, withComments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return fileutil.WriteBytesToFile(pth, content)
+}
@@ -0,0 +1,121 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/bitrise-init/models"
+)
+
+// ScanResult is the serialized form of models.ScanResultModel.
+//
+// The configs are serialized as bitrise.yml strings, both in JSON and in YAML:
+//
+//	configs:
+//	  ios:
+//	    ios-test-config: |
+//	      format_version: "13"
+//	      ...
+type ScanResult struct {
+	Options                     map[string]models.OptionNode                `json:"options,omitempty" yaml:"options,omitempty"`
+	Configs                     map[string]map[string]string                `json:"configs,omitempty" yaml:"configs,omitempty"`
+	Warnings                    map[string]models.Warnings                  `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	Errors                      map[string]models.Errors                    `json:"errors,omitempty" yaml:"errors,omitempty"`
+	ErrorsWithRecommendations   map[string]models.ErrorsWithRecommendations `json:"errors_with_recommendations,omitempty" yaml:"errors_with_recommendations,omitempty"`
+	WarningsWithRecommendations map[string]models.ErrorsWithRecommendations `json:"warnings_with_recommendations,omitempty" yaml:"warnings_with_recommendations,omitempty"`
+	ProjectConfig               *models.ProjectConfigResult                 `json:"project_config,omitempty" yaml:"project_config,omitempty"`
+}
+
+// NewScanResult returns the serialized form of the scan result.
+func NewScanResult(result models.ScanResultModel) (ScanResult, error) {
+	var configs map[string]map[string]string
+	if result.ScannerToBitriseConfigMap != nil {
+		configs = make(map[string]map[string]string, len(result.ScannerToBitriseConfigMap))
+		for scanner, configMap := range result.ScannerToBitriseConfigMap {
+			configStrs, err := EncodeConfigMap(configMap)
+			if err != nil {
+				return ScanResult{}, fmt.Errorf("%s scanner: %w", scanner, err)
+			}
+			configs[scanner] = configStrs
+		}
+	}
+
+	return ScanResult{
+		Options:                     result.ScannerToOptionRoot,
+		Configs:                     configs,
+		Warnings:                    result.ScannerToWarnings,
+		Errors:                      result.ScannerToErrors,
+		ErrorsWithRecommendations:   result.ScannerToErrorsWithRecommendations,
+		WarningsWithRecommendations: result.ScannerToWarningsWithRecommendations,
+		ProjectConfig:               result.ProjectConfig,
+	}, nil
+}
+
+// ScanResultModel parses the configs of the serialized scan result.
+func (result ScanResult) ScanResultModel() (models.ScanResultModel, error) {
+	var configMaps map[string]models.BitriseConfigMap
+	if result.Configs != nil {
+		configMaps = make(map[string]models.BitriseConfigMap, len(result.Configs))
+		for scanner, configStrs := range result.Configs {
+			configMap, err := DecodeConfigMap(configStrs)
+			if err != nil {
+				return models.ScanResultModel{}, fmt.Errorf("%s scanner: %w", scanner, err)
+			}
+			configMaps[scanner] = configMap
+		}
+	}
+
+	return models.ScanResultModel{
+		ScannerToOptionRoot:                  result.Options,
+		ScannerToBitriseConfigMap:            configMaps,
+		ScannerToWarnings:                    result.Warnings,
+		ScannerToErrors:                      result.Errors,
+		ScannerToErrorsWithRecommendations:   result.ErrorsWithRecommendations,
+		ScannerToWarningsWithRecommendations: result.WarningsWithRecommendations,
+		ProjectConfig:                        result.ProjectConfig,
+	}, nil
+}
+
+// EncodeConfigMap serializes the configs as bitrise.yml contents.
+func EncodeConfigMap(configMap models.BitriseConfigMap) (map[string]string, error) {
+	if configMap == nil {
+		return nil, nil
+	}
+
+	configStrs := make(map[string]string, len(configMap))
+	for name, config := range configMap {
+		data, err := models.MarshalConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config (%s): %w", name, err)
+		}
+		configStrs[name] = string(data)
+	}
+	return configStrs, nil
+}
+
+// DecodeConfigMap parses the configs from bitrise.yml contents.
+func DecodeConfigMap(configStrs map[string]string) (models.BitriseConfigMap, error) {
+	if configStrs == nil {
+		return nil, nil
+	}
+
+	configMap := make(models.BitriseConfigMap, len(configStrs))
+	for name, configStr := range configStrs {
+		config, err := models.UnmarshalConfig([]byte(configStr))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config (%s): %w", name, err)
+		}
+		configMap[name] = config
+	}
+	return configMap, nil
+}
+
+// serializable returns the serialized form of the scan results, other values are returned as they are.
+func serializable(a interface{}) (interface{}, error) {
+	switch result := a.(type) {
+	case models.ScanResultModel:
+		return NewScanResult(result)
+	case *models.ScanResultModel:
+		return NewScanResult(*result)
+	}
+	return a, nil
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+)
+
+const testConfigYML = `format_version: "13"
+project_type: ios
+workflows:
+  primary: {}
+`
+
+func TestScanResult_configsAsStrings(t *testing.T) {
+	result := models.ScanResultModel{
+		ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{
+			"ios": {
+				"ios-config": models.NewConfig(bitriseModels.BitriseDataModel{
+					FormatVersion: "13",
+					ProjectType:   "ios",
+					Workflows:     map[string]bitriseModels.WorkflowModel{"primary": {}},
+				}),
+			},
+		},
+	}
+
+	scanResult, err := NewScanResult(result)
+	require.NoError(t, err)
+
+	jsonData, err := json.Marshal(scanResult)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"configs": {"ios": {"ios-config": "format_version: \"13\"\nproject_type: ios\nworkflows:\n  primary: {}\n"}}}`, string(jsonData))
+
+	yamlData, err := yaml.Marshal(scanResult)
+	require.NoError(t, err)
+	var yamlResult struct {
+		Configs map[string]map[string]string `yaml:"configs"`
+	}
+	require.NoError(t, yaml.Unmarshal(yamlData, &yamlResult))
+	require.Equal(t, testConfigYML, yamlResult.Configs["ios"]["ios-config"])
+
+	var fromJSON ScanResult
+	require.NoError(t, json.Unmarshal(jsonData, &fromJSON))
+	parsed, err := fromJSON.ScanResultModel()
+	require.NoError(t, err)
+	require.Equal(t, result.ScannerToBitriseConfigMap, parsed.ScannerToBitriseConfigMap)
+
+	var fromYAML ScanResult
+	require.NoError(t, yaml.Unmarshal(yamlData, &fromYAML))
+	parsed, err = fromYAML.ScanResultModel()
+	require.NoError(t, err)
+	require.Equal(t, result.ScannerToBitriseConfigMap, parsed.ScannerToBitriseConfigMap)
+}
+
+func TestDecodeConfigMap(t *testing.T) {
+	configMap, err := DecodeConfigMap(map[string]string{"ios-config": testConfigYML})
+	require.NoError(t, err)
+	require.Equal(t, "ios", configMap["ios-config"].ProjectType)
+
+	configStrs, err := EncodeConfigMap(configMap)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"ios-config": testConfigYML}, configStrs)
+
+	_, err = DecodeConfigMap(map[string]string{"invalid-config": "workflows: ["})
+	require.ErrorContains(t, err, "failed to unmarshal config (invalid-config)")
+}
@@ -0,0 +1,220 @@
+package projectconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/models"
+)
+
+// Result describes how the project config changed a scanner's option tree.
+type Result struct {
+	Answers         []models.ProjectConfigAnswer
+	PreferredConfig string
+	Warnings        models.Warnings
+}
+
+// Apply pins the scanner's answers and selects the scanner's preferred config in its option tree:
+// the option branches not matching the answers and not leading to the preferred config are removed.
+// If the answers (or the preferred config) do not match any branch, the option tree is not changed by them and a warning is returned.
+func (config *Config) Apply(scannerName string, option *models.OptionNode) Result {
+	var result Result
+	if config == nil || option == nil {
+		return result
+	}
+
+	if answers := config.Answers[scannerName]; len(answers) > 0 {
+		result.Answers, result.Warnings = applyAnswers(option, answers)
+	}
+
+	if preferredConfig := config.PreferredConfigs[scannerName]; preferredConfig != "" {
+		pruned := option.Copy()
+		if pruned != nil && keepConfig(pruned, preferredConfig) {
+			*option = *pruned
+			result.PreferredConfig = preferredConfig
+		} else {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("preferred config (%s) of %s is not available, available configs: %s",
+				preferredConfig, scannerName, strings.Join(ConfigNames(option), ", ")))
+		}
+	}
+
+	return result
+}
+
+// ConfigNames returns the config names referenced by the option tree.
+func ConfigNames(option *models.OptionNode) []string {
+	configNameMap := map[string]bool{}
+	walkOptions(option, func(option *models.OptionNode) {
+		if option.IsConfigOption() {
+			configNameMap[option.Config] = true
+		}
+	})
+
+	var configNames []string
+	for name := range configNameMap {
+		configNames = append(configNames, name)
+	}
+	sort.Strings(configNames)
+	return configNames
+}
+
+func applyAnswers(option *models.OptionNode, answers map[string]string) ([]models.ProjectConfigAnswer, models.Warnings) {
+	var warnings models.Warnings
+
+	usedKeys := map[string]bool{}
+	walkOptions(option, func(option *models.OptionNode) {
+		if key, _, ok := lookupAnswer(option, answers); ok {
+			usedKeys[key] = true
+		}
+	})
+	for _, key := range sortedKeys(answers) {
+		if !usedKeys[key] {
+			warnings = append(warnings, fmt.Sprintf("no option found for the answer %s: %s", key, answers[key]))
+		}
+	}
+	if len(usedKeys) == 0 {
+		return nil, warnings
+	}
+
+	pinned := option.Copy()
+	if pinned == nil || !pinAnswers(pinned, answers) {
+		var keys []string
+		for _, key := range sortedKeys(answers) {
+			if usedKeys[key] {
+				keys = append(keys, fmt.Sprintf("%s: %s", key, answers[key]))
+			}
+		}
+		return nil, append(warnings, fmt.Sprintf("the answers (%s) do not match any of the available option values", strings.Join(keys, ", ")))
+	}
+	*option = *pinned
+
+	var pinnedAnswers []models.ProjectConfigAnswer
+	seen := map[models.ProjectConfigAnswer]bool{}
+	walkOptions(option, func(option *models.OptionNode) {
+		if _, value, ok := lookupAnswer(option, answers); ok {
+			answer := models.ProjectConfigAnswer{Title: option.Title, EnvKey: option.EnvKey, Value: value}
+			if !seen[answer] {
+				seen[answer] = true
+				pinnedAnswers = append(pinnedAnswers, answer)
+			}
+		}
+	})
+	return pinnedAnswers, warnings
+}
+
+// lookupAnswer returns the answer for the option, answers are keyed by env key or by title.
+// The answer of a multi-select option is normalized to the key of its next option.
+func lookupAnswer(option *models.OptionNode, answers map[string]string) (string, string, bool) {
+	if !option.IsValueOption() {
+		return "", "", false
+	}
+
+	key := option.EnvKey
+	value, ok := answers[key]
+	if key == "" || !ok {
+		key = option.Title
+		value, ok = answers[key]
+	}
+	if !ok {
+		return "", "", false
+	}
+
+	if option.Type == models.TypeMultiSelector {
+		value = models.MultiSelectKey(value)
+	}
+	return key, value, true
+}
+
+// pinAnswers removes the option branches not matching the answers, returns false if no branch matches.
+func pinAnswers(option *models.OptionNode, answers map[string]string) bool {
+	if option.IsConfigOption() {
+		return true
+	}
+
+	if _, value, ok := lookupAnswer(option, answers); ok {
+		child, found := option.ChildOptionMap[value]
+		if !found {
+			// the value of a user input is not limited to the listed values, only by its validation rules
+			if !isFreeFormOption(option) || len(option.ChildOptionMap) != 1 || option.ValidateValue(value) != nil {
+				return false
+			}
+			for _, onlyChild := range option.ChildOptionMap {
+				child = onlyChild
+			}
+		}
+		option.ChildOptionMap = map[string]*models.OptionNode{value: child}
+	}
+
+	for value, child := range option.ChildOptionMap {
+		if child != nil && !pinAnswers(child, answers) {
+			delete(option.ChildOptionMap, value)
+		}
+	}
+	dropRemovedValues(option)
+	return len(option.ChildOptionMap) > 0
+}
+
+// keepConfig removes the option branches not leading to the config, returns false if no branch leads to it.
+func keepConfig(option *models.OptionNode, configName string) bool {
+	if option.IsConfigOption() {
+		return option.Config == configName
+	}
+
+	for value, child := range option.ChildOptionMap {
+		if child == nil || !keepConfig(child, configName) {
+			delete(option.ChildOptionMap, value)
+		}
+	}
+	dropRemovedValues(option)
+	return len(option.ChildOptionMap) > 0
+}
+
+// dropRemovedValues clears the default value and the value infos of a selector which refer to removed branches.
+func dropRemovedValues(option *models.OptionNode) {
+	if isFreeFormOption(option) {
+		return
+	}
+
+	if _, ok := option.ChildOptionMap[option.DefaultValue]; !ok {
+		option.DefaultValue = ""
+	}
+	if option.Type != models.TypeSelector {
+		return
+	}
+	for value := range option.ValueInfos {
+		if _, ok := option.ChildOptionMap[value]; !ok {
+			delete(option.ValueInfos, value)
+		}
+	}
+}
+
+func isFreeFormOption(option *models.OptionNode) bool {
+	switch option.Type {
+	case models.TypeUserInput, models.TypeOptionalUserInput, models.TypeOptionalSelector:
+		return true
+	default:
+		return false
+	}
+}
+
+// walkOptions calls fn for every option of the tree, the children are visited in the order of their values.
+func walkOptions(option *models.OptionNode, fn func(option *models.OptionNode)) {
+	if option == nil {
+		return
+	}
+
+	fn(option)
+	for _, value := range sortedKeys(option.ChildOptionMap) {
+		walkOptions(option.ChildOptionMap[value], fn)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,123 @@
+// Package projectconfig reads the project config file (.bitrise-init.yml), which steers the scan of a repository.
+//
+// An example project config file:
+//
+//	# paths relative to the repository root (see path.Match), matching files and directories are not scanned
+//	exclude_paths:
+//	- examples
+//	- "*/Pods"
+//	scanners:
+//	  # these scanners do not run
+//	  disable:
+//	  - node-js
+//	  # these scanners run even if a detected scanner excludes them
+//	  force:
+//	  - android
+//	# option values by scanner name, then by the option's env key (or title, if the option has no env key)
+//	answers:
+//	  ios:
+//	    BITRISE_SCHEME: App
+//	    BITRISE_DISTRIBUTION_METHOD: app-store
+//	# the config to use by scanner name, if the option tree leads to more configs
+//	preferred_configs:
+//	  ios: ios-pod-test-config
+package projectconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FileName is the name of the project config file, read from the root of the scanned directory.
+const FileName = ".bitrise-init.yml"
+
+// Config is the content of the project config file.
+type Config struct {
+	ExcludePaths     []string                     `yaml:"exclude_paths,omitempty"`
+	Scanners         Scanners                     `yaml:"scanners,omitempty"`
+	Answers          map[string]map[string]string `yaml:"answers,omitempty"`
+	PreferredConfigs map[string]string            `yaml:"preferred_configs,omitempty"`
+}
+
+// Scanners enables and disables scanners.
+type Scanners struct {
+	Disable []string `yaml:"disable,omitempty"`
+	Force   []string `yaml:"force,omitempty"`
+}
+
+// Parse parses and validates the content of a project config file.
+func Parse(content []byte) (*Config, error) {
+	var config Config
+	if err := yaml.UnmarshalStrict(content, &config); err != nil {
+		return nil, err
+	}
+
+	for i, pattern := range config.ExcludePaths {
+		pattern = strings.TrimSuffix(path.Clean(strings.TrimPrefix(pattern, "./")), "/")
+		if pattern == "." || pattern == "" {
+			return nil, fmt.Errorf("invalid exclude path (%s): the repository root can not be excluded", config.ExcludePaths[i])
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude path (%s): %w", config.ExcludePaths[i], err)
+		}
+		config.ExcludePaths[i] = pattern
+	}
+
+	for _, name := range config.Scanners.Force {
+		if slices.Contains(config.Scanners.Disable, name) {
+			return nil, fmt.Errorf("scanner (%s) is both disabled and forced", name)
+		}
+	}
+
+	return &config, nil
+}
+
+// Read reads the project config file from dir, returns nil if dir has no project config file.
+func Read(dir string) (*Config, error) {
+	content, err := os.ReadFile(filepath.Join(dir, FileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	config, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	return config, nil
+}
+
+// ReadFS reads the project config file from the root of fsys, returns nil if fsys has no project config file.
+func ReadFS(fsys fs.FS) (*Config, error) {
+	content, err := fs.ReadFile(fsys, FileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	config, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	return config, nil
+}
+
+// IsDisabled reports whether the scanner is disabled, config can be nil.
+func (config *Config) IsDisabled(scannerName string) bool {
+	return config != nil && slices.Contains(config.Scanners.Disable, scannerName)
+}
+
+// IsForced reports whether the scanner runs even if a detected scanner excludes it, config can be nil.
+func (config *Config) IsForced(scannerName string) bool {
+	return config != nil && slices.Contains(config.Scanners.Force, scannerName)
+}
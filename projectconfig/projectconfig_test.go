@@ -0,0 +1,179 @@
+package projectconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *Config
+		wantErr string
+	}{
+		{
+			name: "valid config",
+			content: `exclude_paths:
+- ./examples/
+- "*/Pods"
+scanners:
+  disable: [node-js]
+  force: [android]
+answers:
+  ios:
+    BITRISE_SCHEME: App
+preferred_configs:
+  ios: ios-test-config
+`,
+			want: &Config{
+				ExcludePaths:     []string{"examples", "*/Pods"},
+				Scanners:         Scanners{Disable: []string{"node-js"}, Force: []string{"android"}},
+				Answers:          map[string]map[string]string{"ios": {"BITRISE_SCHEME": "App"}},
+				PreferredConfigs: map[string]string{"ios": "ios-test-config"},
+			},
+		},
+		{
+			name:    "unknown field",
+			content: "exclude: [examples]\n",
+			wantErr: "field exclude not found in type projectconfig.Config",
+		},
+		{
+			name:    "invalid pattern",
+			content: "exclude_paths: [\"[\"]\n",
+			wantErr: "invalid exclude path ([): syntax error in pattern",
+		},
+		{
+			name:    "root excluded",
+			content: "exclude_paths: [./]\n",
+			wantErr: "invalid exclude path (./): the repository root can not be excluded",
+		},
+		{
+			name:    "disabled and forced",
+			content: "scanners:\n  disable: [ios]\n  force: [ios]\n",
+			wantErr: "scanner (ios) is both disabled and forced",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.content))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	config, err := Read(dir)
+	require.NoError(t, err)
+	require.Nil(t, config)
+	require.False(t, config.IsDisabled("ios"))
+	require.False(t, config.IsForced("ios"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("scanners:\n  force: [ios]\n"), 0600))
+	config, err = Read(dir)
+	require.NoError(t, err)
+	require.True(t, config.IsForced("ios"))
+
+	config, err = ReadFS(fstest.MapFS{FileName: {Data: []byte("scanners:\n  disable: [ios]\n")}})
+	require.NoError(t, err)
+	require.True(t, config.IsDisabled("ios"))
+
+	_, err = ReadFS(fstest.MapFS{FileName: {Data: []byte("scanners: []\n")}})
+	require.Error(t, err)
+}
+
+// newTestOption creates an option tree: project path (selector) -> scheme (user input) -> config.
+func newTestOption() *models.OptionNode {
+	projectOption := models.NewOption("Project", "", "BITRISE_PROJECT_PATH", models.TypeSelector)
+	for _, project := range []string{"App.xcodeproj", "Other.xcodeproj"} {
+		schemeOption := models.NewOption("Scheme", "", "BITRISE_SCHEME", models.TypeUserInput)
+		schemeOption.AddConfig(models.UserInputOptionDefaultValue, models.NewConfigOption(project+"-config", nil))
+		projectOption.AddOption(project, schemeOption)
+	}
+	return projectOption
+}
+
+func TestConfig_Apply(t *testing.T) {
+	t.Run("answers", func(t *testing.T) {
+		config := &Config{Answers: map[string]map[string]string{"ios": {
+			"BITRISE_PROJECT_PATH": "Other.xcodeproj",
+			"BITRISE_SCHEME":       "Release",
+			"UNKNOWN_KEY":          "value",
+		}}}
+		option := newTestOption()
+
+		result := config.Apply("ios", option)
+		require.Equal(t, []models.ProjectConfigAnswer{
+			{Title: "Project", EnvKey: "BITRISE_PROJECT_PATH", Value: "Other.xcodeproj"},
+			{Title: "Scheme", EnvKey: "BITRISE_SCHEME", Value: "Release"},
+		}, result.Answers)
+		require.Equal(t, models.Warnings{"no option found for the answer UNKNOWN_KEY: value"}, result.Warnings)
+
+		require.Equal(t, []string{"Other.xcodeproj"}, option.GetValues())
+		require.Equal(t, []string{"Release"}, option.ChildOptionMap["Other.xcodeproj"].GetValues())
+		require.Equal(t, []string{"Other.xcodeproj-config"}, ConfigNames(option))
+	})
+
+	t.Run("not matching answer", func(t *testing.T) {
+		config := &Config{Answers: map[string]map[string]string{"ios": {"Project": "Missing.xcodeproj"}}}
+		option := newTestOption()
+
+		result := config.Apply("ios", option)
+		require.Empty(t, result.Answers)
+		require.Equal(t, models.Warnings{"the answers (Project: Missing.xcodeproj) do not match any of the available option values"}, result.Warnings)
+		require.Equal(t, []string{"App.xcodeproj-config", "Other.xcodeproj-config"}, ConfigNames(option))
+	})
+
+	t.Run("invalid answer", func(t *testing.T) {
+		config := &Config{Answers: map[string]map[string]string{"ios": {"BITRISE_SCHEME": "My App"}}}
+		option := newTestOption()
+		for _, schemeOption := range option.ChildOptionMap {
+			schemeOption.Validation = &models.Validation{Pattern: `^\w+$`}
+		}
+
+		result := config.Apply("ios", option)
+		require.Empty(t, result.Answers)
+		require.Equal(t, models.Warnings{"the answers (BITRISE_SCHEME: My App) do not match any of the available option values"}, result.Warnings)
+		require.Equal(t, []string{"App.xcodeproj-config", "Other.xcodeproj-config"}, ConfigNames(option))
+	})
+
+	t.Run("multi-select answer", func(t *testing.T) {
+		option := models.NewOption("Platforms", "", "", models.TypeMultiSelector)
+		option.AddConfig("android", models.NewConfigOption("android-config", nil))
+		option.AddConfig("android,ios", models.NewConfigOption("both-config", nil))
+		config := &Config{Answers: map[string]map[string]string{"flutter": {"Platforms": "ios, android"}}}
+
+		result := config.Apply("flutter", option)
+		require.Equal(t, []models.ProjectConfigAnswer{{Title: "Platforms", Value: "android,ios"}}, result.Answers)
+		require.Equal(t, []string{"both-config"}, ConfigNames(option))
+	})
+
+	t.Run("preferred config", func(t *testing.T) {
+		config := &Config{PreferredConfigs: map[string]string{"ios": "App.xcodeproj-config", "android": "missing"}}
+		option := newTestOption()
+		option.SetValueInfo("Other.xcodeproj", models.ValueInfo{Recommended: true})
+
+		result := config.Apply("ios", option)
+		require.Equal(t, "App.xcodeproj-config", result.PreferredConfig)
+		require.Empty(t, result.Warnings)
+		require.Equal(t, []string{"App.xcodeproj"}, option.GetValues())
+		// the recommendation of the removed branch is dropped
+		require.Empty(t, option.DefaultValue)
+		require.Empty(t, option.ValueInfos)
+
+		result = config.Apply("android", option)
+		require.Equal(t, models.Warnings{"preferred config (missing) of android is not available, available configs: App.xcodeproj-config"}, result.Warnings)
+	})
+}
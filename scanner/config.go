@@ -1,14 +1,28 @@
 package scanner
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/bitrise-io/bitrise-init/analytics"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/detectors/git"
 	"github.com/bitrise-io/bitrise-init/errormapper"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/projectconfig"
 	"github.com/bitrise-io/bitrise-init/scanners"
+	"github.com/bitrise-io/bitrise-init/snapshot"
 	"github.com/bitrise-io/go-steputils/step"
 	"github.com/bitrise-io/go-utils/colorstring"
 	"github.com/bitrise-io/go-utils/log"
@@ -27,6 +41,8 @@ const (
 	detectedWithErrors
 	// in case DetectPlatform() returned true, Options() and Config() returned no error
 	detected
+	// in case the scanner did not finish before its timeout, or the scan was cancelled
+	timedOut
 )
 
 const (
@@ -34,8 +50,29 @@ const (
 	configsFailedTag        = "configs_failed"
 	detectPlatformFailedTag = "detect_platform_failed"
 	noPlatformDetectedTag   = "no_platform_detected"
+	scannerTimedOutTag      = "scanner_timed_out"
 )
 
+// ConfigOptions ...
+type ConfigOptions struct {
+	HasSSHKey bool
+	// ScannerTimeout limits the time a single scanner can spend on detecting its platform and generating the options and configs.
+	// No limit is applied if it is 0.
+	// Only the scanners implementing scanners.ContextScanner (like the ios, macos and plugin scanners) are stopped,
+	// the others keep running in the background, their late results and logs are dropped.
+	ScannerTimeout time.Duration
+	// RunningScanners, if set, tracks the goroutines of the scanners, including the ones left running in the background after a timeout.
+	// Wait for it before removing the scanned directory.
+	RunningScanners *sync.WaitGroup
+	// FileIndex configures the depth and the ignore rules of the file index shared by the scanners.
+	FileIndex fileindex.Options
+
+	// projectConfig is read from the project config file of the search dir
+	projectConfig *projectconfig.Config
+	// defaultBranch is read from the git metadata of the search dir, the push triggers of the configs are set to it
+	defaultBranch string
+}
+
 type scannerOutput struct {
 	status status
 
@@ -64,7 +101,7 @@ func (o *scannerOutput) AddErrors(tag string, errs ...string) {
 				Error:           err,
 				Recommendations: recommendation,
 			})
-			return
+			continue
 		}
 
 		o.errors = append(o.errors, err)
@@ -79,7 +116,7 @@ func (o *scannerOutput) AddWarnings(tag string, errs ...string) {
 				Error:           err,
 				Recommendations: recommendation,
 			})
-			return
+			continue
 		}
 
 		o.warnings = append(o.warnings, err)
@@ -88,23 +125,37 @@ func (o *scannerOutput) AddWarnings(tag string, errs ...string) {
 
 // Config ...
 func Config(searchDir string, hasSSHKey bool) models.ScanResultModel {
+	return ConfigWithContext(context.Background(), searchDir, ConfigOptions{HasSSHKey: hasSSHKey})
+}
+
+// ConfigWithContext runs the scanners like Config, but stops the scan when ctx is done,
+// and applies opts.ScannerTimeout to every scanner.
+// Scanners not finished in time are reported as timed out, the results of the other scanners are still returned.
+func ConfigWithContext(ctx context.Context, searchDir string, opts ConfigOptions) models.ScanResultModel {
+	result, _ := scan(ctx, searchDir, opts)
+	return result
+}
+
+// scan runs the scanners and returns the scan result together with the file index of the search dir (if the indexing succeeded).
+func scan(ctx context.Context, searchDir string, opts ConfigOptions) (models.ScanResultModel, *fileindex.Index) {
 	result := models.ScanResultModel{}
 
 	//
 	// Setup
-	currentDir, err := os.Getwd()
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to expand current directory path: %s", err)
-		result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
-			Error: errorMsg,
-			Recommendations: step.Recommendation{
-				errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
-			},
-		})
-		return result
-	}
-
+	// Scanners work with absolute paths, the working directory of the process is not changed,
+	// so that scans can run concurrently.
 	if searchDir == "" {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to expand current directory path: %s", err)
+			result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
+				Error: errorMsg,
+				Recommendations: step.Recommendation{
+					errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
+				},
+			})
+			return result, nil
+		}
 		searchDir = currentDir
 	} else {
 		absScerach, err := pathutil.AbsPath(searchDir)
@@ -116,40 +167,171 @@ func Config(searchDir string, hasSSHKey bool) models.ScanResultModel {
 					errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
 				},
 			})
-			return result
+			return result, nil
 		}
 		searchDir = absScerach
 	}
 
-	if searchDir != currentDir {
-		if err := os.Chdir(searchDir); err != nil {
-			errorMsg := fmt.Sprintf("Failed to change dir, to (%s): %s", searchDir, err)
-			result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
-				Error: errorMsg,
-				Recommendations: step.Recommendation{
-					errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
-				},
-			})
-			return result
+	if exist, err := pathutil.IsDirExists(searchDir); err != nil || !exist {
+		errorMsg := fmt.Sprintf("Search dir (%s) does not exist", searchDir)
+		if err != nil {
+			errorMsg = fmt.Sprintf("Failed to check if search dir (%s) exists: %s", searchDir, err)
 		}
-		defer func() {
-			if err := os.Chdir(currentDir); err != nil {
-				log.TWarnf("Failed to change dir, to (%s), error: %s", searchDir, err)
-			}
-		}()
+		result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
+			Error: errorMsg,
+			Recommendations: step.Recommendation{
+				errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
+			},
+		})
+		return result, nil
 	}
+
+	projectConfig, err := projectconfig.Read(searchDir)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to read project config file: %s", err)
+		result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
+			Error: errorMsg,
+			Recommendations: step.Recommendation{
+				errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
+			},
+		})
+		return result, nil
+	}
+	opts = withProjectConfig(opts, projectConfig)
+	opts = withDefaultBranch(opts, os.DirFS(searchDir))
+
+	fileIndex := newFileIndex(searchDir, opts.FileIndex)
 	// ---
 
+	return runAllScanners(ctx, searchDir, fileIndex, opts), fileIndex
+}
+
+// withProjectConfig sets up the scan options to apply the project config, projectConfig can be nil.
+func withProjectConfig(opts ConfigOptions, projectConfig *projectconfig.Config) ConfigOptions {
+	if projectConfig == nil {
+		return opts
+	}
+
+	log.TInfof("Using project config file: %s", projectconfig.FileName)
+	opts.projectConfig = projectConfig
+	opts.FileIndex.IgnoredPaths = append(slices.Clone(opts.FileIndex.IgnoredPaths), projectConfig.ExcludePaths...)
+	return opts
+}
+
+// withDefaultBranch sets up the scan options to trigger the configs on the default branch of the git repository in fsys.
+// The configs keep models.DefaultBranch if fsys is not a git repository.
+func withDefaultBranch(opts ConfigOptions, fsys fs.FS) ConfigOptions {
+	branch, err := git.DefaultBranch(fsys)
+	if err != nil {
+		log.TWarnf("Failed to read the default branch: %s", err)
+		return opts
+	}
+	if branch != "" {
+		log.TInfof("Default branch: %s", branch)
+	}
+	opts.defaultBranch = branch
+	return opts
+}
+
+// fsSearchDir is the search dir of the scans of an fs.FS: the scanners work with absolute paths,
+// the root of the fs.FS is mapped to this (not existing) directory.
+const fsSearchDir = "/bitrise-init-fs"
+
+// ConfigFS runs the scanners like ConfigWithContext, but reads the project from fsys instead of the disk.
+// The file contents are read through fsys, the steps which need the files on the disk (like the Podfile parsing ruby scripts)
+// run on temporary copies of the required files, and the lookups not supported without the disk (like the iOS app icons) are skipped.
+// Scanners not supporting the shared file index (see scanners.FileIndexScanner) do not detect projects in fsys.
+func ConfigFS(ctx context.Context, fsys fs.FS, opts ConfigOptions) models.ScanResultModel {
+	projectConfig, err := projectconfig.ReadFS(fsys)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to read project config file: %s", err)
+		result := models.ScanResultModel{}
+		result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
+			Error: errorMsg,
+			Recommendations: step.Recommendation{
+				errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
+			},
+		})
+		return result
+	}
+	opts = withProjectConfig(opts, projectConfig)
+	opts = withDefaultBranch(opts, fsys)
+
+	log.TInfof("Indexing files")
+
+	fileIndex, err := fileindex.NewFS(fsys, fsSearchDir, opts.FileIndex)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to index files: %s", err)
+		result := models.ScanResultModel{}
+		result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
+			Error: errorMsg,
+			Recommendations: step.Recommendation{
+				errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
+			},
+		})
+		return result
+	}
+
+	log.TPrintf("%d files and directories indexed", len(fileIndex.Entries()))
+	log.Printf("")
+
+	return runAllScanners(ctx, fsSearchDir, fileIndex, opts)
+}
+
+// ConfigGitTree runs the scanners on the commit of the git repository at repoDir (like a bare repository), without checking it out.
+// See ConfigFS and snapshot.GitTree.
+func ConfigGitTree(ctx context.Context, repoDir, commit string, opts ConfigOptions) models.ScanResultModel {
+	fsys, err := snapshot.GitTree(repoDir, commit)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to read git commit: %s", err)
+		result := models.ScanResultModel{}
+		result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
+			Error: errorMsg,
+			Recommendations: step.Recommendation{
+				errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
+			},
+		})
+		return result
+	}
+
+	return ConfigFS(ctx, fsys, opts)
+}
+
+// ConfigTarball runs the scanners on the tar archive (optionally gzip compressed), without extracting it to the disk.
+// The size of the archived file contents is limited to maxSize bytes.
+// See ConfigFS and snapshot.Tarball.
+func ConfigTarball(ctx context.Context, r io.Reader, maxSize int64, opts ConfigOptions) models.ScanResultModel {
+	fsys, err := snapshot.Tarball(r, maxSize)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to read tarball: %s", err)
+		result := models.ScanResultModel{}
+		result.AddErrorWithRecommendation("general", models.ErrorWithRecommendations{
+			Error: errorMsg,
+			Recommendations: step.Recommendation{
+				errormapper.DetailedErrorRecKey: newDetectPlatformFailedGenericDetail(errorMsg),
+			},
+		})
+		return result
+	}
+
+	return ConfigFS(ctx, fsys, opts)
+}
+
+// runAllScanners runs the project scanners, then the automation tool scanners on the search dir and merges their outputs.
+func runAllScanners(ctx context.Context, searchDir string, fileIndex *fileindex.Index, opts ConfigOptions) models.ScanResultModel {
 	//
 	// Scan
 	log.TInfof(colorstring.Blue("Running scanners:"))
-	fmt.Println()
+	log.Printf("")
 
 	// Collect scanner outputs, by scanner name
-	projectScannerToOutputs := runScanners(scanners.ProjectScanners(), searchDir, hasSSHKey)
+	projectScanners := enabledScanners(scanners.ProjectScanners(), opts.projectConfig)
+	setFileIndex(projectScanners, fileIndex)
+
+	projectScannerToOutputs := runScanners(ctx, projectScanners, searchDir, opts)
 	detectedProjectTypes := getDetectedScannerNames(projectScannerToOutputs)
 	log.Printf("Detected project types: %s", detectedProjectTypes)
-	fmt.Println()
+	log.Printf("")
 
 	// Project types are needed by tool scanners, to create decision tree on which project type
 	// to actually use in bitrise.yml
@@ -157,16 +339,17 @@ func Config(searchDir string, hasSSHKey bool) models.ScanResultModel {
 		detectedProjectTypes = []string{otherProjectType}
 	}
 
-	automationToolScanners := scanners.AutomationToolScanners()
+	automationToolScanners := enabledScanners(scanners.AutomationToolScanners(), opts.projectConfig)
 
 	for _, toolScanner := range automationToolScanners {
 		toolScanner.(scanners.AutomationToolScanner).SetDetectedProjectTypes(detectedProjectTypes)
 	}
+	setFileIndex(automationToolScanners, fileIndex)
 
-	scannerToOutput := runScanners(automationToolScanners, searchDir, hasSSHKey)
+	scannerToOutput := runScanners(ctx, automationToolScanners, searchDir, opts)
 	detectedAutomationToolScanners := getDetectedScannerNames(scannerToOutput)
 	log.Printf("Detected automation tools: %s", detectedAutomationToolScanners)
-	fmt.Println()
+	log.Printf("")
 
 	// Merge project and tool scanner outputs
 	for scanner, scannerOutput := range projectScannerToOutputs {
@@ -191,17 +374,17 @@ func Config(searchDir string, hasSSHKey bool) models.ScanResultModel {
 			scannerToWarningsWithRecommendation[scanner] = scannerOutput.warningsWithRecommendation
 		}
 		if (len(scannerOutput.errors) > 0 || len(scannerOutput.errorsWithRecommendation) > 0) &&
-			(scannerOutput.status == detected || scannerOutput.status == detectedWithErrors) {
+			(scannerOutput.status == detected || scannerOutput.status == detectedWithErrors || scannerOutput.status == timedOut) {
 			scannerToErrors[scanner] = scannerOutput.errors
 			scannerToErrorsWithRecommendations[scanner] = scannerOutput.errorsWithRecommendation
 		}
 		if len(scannerOutput.configs) > 0 && scannerOutput.status == detected {
 			scannerToOptions[scanner] = scannerOutput.options
-			scannerToConfigMap[scanner] = scannerOutput.configs
+			scannerToConfigMap[scanner] = withTriggerBranch(scannerOutput.configs, opts.defaultBranch)
 		}
 		icons = append(icons, scannerOutput.icons...)
 	}
-	return models.ScanResultModel{
+	result := models.ScanResultModel{
 		ScannerToOptionRoot:                  scannerToOptions,
 		ScannerToBitriseConfigMap:            scannerToConfigMap,
 		ScannerToWarnings:                    scannerToWarnings,
@@ -210,25 +393,194 @@ func Config(searchDir string, hasSSHKey bool) models.ScanResultModel {
 		ScannerToWarningsWithRecommendations: scannerToWarningsWithRecommendation,
 		Icons:                                icons,
 	}
+	if opts.projectConfig != nil {
+		applyProjectConfig(&result, opts.projectConfig)
+	}
+	return result
+}
+
+// withTriggerBranch returns the configs with their push triggers set to the default branch.
+func withTriggerBranch(configs models.BitriseConfigMap, branch string) models.BitriseConfigMap {
+	if branch == "" {
+		return configs
+	}
+
+	updated := make(models.BitriseConfigMap, len(configs))
+	for name, config := range configs {
+		models.SetDefaultBranch(&config.BitriseDataModel, branch)
+		updated[name] = config
+	}
+	return updated
+}
+
+// enabledScanners drops the scanners disabled in the project config, projectConfig can be nil.
+func enabledScanners(scannerList []scanners.ScannerInterface, projectConfig *projectconfig.Config) []scanners.ScannerInterface {
+	var enabled []scanners.ScannerInterface
+	for _, scanner := range scannerList {
+		if projectConfig.IsDisabled(scanner.Name()) {
+			log.TWarnf("%s scanner is disabled in %s", scanner.Name(), projectconfig.FileName)
+			continue
+		}
+		enabled = append(enabled, scanner)
+	}
+	return enabled
+}
+
+// applyProjectConfig applies the answers and preferred configs of the project config to the detected scanners' option trees,
+// and drops the configs no longer available in the option trees.
+func applyProjectConfig(result *models.ScanResultModel, projectConfig *projectconfig.Config) {
+	configResult := &models.ProjectConfigResult{
+		Path:                     projectconfig.FileName,
+		ExcludedPaths:            projectConfig.ExcludePaths,
+		DisabledScanners:         projectConfig.Scanners.Disable,
+		ForcedScanners:           projectConfig.Scanners.Force,
+		ScannerToAnswers:         map[string][]models.ProjectConfigAnswer{},
+		ScannerToPreferredConfig: map[string]string{},
+	}
+
+	for _, scannerName := range sortedScannerNames(projectConfig) {
+		option, ok := result.ScannerToOptionRoot[scannerName]
+		if !ok {
+			configResult.Warnings = append(configResult.Warnings, fmt.Sprintf("%s sets answers or a preferred config for %s, but it did not detect a project", projectconfig.FileName, scannerName))
+			continue
+		}
+
+		applied := projectConfig.Apply(scannerName, &option)
+		result.ScannerToOptionRoot[scannerName] = option
+
+		configs := models.BitriseConfigMap{}
+		for _, configName := range projectconfig.ConfigNames(&option) {
+			if config, ok := result.ScannerToBitriseConfigMap[scannerName][configName]; ok {
+				configs[configName] = config
+			}
+		}
+		result.ScannerToBitriseConfigMap[scannerName] = configs
+
+		if len(applied.Answers) > 0 {
+			configResult.ScannerToAnswers[scannerName] = applied.Answers
+		}
+		if applied.PreferredConfig != "" {
+			configResult.ScannerToPreferredConfig[scannerName] = applied.PreferredConfig
+		}
+		configResult.Warnings = append(configResult.Warnings, applied.Warnings...)
+	}
+
+	for _, warning := range configResult.Warnings {
+		log.TWarnf("%s", warning)
+	}
+	result.ProjectConfig = configResult
+}
+
+// sortedScannerNames returns the names of the scanners with answers or a preferred config in the project config.
+func sortedScannerNames(projectConfig *projectconfig.Config) []string {
+	var names []string
+	for name := range projectConfig.Answers {
+		names = append(names, name)
+	}
+	for name := range projectConfig.PreferredConfigs {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newFileIndex indexes the search dir once, so that the scanners do not need to walk it one by one.
+// Returns nil if the indexing fails, in this case the scanners walk the search dir themselves.
+func newFileIndex(searchDir string, opts fileindex.Options) *fileindex.Index {
+	log.TInfof("Indexing files in: %s", searchDir)
+
+	fileIndex, err := fileindex.New(searchDir, opts)
+	if err != nil {
+		log.TWarnf("Failed to index files: %s", err)
+		return nil
+	}
+
+	log.TPrintf("%d files and directories indexed", len(fileIndex.Entries()))
+	log.Printf("")
+	return fileIndex
+}
+
+func setFileIndex(scannerList []scanners.ScannerInterface, fileIndex *fileindex.Index) {
+	if fileIndex == nil {
+		return
+	}
+
+	for _, scanner := range scannerList {
+		if fileIndexScanner, ok := scanner.(scanners.FileIndexScanner); ok {
+			fileIndexScanner.SetFileIndex(fileIndex)
+		}
+	}
 }
 
-func runScanners(scannerList []scanners.ScannerInterface, searchDir string, hasSSHKey bool) map[string]scannerOutput {
+// runScanners runs the scanners concurrently, then resolves the scanner exclusions in the order of scannerList,
+// so the result is the same as running the scanners one after another and skipping the excluded ones.
+// The scanners implementing scanners.LoggerScanner log to their own buffer, which is printed in the order of scannerList.
+func runScanners(ctx context.Context, scannerList []scanners.ScannerInterface, searchDir string, opts ConfigOptions) map[string]scannerOutput {
+	log.TInfof("Running %d scanners in parallel...", len(scannerList))
+	log.Printf("")
+
+	outputs := make([]scannerOutput, len(scannerList))
+	logs := make([]*scanLog, len(scannerList))
+	loggers := make([]*logger.Logger, len(scannerList))
+	for i, scanner := range scannerList {
+		logs[i] = &scanLog{}
+		loggers[i] = logger.New(logs[i])
+		if loggerScanner, ok := scanner.(scanners.LoggerScanner); ok {
+			loggerScanner.SetLogger(loggers[i])
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, scanner := range scannerList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i] = runScannerWithTimeout(ctx, loggers[i], scanner, searchDir, opts)
+		}()
+	}
+	wg.Wait()
+	log.Printf("")
+
+	return resolveExcludedScanners(scannerList, outputs, logs, opts.projectConfig)
+}
+
+// resolveExcludedScanners applies the exclusions in the order of the scanners, and writes the logs of the scanners in the same order.
+func resolveExcludedScanners(scannerList []scanners.ScannerInterface, outputs []scannerOutput, logs []*scanLog, projectConfig *projectconfig.Config) map[string]scannerOutput {
 	scannerOutputs := map[string]scannerOutput{}
 	var excludedScannerNames []string
-	for _, scanner := range scannerList {
+	for i, scanner := range scannerList {
 		log.TInfof("Scanner: %s", colorstring.Blue(scanner.Name()))
-		if sliceutil.IsStringInSlice(scanner.Name(), excludedScannerNames) {
+		if sliceutil.IsStringInSlice(scanner.Name(), excludedScannerNames) && projectConfig.IsForced(scanner.Name()) {
+			log.TWarnf("scanner is marked as excluded, but it is forced in %s", projectconfig.FileName)
+		} else if sliceutil.IsStringInSlice(scanner.Name(), excludedScannerNames) {
 			log.TWarnf("scanner is marked as excluded, skipping...")
-			fmt.Println()
+			log.Printf("")
 			continue
 		}
 
 		log.TPrintf("+------------------------------------------------------------------------------+")
 		log.TPrintf("|                                                                              |")
-		scannerOutput := runScanner(scanner, searchDir, hasSSHKey)
+		logs[i].flush()
 		log.TPrintf("|                                                                              |")
 		log.TPrintf("+------------------------------------------------------------------------------+")
-		fmt.Println()
+
+		scannerOutput := outputs[i]
+		switch scannerOutput.status {
+		case detected:
+			log.TSuccessf("Detected")
+		case detectedWithErrors:
+			log.TErrorf("Detected with errors")
+		case timedOut:
+			log.TErrorf("Timed out")
+		default:
+			log.TPrintf("Not detected")
+		}
+		if len(scannerOutput.excludedScanners) > 0 {
+			log.TWarnf("Scanner will exclude scanners: %v", scannerOutput.excludedScanners)
+		}
+		log.Printf("")
 
 		scannerOutputs[scanner.Name()] = scannerOutput
 		excludedScannerNames = append(excludedScannerNames, scannerOutput.excludedScanners...)
@@ -236,15 +588,83 @@ func runScanners(scannerList []scanners.ScannerInterface, searchDir string, hasS
 	return scannerOutputs
 }
 
+// runScannerWithTimeout runs the scanner on a separate goroutine and stops waiting for it,
+// when ctx is done or opts.ScannerTimeout elapses.
+// Scanners implementing scanners.ContextScanner are also notified about the cancellation,
+// others are left running in the background and their output and logs are dropped.
+func runScannerWithTimeout(ctx context.Context, logger *logger.Logger, detector scanners.ScannerInterface, searchDir string, opts ConfigOptions) scannerOutput {
+	if opts.ScannerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.ScannerTimeout)
+		defer cancel()
+	}
+
+	if ctx.Err() != nil {
+		return timedOutScannerOutput(logger, detector, ctx.Err(), opts.ScannerTimeout)
+	}
+
+	outputCh := make(chan scannerOutput, 1)
+	if opts.RunningScanners != nil {
+		opts.RunningScanners.Add(1)
+	}
+	go func() {
+		if opts.RunningScanners != nil {
+			defer opts.RunningScanners.Done()
+		}
+		outputCh <- runScanner(ctx, logger, detector, searchDir, opts.HasSSHKey)
+	}()
+
+	select {
+	case output := <-outputCh:
+		if output.status != detected && ctx.Err() != nil {
+			// The scanner returned due to the cancellation
+			return timedOutScannerOutput(logger, detector, ctx.Err(), opts.ScannerTimeout)
+		}
+		return output
+	case <-ctx.Done():
+		return timedOutScannerOutput(logger, detector, ctx.Err(), opts.ScannerTimeout)
+	}
+}
+
+func timedOutScannerOutput(logger *logger.Logger, detector scanners.ScannerInterface, ctxErr error, timeout time.Duration) scannerOutput {
+	var err error
+	if errors.Is(ctxErr, context.DeadlineExceeded) && timeout > 0 {
+		err = fmt.Errorf("%s scanner timed out after %s", detector.Name(), timeout)
+	} else if errors.Is(ctxErr, context.DeadlineExceeded) {
+		err = fmt.Errorf("%s scanner timed out", detector.Name())
+	} else {
+		err = fmt.Errorf("%s scanner cancelled: %w", detector.Name(), ctxErr)
+	}
+
+	data := detectorErrorData(detector.Name(), err)
+	analytics.LogError(scannerTimedOutTag, data, "%s detector timed out", detector.Name())
+
+	logger.TErrorf("Scanner failed, error: %s", err)
+
+	output := scannerOutput{status: timedOut}
+	output.AddErrors(scannerTimedOutTag, err.Error())
+	return output
+}
+
 // Collect output of a specific scanner
-func runScanner(detector scanners.ScannerInterface, searchDir string, hasSSHKey bool) scannerOutput {
+func runScanner(ctx context.Context, logger *logger.Logger, detector scanners.ScannerInterface, searchDir string, hasSSHKey bool) scannerOutput {
 	output := scannerOutput{}
 
-	if isDetect, err := detector.DetectPlatform(searchDir); err != nil {
+	contextScanner, isContextScanner := detector.(scanners.ContextScanner)
+
+	var isDetect bool
+	var err error
+	if isContextScanner {
+		isDetect, err = contextScanner.DetectPlatformWithContext(ctx, searchDir)
+	} else {
+		isDetect, err = detector.DetectPlatform(searchDir)
+	}
+
+	if err != nil {
 		data := detectorErrorData(detector.Name(), err)
 		analytics.LogError(detectPlatformFailedTag, data, "%s detector DetectPlatform failed", detector.Name())
 
-		log.TErrorf("Scanner failed, error: %s", err)
+		logger.TErrorf("Scanner failed, error: %s", err)
 
 		output.status = notDetected
 		output.AddWarnings(detectPlatformFailedTag, err.Error())
@@ -254,7 +674,14 @@ func runScanner(detector scanners.ScannerInterface, searchDir string, hasSSHKey
 		return output
 	}
 
-	options, projectWarnings, icons, err := detector.Options()
+	var options models.OptionNode
+	var projectWarnings models.Warnings
+	var icons models.Icons
+	if isContextScanner {
+		options, projectWarnings, icons, err = contextScanner.OptionsWithContext(ctx)
+	} else {
+		options, projectWarnings, icons, err = detector.Options()
+	}
 	output.AddWarnings(optionsFailedTag, []string(projectWarnings)...)
 	for _, warning := range projectWarnings {
 		data := detectorErrorData(detector.Name(), errors.New(warning))
@@ -265,7 +692,7 @@ func runScanner(detector scanners.ScannerInterface, searchDir string, hasSSHKey
 		data := detectorErrorData(detector.Name(), err)
 		analytics.LogError(optionsFailedTag, data, "%s detector Options failed", detector.Name())
 
-		log.TErrorf("Analyzer failed, error: %s", err)
+		logger.TErrorf("Analyzer failed, error: %s", err)
 
 		// Error returned as a warning
 		output.status = detectedWithErrors
@@ -285,17 +712,61 @@ func runScanner(detector scanners.ScannerInterface, searchDir string, hasSSHKey
 		data := detectorErrorData(detector.Name(), err)
 		analytics.LogError(configsFailedTag, data, "%s detector Configs failed", detector.Name())
 
-		log.TErrorf("Failed to generate config, error: %s", err)
+		logger.TErrorf("Failed to generate config, error: %s", err)
 
 		output.status = detectedWithErrors
 		output.AddErrors(configsFailedTag, err.Error())
 		return output
 	}
+	if errs := configs.Validate(); len(errs) > 0 {
+		// The invalid configs and the options selecting them are dropped, the scanner fails only if no config is left
+		configs = maps.Clone(configs)
+		var invalidConfigs []string
+		for _, err := range errs {
+			data := detectorErrorData(detector.Name(), err)
+			analytics.LogError(configsFailedTag, data, "%s detector generated an invalid config", detector.Name())
+
+			logger.TErrorf("Invalid config generated, error: %s", err)
+
+			output.AddErrors(configsFailedTag, err.Error())
+
+			var invalidConfigErr *models.InvalidConfigError
+			if errors.As(err, &invalidConfigErr) {
+				invalidConfigs = append(invalidConfigs, invalidConfigErr.Name)
+				delete(configs, invalidConfigErr.Name)
+			}
+		}
 
-	scannerExcludedScanners := detector.ExcludedScannerNames()
-	if len(scannerExcludedScanners) > 0 {
-		log.TWarnf("Scanner will exclude scanners: %v", scannerExcludedScanners)
+		prunedOptions := options.WithoutConfigs(invalidConfigs...)
+		if len(configs) == 0 || prunedOptions == nil {
+			output.status = detectedWithErrors
+			return output
+		}
+		options = *prunedOptions
 	}
+	// Only the issues breaking the option tree fail the scanner
+	issues, optionWarnings := models.CheckOptionTree(options, configs)
+	output.AddWarnings(optionsFailedTag, optionWarnings...)
+	for _, warning := range optionWarnings {
+		data := detectorErrorData(detector.Name(), errors.New(warning))
+		analytics.LogWarn(optionsFailedTag, data, "%s detector options warning", detector.Name())
+
+		logger.TWarnf("Options warning: %s", warning)
+	}
+	if len(issues) > 0 {
+		for _, issue := range issues {
+			data := detectorErrorData(detector.Name(), errors.New(issue))
+			analytics.LogError(configsFailedTag, data, "%s detector options don't match its configs", detector.Name())
+		}
+
+		logger.TErrorf("Options don't match the generated configs: %s", strings.Join(issues, ", "))
+
+		output.status = detectedWithErrors
+		output.AddErrors(configsFailedTag, issues...)
+		return output
+	}
+
+	scannerExcludedScanners := scanners.ExcludedScannerNames(detector)
 
 	output.status = detected
 	output.options = options
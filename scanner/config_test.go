@@ -1,12 +1,30 @@
 package scanner
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/bitrise-io/bitrise-init/errormapper"
+	"github.com/bitrise-io/bitrise-init/logger"
+	"github.com/bitrise-io/bitrise-init/scanners"
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/stretchr/testify/require"
 
 	"github.com/bitrise-io/bitrise-init/models"
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
 )
 
 var GradlewNotFoundRecommendation = errormapper.NewDetailedErrorRecommendation(errormapper.DetailedError{
@@ -46,3 +64,637 @@ func Test_scannerOutput_AddErrors(t *testing.T) {
 		})
 	}
 }
+
+type fakeScanner struct {
+	name     string
+	delay    time.Duration
+	excluded []string
+	// detect is called by DetectPlatform if set
+	detect func()
+}
+
+func (s fakeScanner) Name() string { return s.name }
+
+func (s fakeScanner) DetectPlatform(string) (bool, error) {
+	if s.detect != nil {
+		s.detect()
+	}
+	time.Sleep(s.delay)
+	return true, nil
+}
+
+func (s fakeScanner) ExcludedScannerNames() []string { return s.excluded }
+
+func (s fakeScanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	option := models.NewOption("Title", "Summary", "ENV_KEY", models.TypeSelector)
+	option.AddConfig("value", models.NewConfigOption(s.name+"-config", nil))
+	return *option, nil, nil, nil
+}
+
+func (s fakeScanner) DefaultOptions() models.OptionNode { return models.OptionNode{} }
+
+func (s fakeScanner) Configs(models.SSHKeyActivation) (models.BitriseConfigMap, error) {
+	return models.BitriseConfigMap{s.name + "-config": models.NewConfig(bitriseModels.BitriseDataModel{FormatVersion: "11"})}, nil
+}
+
+func (s fakeScanner) DefaultConfigs() (models.BitriseConfigMap, error) { return nil, nil }
+
+type fakeLoggerScanner struct {
+	fakeScanner
+	logger *logger.Logger
+	// log is called by DetectPlatform with the logger of the scanner
+	log func(logger *logger.Logger)
+}
+
+func (s *fakeLoggerScanner) SetLogger(logger *logger.Logger) { s.logger = logger }
+
+func (s *fakeLoggerScanner) DetectPlatform(searchDir string) (bool, error) {
+	s.log(s.logger)
+	return s.fakeScanner.DetectPlatform(searchDir)
+}
+
+type fakeContextScanner struct {
+	fakeScanner
+}
+
+func (s fakeContextScanner) DetectPlatformWithContext(ctx context.Context, _ string) (bool, error) {
+	select {
+	case <-time.After(s.delay):
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func (s fakeContextScanner) OptionsWithContext(context.Context) (models.OptionNode, models.Warnings, models.Icons, error) {
+	return s.Options()
+}
+
+func Test_runScanners_timeout(t *testing.T) {
+	scannerList := []scanners.ScannerInterface{
+		fakeScanner{name: "slow", delay: time.Minute},
+		fakeContextScanner{fakeScanner{name: "slow-context", delay: time.Minute}},
+		fakeScanner{name: "fast"},
+	}
+
+	outputs := runScanners(context.Background(), scannerList, t.TempDir(), ConfigOptions{ScannerTimeout: 100 * time.Millisecond})
+
+	require.Equal(t, timedOut, outputs["slow"].status)
+	require.Equal(t, []models.ErrorWithRecommendations{{
+		Error:           "slow scanner timed out after 100ms",
+		Recommendations: errormapper.NewDetailedErrorRecommendation(newScannerTimedOutDetail("slow scanner timed out after 100ms")),
+	}}, []models.ErrorWithRecommendations(outputs["slow"].errorsWithRecommendation))
+	require.Equal(t, timedOut, outputs["slow-context"].status)
+
+	require.Equal(t, detected, outputs["fast"].status)
+	require.Equal(t, models.BitriseConfigMap{"fast-config": models.NewConfig(bitriseModels.BitriseDataModel{FormatVersion: "11"})}, outputs["fast"].configs)
+}
+
+func Test_runScanners_runningScanners(t *testing.T) {
+	var runningScanners sync.WaitGroup
+	scannerList := []scanners.ScannerInterface{fakeScanner{name: "slow", delay: 500 * time.Millisecond}}
+
+	outputs := runScanners(context.Background(), scannerList, t.TempDir(), ConfigOptions{ScannerTimeout: 10 * time.Millisecond, RunningScanners: &runningScanners})
+	require.Equal(t, timedOut, outputs["slow"].status)
+
+	// the timed out scanner is still tracked
+	start := time.Now()
+	runningScanners.Wait()
+	require.Greater(t, time.Since(start), 100*time.Millisecond)
+}
+
+type invalidConfigScanner struct {
+	fakeScanner
+}
+
+func (s invalidConfigScanner) Configs(models.SSHKeyActivation) (models.BitriseConfigMap, error) {
+	return models.BitriseConfigMap{
+		s.name + "-config": models.NewConfig(bitriseModels.BitriseDataModel{
+			FormatVersion: "11",
+			Workflows:     map[string]bitriseModels.WorkflowModel{"primary": {}},
+		}),
+		"other-config": models.NewConfig(bitriseModels.BitriseDataModel{
+			Workflows: map[string]bitriseModels.WorkflowModel{"primary": {}},
+		}),
+	}, nil
+}
+
+func Test_runScanner_invalidConfig(t *testing.T) {
+	output := runScanner(context.Background(), nil, invalidConfigScanner{fakeScanner{name: "invalid"}}, t.TempDir(), false)
+
+	require.Equal(t, detectedWithErrors, output.status)
+	require.Nil(t, output.configs)
+	// every invalid config is reported
+	require.Equal(t, []models.ErrorWithRecommendations{
+		{
+			Error:           "generated config (invalid-config) is invalid: workflow (primary) has no steps",
+			Recommendations: errormapper.NewDetailedErrorRecommendation(newInvalidConfigDetail("", "invalid-config", "workflow (primary) has no steps")),
+		},
+		{
+			Error:           "generated config (other-config) is invalid: missing format_version",
+			Recommendations: errormapper.NewDetailedErrorRecommendation(newInvalidConfigDetail("", "other-config", "missing format_version")),
+		},
+	}, []models.ErrorWithRecommendations(output.errorsWithRecommendation))
+}
+
+func TestConfig_invalidConfigs(t *testing.T) {
+	require.NoError(t, scanners.Register(scanners.Registration{
+		Type: scanners.ProjectScannerType,
+		New:  func() scanners.ScannerInterface { return invalidConfigScanner{fakeScanner{name: "invalid"}} },
+	}))
+	defer func() {
+		require.NoError(t, scanners.Unregister("invalid"))
+	}()
+
+	result := Config(t.TempDir(), false)
+	var errs []string
+	for _, err := range result.ScannerToErrorsWithRecommendations["invalid"] {
+		errs = append(errs, err.Error)
+	}
+	require.Equal(t, []string{
+		"generated config (invalid-config) is invalid: workflow (primary) has no steps",
+		"generated config (other-config) is invalid: missing format_version",
+	}, errs)
+}
+
+type partlyInvalidConfigScanner struct {
+	fakeScanner
+}
+
+func (s partlyInvalidConfigScanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	option := models.NewOption("Title", "Summary", "ENV_KEY", models.TypeSelector)
+	option.AddConfig("valid", models.NewConfigOption(s.name+"-config", nil))
+	option.AddConfig("invalid", models.NewConfigOption("invalid-config", nil))
+	option.DefaultValue = "invalid"
+	option.SetValueInfo("invalid", models.ValueInfo{Recommended: true})
+	return *option, nil, nil, nil
+}
+
+func (s partlyInvalidConfigScanner) Configs(models.SSHKeyActivation) (models.BitriseConfigMap, error) {
+	return models.BitriseConfigMap{
+		s.name + "-config": models.NewConfig(bitriseModels.BitriseDataModel{FormatVersion: "11"}),
+		"invalid-config": models.NewConfig(bitriseModels.BitriseDataModel{
+			FormatVersion: "11",
+			Workflows:     map[string]bitriseModels.WorkflowModel{"primary": {}},
+		}),
+	}, nil
+}
+
+func Test_runScanner_partlyInvalidConfigs(t *testing.T) {
+	output := runScanner(context.Background(), nil, partlyInvalidConfigScanner{fakeScanner{name: "partly"}}, t.TempDir(), false)
+
+	require.Equal(t, detected, output.status)
+	require.Equal(t, models.BitriseConfigMap{"partly-config": models.NewConfig(bitriseModels.BitriseDataModel{FormatVersion: "11"})}, output.configs)
+	require.Equal(t, []string{"valid"}, slices.Collect(maps.Keys(output.options.ChildOptionMap)))
+	require.Empty(t, output.options.DefaultValue)
+	require.Empty(t, output.options.ValueInfos)
+	require.Len(t, output.errorsWithRecommendation, 1)
+	require.Equal(t, "generated config (invalid-config) is invalid: workflow (primary) has no steps", output.errorsWithRecommendation[0].Error)
+}
+
+type unselectedConfigScanner struct {
+	fakeScanner
+}
+
+func (s unselectedConfigScanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
+	configs, err := s.fakeScanner.Configs(sshKeyActivation)
+	configs["other-config"] = configs[s.name+"-config"]
+	configs["third-config"] = configs[s.name+"-config"]
+	return configs, err
+}
+
+func Test_runScanner_optionTreeMismatch(t *testing.T) {
+	output := runScanner(context.Background(), nil, unselectedConfigScanner{fakeScanner{name: "mismatch"}}, t.TempDir(), false)
+
+	require.Equal(t, detectedWithErrors, output.status)
+	require.Nil(t, output.configs)
+	require.Len(t, output.errorsWithRecommendation, 2)
+	require.Equal(t, "config (other-config) is not selected by any option", output.errorsWithRecommendation[0].Error)
+	require.Equal(t, "config (third-config) is not selected by any option", output.errorsWithRecommendation[1].Error)
+}
+
+type unselectableDefaultScanner struct {
+	fakeScanner
+}
+
+func (s unselectableDefaultScanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	option, warnings, icons, err := s.fakeScanner.Options()
+	option.DefaultValue = "other"
+	return option, warnings, icons, err
+}
+
+func Test_runScanner_optionTreeWarning(t *testing.T) {
+	output := runScanner(context.Background(), nil, unselectableDefaultScanner{fakeScanner{name: "warning"}}, t.TempDir(), false)
+
+	require.Equal(t, detected, output.status)
+	require.Len(t, output.configs, 1)
+	require.Empty(t, output.errorsWithRecommendation)
+	require.Len(t, output.warningsWithRecommendation, 1)
+	require.Equal(t, `option (Title) has a default value which can't be selected: "other"`, output.warningsWithRecommendation[0].Error)
+}
+
+func Test_runScanners_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	outputs := runScanners(ctx, []scanners.ScannerInterface{fakeScanner{name: "fast"}}, t.TempDir(), ConfigOptions{})
+
+	require.Equal(t, timedOut, outputs["fast"].status)
+	require.Equal(t, "fast scanner cancelled: context canceled", outputs["fast"].errorsWithRecommendation[0].Error)
+}
+
+func Test_runScanners_timedOutScannerDropped(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutWriter(&logs)
+	defer log.SetOutWriter(os.Stdout)
+
+	// The scanner doesn't support cancellation, it keeps running after the timeout.
+	release := make(chan struct{})
+	logged := make(chan struct{})
+	scannerList := []scanners.ScannerInterface{
+		&fakeLoggerScanner{fakeScanner: fakeScanner{name: "slow"}, log: func(logger *logger.Logger) {
+			<-release
+			logger.TPrintf("late scanner log")
+			close(logged)
+		}},
+	}
+
+	outputs := runScanners(context.Background(), scannerList, t.TempDir(), ConfigOptions{ScannerTimeout: 100 * time.Millisecond})
+	require.Equal(t, timedOut, outputs["slow"].status)
+
+	close(release)
+	<-logged
+	require.Equal(t, timedOut, outputs["slow"].status)
+	require.Nil(t, outputs["slow"].configs)
+	require.NotContains(t, logs.String(), "late scanner log")
+}
+
+func Test_runScanners_exclusionResolvedInOrder(t *testing.T) {
+	// "first" excludes "second", so the result of "second" is dropped, and its exclusion of "third" is not applied,
+	// even if "second" finishes first.
+	scannerList := []scanners.ScannerInterface{
+		fakeScanner{name: "first", delay: 100 * time.Millisecond, excluded: []string{"second"}},
+		fakeScanner{name: "second", excluded: []string{"third"}},
+		fakeScanner{name: "third"},
+	}
+
+	outputs := runScanners(context.Background(), scannerList, t.TempDir(), ConfigOptions{})
+
+	require.Len(t, outputs, 2)
+	require.Equal(t, detected, outputs["first"].status)
+	require.Equal(t, detected, outputs["third"].status)
+	require.NotContains(t, outputs, "second")
+}
+
+func Test_runScanners_parallel(t *testing.T) {
+	// Every scanner waits for the other scanners to start, they only finish if they run in parallel.
+	var barrier sync.WaitGroup
+	barrier.Add(3)
+	detect := func() {
+		barrier.Done()
+		barrier.Wait()
+	}
+	scannerList := []scanners.ScannerInterface{
+		fakeScanner{name: "first", detect: detect},
+		fakeScanner{name: "second", detect: detect},
+		fakeScanner{name: "third", detect: detect},
+	}
+
+	outputs := runScanners(context.Background(), scannerList, t.TempDir(), ConfigOptions{ScannerTimeout: 10 * time.Second})
+
+	for _, scanner := range scannerList {
+		require.Equal(t, detected, outputs[scanner.Name()].status, scanner.Name())
+	}
+}
+
+func Test_runScanners_logsInOrder(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutWriter(&logs)
+	defer log.SetOutWriter(os.Stdout)
+
+	// "second" logs before "first", the logs are still written in the order of the scanners.
+	secondLogged := make(chan struct{})
+	scannerList := []scanners.ScannerInterface{
+		&fakeLoggerScanner{fakeScanner: fakeScanner{name: "first"}, log: func(logger *logger.Logger) {
+			<-secondLogged
+			logger.TPrintf("first scanner log")
+		}},
+		&fakeLoggerScanner{fakeScanner: fakeScanner{name: "second"}, log: func(logger *logger.Logger) {
+			logger.TPrintf("second scanner log")
+			close(secondLogged)
+		}},
+	}
+
+	runScanners(context.Background(), scannerList, t.TempDir(), ConfigOptions{})
+
+	// every line is searched for after the previous one
+	output := logs.String()
+	for _, line := range []string{"Scanner: ", "first scanner log", "Scanner: ", "second scanner log"} {
+		idx := strings.Index(output, line)
+		require.NotEqual(t, -1, idx, "%s not found in order in the logs: %s", line, logs.String())
+		output = output[idx+len(line):]
+	}
+}
+
+func TestConfig_concurrentScans(t *testing.T) {
+	nodeJSDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(nodeJSDir, "package.json"), []byte(`{"name": "app", "scripts": {"test": "jest"}}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(nodeJSDir, "package-lock.json"), []byte(`{}`), 0600))
+
+	rubyDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rubyDir, "Gemfile"), []byte(`source "https://rubygems.org"`), 0600))
+
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	const scansPerDir = 4
+	results := make([]models.ScanResultModel, 2*scansPerDir)
+
+	var wg sync.WaitGroup
+	for i := range results {
+		searchDir := nodeJSDir
+		if i%2 == 1 {
+			searchDir = rubyDir
+		}
+
+		wg.Add(1)
+		go func(i int, searchDir string) {
+			defer wg.Done()
+			results[i] = Config(searchDir, false)
+		}(i, searchDir)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		wantScanner := "node-js"
+		if i%2 == 1 {
+			wantScanner = "ruby"
+		}
+
+		require.Len(t, result.ScannerToBitriseConfigMap, 1)
+		require.Contains(t, result.ScannerToBitriseConfigMap, wantScanner)
+	}
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, workDir, currentDir)
+}
+
+func TestConfig_registeredScanner(t *testing.T) {
+	require.NoError(t, scanners.Register(scanners.Registration{
+		Type:             scanners.ProjectScannerType,
+		New:              func() scanners.ScannerInterface { return fakeScanner{name: "in-house"} },
+		Priority:         10000,
+		ExcludedScanners: []string{"node-js"},
+	}))
+	defer func() {
+		require.NoError(t, scanners.Unregister("in-house"))
+	}()
+
+	require.Contains(t, availableScanners(), "in-house")
+
+	nodeJSDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(nodeJSDir, "package.json"), []byte(`{"name": "app", "scripts": {"test": "jest"}}`), 0600))
+
+	result := Config(nodeJSDir, false)
+	require.Contains(t, result.ScannerToBitriseConfigMap, "in-house")
+	require.NotContains(t, result.ScannerToBitriseConfigMap, "node-js")
+
+	manualResult, err := ManualConfig()
+	require.NoError(t, err)
+	require.Contains(t, manualResult.ScannerToOptionRoot, "in-house")
+}
+
+func TestConfigFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"web/package.json":      {Data: []byte(`{"name": "web", "scripts": {"test": "jest", "lint": "eslint"}}`)},
+		"web/package-lock.json": {Data: []byte(`{}`)},
+		"web/.nvmrc":            {Data: []byte("22\n")},
+		"api/Gemfile":           {Data: []byte("source \"https://rubygems.org\"\ngem \"rails\"\ngem \"pg\"\n")},
+		"api/Gemfile.lock":      {Data: []byte("")},
+		"api/config/database.yml": {Data: []byte(`test:
+  adapter: postgresql
+  host: <%= ENV.fetch("DB_HOST") { "localhost" } %>
+`)},
+		"android/gradlew":                          {Data: []byte("#!/bin/sh\n"), Mode: 0755},
+		"android/settings.gradle":                  {Data: []byte("include ':app'\n")},
+		"android/build.gradle":                     {Data: []byte("")},
+		"android/app/build.gradle":                 {Data: []byte("plugins {\n  id 'com.android.application'\n}\n")},
+		"android/app/src/main/AndroidManifest.xml": {Data: []byte("<manifest/>")},
+	}
+
+	searchDir := t.TempDir()
+	for pth, file := range fsys {
+		require.NoError(t, os.MkdirAll(filepath.Join(searchDir, filepath.Dir(pth)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(searchDir, pth), file.Data, 0755))
+	}
+
+	want := Config(searchDir, false)
+	got := ConfigFS(context.Background(), fsys, ConfigOptions{})
+
+	for _, scannerName := range []string{"node-js", "ruby", "android"} {
+		require.Contains(t, got.ScannerToBitriseConfigMap, scannerName)
+	}
+	require.Equal(t, want.ScannerToBitriseConfigMap, got.ScannerToBitriseConfigMap)
+	require.Equal(t, want.ScannerToOptionRoot, got.ScannerToOptionRoot)
+}
+
+var snapshotTestFiles = map[string]string{
+	"web/package.json":      `{"name": "web", "scripts": {"test": "jest"}}`,
+	"web/package-lock.json": `{}`,
+	"api/Gemfile":           "source \"https://rubygems.org\"\ngem \"rails\"\n",
+	"api/Gemfile.lock":      "",
+}
+
+func snapshotTestFS() fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for pth, content := range snapshotTestFiles {
+		fsys[pth] = &fstest.MapFile{Data: []byte(content)}
+	}
+	return fsys
+}
+
+func TestConfigGitTree(t *testing.T) {
+	workDir := t.TempDir()
+	repoDir := filepath.Join(t.TempDir(), "repo.git")
+	runGit := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	runGit("init", "-q", workDir)
+	for pth, content := range snapshotTestFiles {
+		require.NoError(t, os.MkdirAll(filepath.Join(workDir, filepath.Dir(pth)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, pth), []byte(content), 0644))
+	}
+	runGit("-C", workDir, "add", "-A")
+	runGit("-C", workDir, "commit", "-q", "-m", "initial")
+	commit := runGit("-C", workDir, "rev-parse", "HEAD")
+	runGit("clone", "-q", "--bare", workDir, repoDir)
+
+	want := ConfigFS(context.Background(), snapshotTestFS(), ConfigOptions{})
+	got := ConfigGitTree(context.Background(), repoDir, commit, ConfigOptions{})
+
+	for _, scannerName := range []string{"node-js", "ruby"} {
+		require.Contains(t, got.ScannerToBitriseConfigMap, scannerName)
+	}
+	require.Equal(t, want.ScannerToBitriseConfigMap, got.ScannerToBitriseConfigMap)
+	require.Equal(t, want.ScannerToOptionRoot, got.ScannerToOptionRoot)
+
+	result := ConfigGitTree(context.Background(), repoDir, "unknown-branch", ConfigOptions{})
+	require.Len(t, result.ScannerToErrorsWithRecommendations["general"], 1)
+	require.Contains(t, result.ScannerToErrorsWithRecommendations["general"][0].Error, "Failed to read git commit")
+}
+
+func TestConfigTarball(t *testing.T) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for pth, content := range snapshotTestFiles {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: pth, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	want := ConfigFS(context.Background(), snapshotTestFS(), ConfigOptions{})
+	got := ConfigTarball(context.Background(), bytes.NewReader(buf.Bytes()), 1<<20, ConfigOptions{})
+
+	for _, scannerName := range []string{"node-js", "ruby"} {
+		require.Contains(t, got.ScannerToBitriseConfigMap, scannerName)
+	}
+	require.Equal(t, want.ScannerToBitriseConfigMap, got.ScannerToBitriseConfigMap)
+	require.Equal(t, want.ScannerToOptionRoot, got.ScannerToOptionRoot)
+
+	result := ConfigTarball(context.Background(), bytes.NewReader(buf.Bytes()), 10, ConfigOptions{})
+	require.Len(t, result.ScannerToErrorsWithRecommendations["general"], 1)
+	require.Contains(t, result.ScannerToErrorsWithRecommendations["general"][0].Error, "exceeds the size limit")
+}
+
+func TestConfig_projectConfig(t *testing.T) {
+	searchDir := t.TempDir()
+	files := map[string]string{
+		"package.json":          `{"name": "app", "scripts": {"test": "jest"}}`,
+		"package-lock.json":     `{}`,
+		"web/package.json":      `{"name": "web", "scripts": {"lint": "eslint"}}`,
+		"examples/package.json": `{"name": "example"}`,
+		"Gemfile":               `source "https://rubygems.org"`,
+		".bitrise-init.yml": `exclude_paths:
+- examples
+scanners:
+  disable:
+  - ruby
+answers:
+  node-js:
+    Projects: single project
+    NODEJS_PROJECT_DIR: web
+    Package Manager: yarn
+`,
+	}
+	for pth, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(searchDir, filepath.Dir(pth)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(searchDir, pth), []byte(content), 0600))
+	}
+
+	result := Config(searchDir, false)
+	require.NotContains(t, result.ScannerToBitriseConfigMap, "ruby")
+	require.Len(t, result.ScannerToBitriseConfigMap["node-js"], 1)
+	require.Contains(t, result.ScannerToBitriseConfigMap["node-js"], "node-js-yarn-lint-config")
+
+	option := result.ScannerToOptionRoot["node-js"]
+	require.Equal(t, []string{models.SingleProjectOptionValue}, option.GetValues())
+	projectOption := option.ChildOptionMap[models.SingleProjectOptionValue]
+	require.Equal(t, []string{"web"}, projectOption.GetValues())
+	require.Equal(t, []string{"yarn"}, projectOption.ChildOptionMap["web"].GetValues())
+
+	require.Equal(t, &models.ProjectConfigResult{
+		Path:             ".bitrise-init.yml",
+		ExcludedPaths:    []string{"examples"},
+		DisabledScanners: []string{"ruby"},
+		ScannerToAnswers: map[string][]models.ProjectConfigAnswer{
+			"node-js": {
+				{Title: "Projects", Value: "single project"},
+				{Title: "Project Directory", EnvKey: "NODEJS_PROJECT_DIR", Value: "web"},
+				{Title: "Package Manager", Value: "yarn"},
+			},
+		},
+		ScannerToPreferredConfig: map[string]string{},
+	}, result.ProjectConfig)
+}
+
+func TestConfig_invalidProjectConfig(t *testing.T) {
+	searchDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(searchDir, ".bitrise-init.yml"), []byte("unknown: true\n"), 0600))
+
+	result := Config(searchDir, false)
+	require.Len(t, result.ScannerToErrorsWithRecommendations["general"], 1)
+	require.Contains(t, result.ScannerToErrorsWithRecommendations["general"][0].Error, "invalid .bitrise-init.yml")
+}
+
+func TestConfig_defaultBranch(t *testing.T) {
+	searchDir := t.TempDir()
+	files := map[string]string{
+		"package.json":      `{"name": "app", "scripts": {"test": "jest"}}`,
+		"package-lock.json": `{}`,
+		".git/HEAD":         "ref: refs/heads/develop\n",
+	}
+	for pth, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(searchDir, filepath.Dir(pth)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(searchDir, pth), []byte(content), 0600))
+	}
+
+	wantTriggerMap := bitriseModels.TriggerMapModel{
+		{Type: bitriseModels.PullRequestType, WorkflowID: "run_tests", PullRequestSourceBranch: "*"},
+		{Type: bitriseModels.CodePushType, WorkflowID: "run_tests", PushBranch: "develop"},
+	}
+
+	result := Config(searchDir, false)
+	for _, config := range result.ScannerToBitriseConfigMap["node-js"] {
+		require.Equal(t, wantTriggerMap, config.TriggerMap)
+	}
+
+	fsys := fstest.MapFS{}
+	for pth, content := range files {
+		fsys[pth] = &fstest.MapFile{Data: []byte(content)}
+	}
+	result = ConfigFS(context.Background(), fsys, ConfigOptions{})
+	for _, config := range result.ScannerToBitriseConfigMap["node-js"] {
+		require.Equal(t, wantTriggerMap, config.TriggerMap)
+	}
+}
+
+func TestConfig_monorepo(t *testing.T) {
+	fsys := fstest.MapFS{
+		"web/package.json":          {Data: []byte(`{"name": "web", "scripts": {"test": "jest"}}`)},
+		"web/package-lock.json":     {Data: []byte(`{}`)},
+		"web/.nvmrc":                {Data: []byte("22\n")},
+		"packages/api/package.json": {Data: []byte(`{"name": "api", "scripts": {"lint": "eslint"}}`)},
+		"packages/api/yarn.lock":    {Data: []byte("")},
+		".git/HEAD":                 {Data: []byte("ref: refs/heads/develop\n")},
+	}
+
+	result := ConfigFS(context.Background(), fsys, ConfigOptions{})
+	option := result.ScannerToOptionRoot["node-js"]
+	require.Equal(t, models.MonorepoOptionTitle, option.Title)
+	require.Empty(t, option.EnvKey)
+	require.ElementsMatch(t, []string{"web", "packages/api"}, option.ChildOptionMap[models.SingleProjectOptionValue].GetValues())
+
+	config, err := ResolveConfig(result, Answers{models.MonorepoOptionTitle: models.AllProjectsOptionValue})
+	require.NoError(t, err)
+	_, err = config.Validate()
+	require.NoError(t, err)
+	require.Empty(t, config.App.Environments)
+	require.Empty(t, config.TriggerMap)
+	require.Equal(t, []string{"_setup", "run_tests_packages_api", "run_tests_web"}, slices.Sorted(maps.Keys(config.Workflows)))
+
+	web := config.Workflows["run_tests_web"]
+	require.Equal(t, []string{"_setup"}, web.BeforeRun)
+	require.Equal(t, bitriseModels.ToolsModel{"node": "22"}, web.Tools)
+	require.Equal(t, bitriseModels.Triggers{
+		PushTriggers:        []bitriseModels.PushGitEventTriggerItem{{Branch: "develop", ChangedFiles: "web/**"}},
+		PullRequestTriggers: []bitriseModels.PullRequestGitEventTriggerItem{{SourceBranch: "*", ChangedFiles: "web/**"}},
+	}, web.Triggers)
+	require.Equal(t, "packages/api/**", config.Workflows["run_tests_packages_api"].Triggers.PushTriggers[0].ChangedFiles)
+}
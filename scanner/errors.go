@@ -25,6 +25,10 @@ func mapRecommendation(tag, err string) step.Recommendation {
 		matcher = newDetectPlatformFailedMatcher()
 	case optionsFailedTag:
 		matcher = newOptionsFailedMatcher()
+	case configsFailedTag:
+		matcher = newConfigsFailedMatcher()
+	case scannerTimedOutTag:
+		matcher = newScannerTimedOutMatcher()
 	}
 
 	if matcher == nil {
@@ -96,6 +100,40 @@ func newOptionsFailedMatcher() *errormapper.PatternErrorMatcher {
 
 var newOptionsFailedGenericDetail = newDetectPlatformFailedGenericDetail
 
+// configsFailedTag
+func newConfigsFailedMatcher() *errormapper.PatternErrorMatcher {
+	return newPatternErrorMatcher(
+		newGenericDetail,
+		map[string]errormapper.DetailedErrorBuilder{
+			`generated config \((.+)\) is invalid: (.+)`: newInvalidConfigDetail,
+		},
+	)
+}
+
+func newInvalidConfigDetail(_ string, params ...string) errormapper.DetailedError {
+	configName := params[0]
+	reason := params[1]
+	return errormapper.DetailedError{
+		Title:       fmt.Sprintf("We generated an invalid configuration (%s) for your project.", configName),
+		Description: fmt.Sprintf("The bitrise.yml we generated wouldn't run, so it was dropped. You can skip auto-configuration and set up your project manually, and please report the issue to us. The configuration was rejected with the following error:\n%s", reason),
+	}
+}
+
+// scannerTimedOutTag
+func newScannerTimedOutMatcher() *errormapper.PatternErrorMatcher {
+	return newPatternErrorMatcher(
+		newScannerTimedOutDetail,
+		nil,
+	)
+}
+
+func newScannerTimedOutDetail(errorMsg string) errormapper.DetailedError {
+	return errormapper.DetailedError{
+		Title:       "We couldn't finish scanning your project in time.",
+		Description: fmt.Sprintf("Scanning your project took too long, so it was stopped. This can happen with very large repositories or when a project file can't be parsed quickly. You can try again, or skip auto-configuration and set up your project manually. Our auto-configurator returned the following error:\n%s", errorMsg),
+	}
+}
+
 func newGradlewNotFoundDetail(_ string, _ ...string) errormapper.DetailedError {
 	return errormapper.DetailedError{
 		Title:       "We couldn't find your Gradle Wrapper. Please make sure there is a gradlew file in your project's root directory.",
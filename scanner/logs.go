@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/bitrise-io/go-utils/log"
+)
+
+// scanLog collects the logs of a scanner, the scanners run in parallel and their logs are printed in the order of the scanners.
+type scanLog struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	flushed bool
+}
+
+// Write implements io.Writer, the logs written after flush are dropped.
+func (l *scanLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.flushed {
+		// The scanner timed out and was left running in the background
+		return len(p), nil
+	}
+	return l.buf.Write(p)
+}
+
+// flush prints the collected logs with the go-utils log package.
+func (l *scanLog) flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flushed = true
+	if l.buf.Len() == 0 {
+		return
+	}
+	log.Printf("%s", strings.TrimSuffix(l.buf.String(), "\n"))
+	l.buf.Reset()
+}
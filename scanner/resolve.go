@@ -0,0 +1,274 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+)
+
+// PlatformAnswerKey is the answer key selecting the platform (scanner), if the scan detected more platforms.
+// A comma separated list of platforms selects the configs of every listed platform, merged into one config (see models.MergeConfigs).
+const PlatformAnswerKey = "platform"
+
+// Answers are the option values used by ResolveConfig, keyed by the option env keys or titles.
+// When more platforms are selected, the keys can be prefixed with the platform (java/PROJECT_ROOT_DIR),
+// to answer an option asked by more platforms differently.
+type Answers map[string]string
+
+// ParseAnswers parses a YAML answers file: a mapping of option env keys or titles to values, for example:
+//
+//	platform: ios
+//	BITRISE_PROJECT_PATH: App.xcworkspace
+//	BITRISE_SCHEME: App
+//	BITRISE_DISTRIBUTION_METHOD: app-store
+func ParseAnswers(content []byte) (Answers, error) {
+	var answers Answers
+	if err := yaml.Unmarshal(content, &answers); err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
+
+// ReadAnswersFile reads and parses the YAML answers file at pth, see ParseAnswers.
+func ReadAnswersFile(pth string) (Answers, error) {
+	content, err := os.ReadFile(pth)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := ParseAnswers(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse answers file (%s): %w", pth, err)
+	}
+	return answers, nil
+}
+
+// AnswerError is returned by ResolveConfig if the answer for an option is missing or invalid.
+type AnswerError struct {
+	Title  string `json:"title"`
+	EnvKey string `json:"env_key,omitempty"`
+	// Value is the invalid answer, empty if the answer is missing.
+	Value   string `json:"value,omitempty"`
+	Missing bool   `json:"missing,omitempty"`
+	// AvailableValues are the allowed answers, empty if the option accepts any value.
+	AvailableValues []string `json:"available_values,omitempty"`
+	// Reason tells why the answer is invalid, if it breaks the validation rules of the option.
+	Reason string `json:"reason,omitempty"`
+}
+
+func (e *AnswerError) Error() string {
+	option := fmt.Sprintf("%q", e.Title)
+	if e.EnvKey != "" {
+		option += fmt.Sprintf(" (%s)", e.EnvKey)
+	}
+
+	msg := fmt.Sprintf("invalid answer for %s: %s", option, e.Value)
+	if e.Reason != "" {
+		msg += ", " + e.Reason
+	}
+	if e.Missing {
+		msg = fmt.Sprintf("missing answer for %s", option)
+	}
+	if len(e.AvailableValues) > 0 {
+		msg += fmt.Sprintf(", available values: %s", strings.Join(e.AvailableValues, ", "))
+	}
+	return msg
+}
+
+func (answers Answers) lookup(option models.OptionNode) (string, bool) {
+	if option.EnvKey != "" {
+		if value, ok := answers[option.EnvKey]; ok {
+			return value, true
+		}
+	}
+	value, ok := answers[option.Title]
+	return value, ok
+}
+
+// ResolveConfig is the non-interactive variant of AskForConfig: it selects the platform and walks its option tree with the answers,
+// instead of prompting for the values.
+// The platform can be selected by the PlatformAnswerKey answer, it is required if the scan detected more platforms.
+// Returns an *AnswerError if an answer is missing or invalid.
+func ResolveConfig(scanResult models.ScanResultModel, answers Answers) (models.Config, error) {
+	var platforms []string
+	for platform := range scanResult.ScannerToOptionRoot {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	platform, ok := answers[PlatformAnswerKey]
+	if ok && strings.Contains(platform, ",") && len(platforms) > 0 {
+		return resolveMergedConfig(scanResult, platforms, strings.Split(platform, ","), answers)
+	}
+	if len(platforms) == 0 {
+		return models.Config{}, errors.New("no platform detected")
+	} else if ok && !slices.Contains(platforms, platform) {
+		return models.Config{}, &AnswerError{Title: PlatformAnswerKey, Value: platform, AvailableValues: platforms}
+	} else if !ok && len(platforms) == 1 {
+		platform = platforms[0]
+	} else if !ok {
+		return models.Config{}, &AnswerError{Title: PlatformAnswerKey, Missing: true, AvailableValues: platforms}
+	}
+
+	configName, appEnvs, err := ResolveOptions(scanResult.ScannerToOptionRoot[platform], answers)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	return buildConfig(scanResult, platform, configName, appEnvs)
+}
+
+// resolveMergedConfig resolves the config of every selected platform with the answers, and merges them into one config.
+func resolveMergedConfig(scanResult models.ScanResultModel, platforms, selectedPlatforms []string, answers Answers) (models.Config, error) {
+	var parts []models.ConfigPart
+	for _, platform := range selectedPlatforms {
+		platform = strings.TrimSpace(platform)
+		if !slices.Contains(platforms, platform) {
+			return models.Config{}, &AnswerError{Title: PlatformAnswerKey, Value: platform, AvailableValues: platforms}
+		}
+		if slices.ContainsFunc(parts, func(part models.ConfigPart) bool { return part.Namespace == platform }) {
+			continue
+		}
+
+		configName, appEnvs, err := ResolveOptions(scanResult.ScannerToOptionRoot[platform], answers.forPlatform(platform))
+		if err != nil {
+			return models.Config{}, err
+		}
+		config, err := buildConfig(scanResult, platform, configName, appEnvs)
+		if err != nil {
+			return models.Config{}, err
+		}
+		parts = append(parts, models.ConfigPart{Namespace: platform, Config: config})
+	}
+
+	return models.MergeConfigs(parts)
+}
+
+// forPlatform returns the answers with the answers prefixed with the platform overriding the not prefixed ones.
+func (answers Answers) forPlatform(platform string) Answers {
+	platformAnswers := maps.Clone(answers)
+	for key, value := range answers {
+		if scopedKey, scoped := strings.CutPrefix(key, platform+"/"); scoped {
+			platformAnswers[scopedKey] = value
+		}
+	}
+	return platformAnswers
+}
+
+// ResolveOptions is the non-interactive variant of AskForOptions: it walks the option tree with the answers,
+// and returns the selected config name and the app envs set by the answers.
+// Returns an *AnswerError if an answer is missing or invalid.
+func ResolveOptions(options models.OptionNode, answers Answers) (string, []envmanModels.EnvironmentItemModel, error) {
+	appEnvs := []envmanModels.EnvironmentItemModel{}
+
+	option := &options
+	for option != nil && !option.IsConfigOption() {
+		value, next, err := resolveOptionValue(*option, answers)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if option.EnvKey != "" {
+			appEnvs = append(appEnvs, envmanModels.EnvironmentItemModel{option.EnvKey: value})
+		}
+		option = next
+	}
+
+	if option == nil {
+		return "", nil, errors.New("no config selected")
+	}
+	return option.Config, appEnvs, nil
+}
+
+// resolveOptionValue checks the answer of the option according to the option's type,
+// and returns the option's value and the next option in the tree.
+func resolveOptionValue(option models.OptionNode, answers Answers) (string, *models.OptionNode, error) {
+	values := getOptions(option.ChildOptionMap)
+	sort.Strings(values)
+
+	answerError := &AnswerError{Title: option.Title, EnvKey: option.EnvKey}
+	value, answered := answers.lookup(option)
+	value = strings.TrimSpace(value)
+
+	switch option.Type {
+	case models.TypeSelector, models.TypeOptionalSelector:
+		answerError.AvailableValues = values
+		if !answered {
+			if len(values) != 1 {
+				answerError.Missing = true
+				return "", nil, answerError
+			}
+			value = values[0]
+		}
+
+		if child, ok := option.ChildOptionMap[value]; ok {
+			return value, child, nil
+		}
+		if option.Type == models.TypeSelector || value == "" || len(values) == 0 {
+			answerError.Value = value
+			answerError.Missing = value == ""
+			return "", nil, answerError
+		}
+		if err := option.ValidateValue(value); err != nil {
+			// the listed values are not the only valid answers
+			answerError.Value = value
+			answerError.AvailableValues = nil
+			answerError.Reason = err.Error()
+			return "", nil, answerError
+		}
+		// custom value of an optional selector, the options following the custom value are the same as for any listed value
+		return value, option.ChildOptionMap[values[0]], nil
+	case models.TypeMultiSelector:
+		answerError.AvailableValues = option.Values
+		if !answered {
+			if len(values) != 1 {
+				answerError.Missing = true
+				return "", nil, answerError
+			}
+			value = values[0]
+		}
+
+		value = models.MultiSelectKey(value)
+		if child, ok := option.ChildOptionMap[value]; ok {
+			return value, child, nil
+		}
+		answerError.Value = value
+		answerError.Missing = value == ""
+		return "", nil, answerError
+	case models.TypeUserInput, models.TypeOptionalUserInput:
+		if !answered && len(values) == 1 {
+			// the single value of a user input is its default value
+			value = values[0]
+		}
+		if value == "" && option.Type == models.TypeUserInput {
+			answerError.Missing = true
+			return "", nil, answerError
+		}
+		if err := option.ValidateValue(value); err != nil {
+			answerError.Value = value
+			answerError.Reason = err.Error()
+			return "", nil, answerError
+		}
+
+		if child, ok := option.ChildOptionMap[value]; ok {
+			return value, child, nil
+		}
+		if len(values) != 1 {
+			answerError.Value = value
+			answerError.AvailableValues = values
+			return "", nil, answerError
+		}
+		return value, option.ChildOptionMap[values[0]], nil
+	}
+
+	return "", nil, fmt.Errorf("option %q has invalid type: %s", option.Title, option.Type)
+}
@@ -0,0 +1,228 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+// newResolveTestScanResult creates a scan result with an ios option tree:
+// project path (selector) -> scheme (validated user input) -> export method (validated optional selector) -> config.
+func newResolveTestScanResult() models.ScanResultModel {
+	projectOption := models.NewOption("Project or Workspace path", "", "BITRISE_PROJECT_PATH", models.TypeSelector)
+	for _, project := range []string{"App.xcodeproj", "Other.xcodeproj"} {
+		exportMethodOption := models.NewOption("Export method", "", "BITRISE_EXPORT_METHOD", models.TypeOptionalSelector)
+		exportMethodOption.Validation = &models.Validation{AllowedValues: []string{"app-store", "development", "ad-hoc", "enterprise"}}
+		for _, method := range []string{"app-store", "development"} {
+			exportMethodOption.AddConfig(method, models.NewConfigOption(project+"-config", nil))
+		}
+
+		schemeOption := models.NewOption("Scheme name", "", "BITRISE_SCHEME", models.TypeUserInput)
+		schemeOption.Validation = &models.Validation{Pattern: `^\w+$`, Hint: "a scheme name"}
+		schemeOption.AddOption(models.UserInputOptionDefaultValue, exportMethodOption)
+		projectOption.AddOption(project, schemeOption)
+	}
+
+	androidOption := models.NewOption("Module", "", "MODULE", models.TypeUserInput)
+	androidOption.AddConfig("app", models.NewConfigOption("android-config", nil))
+
+	config := func(workflow string) models.Config {
+		return models.NewConfig(bitriseModels.BitriseDataModel{
+			FormatVersion: "13",
+			App:           bitriseModels.AppModel{Environments: []envmanModels.EnvironmentItemModel{{"FASTLANE_XCODE_LIST_TIMEOUT": "120"}}},
+			Workflows:     map[string]bitriseModels.WorkflowModel{workflow: {}},
+		})
+	}
+
+	return models.ScanResultModel{
+		ScannerToOptionRoot: map[string]models.OptionNode{
+			"ios":     *projectOption,
+			"android": *androidOption,
+		},
+		ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{
+			"ios": {
+				"App.xcodeproj-config":   config("app"),
+				"Other.xcodeproj-config": config("other"),
+			},
+			"android": {"android-config": config("android")},
+		},
+	}
+}
+
+func TestResolveConfig(t *testing.T) {
+	scanResult := newResolveTestScanResult()
+
+	config, err := ResolveConfig(scanResult, Answers{
+		PlatformAnswerKey:          "ios",
+		"BITRISE_PROJECT_PATH":     "Other.xcodeproj",
+		"Scheme name":              "Other",
+		"BITRISE_EXPORT_METHOD":    "enterprise",
+		"UNUSED_ANSWER_IS_IGNORED": "value",
+	})
+	require.NoError(t, err)
+	require.Contains(t, config.Workflows, "other")
+	require.Equal(t, []envmanModels.EnvironmentItemModel{
+		{"FASTLANE_XCODE_LIST_TIMEOUT": "120"},
+		{"BITRISE_PROJECT_PATH": "Other.xcodeproj"},
+		{"BITRISE_SCHEME": "Other"},
+		{"BITRISE_EXPORT_METHOD": "enterprise"},
+	}, config.App.Environments)
+
+	// the user input's single value is its default value
+	config, err = ResolveConfig(scanResult, Answers{PlatformAnswerKey: "android"})
+	require.NoError(t, err)
+	require.Contains(t, config.Workflows, "android")
+	require.Equal(t, envmanModels.EnvironmentItemModel{"MODULE": "app"}, config.App.Environments[1])
+}
+
+func TestResolveConfig_morePlatforms(t *testing.T) {
+	scanResult := newResolveTestScanResult()
+
+	config, err := ResolveConfig(scanResult, Answers{
+		PlatformAnswerKey:      "ios, android",
+		"BITRISE_PROJECT_PATH": "App.xcodeproj",
+		"BITRISE_SCHEME":       "App",
+		"Export method":        "app-store",
+		"android/MODULE":       "lib",
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.MergedProjectType, config.ProjectType)
+	require.Contains(t, config.Workflows, "ios_app")
+	require.Contains(t, config.Workflows, "android_android")
+	require.Equal(t, []envmanModels.EnvironmentItemModel{
+		{"FASTLANE_XCODE_LIST_TIMEOUT": "120"},
+		{"BITRISE_PROJECT_PATH": "App.xcodeproj"},
+		{"BITRISE_SCHEME": "App"},
+		{"BITRISE_EXPORT_METHOD": "app-store"},
+		{"MODULE": "lib"},
+	}, config.App.Environments)
+
+	_, err = ResolveConfig(scanResult, Answers{PlatformAnswerKey: "android,web"})
+	require.EqualError(t, err, `invalid answer for "platform": web, available values: android, ios`)
+}
+
+func TestResolveConfig_answerErrors(t *testing.T) {
+	scanResult := newResolveTestScanResult()
+	validAnswers := Answers{
+		PlatformAnswerKey:       "ios",
+		"BITRISE_PROJECT_PATH":  "App.xcodeproj",
+		"BITRISE_SCHEME":        "App",
+		"BITRISE_EXPORT_METHOD": "app-store",
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		value   *string
+		wantErr string
+	}{
+		{
+			name:    "missing platform",
+			key:     PlatformAnswerKey,
+			wantErr: `missing answer for "platform", available values: android, ios`,
+		},
+		{
+			name:    "invalid platform",
+			key:     PlatformAnswerKey,
+			value:   ptr("web"),
+			wantErr: `invalid answer for "platform": web, available values: android, ios`,
+		},
+		{
+			name:    "missing selector value",
+			key:     "BITRISE_PROJECT_PATH",
+			wantErr: `missing answer for "Project or Workspace path" (BITRISE_PROJECT_PATH), available values: App.xcodeproj, Other.xcodeproj`,
+		},
+		{
+			name:    "invalid selector value",
+			key:     "BITRISE_PROJECT_PATH",
+			value:   ptr("Missing.xcodeproj"),
+			wantErr: `invalid answer for "Project or Workspace path" (BITRISE_PROJECT_PATH): Missing.xcodeproj, available values: App.xcodeproj, Other.xcodeproj`,
+		},
+		{
+			name:    "empty user input",
+			key:     "BITRISE_SCHEME",
+			value:   ptr(" "),
+			wantErr: `missing answer for "Scheme name" (BITRISE_SCHEME)`,
+		},
+		{
+			name:    "invalid user input",
+			key:     "BITRISE_SCHEME",
+			value:   ptr("My App"),
+			wantErr: `invalid answer for "Scheme name" (BITRISE_SCHEME): My App, the value doesn't match the pattern ^\w+$, expected a scheme name`,
+		},
+		{
+			name:    "invalid optional selector value",
+			key:     "BITRISE_EXPORT_METHOD",
+			value:   ptr("ipa"),
+			wantErr: `invalid answer for "Export method" (BITRISE_EXPORT_METHOD): ipa, the value is not one of the allowed values (app-store, development, ad-hoc, enterprise)`,
+		},
+		{
+			name:    "missing optional selector value",
+			key:     "BITRISE_EXPORT_METHOD",
+			wantErr: `missing answer for "Export method" (BITRISE_EXPORT_METHOD), available values: app-store, development`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := Answers{}
+			for key, value := range validAnswers {
+				answers[key] = value
+			}
+			delete(answers, tt.key)
+			if tt.value != nil {
+				answers[tt.key] = *tt.value
+			}
+
+			_, err := ResolveConfig(scanResult, answers)
+			require.EqualError(t, err, tt.wantErr)
+
+			var answerErr *AnswerError
+			require.True(t, errors.As(err, &answerErr))
+		})
+	}
+}
+
+func TestResolveOptions_multiSelector(t *testing.T) {
+	platformsOption := models.NewOption("Platforms to build", "", "PLATFORMS", models.TypeMultiSelector)
+	platformsOption.AddConfig(models.MultiSelectKey("ios", "android"), models.NewConfigOption("both-config", nil))
+	platformsOption.AddConfig("android", models.NewConfigOption("android-config", nil))
+	platformsOption.AddConfig("", models.NewConfigOption("test-config", nil))
+
+	configName, appEnvs, err := ResolveOptions(*platformsOption, Answers{"PLATFORMS": "ios, android"})
+	require.NoError(t, err)
+	require.Equal(t, "both-config", configName)
+	require.Equal(t, []envmanModels.EnvironmentItemModel{{"PLATFORMS": "android,ios"}}, appEnvs)
+
+	configName, _, err = ResolveOptions(*platformsOption, Answers{"PLATFORMS": ""})
+	require.NoError(t, err)
+	require.Equal(t, "test-config", configName)
+
+	_, _, err = ResolveOptions(*platformsOption, Answers{"PLATFORMS": "ios"})
+	require.EqualError(t, err, `invalid answer for "Platforms to build" (PLATFORMS): ios, available values: android, ios`)
+
+	_, _, err = ResolveOptions(*platformsOption, Answers{})
+	require.EqualError(t, err, `missing answer for "Platforms to build" (PLATFORMS), available values: android, ios`)
+}
+
+func TestReadAnswersFile(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "answers.yml")
+	require.NoError(t, os.WriteFile(pth, []byte("platform: android\nMODULE: app\nBUILD_NUMBER: 12\n"), 0600))
+
+	answers, err := ReadAnswersFile(pth)
+	require.NoError(t, err)
+	require.Equal(t, Answers{PlatformAnswerKey: "android", "MODULE": "app", "BUILD_NUMBER": "12"}, answers)
+
+	require.NoError(t, os.WriteFile(pth, []byte("- platform\n"), 0600))
+	_, err = ReadAnswersFile(pth)
+	require.ErrorContains(t, err, "failed to parse answers file")
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -1,12 +1,14 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 
 	"github.com/bitrise-io/bitrise-init/analytics"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/bitrise-init/errormapper"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/output"
@@ -17,9 +19,9 @@ import (
 
 // GenerateScanResult runs the scanner, returns the results and if any platform was detected.
 func GenerateScanResult(searchDir string, hasSSHKey bool) (models.ScanResultModel, bool) {
-	scanResult := Config(searchDir, hasSSHKey)
+	scanResult, fileIndex := scan(context.Background(), searchDir, ConfigOptions{HasSSHKey: hasSSHKey})
 
-	logUnknownTools(searchDir)
+	logUnknownTools(searchDir, fileIndex)
 
 	var platforms []string
 	for platform := range scanResult.ScannerToOptionRoot {
@@ -54,23 +56,29 @@ func GenerateAndWriteResults(searchDir string, outputDir string, format output.F
 	log.TPrintf("scan result: %s", outputPth)
 
 	if !detected {
-		printDirTree()
+		printDirTree(searchDir)
 		//nolint:staticcheck // Other components potentially rely on the error message
 		return result, fmt.Errorf("No known platform detected")
 	}
 	return result, nil
 }
 
-func printDirTree() {
+func printDirTree(dir string) {
 	cmd := command.New("which", "tree")
 	out, err := cmd.RunAndReturnTrimmedCombinedOutput()
 	if err != nil || out == "" {
 		log.TErrorf("tree not installed, can not list files")
 	} else {
-		fmt.Println()
-		cmd := command.NewWithStandardOuts("tree", ".", "-L", "3")
+		log.Printf("")
+		cmd := command.New("tree", ".", "-L", "3")
+		cmd.SetDir(dir)
 		log.TPrintf("$ %s", cmd.PrintableCommandArgs())
-		if err := cmd.Run(); err != nil {
+		// printed with the logs, to keep the standard output of the CLI for its JSON output
+		out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+		if out != "" {
+			log.Printf("%s", out)
+		}
+		if err != nil {
 			log.TErrorf("Failed to list files in current directory, error: %s", err)
 		}
 	}
@@ -91,9 +99,15 @@ func writeScanResult(scanResult models.ScanResultModel, outputDir string, format
 	return output.WriteToFile(scanResult, format, path.Join(outputDir, "result"))
 }
 
-func logUnknownTools(searchDir string) {
+func logUnknownTools(searchDir string, fileIndex *fileindex.Index) {
 	for _, detector := range UnknownToolDetectors {
-		result, err := detector.DetectToolIn(searchDir)
+		var result DetectionResult
+		var err error
+		if indexedDetector, ok := detector.(IndexedUnknownToolDetector); ok && fileIndex != nil {
+			result, err = indexedDetector.DetectToolInIndex(fileIndex)
+		} else {
+			result, err = detector.DetectToolIn(searchDir)
+		}
 		if err != nil {
 			log.Warnf("Failed to detect %s: %s", detector.ToolName(), err)
 		}
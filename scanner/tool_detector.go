@@ -1,12 +1,12 @@
 package scanner
 
 import (
-	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/go-utils/sliceutil"
 
 	"github.com/bitrise-io/go-utils/log"
@@ -23,6 +23,13 @@ type UnknownToolDetector interface {
 	DetectToolIn(rootPath string) (DetectionResult, error)
 }
 
+// IndexedUnknownToolDetector contains additional methods (relative to UnknownToolDetector)
+// implemented by detectors, which can search the shared file index of the scan instead of walking rootPath.
+type IndexedUnknownToolDetector interface {
+	// DetectToolInIndex should search the indexed files looking for the given tool
+	DetectToolInIndex(index *fileindex.Index) (DetectionResult, error)
+}
+
 // DetectionResult ...
 type DetectionResult struct {
 	Detected    bool
@@ -61,10 +68,15 @@ func (d toolDetector) ToolName() string {
 }
 
 func (d toolDetector) DetectToolIn(rootPath string) (DetectionResult, error) {
-	fileNames, _, tree, err := walkProjectDir(rootPath)
+	index, err := newToolDetectorIndex(rootPath)
 	if err != nil {
 		return DetectionResult{}, err
 	}
+	return d.DetectToolInIndex(index)
+}
+
+func (d toolDetector) DetectToolInIndex(index *fileindex.Index) (DetectionResult, error) {
+	fileNames, _, tree := walkProjectDir(index)
 
 	if sliceutil.IsStringInSlice(d.primaryFile, fileNames) {
 		return DetectionResult{
@@ -95,10 +107,15 @@ func (d kotlinMultiplatformDetector) ToolName() string {
 }
 
 func (d kotlinMultiplatformDetector) DetectToolIn(rootPath string) (DetectionResult, error) {
-	fileNames, filePaths, tree, err := walkProjectDir(rootPath)
+	index, err := newToolDetectorIndex(rootPath)
 	if err != nil {
 		return DetectionResult{}, err
 	}
+	return d.DetectToolInIndex(index)
+}
+
+func (d kotlinMultiplatformDetector) DetectToolInIndex(index *fileindex.Index) (DetectionResult, error) {
+	fileNames, filePaths, tree := walkProjectDir(index)
 
 	fileNamePattern := `.+\.gradle(\.kts)?$`
 	re, err := regexp.Compile(fileNamePattern)
@@ -137,52 +154,54 @@ func (d kotlinMultiplatformDetector) canFindPatternIn(fileContent string) bool {
 		strings.Contains(fileContent, `org.jetbrains.kotlin.multiplatform`)
 }
 
-// walkProjectDir recursively walks through every file and directory up to the defined depth limit while ignoring some
+// newToolDetectorIndex indexes rootPath up to the depth used by walkProjectDir.
+func newToolDetectorIndex(rootPath string) (*fileindex.Index, error) {
+	return fileindex.New(rootPath, fileindex.Options{
+		MaxDepth:    maxDepth + 1,
+		IgnoredDirs: excludedDirs,
+	})
+}
+
+// walkProjectDir iterates over every indexed file and directory up to the defined depth limit while ignoring some
 // directories. It returns with a list of fileNames, a list of (absolute) filePaths and a visual tree representation
 // of the directory structure (taking the depth limit and ignored folders into account)
-func walkProjectDir(rootPath string) (fileNames []string, filePaths []string, tree string, err error) {
+func walkProjectDir(index *fileindex.Index) (fileNames []string, filePaths []string, tree string) {
 	treeBuilder := strings.Builder{}
 
-	err = filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			log.Warnf("error while traversing %s: %s", path, err)
-			return filepath.SkipDir
-		}
-
-		relativePath, err := filepath.Rel(rootPath, path)
-		if err != nil {
-			log.Warnf(err.Error())
-			return filepath.SkipDir
+	// Entries are listed in walk order, so an excluded or too deep directory is always visited before its contents.
+	var skippedDirs []string
+	for _, entry := range index.Entries() {
+		if isInSkippedDir(entry.AbsPath, skippedDirs) {
+			continue
 		}
 
-		depth := strings.Count(relativePath, string(filepath.Separator)) + 1
-
-		if d.IsDir() {
-			if sliceutil.IsStringInSlice(d.Name(), excludedDirs) {
-				return filepath.SkipDir
-			}
-			if depth > maxDepth {
-				return filepath.SkipDir
-			}
+		if entry.IsDir && (sliceutil.IsStringInSlice(entry.Name, excludedDirs) || entry.Depth > maxDepth) {
+			skippedDirs = append(skippedDirs, entry.AbsPath)
+			continue
 		}
 
-		fileNames = append(fileNames, d.Name())
-		filePaths = append(filePaths, path)
+		fileNames = append(fileNames, entry.Name)
+		filePaths = append(filePaths, entry.AbsPath)
 
 		var treePrefix = ""
-		if depth > 1 {
-			treePrefix = strings.Repeat("· ", depth-1)
+		if entry.Depth > 1 {
+			treePrefix = strings.Repeat("· ", entry.Depth-1)
 		}
-		var entryName = d.Name()
-		if d.IsDir() {
+		var entryName = entry.Name
+		if entry.IsDir {
 			entryName = entryName + "/"
 		}
-		if relativePath != "." {
-			treeBuilder.WriteString(treePrefix + entryName + "\n")
-		}
+		treeBuilder.WriteString(treePrefix + entryName + "\n")
+	}
 
-		return nil
-	})
+	return fileNames, filePaths, treeBuilder.String()
+}
 
-	return fileNames, filePaths, treeBuilder.String(), err
+func isInSkippedDir(pth string, skippedDirs []string) bool {
+	for _, dir := range skippedDirs {
+		if strings.HasPrefix(pth, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
 }
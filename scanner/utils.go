@@ -3,42 +3,75 @@ package scanner
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
-	yaml "gopkg.in/yaml.v2"
-
 	"github.com/bitrise-io/bitrise-init/models"
-	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
 	"github.com/bitrise-io/goinp/goinp"
 )
 
 func getDefaultValue(opt models.OptionNode) string {
 	if opt.Type == models.TypeOptionalSelector {
+		// asked for a custom value, not one of the listed values
 		return ""
 	}
+	if opt.DefaultValue != "" {
+		return opt.DefaultValue
+	}
 
-	for key := range opt.ChildOptionMap {
-		return key
+	options := getOptions(opt.ChildOptionMap)
+	if len(options) == 0 {
+		return ""
 	}
-	return ""
+	return options[0]
 }
 
 func getOptions(opts map[string]*models.OptionNode) (options []string) {
 	for key := range opts {
 		options = append(options, key)
 	}
+	sort.Strings(options)
 	return
 }
 
-func selectOption(options []string) (string, error) {
+// valueLabel returns the value with its recommendation, if the option has one for it.
+func valueLabel(option models.OptionNode, value string) string {
+	info, ok := option.ValueInfos[value]
+	if !ok || !info.Recommended {
+		return value
+	}
+
+	details := []string{"recommended"}
+	if info.Reason != "" {
+		details = append(details, info.Reason)
+	}
+	if info.DetectedFrom != "" {
+		details = append(details, "detected in "+info.DetectedFrom)
+	}
+	return fmt.Sprintf("%s (%s)", value, strings.Join(details, ", "))
+}
+
+// optionNumbers returns the numbers of the (non-empty) values in the list, as they are typed in by the user.
+func optionNumbers(options []string, values ...string) string {
+	var numbers []string
 	for i, option := range options {
-		fmt.Printf("[%d] : %s\n", i+1, option)
+		if option != "" && slices.Contains(values, option) {
+			numbers = append(numbers, strconv.Itoa(i+1))
+		}
+	}
+	return strings.Join(numbers, models.MultiSelectSeparator)
+}
+
+func selectOption(options []string, labels []string, defaultAnswer string) (string, error) {
+	for i, label := range labels {
+		fmt.Printf("[%d] : %s\n", i+1, label)
 	}
 	fmt.Printf("Type in the option's number, then hit Enter: ")
 
-	answer, err := goinp.AskForOptionalInput("", false)
+	answer, err := goinp.AskForOptionalInput(defaultAnswer, false)
 	if err != nil {
 		return "", err
 	}
@@ -55,6 +88,49 @@ func selectOption(options []string) (string, error) {
 	return options[optionNo-1], nil
 }
 
+// selectOptions asks for any number of the options, the empty answer selects none of them.
+func selectOptions(options []string, labels []string, defaultAnswer string) ([]string, error) {
+	for i, label := range labels {
+		fmt.Printf("[%d] : %s\n", i+1, label)
+	}
+	fmt.Printf("Type in the options' numbers separated by commas (or nothing to select none), then hit Enter: ")
+
+	answer, err := goinp.AskForOptionalInput(defaultAnswer, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []string
+	for _, item := range strings.Split(answer, models.MultiSelectSeparator) {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		optionNo, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse option number (%s), pick numbers from 1-%d", item, len(options))
+		}
+		if optionNo-1 < 0 || optionNo-1 >= len(options) {
+			return nil, fmt.Errorf("invalid option number (%d), pick numbers from 1-%d", optionNo, len(options))
+		}
+		selected = append(selected, options[optionNo-1])
+	}
+	return selected, nil
+}
+
+// multiSelectKeys returns the supported selections of a multi-select option, "(none)" stands for the empty selection.
+func multiSelectKeys(option models.OptionNode) []string {
+	var keys []string
+	for key := range option.ChildOptionMap {
+		if key == "" {
+			key = "(none)"
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func askForOptionValue(option models.OptionNode) (string, string, error) {
 	const customValueOptionText = "<custom value>"
 
@@ -78,7 +154,12 @@ func askForOptionValue(option models.OptionNode) (string, string, error) {
 			return option.EnvKey, options[0], nil
 		}
 
-		selected, err := selectOption(options)
+		labels := make([]string, len(options))
+		for i, value := range options {
+			labels[i] = valueLabel(option, value)
+		}
+
+		selected, err := selectOption(options, labels, optionNumbers(options, option.DefaultValue))
 		if err != nil {
 			return "", "", err
 		}
@@ -93,10 +174,40 @@ func askForOptionValue(option models.OptionNode) (string, string, error) {
 		if optional {
 			suffix = " (optional): "
 		}
+		if option.DefaultValue != "" && option.Type != models.TypeOptionalSelector {
+			fmt.Println("Recommended value for \"" + option.Title + "\": " + valueLabel(option, option.DefaultValue))
+		}
 		fmt.Print("Enter value for \"" + option.Title + "\"" + suffix)
 
 		answer, err := goinp.AskForOptionalInput(getDefaultValue(option), optional)
-		return option.EnvKey, strings.TrimSpace(answer), err
+		if err != nil {
+			return "", "", err
+		}
+
+		answer = strings.TrimSpace(answer)
+		if err := option.ValidateValue(answer); err != nil {
+			return "", "", fmt.Errorf("invalid value (%s) for \"%s\": %w", answer, option.Title, err)
+		}
+		return option.EnvKey, answer, nil
+	case models.TypeMultiSelector:
+		fmt.Println("Select \"" + option.Title + "\" from the list:")
+
+		labels := make([]string, len(option.Values))
+		for i, value := range option.Values {
+			labels[i] = valueLabel(option, value)
+		}
+
+		defaultValues := strings.Split(option.DefaultValue, models.MultiSelectSeparator)
+		selected, err := selectOptions(option.Values, labels, optionNumbers(option.Values, defaultValues...))
+		if err != nil {
+			return "", "", err
+		}
+
+		key := models.MultiSelectKey(selected...)
+		if _, ok := option.ChildOptionMap[key]; !ok {
+			return "", "", fmt.Errorf("the selected values (%s) are not supported together, supported selections: %s", key, strings.Join(multiSelectKeys(option), "; "))
+		}
+		return option.EnvKey, key, nil
 	}
 
 	return "", "", fmt.Errorf("invalid input type")
@@ -163,7 +274,7 @@ func AskForOptions(options models.OptionNode) (string, []envmanModels.Environmen
 }
 
 // AskForConfig ...
-func AskForConfig(scanResult models.ScanResultModel) (bitriseModels.BitriseDataModel, error) {
+func AskForConfig(scanResult models.ScanResultModel) (models.Config, error) {
 
 	//
 	// Select platform
@@ -174,15 +285,15 @@ func AskForConfig(scanResult models.ScanResultModel) (bitriseModels.BitriseDataM
 
 	platform := ""
 	if len(platforms) == 0 {
-		return bitriseModels.BitriseDataModel{}, errors.New("no platform detected")
+		return models.Config{}, errors.New("no platform detected")
 	} else if len(platforms) == 1 {
 		platform = platforms[0]
 	} else {
 		fmt.Println("Select platform:")
 		var err error
-		platform, err = selectOption(platforms)
+		platform, err = selectOption(platforms, platforms, "")
 		if err != nil {
-			return bitriseModels.BitriseDataModel{}, err
+			return models.Config{}, err
 		}
 	}
 	// ---
@@ -191,27 +302,27 @@ func AskForConfig(scanResult models.ScanResultModel) (bitriseModels.BitriseDataM
 	// Select config
 	options, ok := scanResult.ScannerToOptionRoot[platform]
 	if !ok {
-		return bitriseModels.BitriseDataModel{}, fmt.Errorf("invalid platform selected: %s", platform)
+		return models.Config{}, fmt.Errorf("invalid platform selected: %s", platform)
 	}
 
 	configPth, appEnvs, err := AskForOptions(options)
 	if err != nil {
-		return bitriseModels.BitriseDataModel{}, err
+		return models.Config{}, err
 	}
 	// --
 
-	//
-	// Build config
-	configMap := scanResult.ScannerToBitriseConfigMap[platform]
-	configStr := configMap[configPth]
+	return buildConfig(scanResult, platform, configPth, appEnvs)
+}
 
-	var config bitriseModels.BitriseDataModel
-	if err := yaml.Unmarshal([]byte(configStr), &config); err != nil {
-		return bitriseModels.BitriseDataModel{}, fmt.Errorf("failed to unmarshal config, error: %w", err)
+// buildConfig returns the platform's config with the app envs selected by the options.
+func buildConfig(scanResult models.ScanResultModel, platform, configName string, appEnvs []envmanModels.EnvironmentItemModel) (models.Config, error) {
+	config, ok := scanResult.ScannerToBitriseConfigMap[platform][configName]
+	if !ok {
+		return models.Config{}, fmt.Errorf("config (%s) not found for platform: %s", configName, platform)
 	}
 
-	config.App.Environments = append(config.App.Environments, appEnvs...)
-	// ---
+	// the scan result's config is not modified
+	config.App.Environments = append(append([]envmanModels.EnvironmentItemModel{}, config.App.Environments...), appEnvs...)
 
 	return config, nil
 }
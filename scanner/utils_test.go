@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDefaultValue(t *testing.T) {
+	schemeOption := models.NewOption("Scheme", "", "BITRISE_SCHEME", models.TypeSelector)
+	schemeOption.AddConfig("Other", models.NewConfigOption("other-config", nil))
+	schemeOption.AddConfig("App", models.NewConfigOption("app-config", nil))
+	require.Equal(t, "App", getDefaultValue(*schemeOption))
+
+	schemeOption.SetValueInfo("Other", models.ValueInfo{Recommended: true, Reason: "the scheme has test targets", DetectedFrom: "App.xcodeproj"})
+	require.Equal(t, "Other", getDefaultValue(*schemeOption))
+	require.Equal(t, "Other (recommended, the scheme has test targets, detected in App.xcodeproj)", valueLabel(*schemeOption, "Other"))
+	require.Equal(t, "App", valueLabel(*schemeOption, "App"))
+	require.Equal(t, "2", optionNumbers(getOptions(schemeOption.ChildOptionMap), schemeOption.DefaultValue))
+
+	// the custom value of an optional selector is asked without a default
+	schemeOption.Type = models.TypeOptionalSelector
+	require.Equal(t, "", getDefaultValue(*schemeOption))
+
+	variantOption := models.NewOption("Variant", "", "VARIANT", models.TypeOptionalUserInput)
+	variantOption.AddConfig(models.UserInputOptionDefaultValue, models.NewConfigOption("config", nil))
+	require.Equal(t, "", getDefaultValue(*variantOption))
+	variantOption.SetValueInfo("debug", models.ValueInfo{Recommended: true})
+	require.Equal(t, "debug", getDefaultValue(*variantOption))
+}
+
+func TestOptionNumbers(t *testing.T) {
+	options := []string{"android", "ios", "web"}
+	require.Equal(t, "1,3", optionNumbers(options, "web", "android"))
+	require.Equal(t, "", optionNumbers(options, ""))
+	require.Equal(t, "", optionNumbers([]string{"", "App"}, ""))
+}
@@ -3,21 +3,19 @@ package android
 import (
 	"bufio"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
-	"gopkg.in/yaml.v2"
-
 	"github.com/bitrise-io/bitrise-init/analytics"
 	"github.com/bitrise-io/bitrise-init/detectors/direntry"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/bitrise-init/detectors/gradle"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/java"
 	"github.com/bitrise-io/bitrise-init/steps"
 	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 /*
@@ -37,12 +35,14 @@ const (
 	testsWorkflowID         = "run_tests"
 	testsWorkflowSummary    = "Run your Android unit tests and get the test report."
 	testWorkflowDescription = "The workflow will first clone your Git repository, cache your Gradle dependencies, install Android tools, run your Android unit tests and save the test report."
+	testsWorkflowReason     = "Runs the unit tests of the project on every pull request."
 
 	testPipelineID = "run_tests"
 
 	runInstrumentedTestsWorkflowID          = "run_instrumented_tests"
 	runInstrumentedTestsWorkflowSummary     = "Run your Android instrumented tests and get the test report."
 	runInstrumentedTestsWorkflowDescription = "The workflow will first clone your Git repository, cache your Gradle dependencies, install Android tools, run your Android instrumented tests and save the test report."
+	runInstrumentedTestsWorkflowReason      = "Runs the instrumented tests on emulators, split into parallel shards by the run_tests pipeline."
 	TestShardCountEnvKey                    = "TEST_SHARD_COUNT"
 	TestShardCountEnvValue                  = 2
 	ParallelTotalEnvKey                     = "BITRISE_IO_PARALLEL_TOTAL"
@@ -51,6 +51,7 @@ const (
 	buildWorkflowID          = "build_apk"
 	buildWorkflowSummary     = "Run your Android unit tests and create an APK file to install your app on a device or share it with your team."
 	buildWorkflowDescription = "The workflow will first clone your Git repository, install Android tools, set the project's version code based on the build number, run Android lint and unit tests, build the project's APK file and save it."
+	buildWorkflowReason      = "Checks and builds the app on every push to the default branch, the APK is uploaded to Bitrise."
 
 	ProjectLocationInputKey     = "project_location"
 	ProjectLocationInputEnvKey  = "PROJECT_LOCATION"
@@ -64,6 +65,13 @@ const (
 	VariantInputTitle   = "Variant"
 	VariantInputSummary = "Your Android build variant. You can add variants at any time, as well as further configure your existing variants later."
 
+	// DebugVariant is the variant every Android project has: the debug build type is built without a signing config.
+	DebugVariant       = "debug"
+	debugVariantReason = "every Android project has a debug build type, which needs no signing config"
+	// the variant input of the steps takes more variants in separate lines
+	variantPattern = `^[A-Za-z][A-Za-z0-9]*(\s*\n\s*[A-Za-z][A-Za-z0-9]*)*$`
+	variantHint    = "variant names in separate lines, like debug or freeRelease"
+
 	ModuleInputKey     = "module"
 	ModuleInputEnvKey  = "MODULE"
 	ModuleInputTitle   = "Module"
@@ -89,6 +97,9 @@ type GradleModule struct {
 // Scanner ...
 type Scanner struct {
 	Results []DetectResult
+
+	fileIndex *fileindex.Index
+	logger    *logger.Logger
 }
 
 // NewScanner ...
@@ -101,6 +112,16 @@ func (scanner *Scanner) Name() string {
 	return ScannerName
 }
 
+// SetFileIndex ...
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 // ExcludedScannerNames ...
 func (scanner *Scanner) ExcludedScannerNames() []string {
 	return []string{java.ProjectType}
@@ -114,16 +135,16 @@ type DetectResult struct {
 
 // DetectPlatform ...
 func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
-	log.TInfof("Searching for Gradle project files...")
+	scanner.logger.TInfof("Searching for Gradle project files...")
 
-	rootEntry, err := direntry.WalkDir(searchDir, 6)
+	rootEntry, err := direntry.WalkDirWithIndex(scanner.fileIndex, searchDir, 6)
 	if err != nil {
 		return false, err
 	}
 
 	gradleWrapperScripts := rootEntry.FindAllEntriesByName("gradlew", false)
 
-	log.TDonef("%d Gradle wrapper script(s) found", len(gradleWrapperScripts))
+	scanner.logger.TDonef("%d Gradle wrapper script(s) found", len(gradleWrapperScripts))
 	if len(gradleWrapperScripts) == 0 {
 		return false, nil
 	}
@@ -131,9 +152,9 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 	var results []DetectResult
 	for i, gradleWrapperScript := range gradleWrapperScripts {
 		if i > 0 {
-			log.TPrintf("")
+			scanner.logger.TPrintf("")
 		}
-		log.TInfof("Scanning project with Gradle wrapper script: %s", gradleWrapperScript.AbsPath)
+		scanner.logger.TInfof("Scanning project with Gradle wrapper script: %s", gradleWrapperScript.AbsPath)
 
 		projectRootDir := gradleWrapperScript.Parent()
 		if projectRootDir == nil {
@@ -144,18 +165,18 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 			return false, err
 		}
 		if gradleProject == nil {
-			log.TWarnf("No Gradle project found in %s", projectRootDir.AbsPath)
+			scanner.logger.TWarnf("No Gradle project found in %s", projectRootDir.AbsPath)
 			continue
 		}
 
-		printGradleProject(*gradleProject)
+		printGradleProject(scanner.logger, *gradleProject)
 
 		if len(gradleProject.AllBuildScriptFileEntries) == 0 {
 			analytics.LogInfo("android-no-build-scripts-found", nil, "no build script files found")
 			return false, fmt.Errorf("no Gradle build script file found")
 		}
 
-		log.TPrintf("Searching for Android dependencies...")
+		scanner.logger.TPrintf("Searching for Android dependencies...")
 		androidDetected, err := gradleProject.DetectAnyDependencies([]string{
 			"com.android.application",
 		})
@@ -163,9 +184,9 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 			return false, err
 		}
 
-		log.TDonef("Android dependencies found: %v", androidDetected)
+		scanner.logger.TDonef("Android dependencies found: %v", androidDetected)
 		if !androidDetected {
-			log.TDonef("No Android dependencies found, skipping this project")
+			scanner.logger.TDonef("No Android dependencies found, skipping this project")
 			continue
 		}
 
@@ -174,11 +195,11 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 		}
 
 		if gradleProject.SettingsGradleFileEntry != nil && len(gradleProject.IncludedProjects) == 0 {
-			log.TWarnf("No included projects found in settings.gradle file")
-			remoteLogNoIncludedProjectsFound(gradleProject.SettingsGradleFileEntry.AbsPath)
+			scanner.logger.TWarnf("No included projects found in settings.gradle file")
+			remoteLogNoIncludedProjectsFound(scanner.logger, *gradleProject.SettingsGradleFileEntry)
 		}
 
-		log.TPrintf("Scanning Gradle modules...")
+		scanner.logger.TPrintf("Scanning Gradle modules...")
 		var modules []GradleModule
 		if len(gradleProject.IncludedProjects) > 0 {
 			for _, includedProject := range gradleProject.IncludedProjects {
@@ -190,9 +211,9 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 				})
 			}
 
-			log.TDonef("%d included module(s) found:", len(modules))
+			scanner.logger.TDonef("%d included module(s) found:", len(modules))
 			for _, module := range modules {
-				log.TPrintf("- %s", module.ModulePath)
+				scanner.logger.TPrintf("- %s", module.ModulePath)
 			}
 		} else {
 			for _, buildScript := range gradleProject.AllBuildScriptFileEntries {
@@ -208,26 +229,26 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 				})
 			}
 
-			log.TDonef("%d module(s) found:", len(modules))
+			scanner.logger.TDonef("%d module(s) found:", len(modules))
 			for _, module := range modules {
-				log.TPrintf("- %s", module.ModulePath)
+				scanner.logger.TPrintf("- %s", module.ModulePath)
 			}
 		}
 		result.Modules = modules
 
-		log.TPrintf("Searching for project icons...")
-		result.Icons, err = LookupIcons(result.GradleProject.RootDirEntry.AbsPath, searchDir)
+		scanner.logger.TPrintf("Searching for project icons...")
+		result.Icons, err = LookupIcons(scanner.logger, result.GradleProject.RootDirEntry.AbsPath, searchDir)
 		if err != nil {
-			log.TWarnf("Failed to find icons: %v", err)
+			scanner.logger.TWarnf("Failed to find icons: %v", err)
 			analytics.LogInfo("android-icon-lookup", analytics.DetectorErrorData("android", err), "Failed to lookup android icon")
 		}
-		log.TDonef("%d icon(s) found", len(result.Icons))
+		scanner.logger.TDonef("%d icon(s) found", len(result.Icons))
 
 		results = append(results, result)
 	}
 
 	if len(results) == 0 {
-		log.TDonef("No Android projects found")
+		scanner.logger.TDonef("No Android projects found")
 		return false, nil
 	}
 
@@ -236,6 +257,12 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 	return len(results) > 0, nil
 }
 
+// SetupVariantOption recommends the debug variant as the answer of a variant option, and validates the variant names typed in.
+func SetupVariantOption(variantOption *models.OptionNode) {
+	variantOption.SetValueInfo(DebugVariant, models.ValueInfo{Recommended: true, Reason: debugVariantReason})
+	variantOption.Validation = &models.Validation{Pattern: variantPattern, Hint: variantHint}
+}
+
 // Options ...
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
 	projectLocationOption := models.NewOption(ProjectLocationInputTitle, ProjectLocationInputSummary, ProjectLocationInputEnvKey, models.TypeSelector)
@@ -244,6 +271,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	for _, result := range scanner.Results {
 		moduleOption := models.NewOption(ModuleInputTitle, ModuleInputSummary, ModuleInputEnvKey, models.TypeUserInput)
 		variantOption := models.NewOption(VariantInputTitle, VariantInputSummary, VariantInputEnvKey, models.TypeOptionalUserInput)
+		SetupVariantOption(variantOption)
 
 		iconIDs := make([]string, len(result.Icons))
 		for i, icon := range result.Icons {
@@ -273,6 +301,7 @@ func (scanner *Scanner) DefaultOptions() models.OptionNode {
 	projectLocationOption := models.NewOption(ProjectLocationInputTitle, ProjectLocationInputSummary, ProjectLocationInputEnvKey, models.TypeUserInput)
 	moduleOption := models.NewOption(ModuleInputTitle, ModuleInputSummary, ModuleInputEnvKey, models.TypeUserInput)
 	variantOption := models.NewOption(VariantInputTitle, VariantInputSummary, VariantInputEnvKey, models.TypeOptionalUserInput)
+	SetupVariantOption(variantOption)
 
 	buildScriptOption := models.NewOption(BuildScriptInputTitle, BuildScriptInputSummary, "", models.TypeSelector)
 	regularConfigOption := models.NewConfigOption(DefaultConfigName, nil)
@@ -344,19 +373,14 @@ func (scanner *Scanner) generateConfigs(sshKeyActivation models.SSHKeyActivation
 			return models.BitriseConfigMap{}, err
 		}
 
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
-
-		bitriseDataMap[param.name] = string(data)
+		bitriseDataMap[param.name] = config
 	}
 
 	return bitriseDataMap, nil
 }
 
 func (scanner *Scanner) generateConfigBuilder(sshKeyActivation models.SSHKeyActivation, useKotlinBuildScript bool) models.ConfigBuilderModel {
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 
 	projectLocationEnv, gradlewPath, moduleEnv, variantEnv := "$"+ProjectLocationInputEnvKey, "$"+ProjectLocationInputEnvKey+"/gradlew", "$"+ModuleInputEnvKey, "$"+VariantInputEnvKey
 
@@ -382,6 +406,7 @@ func (scanner *Scanner) generateConfigBuilder(sshKeyActivation models.SSHKeyActi
 	configBuilder.AppendStepListItemsTo(testsWorkflowID, steps.DefaultDeployStepList()...)
 	configBuilder.SetWorkflowSummaryTo(testsWorkflowID, testsWorkflowSummary)
 	configBuilder.SetWorkflowDescriptionTo(testsWorkflowID, testWorkflowDescription)
+	configBuilder.SetWorkflowReasonTo(testsWorkflowID, testsWorkflowReason)
 
 	//-- instrumented test
 	configBuilder.AppendStepListItemsTo(runInstrumentedTestsWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
@@ -404,6 +429,7 @@ func (scanner *Scanner) generateConfigBuilder(sshKeyActivation models.SSHKeyActi
 	configBuilder.AppendStepListItemsTo(runInstrumentedTestsWorkflowID, steps.DefaultDeployStepList()...)
 	configBuilder.SetWorkflowSummaryTo(runInstrumentedTestsWorkflowID, runInstrumentedTestsWorkflowSummary)
 	configBuilder.SetWorkflowDescriptionTo(runInstrumentedTestsWorkflowID, runInstrumentedTestsWorkflowDescription)
+	configBuilder.SetWorkflowReasonTo(runInstrumentedTestsWorkflowID, runInstrumentedTestsWorkflowReason)
 
 	configBuilder.SetGraphPipelineWorkflowTo(testPipelineID, runInstrumentedTestsWorkflowID, bitriseModels.GraphPipelineWorkflowModel{
 		Parallel: "$" + TestShardCountEnvKey,
@@ -468,26 +494,31 @@ func (scanner *Scanner) generateConfigBuilder(sshKeyActivation models.SSHKeyActi
 
 	configBuilder.SetWorkflowDescriptionTo(buildWorkflowID, buildWorkflowDescription)
 	configBuilder.SetWorkflowSummaryTo(buildWorkflowID, buildWorkflowSummary)
+	configBuilder.SetWorkflowReasonTo(buildWorkflowID, buildWorkflowReason)
+
+	//-- triggers
+	configBuilder.AddPullRequestTriggerToWorkflow(testsWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(buildWorkflowID)
 
 	return *configBuilder
 }
 
-func printGradleProject(gradleProject gradle.Project) {
-	log.TPrintf("Project root dir: %s", gradleProject.RootDirEntry.RelPath)
-	log.TPrintf("Gradle wrapper script: %s", gradleProject.GradlewFileEntry.RelPath)
+func printGradleProject(logger *logger.Logger, gradleProject gradle.Project) {
+	logger.TPrintf("Project root dir: %s", gradleProject.RootDirEntry.RelPath)
+	logger.TPrintf("Gradle wrapper script: %s", gradleProject.GradlewFileEntry.RelPath)
 	if gradleProject.ConfigDirEntry != nil {
-		log.TPrintf("Gradle config dir: %s", gradleProject.ConfigDirEntry.RelPath)
+		logger.TPrintf("Gradle config dir: %s", gradleProject.ConfigDirEntry.RelPath)
 	}
 	if gradleProject.VersionCatalogFileEntry != nil {
-		log.TPrintf("Version catalog file: %s", gradleProject.VersionCatalogFileEntry.RelPath)
+		logger.TPrintf("Version catalog file: %s", gradleProject.VersionCatalogFileEntry.RelPath)
 	}
 	if gradleProject.SettingsGradleFileEntry != nil {
-		log.TPrintf("Gradle settings file: %s", gradleProject.SettingsGradleFileEntry.RelPath)
+		logger.TPrintf("Gradle settings file: %s", gradleProject.SettingsGradleFileEntry.RelPath)
 	}
 	if len(gradleProject.IncludedProjects) > 0 {
-		log.TPrintf("Included projects:")
+		logger.TPrintf("Included projects:")
 		for _, includedProject := range gradleProject.IncludedProjects {
-			log.TPrintf("- %s: %s", includedProject.Name, includedProject.BuildScriptFileEntry.RelPath)
+			logger.TPrintf("- %s: %s", includedProject.Name, includedProject.BuildScriptFileEntry.RelPath)
 		}
 	}
 }
@@ -503,8 +534,9 @@ func modulePathFromBuildScriptPath(projectRootDir, buildScriptPth string) string
 	return strings.Join(pathComponents[:len(pathComponents)-1], "/")
 }
 
-func remoteLogNoIncludedProjectsFound(settingGradlePth string) {
-	file, err := os.Open(settingGradlePth)
+func remoteLogNoIncludedProjectsFound(logger *logger.Logger, settingGradleFileEntry direntry.DirEntry) {
+	settingGradlePth := settingGradleFileEntry.AbsPath
+	file, err := settingGradleFileEntry.Open()
 	if err != nil {
 		analytics.LogInfo("android-no-included-projects", map[string]interface{}{
 			"error": err.Error(),
@@ -513,7 +545,7 @@ func remoteLogNoIncludedProjectsFound(settingGradlePth string) {
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			log.TWarnf("Unable to close file %s: %s", settingGradlePth, err)
+			logger.TWarnf("Unable to close file %s: %s", settingGradlePth, err)
 		}
 	}()
 
@@ -3,6 +3,7 @@ package android
 import (
 	"testing"
 
+	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,3 +45,13 @@ func Test_modulePathFromBuildScriptPath(t *testing.T) {
 		})
 	}
 }
+
+func TestSetupVariantOption(t *testing.T) {
+	option := models.NewOption(VariantInputTitle, VariantInputSummary, VariantInputEnvKey, models.TypeOptionalUserInput)
+	SetupVariantOption(option)
+
+	require.NoError(t, option.ValidateValue("freeRelease"))
+	require.NoError(t, option.ValidateValue("debug\nfreeRelease"))
+	require.Error(t, option.ValidateValue("free-release"))
+	require.Error(t, option.ValidateValue("debug\nfree release"))
+}
@@ -8,15 +8,15 @@ import (
 
 	"github.com/beevik/etree"
 	"github.com/bitrise-io/bitrise-init/analytics"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/sliceutil"
 )
 
 // LookupIcons returns the largest resolution for all potential android icons.
-func LookupIcons(projectDir string, basepath string) (models.Icons, error) {
-	iconPaths, err := lookupIcons(projectDir)
+func LookupIcons(logger *logger.Logger, projectDir string, basepath string) (models.Icons, error) {
+	iconPaths, err := lookupIcons(logger, projectDir)
 	if err != nil {
 		return nil, err
 	}
@@ -28,13 +28,13 @@ type icon struct {
 	fileNameBase string
 }
 
-func lookupIconName(manifestPth string) ([]icon, error) {
+func lookupIconName(logger *logger.Logger, manifestPth string) ([]icon, error) {
 	doc := etree.NewDocument()
 	if err := doc.ReadFromFile(manifestPth); err != nil {
 		return nil, err
 	}
 
-	log.Debugf("Looking for app icons. Manifest path: %s", manifestPth)
+	logger.Debugf("Looking for app icons. Manifest path: %s", manifestPth)
 	return parseIconName(doc)
 }
 
@@ -83,7 +83,7 @@ func lookupIconPaths(resPth string, icon icon) ([]string, error) {
 	return nil, nil
 }
 
-func lookupIcons(projectDir string) ([]string, error) {
+func lookupIcons(logger *logger.Logger, projectDir string) ([]string, error) {
 	variantPaths := filepath.Join(regexp.QuoteMeta(projectDir), "*", "src", "*")
 	manifestPaths, err := filepath.Glob(filepath.Join(variantPaths, "AndroidManifest.xml"))
 	if err != nil {
@@ -106,7 +106,7 @@ func lookupIcons(projectDir string) ([]string, error) {
 		},
 	}
 	for _, manifestPath := range manifestPaths {
-		icons, err := lookupIconName(manifestPath)
+		icons, err := lookupIconName(logger, manifestPath)
 		if err != nil {
 			analytics.LogInfo("android-icon-lookup", analytics.DetectorErrorData("android", err), "Failed to lookup android icon")
 			continue
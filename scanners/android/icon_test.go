@@ -112,7 +112,7 @@ func TestLookupIconsMultipleApps(t *testing.T) {
 				createDummyApp(app)
 			}
 
-			got, err := lookupIcons(tt.projectDir)
+			got, err := lookupIcons(nil, tt.projectDir)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("LookupPossibleMatches() error = %v, wantErr %v", err, tt.wantErr)
 				return
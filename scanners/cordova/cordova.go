@@ -5,8 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 
-	"gopkg.in/yaml.v2"
-
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/android"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
@@ -15,8 +15,6 @@ import (
 	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/bitrise-io/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
-	"github.com/bitrise-io/go-utils/log"
-	"github.com/bitrise-io/go-utils/pathutil"
 )
 
 // ScannerName ...
@@ -47,6 +45,11 @@ const (
 	targetEmulator = "emulator"
 )
 
+const (
+	testWorkflowReason  = "Runs the Jasmine or Karma tests of the project."
+	buildWorkflowReason = "Builds the app for the selected platforms and uploads the artifacts to Bitrise."
+)
+
 //------------------
 // ScannerInterface
 //------------------
@@ -58,6 +61,8 @@ type Scanner struct {
 	searchDir           string
 	hasKarmaJasmineTest bool
 	hasJasmineTest      bool
+	fileIndex           *fileindex.Index
+	logger              *logger.Logger
 }
 
 // NewScanner ...
@@ -72,58 +77,58 @@ func (Scanner) Name() string {
 
 // DetectPlatform ...
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, true)
+	fileList, err := scanner.fileIndex.ListPathInDirSortedByComponents(searchDir, false)
 	if err != nil {
 		return false, fmt.Errorf("failed to search for files in (%s), error: %w", searchDir, err)
 	}
 
 	// Search for config.xml file
-	log.TInfof("Searching for config.xml file")
+	scanner.logger.TInfof("Searching for config.xml file")
 
 	configXMLPth, err := FilterRootConfigXMLFile(fileList)
 	if err != nil {
 		return false, fmt.Errorf("failed to search for config.xml file, error: %w", err)
 	}
 
-	log.TPrintf("config.xml: %s", configXMLPth)
+	scanner.logger.TPrintf("config.xml: %s", configXMLPth)
 
 	if configXMLPth == "" {
-		log.TPrintf("platform not detected")
+		scanner.logger.TPrintf("platform not detected")
 		return false, nil
 	}
 
-	widget, err := ParseConfigXML(configXMLPth)
+	widget, err := ParseConfigXML(scanner.fileIndex, configXMLPth)
 	if err != nil {
-		log.TPrintf("can not parse config.xml as a Cordova widget, error: %s", err)
-		log.TPrintf("platform not detected")
+		scanner.logger.TPrintf("can not parse config.xml as a Cordova widget, error: %s", err)
+		scanner.logger.TPrintf("platform not detected")
 		return false, nil
 	}
 
 	// ensure it is a cordova widget
 	if !strings.Contains(widget.XMLNSCDV, "cordova.apache.org") {
-		log.TPrintf("config.xml propert: xmlns:cdv does not contain cordova.apache.org")
-		log.TPrintf("platform not detected")
+		scanner.logger.TPrintf("config.xml propert: xmlns:cdv does not contain cordova.apache.org")
+		scanner.logger.TPrintf("platform not detected")
 		return false, nil
 	}
 
 	// ensure it is not an ionic project
 	projectBaseDir := filepath.Dir(configXMLPth)
 
-	if exist, err := pathutil.IsPathExists(filepath.Join(projectBaseDir, "ionic.project")); err != nil {
+	if exist, err := scanner.fileIndex.IsPathExists(filepath.Join(projectBaseDir, "ionic.project")); err != nil {
 		return false, fmt.Errorf("failed to check if project is an ionic project, error: %w", err)
 	} else if exist {
-		log.TPrintf("ionic.project file found seems to be an ionic project")
+		scanner.logger.TPrintf("ionic.project file found seems to be an ionic project")
 		return false, nil
 	}
 
-	if exist, err := pathutil.IsPathExists(filepath.Join(projectBaseDir, "ionic.config.json")); err != nil {
+	if exist, err := scanner.fileIndex.IsPathExists(filepath.Join(projectBaseDir, "ionic.config.json")); err != nil {
 		return false, fmt.Errorf("failed to check if project is an ionic project, error: %w", err)
 	} else if exist {
-		log.TPrintf("ionic.config.json file found seems to be an ionic project")
+		scanner.logger.TPrintf("ionic.config.json file found seems to be an ionic project")
 		return false, nil
 	}
 
-	log.TSuccessf("Platform detected")
+	scanner.logger.TSuccessf("Platform detected")
 
 	scanner.cordovaConfigPth = configXMLPth
 	scanner.searchDir = searchDir
@@ -131,6 +136,16 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	return true, nil
 }
 
+// SetFileIndex ...
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 // ExcludedScannerNames ...
 func (*Scanner) ExcludedScannerNames() []string {
 	return []string{
@@ -148,13 +163,13 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	projectRootDir := filepath.Dir(scanner.cordovaConfigPth)
 
 	packagesJSONPth := filepath.Join(projectRootDir, "package.json")
-	packages, err := utility.ParsePackagesJSON(packagesJSONPth)
+	packages, err := utility.ParsePackagesJSON(scanner.fileIndex, packagesJSONPth)
 	if err != nil {
 		return models.OptionNode{}, warnings, nil, err
 	}
 
 	// Search for karma/jasmine tests
-	log.TPrintf("Searching for karma/jasmine test")
+	scanner.logger.TPrintf("Searching for karma/jasmine test")
 
 	karmaTestDetected := false
 
@@ -171,17 +186,17 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 			}
 		}
 	}
-	log.TPrintf("karma-jasmine dependency found: %v", karmaJasmineDependencyFound)
+	scanner.logger.TPrintf("karma-jasmine dependency found: %v", karmaJasmineDependencyFound)
 
 	if karmaJasmineDependencyFound {
 		karmaConfigJSONPth := filepath.Join(projectRootDir, "karma.conf.js")
-		if exist, err := pathutil.IsPathExists(karmaConfigJSONPth); err != nil {
+		if exist, err := scanner.fileIndex.IsPathExists(karmaConfigJSONPth); err != nil {
 			return models.OptionNode{}, warnings, nil, err
 		} else if exist {
 			karmaTestDetected = true
 		}
 	}
-	log.TPrintf("karma.conf.js found: %v", karmaTestDetected)
+	scanner.logger.TPrintf("karma.conf.js found: %v", karmaTestDetected)
 
 	scanner.hasKarmaJasmineTest = karmaTestDetected
 	// ---
@@ -190,7 +205,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	jasminTestDetected := false
 
 	if !karmaTestDetected {
-		log.TPrintf("Searching for jasmine test")
+		scanner.logger.TPrintf("Searching for jasmine test")
 
 		jasmineDependencyFound := false
 		for dependency := range packages.Dependencies {
@@ -207,18 +222,18 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 				}
 			}
 		}
-		log.TPrintf("jasmine dependency found: %v", jasmineDependencyFound)
+		scanner.logger.TPrintf("jasmine dependency found: %v", jasmineDependencyFound)
 
 		if jasmineDependencyFound {
 			jasmineConfigJSONPth := filepath.Join(projectRootDir, "spec", "support", "jasmine.json")
-			if exist, err := pathutil.IsPathExists(jasmineConfigJSONPth); err != nil {
+			if exist, err := scanner.fileIndex.IsPathExists(jasmineConfigJSONPth); err != nil {
 				return models.OptionNode{}, warnings, nil, err
 			} else if exist {
 				jasminTestDetected = true
 			}
 		}
 
-		log.TPrintf("jasmine.json found: %v", jasminTestDetected)
+		scanner.logger.TPrintf("jasmine.json found: %v", jasminTestDetected)
 
 		scanner.hasJasmineTest = jasminTestDetected
 	}
@@ -286,7 +301,7 @@ func (*Scanner) DefaultOptions() models.OptionNode {
 }
 
 func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: sshKeyActivation,
 	})...)
@@ -303,6 +318,7 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem("install", workdir))
 
 		// CI
+		configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, testWorkflowReason)
 		if scanner.hasKarmaJasmineTest {
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.KarmaJasmineTestRunnerStepListItem(workdirEnvList...))
 		} else if scanner.hasJasmineTest {
@@ -312,6 +328,7 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
 
 		// CD
+		configBuilder.SetWorkflowReasonTo(models.DeployWorkflowID, buildWorkflowReason)
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: sshKeyActivation,
 		})...)
@@ -337,21 +354,20 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CordovaArchiveStepListItem(cordovaArchiveEnvs...))
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList()...)
 
-		config, err := configBuilder.Generate(ScannerName)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(models.DeployWorkflowID)
 
-		data, err := yaml.Marshal(config)
+		config, err := configBuilder.Generate(ScannerName)
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
 
 		return models.BitriseConfigMap{
-			configName: string(data),
+			configName: config,
 		}, nil
 	}
 
+	configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, buildWorkflowReason)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.RestoreNPMCache())
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem("install", workdir))
@@ -369,27 +385,26 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.SaveNPMCache())
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
 
-	config, err := configBuilder.Generate(ScannerName)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
+	configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(models.PrimaryWorkflowID)
 
-	data, err := yaml.Marshal(config)
+	config, err := configBuilder.Generate(ScannerName)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
 	return models.BitriseConfigMap{
-		configName: string(data),
+		configName: config,
 	}, nil
 }
 
 // DefaultConfigs ...
 func (*Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: models.SSHKeyActivationConditional,
 	})...)
+	configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, buildWorkflowReason)
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 
@@ -408,17 +423,15 @@ func (*Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
 
-	config, err := configBuilder.Generate(ScannerName)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
+	configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(models.PrimaryWorkflowID)
 
-	data, err := yaml.Marshal(config)
+	config, err := configBuilder.Generate(ScannerName)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
 	return models.BitriseConfigMap{
-		defaultConfigName: string(data),
+		defaultConfigName: config,
 	}, nil
 }
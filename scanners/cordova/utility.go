@@ -3,7 +3,7 @@ package cordova
 import (
 	"encoding/xml"
 
-	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
@@ -23,8 +23,8 @@ func parseConfigXMLContent(content string) (WidgetModel, error) {
 }
 
 // ParseConfigXML ...
-func ParseConfigXML(pth string) (WidgetModel, error) {
-	content, err := fileutil.ReadStringFromFile(pth)
+func ParseConfigXML(index *fileindex.Index, pth string) (WidgetModel, error) {
+	content, err := index.ReadStringFromFile(pth)
 	if err != nil {
 		return WidgetModel{}, err
 	}
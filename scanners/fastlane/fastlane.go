@@ -2,16 +2,14 @@ package fastlane
 
 import (
 	"fmt"
+	"path/filepath"
 
-	"gopkg.in/yaml.v2"
-
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/bitrise-io/bitrise-init/toolscanner"
-	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
-	"github.com/bitrise-io/go-utils/log"
-	"github.com/bitrise-io/go-utils/pathutil"
 )
 
 const scannerName = "fastlane"
@@ -35,6 +33,12 @@ const (
 	laneInputTitle   = "Fastlane lane"
 	laneInputEnvKey  = "FASTLANE_LANE"
 	laneInputSummary = "The lane that will be used in your builds, stored as an Environment Variable. You can change this at any time."
+	// the lane can be followed by its options, in the key:value format of the fastlane command line
+	lanePattern    = `^([a-z]+ )?[A-Za-z_][A-Za-z0-9_]*( +[A-Za-z_][A-Za-z0-9_]*:\S*)*$`
+	laneHint       = "a lane name, optionally prefixed with its platform and followed by its options, like beta or ios beta version:1.2"
+	laneStepReason = "Runs the lane stored in the FASTLANE_LANE Env Var, the lane does the build itself."
+
+	primaryWorkflowReason = "Runs the fastlane lane on every pull request and on every push to the default branch."
 )
 
 const (
@@ -64,8 +68,12 @@ const (
 
 // Scanner ...
 type Scanner struct {
+	// Fastfiles are relative to searchDir
 	Fastfiles    []string
+	searchDir    string
 	projectTypes []string
+	fileIndex    *fileindex.Index
+	logger       *logger.Logger
 }
 
 // NewScanner ...
@@ -80,13 +88,13 @@ func (Scanner) Name() string {
 
 // DetectPlatform ...
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, true)
+	fileList, err := scanner.fileIndex.ListPathInDirSortedByComponents(searchDir, true)
 	if err != nil {
 		return false, fmt.Errorf("failed to search for files in (%s), error: %w", searchDir, err)
 	}
 
 	// Search for Fastfile
-	log.TInfof("Searching for Fastfiles")
+	scanner.logger.TInfof("Searching for Fastfiles")
 
 	fastfiles, err := FilterFastfiles(fileList)
 	if err != nil {
@@ -94,22 +102,33 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	}
 
 	scanner.Fastfiles = fastfiles
+	scanner.searchDir = searchDir
 
-	log.TPrintf("%d Fastfiles detected", len(fastfiles))
+	scanner.logger.TPrintf("%d Fastfiles detected", len(fastfiles))
 	for _, file := range fastfiles {
-		log.TPrintf("- %s", file)
+		scanner.logger.TPrintf("- %s", file)
 	}
 
 	if len(fastfiles) == 0 {
-		log.TPrintf("platform not detected")
+		scanner.logger.TPrintf("platform not detected")
 		return false, nil
 	}
 
-	log.TSuccessf("Platform detected")
+	scanner.logger.TSuccessf("Platform detected")
 
 	return true, nil
 }
 
+// SetFileIndex ...
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 // ExcludedScannerNames ...
 func (*Scanner) ExcludedScannerNames() []string {
 	return []string{}
@@ -126,22 +145,22 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	workDirOption := models.NewOption(workDirInputTitle, workDirInputSummary, workDirInputEnvKey, models.TypeSelector)
 
 	for _, fastfile := range scanner.Fastfiles {
-		log.TInfof("Inspecting Fastfile: %s", fastfile)
+		scanner.logger.TInfof("Inspecting Fastfile: %s", fastfile)
 
 		workDir := WorkDir(fastfile)
-		log.TPrintf("fastlane work dir: %s", workDir)
+		scanner.logger.TPrintf("fastlane work dir: %s", workDir)
 
-		lanes, err := InspectFastfile(fastfile)
+		lanes, err := InspectFastfile(scanner.fileIndex, filepath.Join(scanner.searchDir, fastfile))
 		if err != nil {
-			log.TWarnf("Failed to inspect Fastfile, error: %s", err)
+			scanner.logger.TWarnf("Failed to inspect Fastfile, error: %s", err)
 			warnings = append(warnings, fmt.Sprintf("Failed to inspect Fastfile (%s), error: %s", fastfile, err))
 			continue
 		}
 
-		log.TPrintf("%d lanes found", len(lanes))
+		scanner.logger.TPrintf("%d lanes found", len(lanes))
 
 		if len(lanes) == 0 {
-			log.TWarnf("No lanes found")
+			scanner.logger.TWarnf("No lanes found")
 			warnings = append(warnings, fmt.Sprintf("No lanes found for Fastfile: %s", fastfile))
 			continue
 		}
@@ -152,7 +171,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 		workDirOption.AddOption(workDir, laneOption)
 
 		for _, lane := range lanes {
-			log.TPrintf("- %s", lane)
+			scanner.logger.TPrintf("- %s", lane)
 
 			configOption := models.NewConfigOption(configName, nil)
 			laneOption.AddConfig(lane, configOption)
@@ -160,7 +179,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	}
 
 	if !isValidFastfileFound {
-		log.TErrorf("No valid Fastfile found")
+		scanner.logger.TErrorf("No valid Fastfile found")
 		warnings = append(warnings, "No valid Fastfile found")
 		return models.OptionNode{}, warnings, nil, nil
 	}
@@ -176,6 +195,7 @@ func (*Scanner) DefaultOptions() models.OptionNode {
 	workDirOption := models.NewOption(workDirInputTitle, workDirInputSummary, workDirInputEnvKey, models.TypeUserInput)
 
 	laneOption := models.NewOption(laneInputTitle, laneInputSummary, laneInputEnvKey, models.TypeUserInput)
+	laneOption.Validation = &models.Validation{Pattern: lanePattern, Hint: laneHint}
 	workDirOption.AddOption(models.UserInputOptionDefaultValue, laneOption)
 
 	projectTypeOption := models.NewOption(projectTypeInputTitle, projectTypeInputSummary, "", models.TypeSelector)
@@ -190,8 +210,9 @@ func (*Scanner) DefaultOptions() models.OptionNode {
 }
 
 func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
-	generateConfig := func(isIOS bool) (bitriseModels.BitriseDataModel, error) {
-		configBuilder := models.NewDefaultConfigBuilder()
+	generateConfig := func(isIOS bool) (models.Config, error) {
+		configBuilder := steps.NewConfigBuilder()
+		configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, primaryWorkflowReason)
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: sshKeyActivation,
 		})...)
@@ -200,7 +221,7 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 		}
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FastlaneStepListItem(
+		configBuilder.AppendStepListItemsWithReasonTo(models.PrimaryWorkflowID, laneStepReason, steps.FastlaneStepListItem(
 			envmanModels.EnvironmentItemModel{laneInputKey: "$" + laneInputEnvKey},
 			envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey},
 			envmanModels.EnvironmentItemModel{cacheInputKey: cacheInputNo},
@@ -208,6 +229,9 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
 
+		configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(models.PrimaryWorkflowID)
+
 		// Fill in project type later, from the list of detected project types
 		return configBuilder.Generate(unknownProjectType,
 			envmanModels.EnvironmentItemModel{
@@ -216,7 +240,7 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 	}
 
 	// Create list of possible configs with project types
-	nameToConfigModel := map[string]bitriseModels.BitriseDataModel{}
+	configMap := models.BitriseConfigMap{}
 
 	for _, platform := range scanner.projectTypes {
 		config, err := generateConfig(platform == iosPlatform)
@@ -225,50 +249,41 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 		}
 
 		for cfgName, dataModel := range toolscanner.AddProjectTypeToConfig(configName, config, []string{platform}) {
-			nameToConfigModel[cfgName] = dataModel
+			configMap[cfgName] = dataModel
 		}
 	}
 
-	nameToConfigString := map[string]string{}
-	for configName, config := range nameToConfigModel {
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
-		nameToConfigString[configName] = string(data)
-	}
-	return nameToConfigString, nil
+	return configMap, nil
 }
 
 func (*Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 	configMap := models.BitriseConfigMap{}
 
 	for _, p := range platforms {
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := steps.NewConfigBuilder()
+		configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, primaryWorkflowReason)
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: models.SSHKeyActivationConditional})...)
 
 		if p == iosPlatform {
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 		}
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FastlaneStepListItem(
+		configBuilder.AppendStepListItemsWithReasonTo(models.PrimaryWorkflowID, laneStepReason, steps.FastlaneStepListItem(
 			envmanModels.EnvironmentItemModel{laneInputKey: "$" + laneInputEnvKey},
 			envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey},
 			envmanModels.EnvironmentItemModel{cacheInputKey: cacheInputNo},
 		))
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
 
-		config, err := configBuilder.Generate(p, envmanModels.EnvironmentItemModel{fastlaneXcodeListTimeoutEnvKey: fastlaneXcodeListTimeoutEnvValue})
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(models.PrimaryWorkflowID)
 
-		data, err := yaml.Marshal(config)
+		config, err := configBuilder.Generate(p, envmanModels.EnvironmentItemModel{fastlaneXcodeListTimeoutEnvKey: fastlaneXcodeListTimeoutEnvValue})
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
 
-		configMap[fmt.Sprintf(defaultConfigNameFormat, p)] = string(data)
+		configMap[fmt.Sprintf(defaultConfigNameFormat, p)] = config
 	}
 
 	return configMap, nil
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/stretchr/testify/require"
 )
 
@@ -147,3 +148,15 @@ func TestFastlaneWorkDir(t *testing.T) {
 		require.Equal(t, expected, actual)
 	}
 }
+
+func TestDefaultOptions_laneValidation(t *testing.T) {
+	laneOption := (&Scanner{}).DefaultOptions().ChildOptionMap[models.UserInputOptionDefaultValue]
+	require.Equal(t, laneInputTitle, laneOption.Title)
+
+	for _, lane := range []string{"beta", "ios beta", "ios beta version:1.2 skip_upload:true", "test_all"} {
+		require.NoError(t, laneOption.ValidateValue(lane), lane)
+	}
+	for _, lane := range []string{"1beta", "ios beta version", "ios beta --verbose"} {
+		require.Error(t, laneOption.ValidateValue(lane), lane)
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
@@ -84,8 +84,8 @@ func inspectFastfileContent(content string) ([]string, error) {
 }
 
 // InspectFastfile ...
-func InspectFastfile(fastFile string) ([]string, error) {
-	content, err := fileutil.ReadStringFromFile(fastFile)
+func InspectFastfile(index *fileindex.Index, fastFile string) ([]string, error) {
+	content, err := index.ReadStringFromFile(fastFile)
 	if err != nil {
 		return []string{}, err
 	}
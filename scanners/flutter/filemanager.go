@@ -0,0 +1,79 @@
+package flutter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/go-utils/v2/fileutil"
+)
+
+// indexFileManager implements the fileutil.FileManager reads used by flutterproject on top of the file index,
+// so that projects scanned from an fs.FS can be inspected too.
+type indexFileManager struct {
+	fileutil.FileManager
+	index *fileindex.Index
+}
+
+func newIndexFileManager(index *fileindex.Index) fileutil.FileManager {
+	return indexFileManager{
+		FileManager: fileutil.NewFileManager(),
+		index:       index,
+	}
+}
+
+// Open returns an *os.File, files of a virtual index are copied to an unlinked temporary file.
+func (m indexFileManager) Open(path string) (*os.File, error) {
+	if m.index.OnDisk() {
+		return os.Open(path)
+	}
+
+	content, err := m.index.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp("", "bitrise-init-fs")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(file.Name()); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func (m indexFileManager) OpenReaderIfExists(path string) (io.Reader, error) {
+	content, err := m.index.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(content), nil
+}
+
+func (m indexFileManager) ReadDirEntryNames(path string) ([]string, error) {
+	entries, err := m.index.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	return names, nil
+}
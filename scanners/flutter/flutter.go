@@ -2,8 +2,12 @@ package flutter
 
 import (
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/android"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
@@ -12,16 +16,13 @@ import (
 	envmanModels "github.com/bitrise-io/envman/v2/models"
 	"github.com/bitrise-io/go-flutter/flutterproject"
 	"github.com/bitrise-io/go-flutter/fluttersdk"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
-	"github.com/bitrise-io/go-utils/v2/fileutil"
-	pathutilv2 "github.com/bitrise-io/go-utils/v2/pathutil"
-	"gopkg.in/yaml.v2"
 )
 
 const (
 	scannerName                 = "flutter"
 	configName                  = "flutter-config"
+	monorepoConfigName          = "flutter-monorepo-config"
 	testWorkflowID              = "run_tests"
 	buildWorkflowID             = "build_app"
 	projectLocationInputKey     = "project_location"
@@ -29,8 +30,15 @@ const (
 	projectLocationInputTitle   = "Project location"
 	projectLocationInputSummary = "The path to your Flutter project, stored as an Environment Variable. In your Workflows, you can specify paths relative to this path. You can change this at any time."
 	platformInputKey            = "platform"
+	platformsInputTitle         = "Platforms to build"
+	platformsInputSummary       = "The platforms of the project built by the build_app workflow, the project is tested or analysed in any case."
+	platformAndroid             = "android"
+	platformIOS                 = "ios"
 	iosOutputTypeKey            = "ios_output_type"
 	iosOutputTypeArchive        = "archive"
+
+	testWorkflowReason     = "Runs the tests of the project, or analyses its code if it has no tests."
+	buildAppWorkflowReason = "Builds the app for the selected platforms on every push to the default branch, the artifacts are uploaded to Bitrise."
 )
 
 const (
@@ -68,7 +76,9 @@ type project struct {
 
 // Scanner ...
 type Scanner struct {
-	projects []project
+	projects  []project
+	fileIndex *fileindex.Index
+	logger    *logger.Logger
 }
 
 // NewScanner ...
@@ -83,29 +93,29 @@ func (scanner *Scanner) Name() string {
 
 // DetectPlatform ...
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	log.TInfof("Search for project(s)")
-	projectLocations, err := findProjectLocations(searchDir)
+	scanner.logger.TInfof("Search for project(s)")
+	projectLocations, err := findProjectLocations(scanner.fileIndex, searchDir)
 	if err != nil {
 		return false, err
 	}
 
-	log.TPrintf("Paths containing pubspec.yaml(%d):", len(projectLocations))
+	scanner.logger.TPrintf("Paths containing pubspec.yaml(%d):", len(projectLocations))
 	for _, p := range projectLocations {
-		log.TPrintf("- %s", p)
+		scanner.logger.TPrintf("- %s", p)
 	}
-	log.TPrintf("")
+	scanner.logger.TPrintf("")
 
-	log.TInfof("Fetching pubspec.yaml files")
+	scanner.logger.TInfof("Fetching pubspec.yaml files")
 
 	currentID := -1
 	for _, projectLocation := range projectLocations {
-		flutterProj, err := flutterproject.New(projectLocation, fileutil.NewFileManager(), pathutilv2.NewPathChecker(), fluttersdk.NewSDKVersionFinder())
+		flutterProj, err := flutterproject.New(filepath.Join(searchDir, projectLocation), newIndexFileManager(scanner.fileIndex), scanner.fileIndex, fluttersdk.NewSDKVersionFinder())
 		if err != nil {
-			log.TErrorf(err.Error())
+			scanner.logger.TErrorf(err.Error())
 			continue
 		}
 
-		rootDir := flutterProj.RootDir()
+		rootDir := projectLocation
 		projectName := flutterProj.Pubspec().Name
 		hasTest := flutterProj.TestDirPth() != ""
 		hasIosProject := flutterProj.IOSProjectPth() != ""
@@ -117,7 +127,7 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 		//  This is not a huge issue, because just a few SDK versions are available on multiple channels (like 2.2.2).
 		flutterVersion, _, err := flutterProj.FlutterSDKVersionToUse()
 		if err != nil {
-			log.Warnf(err.Error())
+			scanner.logger.Warnf(err.Error())
 		}
 
 		currentID++
@@ -133,20 +143,30 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 
 		scanner.projects = append(scanner.projects, proj)
 
-		log.TPrintf("- Project path: %s", rootDir)
-		log.TPrintf("  Project name: %s", projectName)
-		log.TPrintf("  Has test: %v", hasTest)
-		log.TPrintf("  Has Android project: %v", hasAndroidProject)
-		log.TPrintf("  Has iOS project: %v", hasIosProject)
-		log.TPrintf("  Has Web project: %v", hasWebProject)
+		scanner.logger.TPrintf("- Project path: %s", rootDir)
+		scanner.logger.TPrintf("  Project name: %s", projectName)
+		scanner.logger.TPrintf("  Has test: %v", hasTest)
+		scanner.logger.TPrintf("  Has Android project: %v", hasAndroidProject)
+		scanner.logger.TPrintf("  Has iOS project: %v", hasIosProject)
+		scanner.logger.TPrintf("  Has Web project: %v", hasWebProject)
 		if flutterVersion != "" {
-			log.TPrintf("  Flutter version to use: %s", proj.flutterVersionToUse)
+			scanner.logger.TPrintf("  Flutter version to use: %s", proj.flutterVersionToUse)
 		}
 	}
 
 	return len(scanner.projects) > 0, nil
 }
 
+// SetFileIndex ...
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 // ExcludedScannerNames ...
 func (scanner *Scanner) ExcludedScannerNames() []string {
 	return []string{
@@ -165,22 +185,31 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 		configOption := models.NewConfigOption(configNameFor(proj), nil)
 		flutterProjectLocationOption.AddConfig(proj.rootDir, configOption)
 	}
+	if len(scanner.projects) > 1 {
+		return *models.NewMonorepoOption(flutterProjectLocationOption, monorepoConfigName), models.Warnings{}, nil, nil
+	}
 
 	return *flutterProjectLocationOption, models.Warnings{}, nil, nil
 }
 
-var defaultProjects = []project{
-	{hasTest: true, hasAndroidProject: true, hasIosProject: true, hasWebProject: true},
+// defaultProjects are the default projects for the selections of the platforms to build.
+var defaultProjects = map[string]project{
+	models.MultiSelectKey():                             {hasTest: true, hasWebProject: true},
+	models.MultiSelectKey(platformAndroid):              {hasTest: true, hasAndroidProject: true, hasWebProject: true},
+	models.MultiSelectKey(platformIOS):                  {hasTest: true, hasIosProject: true, hasWebProject: true},
+	models.MultiSelectKey(platformAndroid, platformIOS): {hasTest: true, hasAndroidProject: true, hasIosProject: true, hasWebProject: true},
 }
 
 // DefaultOptions ...
 func (scanner *Scanner) DefaultOptions() models.OptionNode {
 	flutterProjectLocationOption := models.NewOption(projectLocationInputTitle, projectLocationInputSummary, projectLocationInputEnvKey, models.TypeUserInput)
+	platformsOption := models.NewOption(platformsInputTitle, platformsInputSummary, "", models.TypeMultiSelector)
+	flutterProjectLocationOption.AddOption(models.UserInputOptionDefaultValue, platformsOption)
 
-	for i, proj := range defaultProjects {
-		proj.id = i
-		configOption := models.NewConfigOption(configNameFor(proj), nil)
-		flutterProjectLocationOption.AddConfig(models.UserInputOptionDefaultValue, configOption)
+	// the values of the option are listed in the order they are added
+	for _, platforms := range slices.Sorted(maps.Keys(defaultProjects)) {
+		configOption := models.NewConfigOption(configNameFor(defaultProjects[platforms]), nil)
+		platformsOption.AddConfig(platforms, configOption)
 	}
 
 	return *flutterProjectLocationOption
@@ -198,15 +227,21 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 		configs[configNameFor(proj)] = config
 	}
 
+	if len(scanner.projects) > 1 {
+		config, err := generateMonorepoConfig(sshKeyActivation, scanner.projects)
+		if err != nil {
+			return nil, err
+		}
+		configs[monorepoConfigName] = config
+	}
+
 	return configs, nil
 }
 
 func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 	configs := models.BitriseConfigMap{}
 
-	for i, proj := range defaultProjects {
-		proj.id = i
-
+	for _, proj := range defaultProjects {
 		config, err := generateConfig(models.SSHKeyActivationConditional, proj)
 		if err != nil {
 			return nil, err
@@ -217,8 +252,9 @@ func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 	return configs, nil
 }
 
-func findProjectLocations(searchDir string) ([]string, error) {
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, true)
+// findProjectLocations returns the pubspec.yaml containing directories, relative to searchDir.
+func findProjectLocations(index *fileindex.Index, searchDir string) ([]string, error) {
+	fileList, err := index.ListPathInDirSortedByComponents(searchDir, true)
 	if err != nil {
 		return nil, err
 	}
@@ -240,83 +276,134 @@ func findProjectLocations(searchDir string) ([]string, error) {
 	return paths, nil
 }
 
-func generateConfig(sshKeyActivation models.SSHKeyActivation, proj project) (string, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func generateConfig(sshKeyActivation models.SSHKeyActivation, proj project) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
 
 	// Common steps to all workflows
 	prepareSteps := steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKeyActivation})
-	flutterInstallStep := steps.FlutterInstallStepListItem(proj.flutterVersionToUse)
-	deploySteps := steps.DefaultDeployStepList()
+	projectLocation := "$" + projectLocationInputEnvKey
 
 	// primary
 	configBuilder.SetWorkflowDescriptionTo(testWorkflowID, testWorkflowDescription)
+	configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
 
 	configBuilder.AppendStepListItemsTo(testWorkflowID, prepareSteps...)
 
-	configBuilder.AppendStepListItemsTo(testWorkflowID, flutterInstallStep)
+	appendTestSteps(configBuilder, testWorkflowID, proj, projectLocation)
 
-	// restore cache is after flutter-installer, to prevent removal of pub system cache
-	configBuilder.AppendStepListItemsTo(testWorkflowID, steps.RestoreDartCache())
+	if hasBuildWorkflow(proj) {
+		// deploy
+		configBuilder.SetWorkflowDescriptionTo(buildWorkflowID, buildAppWorkflowDescription)
+		configBuilder.SetWorkflowReasonTo(buildWorkflowID, buildAppWorkflowReason)
 
-	if proj.hasTest {
-		configBuilder.AppendStepListItemsTo(testWorkflowID, steps.FlutterTestStepListItem(
-			envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-		))
+		configBuilder.AppendStepListItemsTo(buildWorkflowID, prepareSteps...)
+
+		appendBuildSteps(configBuilder, buildWorkflowID, proj, projectLocation)
+	}
+
+	configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+	if hasBuildWorkflow(proj) {
+		configBuilder.AddPushTriggerToWorkflow(buildWorkflowID)
 	} else {
-		configBuilder.AppendStepListItemsTo(testWorkflowID, steps.FlutterAnalyzeStepListItem(
-			envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-		))
+		configBuilder.AddPushTriggerToWorkflow(testWorkflowID)
 	}
 
-	configBuilder.AppendStepListItemsTo(testWorkflowID, steps.SaveDartCache())
+	config, err := configBuilder.Generate(scannerName)
+	if err != nil {
+		return models.Config{}, err
+	}
 
-	configBuilder.AppendStepListItemsTo(testWorkflowID, deploySteps...)
+	return config, nil
+}
 
-	if proj.hasIosProject || proj.hasAndroidProject {
-		// deploy
-		configBuilder.SetWorkflowDescriptionTo(buildWorkflowID, buildAppWorkflowDescription)
+// generateMonorepoConfig generates a config with the test and build workflows of every project,
+// triggered by the changes of the project's files.
+func generateMonorepoConfig(sshKeyActivation models.SSHKeyActivation, projects []project) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
 
-		configBuilder.AppendStepListItemsTo(buildWorkflowID, prepareSteps...)
+	configBuilder.SetWorkflowReasonTo(models.SetupWorkflowID, models.SetupWorkflowReason)
+	configBuilder.AppendStepListItemsTo(models.SetupWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKeyActivation})...)
 
-		if proj.hasIosProject {
-			configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+	for _, proj := range projects {
+		changedFiles := models.ProjectChangedFiles(proj.rootDir)
+
+		testWorkflow := models.ProjectWorkflowID(testWorkflowID, proj.rootDir)
+		configBuilder.SetWorkflowDescriptionTo(testWorkflow, testWorkflowDescription)
+		configBuilder.SetWorkflowReasonTo(testWorkflow, testWorkflowReason)
+		configBuilder.SetWorkflowBeforeRunTo(testWorkflow, models.SetupWorkflowID)
+		appendTestSteps(configBuilder, testWorkflow, proj, proj.rootDir)
+		configBuilder.AddChangedFilesPullRequestTriggerTo(testWorkflow, changedFiles)
+
+		if !hasBuildWorkflow(proj) {
+			configBuilder.AddChangedFilesPushTriggerTo(testWorkflow, changedFiles)
+			continue
 		}
 
-		configBuilder.AppendStepListItemsTo(buildWorkflowID, flutterInstallStep)
+		buildWorkflow := models.ProjectWorkflowID(buildWorkflowID, proj.rootDir)
+		configBuilder.SetWorkflowDescriptionTo(buildWorkflow, buildAppWorkflowDescription)
+		configBuilder.SetWorkflowReasonTo(buildWorkflow, buildAppWorkflowReason)
+		configBuilder.SetWorkflowBeforeRunTo(buildWorkflow, models.SetupWorkflowID)
+		appendBuildSteps(configBuilder, buildWorkflow, proj, proj.rootDir)
+		configBuilder.AddChangedFilesPushTriggerTo(buildWorkflow, changedFiles)
+	}
+
+	return configBuilder.Generate(scannerName)
+}
+
+func hasBuildWorkflow(proj project) bool {
+	return proj.hasIosProject || proj.hasAndroidProject
+}
 
-		configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.FlutterAnalyzeStepListItem(
-			envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
+// appendTestSteps appends the steps testing or analysing the project at projectLocation to the workflow.
+func appendTestSteps(configBuilder *models.ConfigBuilderModel, workflow models.WorkflowID, proj project, projectLocation string) {
+	configBuilder.AppendStepListItemsTo(workflow, steps.FlutterInstallStepListItem(proj.flutterVersionToUse))
+
+	// restore cache is after flutter-installer, to prevent removal of pub system cache
+	configBuilder.AppendStepListItemsTo(workflow, steps.RestoreDartCache())
+
+	if proj.hasTest {
+		configBuilder.AppendStepListItemsTo(workflow, steps.FlutterTestStepListItem(
+			envmanModels.EnvironmentItemModel{projectLocationInputKey: projectLocation},
 		))
+	} else {
+		configBuilder.AppendStepListItemsTo(workflow, steps.FlutterAnalyzeStepListItem(
+			envmanModels.EnvironmentItemModel{projectLocationInputKey: projectLocation},
+		))
+	}
 
-		if proj.hasTest {
-			configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.FlutterTestStepListItem(
-				envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-			))
-		}
+	configBuilder.AppendStepListItemsTo(workflow, steps.SaveDartCache())
 
-		flutterBuildInputs := []envmanModels.EnvironmentItemModel{
-			{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-			{platformInputKey: targetPlatformInputValueFor(proj)},
-		}
-		if proj.hasIosProject {
-			flutterBuildInputs = append(flutterBuildInputs, envmanModels.EnvironmentItemModel{iosOutputTypeKey: iosOutputTypeArchive})
-		}
-		configBuilder.AppendStepListItemsTo(buildWorkflowID, steps.FlutterBuildStepListItem(flutterBuildInputs...))
+	configBuilder.AppendStepListItemsTo(workflow, steps.DefaultDeployStepList()...)
+}
 
-		configBuilder.AppendStepListItemsTo(buildWorkflowID, deploySteps...)
+// appendBuildSteps appends the steps building the project at projectLocation to the workflow.
+func appendBuildSteps(configBuilder *models.ConfigBuilderModel, workflow models.WorkflowID, proj project, projectLocation string) {
+	if proj.hasIosProject {
+		configBuilder.AppendStepListItemsTo(workflow, steps.CertificateAndProfileInstallerStepListItem())
 	}
 
-	config, err := configBuilder.Generate(scannerName)
-	if err != nil {
-		return "", err
+	configBuilder.AppendStepListItemsTo(workflow, steps.FlutterInstallStepListItem(proj.flutterVersionToUse))
+
+	configBuilder.AppendStepListItemsTo(workflow, steps.FlutterAnalyzeStepListItem(
+		envmanModels.EnvironmentItemModel{projectLocationInputKey: projectLocation},
+	))
+
+	if proj.hasTest {
+		configBuilder.AppendStepListItemsTo(workflow, steps.FlutterTestStepListItem(
+			envmanModels.EnvironmentItemModel{projectLocationInputKey: projectLocation},
+		))
 	}
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return "", err
+	flutterBuildInputs := []envmanModels.EnvironmentItemModel{
+		{projectLocationInputKey: projectLocation},
+		{platformInputKey: targetPlatformInputValueFor(proj)},
+	}
+	if proj.hasIosProject {
+		flutterBuildInputs = append(flutterBuildInputs, envmanModels.EnvironmentItemModel{iosOutputTypeKey: iosOutputTypeArchive})
 	}
+	configBuilder.AppendStepListItemsTo(workflow, steps.FlutterBuildStepListItem(flutterBuildInputs...))
 
-	return string(data), nil
+	configBuilder.AppendStepListItemsTo(workflow, steps.DefaultDeployStepList()...)
 }
 
 func targetPlatformInputValueFor(proj project) string {
@@ -0,0 +1,122 @@
+package scanners
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanners/scannertest"
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden bitrise.yml files")
+
+// TestDefaultConfigs_golden compares the default configs of the scanners to testdata/golden/<scanner>/<config>.yml.
+// Run with -update to regenerate the files.
+func TestDefaultConfigs_golden(t *testing.T) {
+	for _, scanner := range append(ProjectScanners(), AutomationToolScanners()...) {
+		t.Run(scanner.Name(), func(t *testing.T) {
+			configMap, err := scanner.DefaultConfigs()
+			require.NoError(t, err)
+
+			dir := filepath.Join("testdata", "golden", scanner.Name())
+			if *updateGolden {
+				require.NoError(t, os.RemoveAll(dir))
+				require.NoError(t, os.MkdirAll(dir, 0755))
+			}
+
+			for name, config := range configMap {
+				content, err := models.MarshalConfig(config)
+				require.NoError(t, err)
+
+				pth := filepath.Join(dir, name+".yml")
+				if *updateGolden {
+					require.NoError(t, os.WriteFile(pth, content, 0644))
+					continue
+				}
+
+				golden, err := os.ReadFile(pth)
+				require.NoError(t, err)
+				require.Equal(t, string(golden), string(content), "config: %s", name)
+			}
+
+			goldenFiles, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+			require.NoError(t, err)
+			require.Len(t, goldenFiles, len(configMap))
+		})
+	}
+}
+
+func TestDefaultConfigs_deterministic(t *testing.T) {
+	for _, scanner := range append(ProjectScanners(), AutomationToolScanners()...) {
+		first, err := scanner.DefaultConfigs()
+		require.NoError(t, err)
+		firstStrs, err := output.EncodeConfigMap(first)
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			configMap, err := scanner.DefaultConfigs()
+			require.NoError(t, err)
+			configStrs, err := output.EncodeConfigMap(configMap)
+			require.NoError(t, err)
+			require.Equal(t, firstStrs, configStrs, "scanner: %s", scanner.Name())
+		}
+	}
+}
+
+// TestDefaultConfigs_triggers checks that the default configs trigger an existing workflow or pipeline on pull requests and on pushes.
+func TestDefaultConfigs_triggers(t *testing.T) {
+	for _, scanner := range append(ProjectScanners(), AutomationToolScanners()...) {
+		configMap, err := scanner.DefaultConfigs()
+		require.NoError(t, err)
+
+		for name, config := range configMap {
+			_, err := config.Validate()
+			require.NoError(t, err, "config: %s", name)
+
+			var types []bitriseModels.TriggerItemType
+			for _, item := range config.TriggerMap {
+				types = append(types, item.Type)
+			}
+			require.ElementsMatch(t, []bitriseModels.TriggerItemType{bitriseModels.PullRequestType, bitriseModels.CodePushType}, types, "config: %s", name)
+		}
+	}
+}
+
+func TestDefaultOptions_matchDefaultConfigs(t *testing.T) {
+	for _, scanner := range append(ProjectScanners(), AutomationToolScanners()...) {
+		scannertest.CheckDefaults(t, scanner)
+	}
+}
+
+// TestDefaultConfigs_workflowReasons checks that every workflow of the default configs is explained by a comment.
+func TestDefaultConfigs_workflowReasons(t *testing.T) {
+	for _, scanner := range append(ProjectScanners(), AutomationToolScanners()...) {
+		configMap, err := scanner.DefaultConfigs()
+		require.NoError(t, err)
+
+		for name, config := range configMap {
+			content, err := models.MarshalConfigWithComments(config)
+			require.NoError(t, err)
+
+			var document yaml.Node
+			require.NoError(t, yaml.Unmarshal(content, &document))
+			root := document.Content[0]
+			for i := 0; i+1 < len(root.Content); i += 2 {
+				if root.Content[i].Value != "workflows" {
+					continue
+				}
+				workflows := root.Content[i+1]
+				for j := 0; j+1 < len(workflows.Content); j += 2 {
+					key := workflows.Content[j]
+					require.NotEmpty(t, key.HeadComment, "config: %s, workflow: %s", name, key.Value)
+				}
+			}
+		}
+	}
+}
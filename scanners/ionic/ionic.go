@@ -5,8 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 
-	"gopkg.in/yaml.v2"
-
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/android"
 	"github.com/bitrise-io/bitrise-init/scanners/cordova"
@@ -16,8 +16,6 @@ import (
 	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/bitrise-io/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
-	"github.com/bitrise-io/go-utils/log"
-	"github.com/bitrise-io/go-utils/pathutil"
 )
 
 const scannerName = "ionic"
@@ -47,6 +45,11 @@ const (
 	targetEmulator = "emulator"
 )
 
+const (
+	testWorkflowReason  = "Runs the Jasmine or Karma tests of the project."
+	buildWorkflowReason = "Builds the app for the selected platforms and uploads the artifacts to Bitrise."
+)
+
 //------------------
 // ScannerInterface
 //------------------
@@ -58,6 +61,8 @@ type Scanner struct {
 	searchDir           string
 	hasKarmaJasmineTest bool
 	hasJasmineTest      bool
+	fileIndex           *fileindex.Index
+	logger              *logger.Logger
 }
 
 // NewScanner ...
@@ -72,7 +77,7 @@ func (Scanner) Name() string {
 
 // DetectPlatform ...
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, true)
+	fileList, err := scanner.fileIndex.ListPathInDirSortedByComponents(searchDir, false)
 	if err != nil {
 		return false, fmt.Errorf("failed to search for files in (%s), error: %w", searchDir, err)
 	}
@@ -92,11 +97,11 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	}
 
 	if ionicConfigPath == "" {
-		log.Printf("No ionic.project file nor ionic.config.json found.")
+		scanner.logger.Printf("No ionic.project file nor ionic.config.json found.")
 		return false, nil
 	}
 
-	log.TSuccessf("Platform detected")
+	scanner.logger.TSuccessf("Platform detected")
 
 	scanner.ionicConfigPath = ionicConfigPath
 	scanner.searchDir = searchDir
@@ -104,6 +109,16 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	return true, nil
 }
 
+// SetFileIndex ...
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 // ExcludedScannerNames ...
 func (Scanner) ExcludedScannerNames() []string {
 	return []string{
@@ -123,13 +138,13 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	projectRootDir := filepath.Dir(scanner.ionicConfigPath)
 
 	packagesJSONPth := filepath.Join(projectRootDir, "package.json")
-	packages, err := utility.ParsePackagesJSON(packagesJSONPth)
+	packages, err := utility.ParsePackagesJSON(scanner.fileIndex, packagesJSONPth)
 	if err != nil {
 		return models.OptionNode{}, warnings, nil, err
 	}
 
 	// Search for karma/jasmine tests
-	log.TPrintf("Searching for karma/jasmine test")
+	scanner.logger.TPrintf("Searching for karma/jasmine test")
 
 	karmaTestDetected := false
 
@@ -146,17 +161,17 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 			}
 		}
 	}
-	log.TPrintf("karma-jasmine dependency found: %v", karmaJasmineDependencyFound)
+	scanner.logger.TPrintf("karma-jasmine dependency found: %v", karmaJasmineDependencyFound)
 
 	if karmaJasmineDependencyFound {
 		karmaConfigJSONPth := filepath.Join(projectRootDir, "karma.conf.js")
-		if exist, err := pathutil.IsPathExists(karmaConfigJSONPth); err != nil {
+		if exist, err := scanner.fileIndex.IsPathExists(karmaConfigJSONPth); err != nil {
 			return models.OptionNode{}, warnings, nil, err
 		} else if exist {
 			karmaTestDetected = true
 		}
 	}
-	log.TPrintf("karma.conf.js found: %v", karmaTestDetected)
+	scanner.logger.TPrintf("karma.conf.js found: %v", karmaTestDetected)
 
 	scanner.hasKarmaJasmineTest = karmaTestDetected
 	// ---
@@ -165,7 +180,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 	jasminTestDetected := false
 
 	if !karmaTestDetected {
-		log.TPrintf("Searching for jasmine test")
+		scanner.logger.TPrintf("Searching for jasmine test")
 
 		jasmineDependencyFound := false
 		for dependency := range packages.Dependencies {
@@ -182,25 +197,25 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 				}
 			}
 		}
-		log.TPrintf("jasmine dependency found: %v", jasmineDependencyFound)
+		scanner.logger.TPrintf("jasmine dependency found: %v", jasmineDependencyFound)
 
 		if jasmineDependencyFound {
 			jasmineConfigJSONPth := filepath.Join(projectRootDir, "spec", "support", "jasmine.json")
-			if exist, err := pathutil.IsPathExists(jasmineConfigJSONPth); err != nil {
+			if exist, err := scanner.fileIndex.IsPathExists(jasmineConfigJSONPth); err != nil {
 				return models.OptionNode{}, warnings, nil, err
 			} else if exist {
 				jasminTestDetected = true
 			}
 		}
 
-		log.TPrintf("jasmine.json found: %v", jasminTestDetected)
+		scanner.logger.TPrintf("jasmine.json found: %v", jasminTestDetected)
 
 		scanner.hasJasmineTest = jasminTestDetected
 	}
 	// ---
 
 	// Configure Cordova
-	cordovaConfigExist, err := pathutil.IsPathExists(filepath.Join(projectRootDir, "config.xml"))
+	cordovaConfigExist, err := scanner.fileIndex.IsPathExists(filepath.Join(projectRootDir, "config.xml"))
 	if err != nil {
 		return models.OptionNode{},
 			warnings,
@@ -208,7 +223,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Ic
 			fmt.Errorf("failed to search for config.xml file: %w", err)
 	}
 
-	log.TPrintf("config.xml: %s", filepath.Join(projectRootDir, "config.xml"))
+	scanner.logger.TPrintf("config.xml: %s", filepath.Join(projectRootDir, "config.xml"))
 
 	if !cordovaConfigExist {
 		warning := "Cordova config.xml not found."
@@ -280,7 +295,7 @@ func (Scanner) DefaultOptions() models.OptionNode {
 }
 
 func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: sshKeyActivation,
 	})...)
@@ -297,6 +312,7 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem("install", workdir))
 
 		// CI
+		configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, testWorkflowReason)
 		if scanner.hasKarmaJasmineTest {
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.KarmaJasmineTestRunnerStepListItem(workdirEnvList...))
 		} else if scanner.hasJasmineTest {
@@ -306,6 +322,7 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
 
 		// CD
+		configBuilder.SetWorkflowReasonTo(models.DeployWorkflowID, buildWorkflowReason)
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{})...)
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 
@@ -329,21 +346,20 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.IonicArchiveStepListItem(ionicArchiveEnvs...))
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList()...)
 
-		config, err := configBuilder.Generate(scannerName)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(models.DeployWorkflowID)
 
-		data, err := yaml.Marshal(config)
+		config, err := configBuilder.Generate(scannerName)
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
 
 		return models.BitriseConfigMap{
-			configName: string(data),
+			configName: config,
 		}, nil
 	}
 
+	configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, buildWorkflowReason)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.RestoreNPMCache())
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem("install", workdir))
@@ -361,24 +377,23 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.SaveNPMCache())
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
 
-	config, err := configBuilder.Generate(scannerName)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
+	configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(models.PrimaryWorkflowID)
 
-	data, err := yaml.Marshal(config)
+	config, err := configBuilder.Generate(scannerName)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
 	return models.BitriseConfigMap{
-		configName: string(data),
+		configName: config,
 	}, nil
 }
 
 func (Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: models.SSHKeyActivationConditional})...)
+	configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, buildWorkflowReason)
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 
@@ -396,17 +411,15 @@ func (Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
 
-	config, err := configBuilder.Generate(scannerName)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
+	configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(models.PrimaryWorkflowID)
 
-	data, err := yaml.Marshal(config)
+	config, err := configBuilder.Generate(scannerName)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
 	return models.BitriseConfigMap{
-		defaultConfigName: string(data),
+		defaultConfigName: config,
 	}, nil
 }
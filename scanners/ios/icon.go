@@ -5,9 +5,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-xcode/xcodeproject/xcodeproj"
 	"github.com/bitrise-io/go-xcode/xcodeproject/xcscheme"
 )
@@ -27,23 +27,23 @@ func getMainTarget(project xcodeproj.XcodeProj, scheme xcscheme.Scheme) (xcodepr
 	return mainTarget, nil
 }
 
-func lookupIconByScheme(project xcodeproj.XcodeProj, scheme xcscheme.Scheme, basePath string) (models.Icons, error) {
+func lookupIconByScheme(logger *logger.Logger, project xcodeproj.XcodeProj, scheme xcscheme.Scheme, basePath string) (models.Icons, error) {
 	mainTarget, err := getMainTarget(project, scheme)
 	if err != nil {
-		log.TDebugf("%s", err)
+		logger.TDebugf("%s", err)
 		return nil, nil
 	}
 
-	return lookupIconByTarget(project.Path, mainTarget, basePath)
+	return lookupIconByTarget(logger, project.Path, mainTarget, basePath)
 }
 
-func lookupIconByTarget(projectPath string, target xcodeproj.Target, basepath string) (models.Icons, error) {
+func lookupIconByTarget(logger *logger.Logger, projectPath string, target xcodeproj.Target, basepath string) (models.Icons, error) {
 	targetToAppIconSetPaths, err := xcodeproj.AppIconSetPaths(projectPath)
 	if err != nil {
 		return nil, err
 	}
 	appIconSetPaths, ok := targetToAppIconSetPaths[target.ID]
-	log.TDebugf("Appiconsets for target (%s): %s", target.Name, appIconSetPaths)
+	logger.TDebugf("Appiconsets for target (%s): %s", target.Name, appIconSetPaths)
 	if !ok {
 		return nil, nil
 	}
@@ -54,10 +54,10 @@ func lookupIconByTarget(projectPath string, target xcodeproj.Target, basepath st
 		if err != nil {
 			return nil, fmt.Errorf("could not get icon, error: %w", err)
 		} else if !found {
-			log.TDebugf("No icon found at %s", appIconSetPath)
+			logger.TDebugf("No icon found at %s", appIconSetPath)
 			return nil, nil
 		}
-		log.TDebugf("App icons: %+v", icon)
+		logger.TDebugf("App icons: %+v", icon)
 
 		iconPath := filepath.Join(appIconSetPath, icon.Filename)
 		if _, err := os.Stat(iconPath); err != nil && os.IsNotExist(err) {
@@ -1,6 +1,10 @@
 package ios
 
 import (
+	"context"
+
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 )
 
@@ -16,6 +20,9 @@ type Scanner struct {
 
 	ExcludeAppIcon            bool
 	SuppressPodFileParseError bool
+
+	fileIndex *fileindex.Index
+	logger    *logger.Logger
 }
 
 // NewScanner ...
@@ -30,13 +37,18 @@ func (scanner *Scanner) Name() string {
 
 // DetectPlatform ...
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	result, err := ParseProjects(XcodeProjectTypeIOS, searchDir, scanner.ExcludeAppIcon, scanner.SuppressPodFileParseError)
+	return scanner.DetectPlatformWithContext(context.Background(), searchDir)
+}
+
+// DetectPlatformWithContext ...
+func (scanner *Scanner) DetectPlatformWithContext(ctx context.Context, searchDir string) (bool, error) {
+	result, err := ParseProjectsWithContext(ctx, scanner.logger, scanner.fileIndex, XcodeProjectTypeIOS, searchDir, scanner.ExcludeAppIcon, scanner.SuppressPodFileParseError)
 	if err != nil {
 		return false, err
 	}
 
 	if len(result.Projects) == 0 {
-		result, err = ParseSPMProject(XcodeProjectTypeIOS, searchDir)
+		result, err = ParseSPMProject(scanner.logger, scanner.fileIndex, XcodeProjectTypeIOS, searchDir)
 		if err != nil {
 			return false, err
 		}
@@ -47,6 +59,16 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	return detected, nil
 }
 
+// SetFileIndex ...
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 // ExcludedScannerNames ...
 func (scanner *Scanner) ExcludedScannerNames() []string {
 	return []string{}
@@ -54,7 +76,16 @@ func (scanner *Scanner) ExcludedScannerNames() []string {
 
 // Options ...
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
-	options, configDescriptors, icons, warnings, err := GenerateOptions(XcodeProjectTypeIOS, scanner.DetectResult)
+	return scanner.OptionsWithContext(context.Background())
+}
+
+// OptionsWithContext ...
+func (scanner *Scanner) OptionsWithContext(ctx context.Context) (models.OptionNode, models.Warnings, models.Icons, error) {
+	if err := ctx.Err(); err != nil {
+		return models.OptionNode{}, nil, nil, err
+	}
+
+	options, configDescriptors, icons, warnings, err := GenerateOptions(scanner.logger, XcodeProjectTypeIOS, scanner.DetectResult)
 	if err != nil {
 		return models.OptionNode{}, warnings, nil, err
 	}
@@ -0,0 +1,86 @@
+package ios
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
+)
+
+// mirroredFiles are the files (besides the Xcode project and workspace bundles) read by the project parsers
+// and the Podfile parsing ruby scripts.
+var mirroredFiles = []string{
+	podfileBase, "Podfile.lock",
+	cartfileBase, cartfileResolvedBase,
+	spmProjectFile, "Package.resolved",
+	"Gemfile", "Gemfile.lock",
+}
+
+func isMirroredEntry(entry fileindex.Entry) bool {
+	if slices.Contains(mirroredFiles, entry.Name) {
+		return true
+	}
+	for _, component := range strings.Split(filepath.ToSlash(entry.RelPath), "/") {
+		if ext := filepath.Ext(component); ext == ".xcodeproj" || ext == ".xcworkspace" {
+			return true
+		}
+	}
+	return false
+}
+
+// materializeSearchDir writes the files of a virtual file index matching the filter to a temporary directory
+// and returns the temporary directory and the location of searchDir in it.
+func materializeSearchDir(fileIndex *fileindex.Index, searchDir string, filter func(entry fileindex.Entry) bool) (string, string, func(), error) {
+	absSearchDir, err := filepath.Abs(searchDir)
+	if err != nil {
+		return "", "", nil, err
+	}
+	relSearchDir, err := filepath.Rel(fileIndex.RootDir(), absSearchDir)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	mirrorDir, cleanup, err := fileIndex.Materialize(filter)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	mirrorSearchDir := filepath.Join(mirrorDir, relSearchDir)
+	if err := os.MkdirAll(mirrorSearchDir, 0755); err != nil {
+		cleanup()
+		return "", "", nil, err
+	}
+	return mirrorDir, mirrorSearchDir, cleanup, nil
+}
+
+// parseProjectsInMirror parses the projects of a file index not backed by the disk:
+// the Xcode project parsers and the ruby scripts read the disk, so the relevant files are copied to a temporary directory first.
+// App icons are not looked up, the asset catalogs are not copied.
+func parseProjectsInMirror(ctx context.Context, logger *logger.Logger, fileIndex *fileindex.Index, projectType XcodeProjectType, searchDir string, suppressPodFileParseError bool) (DetectResult, error) {
+	mirrorDir, mirrorSearchDir, cleanup, err := materializeSearchDir(fileIndex, searchDir, isMirroredEntry)
+	if err != nil {
+		return DetectResult{}, err
+	}
+	defer cleanup()
+
+	logger.TPrintf("The project is not on the disk, app icon lookup is skipped")
+
+	result, err := ParseProjectsWithContext(ctx, logger, nil, projectType, mirrorSearchDir, true, suppressPodFileParseError)
+
+	// warnings may refer to files in the temporary directory
+	replacer := strings.NewReplacer(mirrorDir, fileIndex.RootDir())
+	for i, warning := range result.Warnings {
+		result.Warnings[i] = replacer.Replace(warning)
+	}
+	for i := range result.Projects {
+		for j, warning := range result.Projects[i].Warnings {
+			result.Projects[i].Warnings[j] = replacer.Replace(warning)
+		}
+	}
+
+	return result, err
+}
@@ -1,14 +1,15 @@
 package ios
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/bitrise-io/go-utils/sliceutil"
 )
@@ -21,6 +22,16 @@ var AllowPodfileBaseFilter = pathutil.BaseFilter(podfileBase, true)
 type podfileParser struct {
 	podfilePth                string
 	suppressPodFileParseError bool
+	// ctx cancels the ruby scripts run by the parser, context.Background() is used if not set.
+	ctx    context.Context
+	logger *logger.Logger
+}
+
+func (podfileParser podfileParser) context() context.Context {
+	if podfileParser.ctx == nil {
+		return context.Background()
+	}
+	return podfileParser.ctx
 }
 
 func (podfileParser podfileParser) getTargetDefinitionProjectMap(cocoapodsVersion string) (map[string]string, error) {
@@ -72,7 +83,7 @@ end
 
 	envs := []string{fmt.Sprintf("PODFILE_PATH=%s", podfileParser.podfilePth)}
 
-	out, err := runRubyScriptForOutput(rubyScriptContent, gemfileContent, envs)
+	out, err := runRubyScriptForOutput(podfileParser.context(), podfileParser.logger, rubyScriptContent, gemfileContent, envs)
 	if err != nil {
 		return map[string]string{}, fmt.Errorf("ruby script failed: %w", err)
 	}
@@ -105,8 +116,8 @@ func (podfileParser podfileParser) shouldRaiseReadDefinitionError(err string) bo
 
 	isInvalidPodfileError := strings.Contains(err, "Pod::DSLError")
 	if isInvalidPodfileError && podfileParser.suppressPodFileParseError {
-		log.TWarnf("Could not parse podfile: %s", err)
-		log.TWarnf("Will continue using default Cocoapods paths.")
+		podfileParser.logger.TWarnf("Could not parse podfile: %s", err)
+		podfileParser.logger.TWarnf("Will continue using default Cocoapods paths.")
 		return false
 	}
 
@@ -168,7 +179,7 @@ end
 
 	envs := []string{fmt.Sprintf("PODFILE_PATH=%s", podfileParser.podfilePth)}
 
-	out, err := runRubyScriptForOutput(rubyScriptContent, gemfileContent, envs)
+	out, err := runRubyScriptForOutput(podfileParser.context(), podfileParser.logger, rubyScriptContent, gemfileContent, envs)
 	if err != nil {
 		return "", fmt.Errorf("ruby script failed: %w", err)
 	}
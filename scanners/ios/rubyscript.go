@@ -1,26 +1,27 @@
 package ios
 
 import (
+	"context"
 	"errors"
-	"path"
-
 	"os"
+	"os/exec"
+	"path"
 
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/go-utils/command"
 	"github.com/bitrise-io/go-utils/errorutil"
 	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
-func runRubyScriptForOutput(scriptContent, gemfileContent string, withEnvs []string) (string, error) {
+func runRubyScriptForOutput(ctx context.Context, logger *logger.Logger, scriptContent, gemfileContent string, withEnvs []string) (string, error) {
 	tmpDir, err := pathutil.NormalizedOSTempDirPath("__bitrise-init__")
 	if err != nil {
 		return "", err
 	}
 	defer func() {
 		if err := os.RemoveAll(tmpDir); err != nil {
-			log.TErrorf("Failed to remove tmp dir (%s), error: %s", tmpDir, err)
+			logger.TErrorf("Failed to remove tmp dir (%s), error: %s", tmpDir, err)
 		}
 	}()
 
@@ -31,13 +32,16 @@ func runRubyScriptForOutput(scriptContent, gemfileContent string, withEnvs []str
 			return "", err
 		}
 
-		cmd := command.New("bundle", "install")
+		cmd := command.NewWithCmd(exec.CommandContext(ctx, "bundle", "install"))
 		cmd.SetDir(tmpDir)
 
 		withEnvs = append(withEnvs, "BUNDLE_GEMFILE="+gemfilePth)
 		cmd.AppendEnvs(withEnvs...)
 
 		if out, err := cmd.RunAndReturnTrimmedCombinedOutput(); err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
 			if errorutil.IsExitStatusError(err) {
 				return "", errors.New(out)
 			}
@@ -54,9 +58,9 @@ func runRubyScriptForOutput(scriptContent, gemfileContent string, withEnvs []str
 	var cmd *command.Model
 
 	if gemfileContent != "" {
-		cmd = command.New("bundle", "exec", "ruby", rubyScriptPth)
+		cmd = command.NewWithCmd(exec.CommandContext(ctx, "bundle", "exec", "ruby", rubyScriptPth))
 	} else {
-		cmd = command.New("ruby", rubyScriptPth)
+		cmd = command.NewWithCmd(exec.CommandContext(ctx, "ruby", rubyScriptPth))
 	}
 
 	// Set the temp dir as working dir, so the project defined `.ruby-version` does not cause ruby resolution to fail:
@@ -69,6 +73,9 @@ func runRubyScriptForOutput(scriptContent, gemfileContent string, withEnvs []str
 
 	out, err := cmd.RunAndReturnTrimmedCombinedOutput()
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		if errorutil.IsExitStatusError(err) {
 			return "", errors.New(out)
 		}
@@ -2,9 +2,12 @@ package ios
 
 import (
 	"encoding/json"
+	"fmt"
 	"path/filepath"
 
-	"github.com/bitrise-io/bitrise-init/utility"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
+	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/go-utils/command"
 )
 
@@ -41,12 +44,33 @@ type spmProject struct {
 	Dependencies []spmDependency `json:"dependencies"`
 }
 
-func ParseSPMProject(projectType XcodeProjectType, searchDir string) (DetectResult, error) {
+// ParseSPMProject detects a Swift package in searchDir, the package manifest is looked up in fileIndex.
+// The manifest is evaluated by the swift command, if the file index is not backed by the disk
+// the manifest is copied to a temporary directory and a failing evaluation is reported as a warning.
+func ParseSPMProject(logger *logger.Logger, fileIndex *fileindex.Index, projectType XcodeProjectType, searchDir string) (DetectResult, error) {
 	packagePath := filepath.Join(searchDir, spmProjectFile)
-	if !utility.FileExists(packagePath) {
+	if !fileIndex.FileExists(packagePath) {
 		return DetectResult{}, nil
 	}
 
+	if !fileIndex.OnDisk() {
+		_, mirrorSearchDir, cleanup, err := materializeSearchDir(fileIndex, searchDir, func(entry fileindex.Entry) bool {
+			return entry.Name == spmProjectFile || entry.Name == "Package.resolved"
+		})
+		if err != nil {
+			return DetectResult{}, err
+		}
+		defer cleanup()
+
+		result, err := ParseSPMProject(logger, nil, projectType, mirrorSearchDir)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to evaluate %s: %s", spmProjectFile, err)
+			logger.TWarnf(warning)
+			return DetectResult{Warnings: models.Warnings{warning}}, nil
+		}
+		return result, nil
+	}
+
 	cmd := command.New("swift", "package", "dump-package")
 	cmd.SetDir(searchDir)
 	output, err := cmd.RunAndReturnTrimmedOutput()
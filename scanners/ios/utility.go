@@ -1,15 +1,16 @@
 package ios
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
-	"gopkg.in/yaml.v2"
-
 	"github.com/bitrise-io/bitrise-init/analytics"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/steps"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/bitrise-io/go-utils/sliceutil"
 	"github.com/bitrise-io/go-xcode/xcodeproject/xcscheme"
@@ -207,10 +208,10 @@ It is <a href="https://github.com/Carthage/Carthage/blob/master/Documentation/Ar
 	return carthageCommand, warning
 }
 
-func relPathForLog(searchDir string, path string) string {
+func relPathForLog(logger *logger.Logger, searchDir string, path string) string {
 	relPath, err := filepath.Rel(searchDir, path)
 	if err != nil {
-		log.TWarnf("failed to get relative path: %s", err)
+		logger.TWarnf("failed to get relative path: %s", err)
 		return ""
 	}
 
@@ -219,34 +220,44 @@ func relPathForLog(searchDir string, path string) string {
 
 // ParseProjects collects available iOS/macOS projects
 func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIcon, suppressPodFileParseError bool) (DetectResult, error) {
+	return ParseProjectsWithContext(context.Background(), nil, nil, projectType, searchDir, excludeAppIcon, suppressPodFileParseError)
+}
+
+// ParseProjectsWithContext collects available iOS/macOS projects, the Podfile parsing ruby scripts are cancelled when ctx is done.
+// The project files are looked up in fileIndex, if searchDir is indexed.
+func ParseProjectsWithContext(ctx context.Context, logger *logger.Logger, fileIndex *fileindex.Index, projectType XcodeProjectType, searchDir string, excludeAppIcon, suppressPodFileParseError bool) (DetectResult, error) {
+	if !fileIndex.OnDisk() {
+		return parseProjectsInMirror(ctx, logger, fileIndex, projectType, searchDir, suppressPodFileParseError)
+	}
+
 	var (
 		projects []Project
 		warnings models.Warnings
 	)
 
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, false)
+	fileList, err := fileIndex.ListPathInDirSortedByComponents(searchDir, false)
 	if err != nil {
 		return DetectResult{}, err
 	}
 
 	// Separate workspaces and standalone projects
-	log.TInfof("Filtering relevant Xcode project files")
+	logger.TInfof("Filtering relevant Xcode project files")
 	projectFiles, err := FilterRelevantProjectFiles(fileList, projectType)
 	if err != nil {
 		return DetectResult{}, err
 	}
 
-	log.TPrintf("%d Xcode %s project files found", len(projectFiles), string(projectType))
+	logger.TPrintf("%d Xcode %s project files found", len(projectFiles), string(projectType))
 	for _, xcodeprojectFile := range projectFiles {
-		log.TPrintf("- %s", relPathForLog(searchDir, xcodeprojectFile))
+		logger.TPrintf("- %s", relPathForLog(logger, searchDir, xcodeprojectFile))
 	}
 
 	if len(projectFiles) == 0 {
-		log.TPrintf("Platform not detected")
+		logger.TPrintf("Platform not detected")
 		return DetectResult{}, nil
 	}
 
-	log.TSuccessf("Platform detected")
+	logger.TSuccessf("Platform detected")
 
 	workspaceFiles, err := FilterRelevantWorkspaceFiles(fileList, projectType)
 	if err != nil {
@@ -259,38 +270,44 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 	}
 
 	// Detect SPM
-	log.TInfof("Searching for Swift Package Manager dependencies")
+	logger.TInfof("Searching for Swift Package Manager dependencies")
 	hasSPMDeps, err := HasSPMDependencies(fileList)
 	if err != nil {
 		return DetectResult{}, err
 	}
 	if hasSPMDeps {
-		log.TPrintf("Swift Package Manager usage detected")
+		logger.TPrintf("Swift Package Manager usage detected")
 	}
 
 	// Create cocoapods workspace-project mapping
-	log.TInfof("Searching for Podfile")
+	logger.TInfof("Searching for Podfile")
 
 	podfiles, err := FilterRelevantPodfiles(fileList)
 	if err != nil {
 		return DetectResult{}, err
 	}
 
-	log.TPrintf("%d Podfiles detected", len(podfiles))
+	logger.TPrintf("%d Podfiles detected", len(podfiles))
 
 	for _, podfile := range podfiles {
-		log.TPrintf("- %s", relPathForLog(searchDir, podfile))
+		logger.TPrintf("- %s", relPathForLog(logger, searchDir, podfile))
 
 		podfileParser := podfileParser{
 			podfilePth:                podfile,
 			suppressPodFileParseError: suppressPodFileParseError,
+			ctx:                       ctx,
+			logger:                    logger,
 		}
 
 		workspaceProjectMap, err := podfileParser.GetWorkspaceProjectMap(projectFiles)
 		if err != nil {
+			if ctx.Err() != nil {
+				return DetectResult{}, ctx.Err()
+			}
+
 			warning := fmt.Sprintf("Failed to determine cocoapods project-workspace mapping, error: %s", err)
 			warnings = append(warnings, warning)
-			log.Warnf(warning)
+			logger.Warnf(warning)
 
 			continue
 		}
@@ -299,7 +316,7 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 		if err != nil {
 			warning := fmt.Sprintf("Failed to create cocoapods project-workspace mapping, error: %s", err)
 			warnings = append(warnings, warning)
-			log.Warnf(warning)
+			logger.Warnf(warning)
 
 			continue
 		}
@@ -307,7 +324,7 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 	}
 
 	// Carthage
-	log.TInfof("Searching for Cartfile")
+	logger.TInfof("Searching for Cartfile")
 
 	cartfiles, err := FilterRelevantCartFile(fileList)
 	if err != nil {
@@ -316,9 +333,9 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 		}, err
 	}
 
-	log.TPrintf("%d Cartfiles detected", len(cartfiles))
+	logger.TPrintf("%d Cartfiles detected", len(cartfiles))
 	for _, file := range cartfiles {
-		log.TPrintf("- %s", relPathForLog(searchDir, file))
+		logger.TPrintf("- %s", relPathForLog(logger, searchDir, file))
 	}
 
 	for _, container := range append(detectedContainers.standaloneProjects, detectedContainers.workspaces...) {
@@ -333,7 +350,7 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 			return DetectResult{Warnings: warnings}, fmt.Errorf("failed to get relative path: %w", err)
 		}
 
-		log.TInfof("Inspecting file: %s", containerRelPath)
+		logger.TInfof("Inspecting file: %s", containerRelPath)
 		carthageCommand, warning := detectCarthageCommand(containerPath)
 		if warning != "" {
 			projectWarnings = append(projectWarnings, warning)
@@ -350,7 +367,7 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 		}
 
 		for _, missingProject := range missingProjects {
-			log.Warnf("Skipping Project (%s), as it is not present", relPathForLog(searchDir, missingProject))
+			logger.Warnf("Skipping Project (%s), as it is not present", relPathForLog(logger, searchDir, missingProject))
 		}
 
 		for _, project := range containerProjects {
@@ -362,12 +379,12 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 			}
 
 			for _, scheme := range sharedSchemes {
-				log.TPrintf("- %s", scheme.Name)
+				logger.TPrintf("- %s", scheme.Name)
 
 				var icons models.Icons
 				if !excludeAppIcon {
-					if icons, err = lookupIconByScheme(project, scheme, searchDir); err != nil {
-						log.Warnf("could not get icons for app: %s, error: %s", containerRelPath, err)
+					if icons, err = lookupIconByScheme(logger, project, scheme, searchDir); err != nil {
+						logger.Warnf("could not get icons for app: %s, error: %s", containerRelPath, err)
 						analytics.LogInfo(iconFailureTag, analytics.DetectorErrorData(string(XcodeProjectTypeIOS), err), "Failed to lookup ios icons")
 					}
 				}
@@ -375,7 +392,7 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 				detectedSchemes = append(detectedSchemes, Scheme{
 					Name:       scheme.Name,
 					HasXCTests: scheme.IsTestable(),
-					HasAppClip: schemeHasAppClipTarget(project, scheme),
+					HasAppClip: schemeHasAppClipTarget(logger, project, scheme),
 					Icons:      icons,
 				})
 			}
@@ -398,7 +415,7 @@ func ParseProjects(projectType XcodeProjectType, searchDir string, excludeAppIco
 	}, nil
 }
 
-func GenerateOptions(projectType XcodeProjectType, result DetectResult) (models.OptionNode, []ConfigDescriptor, models.Icons, models.Warnings, error) {
+func GenerateOptions(logger *logger.Logger, projectType XcodeProjectType, result DetectResult) (models.OptionNode, []ConfigDescriptor, models.Icons, models.Warnings, error) {
 	var (
 		exportMethodInputTitle   string
 		exportMethodInputSummary string
@@ -430,6 +447,7 @@ func GenerateOptions(projectType XcodeProjectType, result DetectResult) (models.
 
 		schemeOption := models.NewOption(SchemeInputTitle, SchemeInputSummary, SchemeInputEnvKey, models.TypeSelector)
 		projectPathOption.AddOption(project.RelPath, schemeOption)
+		recommendTestableSchemes(schemeOption, project)
 
 		for _, scheme := range project.Schemes {
 			// SPM projects do not have an icon and do not need the export options.
@@ -480,13 +498,30 @@ func GenerateOptions(projectType XcodeProjectType, result DetectResult) (models.
 
 	configDescriptors = RemoveDuplicatedConfigDescriptors(configDescriptors, projectType)
 	if len(configDescriptors) == 0 {
-		log.TErrorf("No valid %s config found", string(projectType))
+		logger.TErrorf("No valid %s config found", string(projectType))
 		return models.OptionNode{}, []ConfigDescriptor{}, nil, allWarnings, fmt.Errorf("no valid %s config found", string(projectType))
 	}
 
 	return *projectPathOption, configDescriptors, iconsForAllProjects, allWarnings, nil
 }
 
+// recommendTestableSchemes recommends the schemes with test targets, if only some of the project's schemes have them.
+func recommendTestableSchemes(schemeOption *models.OptionNode, project Project) {
+	var testableSchemes []string
+	for _, scheme := range project.Schemes {
+		if scheme.HasXCTests {
+			testableSchemes = append(testableSchemes, scheme.Name)
+		}
+	}
+	if len(testableSchemes) == 0 || len(testableSchemes) == len(project.Schemes) {
+		return
+	}
+
+	for _, scheme := range testableSchemes {
+		schemeOption.SetValueInfo(scheme, models.ValueInfo{Recommended: true, Reason: "the scheme has test targets", DetectedFrom: project.RelPath})
+	}
+}
+
 func GenerateDefaultOptions(projectType XcodeProjectType) models.OptionNode {
 	projectPathOption := models.NewOption(ProjectPathInputTitle, ProjectPathInputSummary, ProjectPathInputEnvKey, models.TypeUserInput)
 
@@ -532,7 +567,7 @@ func GenerateConfigBuilder(
 	carthageCommand,
 	exportMethod string,
 ) models.ConfigBuilderModel {
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 
 	params := workflowSetupParams{
 		projectType:        projectType,
@@ -555,6 +590,8 @@ func GenerateConfigBuilder(
 		createDeployWorkflow(params)
 	}
 
+	createTriggers(params, !isSPMProject)
+
 	return *configBuilder
 }
 
@@ -598,12 +635,7 @@ func GenerateConfig(projectType XcodeProjectType, configDescriptors []ConfigDesc
 			return models.BitriseConfigMap{}, err
 		}
 
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
-
-		bitriseDataMap[descriptor.ConfigName(projectType)] = string(data)
+		bitriseDataMap[descriptor.ConfigName(projectType)] = config
 	}
 
 	return bitriseDataMap, nil
@@ -631,12 +663,7 @@ func GenerateDefaultConfig(projectType XcodeProjectType) (models.BitriseConfigMa
 		return models.BitriseConfigMap{}, err
 	}
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
-
 	return models.BitriseConfigMap{
-		fmt.Sprintf(defaultConfigNameFormat, string(projectType)): string(data),
+		fmt.Sprintf(defaultConfigNameFormat, string(projectType)): config,
 	}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/go-utils/command/git"
 	"github.com/bitrise-io/go-utils/fileutil"
 	"github.com/stretchr/testify/assert"
@@ -92,6 +93,28 @@ func gitClone(t *testing.T, dir, uri string) {
 	require.NoError(t, err)
 	require.NoError(t, g.Clone(uri).Run())
 }
+
+func TestGenerateOptions_recommendsTestableSchemes(t *testing.T) {
+	result := DetectResult{Projects: []Project{{
+		RelPath: "App.xcodeproj",
+		Schemes: []Scheme{{Name: "App"}, {Name: "AppTests", HasXCTests: true}},
+	}}}
+
+	options, _, _, _, err := GenerateOptions(nil, XcodeProjectTypeIOS, result)
+	require.NoError(t, err)
+	schemeOption := options.ChildOptionMap["App.xcodeproj"]
+	require.Equal(t, "AppTests", schemeOption.DefaultValue)
+	require.Equal(t, map[string]models.ValueInfo{
+		"AppTests": {Recommended: true, Reason: "the scheme has test targets", DetectedFrom: "App.xcodeproj"},
+	}, schemeOption.ValueInfos)
+
+	// no scheme is recommended if all of them have tests
+	result.Projects[0].Schemes[0].HasXCTests = true
+	options, _, _, _, err = GenerateOptions(nil, XcodeProjectTypeIOS, result)
+	require.NoError(t, err)
+	require.Empty(t, options.ChildOptionMap["App.xcodeproj"].DefaultValue)
+}
+
 func TestParseProjects(t *testing.T) {
 	t.Run("ios-no-shared-schemes-files-and-autocreate-schemes-disabled", func(t *testing.T) {
 		sampleAppDir := t.TempDir()
@@ -42,8 +42,13 @@ const (
 	buildWorkflowSummary     = "Build your Xcode project."
 	buildWorkflowDescription = "The workflow will first clone your Git repository, cache and install your project's dependencies if any and build your project."
 
-	buildForTestingWorkflowID     = "build_for_testing"
-	testWithoutBuildingWorkflowID = "test_without_building"
+	testWorkflowReason  = "Runs the tests of the project to verify the changes."
+	buildWorkflowReason = "Builds the project to verify the changes, the project has no test target."
+
+	buildForTestingWorkflowID         = "build_for_testing"
+	buildForTestingWorkflowReason     = "Builds the tests once, the test bundle is shared with the test_without_building workflow of the run_tests pipeline."
+	testWithoutBuildingWorkflowID     = "test_without_building"
+	testWithoutBuildingWorkflowReason = "Runs a shard of the tests, the run_tests pipeline runs the shards in parallel."
 
 	// deploy workflow
 	deployWorkflowID = "deploy"
@@ -55,6 +60,8 @@ const (
 
 	archiveAndExportWorkflowWithoutTestsSummary     = "Create an IPA file to install your app on a device or share it with your team."
 	archiveAndExportWorkflowWithoutTestsDescription = "The workflow will first clone your Git repository, cache and install your project's dependencies if any, export an IPA file from the project and save it."
+
+	deployWorkflowReason = "Archives and exports the app on every push to the default branch, the app is uploaded to Bitrise."
 )
 
 type workflowSetupParams struct {
@@ -83,6 +90,11 @@ func createVerificationWorkflow(params workflowSetupParams) {
 	addSharedTeardownSteps(models.WorkflowID(id), params, true)
 	addSummary(models.WorkflowID(id), params.configBuilder, summary)
 	addDescription(models.WorkflowID(id), params.configBuilder, description)
+	if params.hasTests {
+		addReason(models.WorkflowID(id), params.configBuilder, testWorkflowReason)
+	} else {
+		addReason(models.WorkflowID(id), params.configBuilder, buildWorkflowReason)
+	}
 }
 
 func createDeployWorkflow(params workflowSetupParams) {
@@ -99,6 +111,7 @@ func createDeployWorkflow(params workflowSetupParams) {
 	addSharedTeardownSteps(models.WorkflowID(id), params, false) // No cache in deploy workflows
 	addSummary(models.WorkflowID(id), params.configBuilder, summary)
 	addDescription(models.WorkflowID(id), params.configBuilder, description)
+	addReason(models.WorkflowID(id), params.configBuilder, deployWorkflowReason)
 }
 
 func createBuildForTestingWorkflow(params workflowSetupParams) {
@@ -116,6 +129,7 @@ func createBuildForTestingWorkflow(params workflowSetupParams) {
 		steps.XcodeTestShardCalculationStepListItem(xcodeTestShardCalculationStepInputModels()...),
 		steps.DeployToBitriseIoStepListItem(buildForTestingDeployToBitriseIoStepInputModels()...),
 	)
+	addReason(workflow, params.configBuilder, buildForTestingWorkflowReason)
 }
 
 func createTestWithoutBuildingWorkflow(params workflowSetupParams) {
@@ -130,6 +144,7 @@ func createTestWithoutBuildingWorkflow(params workflowSetupParams) {
 		steps.PullIntermediateFilesStepListItem(),
 		steps.XcodeTestWithoutBuildingStepListItem(xcodeTestWithoutBuildingStepInputModels()...),
 	)
+	addReason(workflow, params.configBuilder, testWithoutBuildingWorkflowReason)
 }
 
 func createRunTestsParallelPipeline(params workflowSetupParams) {
@@ -144,6 +159,19 @@ func createRunTestsParallelPipeline(params workflowSetupParams) {
 	})
 }
 
+// createTriggers starts the verification workflow on pull requests and the deploy workflow (if any) on pushes to the default branch.
+func createTriggers(params workflowSetupParams, hasDeployWorkflow bool) {
+	verificationID, _, _ := verificationWorkflowIDSummaryAndDescription(params.projectType, params.hasTests)
+	params.configBuilder.AddPullRequestTriggerToWorkflow(models.WorkflowID(verificationID))
+
+	if hasDeployWorkflow {
+		deployID, _, _ := deployWorkflowIDSummaryAndDescription(params.projectType, params.hasTests)
+		params.configBuilder.AddPushTriggerToWorkflow(models.WorkflowID(deployID))
+	} else {
+		params.configBuilder.AddPushTriggerToWorkflow(models.WorkflowID(verificationID))
+	}
+}
+
 func verificationWorkflowIDSummaryAndDescription(projectType XcodeProjectType, hasTests bool) (string, string, string) {
 	var id string
 	var summary string
@@ -285,6 +313,10 @@ func addSummary(workflow models.WorkflowID, configBuilder *models.ConfigBuilderM
 	configBuilder.SetWorkflowSummaryTo(workflow, summary)
 }
 
+func addReason(workflow models.WorkflowID, configBuilder *models.ConfigBuilderModel, reason string) {
+	configBuilder.SetWorkflowReasonTo(workflow, reason)
+}
+
 // Helpers
 
 func baseXcodeStepInputModels() []envmanModels.EnvironmentItemModel {
@@ -3,15 +3,14 @@ package java
 import (
 	"fmt"
 
-	"gopkg.in/yaml.v2"
-
 	"github.com/bitrise-io/bitrise-init/detectors/direntry"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/bitrise-init/detectors/gradle"
 	"github.com/bitrise-io/bitrise-init/detectors/maven"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 const (
@@ -22,7 +21,8 @@ const (
 	buildToolGradle       = "Gradle"
 	buildToolMaven        = "Maven"
 
-	testWorkflowID = "run_tests"
+	testWorkflowID     = "run_tests"
+	testWorkflowReason = "Runs the unit tests of the project on every change."
 
 	gradleConfigName                 = "java-gradle-config"
 	defaultGradleConfigName          = "default-java-gradle-config"
@@ -46,6 +46,8 @@ set -euxo pipefail
 type Scanner struct {
 	gradleProject *gradle.Project
 	mavenProject  *maven.Project
+	fileIndex     *fileindex.Index
+	logger        *logger.Logger
 }
 
 func NewScanner() *Scanner {
@@ -57,20 +59,20 @@ func (s *Scanner) Name() string {
 }
 
 func (s *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	log.TInfof("Searching for Gradle project files...")
+	s.logger.TInfof("Searching for Gradle project files...")
 
-	rootEntry, err := direntry.WalkDir(searchDir, 6)
+	rootEntry, err := direntry.WalkDirWithIndex(s.fileIndex, searchDir, 6)
 	if err != nil {
 		return false, err
 	}
 
 	gradleWrapperScripts := rootEntry.FindAllEntriesByName("gradlew", false)
-	log.TDonef("%d Gradle wrapper script(s) found", len(gradleWrapperScripts))
+	s.logger.TDonef("%d Gradle wrapper script(s) found", len(gradleWrapperScripts))
 
 	if len(gradleWrapperScripts) > 0 {
 		gradleWrapperScript := gradleWrapperScripts[0]
 
-		log.TInfof("Scanning project with Gradle wrapper script: %s", gradleWrapperScript.AbsPath)
+		s.logger.TInfof("Scanning project with Gradle wrapper script: %s", gradleWrapperScript.AbsPath)
 
 		projectRootDir := gradleWrapperScript.Parent()
 		if projectRootDir == nil {
@@ -83,22 +85,22 @@ func (s *Scanner) DetectPlatform(searchDir string) (bool, error) {
 
 		if gradleProject != nil {
 			s.gradleProject = gradleProject
-			printGradleProject(*gradleProject)
+			printGradleProject(s.logger, *gradleProject)
 			return true, nil
 		} else {
-			log.TWarnf("No Gradle project found in %s", projectRootDir.AbsPath)
+			s.logger.TWarnf("No Gradle project found in %s", projectRootDir.AbsPath)
 		}
 	}
 
-	log.TInfof("Searching for Maven project files...")
+	s.logger.TInfof("Searching for Maven project files...")
 
 	projectObjectModels := rootEntry.FindAllEntriesByName("pom.xml", false)
-	log.TDonef("%d POM file(s) found", len(projectObjectModels))
+	s.logger.TDonef("%d POM file(s) found", len(projectObjectModels))
 
 	if len(projectObjectModels) > 0 {
 		projectObjectModel := projectObjectModels[0]
 
-		log.TInfof("Scanning project with POM file: %s", projectObjectModel.AbsPath)
+		s.logger.TInfof("Scanning project with POM file: %s", projectObjectModel.AbsPath)
 
 		projectRootDir := projectObjectModel.Parent()
 		if projectRootDir == nil {
@@ -111,16 +113,25 @@ func (s *Scanner) DetectPlatform(searchDir string) (bool, error) {
 
 		if mavenProject != nil {
 			s.mavenProject = mavenProject
-			printMavenProject(*mavenProject)
+			printMavenProject(s.logger, *mavenProject)
 			return true, nil
 		} else {
-			log.Warnf("No Maven project found in %s", projectRootDir.AbsPath)
+			s.logger.Warnf("No Maven project found in %s", projectRootDir.AbsPath)
 		}
 	}
 
 	return false, nil
 }
 
+func (s *Scanner) SetFileIndex(index *fileindex.Index) {
+	s.fileIndex = index
+}
+
+// SetLogger ...
+func (s *Scanner) SetLogger(logger *logger.Logger) {
+	s.logger = logger
+}
+
 func (s *Scanner) ExcludedScannerNames() []string {
 	return []string{}
 }
@@ -162,7 +173,10 @@ func (s *Scanner) DefaultOptions() models.OptionNode {
 }
 
 func (s *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
+	configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
+	configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(testWorkflowID)
 	bitriseDataMap := models.BitriseConfigMap{}
 
 	if s.gradleProject != nil {
@@ -182,12 +196,7 @@ func (s *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.Bitr
 			return models.BitriseConfigMap{}, err
 		}
 
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
-
-		bitriseDataMap[gradleConfigName] = string(data)
+		bitriseDataMap[gradleConfigName] = config
 	}
 
 	if s.mavenProject != nil {
@@ -207,12 +216,7 @@ func (s *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.Bitr
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
-
-		bitriseDataMap[mavenConfigName] = string(data)
+		bitriseDataMap[mavenConfigName] = config
 	}
 
 	return bitriseDataMap, nil
@@ -222,9 +226,12 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 	bitriseDataMap := models.BitriseConfigMap{}
 
 	{
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := steps.NewConfigBuilder()
 
 		gradleProjectRootDir := "$" + gradleProjectRootDirInputEnvKey
+		configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
+		configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(testWorkflowID)
 		configBuilder.AppendStepListItemsTo(testWorkflowID,
 			steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: models.SSHKeyActivationConditional})...,
 		)
@@ -239,17 +246,16 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
-		bitriseDataMap[defaultGradleConfigName] = string(data)
+		bitriseDataMap[defaultGradleConfigName] = config
 	}
 
 	{
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := steps.NewConfigBuilder()
 
 		mavenProjectRootDir := "$" + mavenProjectRootDirInputEnvKey
+		configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
+		configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(testWorkflowID)
 		configBuilder.AppendStepListItemsTo(testWorkflowID,
 			steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: models.SSHKeyActivationConditional})...,
 		)
@@ -266,38 +272,34 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
-		bitriseDataMap[defaultMavenConfigName] = string(data)
+		bitriseDataMap[defaultMavenConfigName] = config
 	}
 
 	return bitriseDataMap, nil
 }
 
-func printGradleProject(gradleProject gradle.Project) {
-	log.TPrintf("Project root dir: %s", gradleProject.RootDirEntry.RelPath)
-	log.TPrintf("Gradle wrapper script: %s", gradleProject.GradlewFileEntry.RelPath)
+func printGradleProject(logger *logger.Logger, gradleProject gradle.Project) {
+	logger.TPrintf("Project root dir: %s", gradleProject.RootDirEntry.RelPath)
+	logger.TPrintf("Gradle wrapper script: %s", gradleProject.GradlewFileEntry.RelPath)
 	if gradleProject.ConfigDirEntry != nil {
-		log.TPrintf("Gradle config dir: %s", gradleProject.ConfigDirEntry.RelPath)
+		logger.TPrintf("Gradle config dir: %s", gradleProject.ConfigDirEntry.RelPath)
 	}
 	if gradleProject.VersionCatalogFileEntry != nil {
-		log.TPrintf("Version catalog file: %s", gradleProject.VersionCatalogFileEntry.RelPath)
+		logger.TPrintf("Version catalog file: %s", gradleProject.VersionCatalogFileEntry.RelPath)
 	}
 	if gradleProject.SettingsGradleFileEntry != nil {
-		log.TPrintf("Gradle settings file: %s", gradleProject.SettingsGradleFileEntry.RelPath)
+		logger.TPrintf("Gradle settings file: %s", gradleProject.SettingsGradleFileEntry.RelPath)
 	}
 	if len(gradleProject.IncludedProjects) > 0 {
-		log.TPrintf("Included projects:")
+		logger.TPrintf("Included projects:")
 		for _, includedProject := range gradleProject.IncludedProjects {
-			log.TPrintf("- %s: %s", includedProject.Name, includedProject.BuildScriptFileEntry.RelPath)
+			logger.TPrintf("- %s: %s", includedProject.Name, includedProject.BuildScriptFileEntry.RelPath)
 		}
 	}
 }
 
-func printMavenProject(mavenProject maven.Project) {
-	log.TPrintf("Project root dir: %s", mavenProject.RootDirEntry.RelPath)
-	log.TPrintf("Maven POM file: %s", mavenProject.ProjectObjectModelFileEntry.RelPath)
-	log.TPrintf("Maven wrapper file: %s", mavenProject.MavenWrapperFileEntry.RelPath)
+func printMavenProject(logger *logger.Logger, mavenProject maven.Project) {
+	logger.TPrintf("Project root dir: %s", mavenProject.RootDirEntry.RelPath)
+	logger.TPrintf("Maven POM file: %s", mavenProject.ProjectObjectModelFileEntry.RelPath)
+	logger.TPrintf("Maven wrapper file: %s", mavenProject.MavenWrapperFileEntry.RelPath)
 }
@@ -3,11 +3,11 @@ package kmp
 import (
 	"fmt"
 
-	"gopkg.in/yaml.v2"
-
 	"github.com/bitrise-io/bitrise-init/detectors/direntry"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/bitrise-init/detectors/gradle"
 	"github.com/bitrise-io/bitrise-init/detectors/kmp"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/android"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
@@ -15,7 +15,6 @@ import (
 	"github.com/bitrise-io/bitrise-init/steps"
 	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 /*
@@ -34,18 +33,18 @@ const (
 	gradleProjectRootDirInputEnvKey  = "PROJECT_ROOT_DIR"
 	gradleProjectRootDirInputTitle   = "The root directory of the Kotlin Multiplatform project."
 	gradleProjectRootDirInputSummary = "The root directory of the Kotlin Multiplatform project, which contains all source files from your project, as well as Gradle files, including the Gradle Wrapper (gradlew) file."
-	optionValueYes                   = "yes"
-	optionValueNo                    = "no"
+	applicationTargetsInputTitle     = "Application targets"
+	applicationTargetsInputSummary   = "The application targets of the project to build, besides testing the shared Kotlin code."
+	applicationTargetAndroid         = "android"
+	applicationTargetIOS             = "ios"
 )
 
 // Android App project options
 const (
-	moduleInputTitle                  = "Android Application Module"
-	moduleInputSummary                = "The name of the Android application module to build."
-	variantInputTitle                 = "Android Application Variant"
-	variantInputSummary               = "The name of the Android application variant to build."
-	hasAndroidApplicationInputTitle   = "Has Android Application target?"
-	HasAndroidApplicationInputSummary = "Indicates whether the project contains an Android Application target."
+	moduleInputTitle    = "Android Application Module"
+	moduleInputSummary  = "The name of the Android application module to build."
+	variantInputTitle   = "Android Application Variant"
+	variantInputSummary = "The name of the Android application variant to build."
 )
 
 // iOS App project options
@@ -56,8 +55,6 @@ const (
 	schemeInputSummary             = "The name of the iOS application scheme to build."
 	distributionMethodInputTitle   = "iOS Application Distribution method"
 	distributionMethodInputSummary = "The export method to use to build the iOS application IPA file."
-	hasIOSApplicationInputTitle    = "Has iOS Application target?"
-	hasIOSApplicationInputSummary  = "Indicates whether the project contains an iOS Application target."
 )
 
 // Config names
@@ -77,8 +74,17 @@ const (
 	buildPipelineID        = "build"
 )
 
+// Workflow reasons
+const (
+	testWorkflowReason         = "Runs the tests of the shared Kotlin code."
+	androidBuildWorkflowReason = "Builds the Android app of the project."
+	iosBuildWorkflowReason     = "Builds the iOS app of the project."
+)
+
 type Scanner struct {
 	kmpProject *kmp.Project
+	fileIndex  *fileindex.Index
+	logger     *logger.Logger
 }
 
 func NewScanner() *Scanner {
@@ -90,22 +96,22 @@ func (s *Scanner) Name() string {
 }
 
 func (s *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	log.TInfof("Searching for Gradle project files...")
+	s.logger.TInfof("Searching for Gradle project files...")
 
-	rootEntry, err := direntry.WalkDir(searchDir, 6)
+	rootEntry, err := direntry.WalkDirWithIndex(s.fileIndex, searchDir, 6)
 	if err != nil {
 		return false, err
 	}
 
 	gradleWrapperScripts := rootEntry.FindAllEntriesByName("gradlew", false)
 
-	log.TDonef("%d Gradle wrapper script(s) found", len(gradleWrapperScripts))
+	s.logger.TDonef("%d Gradle wrapper script(s) found", len(gradleWrapperScripts))
 	if len(gradleWrapperScripts) == 0 {
 		return false, nil
 	}
 	gradleWrapperScript := gradleWrapperScripts[0]
 
-	log.TInfof("Scanning project with Gradle wrapper script: %s", gradleWrapperScript.AbsPath)
+	s.logger.TInfof("Scanning project with Gradle wrapper script: %s", gradleWrapperScript.AbsPath)
 
 	projectRootDir := gradleWrapperScript.Parent()
 	if projectRootDir == nil {
@@ -116,11 +122,11 @@ func (s *Scanner) DetectPlatform(searchDir string) (bool, error) {
 		return false, err
 	}
 	if gradleProject == nil {
-		log.TWarnf("No Gradle project found in %s", projectRootDir.AbsPath)
+		s.logger.TWarnf("No Gradle project found in %s", projectRootDir.AbsPath)
 		return false, nil
 	}
 
-	kmpProject, err := kmp.ScanProject(*gradleProject)
+	kmpProject, err := kmp.ScanProject(s.logger, *gradleProject)
 	if err != nil {
 		return false, fmt.Errorf("failed to scan Kotlin Multiplatform project: %w", err)
 	}
@@ -129,13 +135,22 @@ func (s *Scanner) DetectPlatform(searchDir string) (bool, error) {
 		return false, nil
 	}
 
-	printKMPProject(*kmpProject)
+	printKMPProject(s.logger, *kmpProject)
 
 	s.kmpProject = kmpProject
 
 	return true, nil
 }
 
+func (s *Scanner) SetFileIndex(index *fileindex.Index) {
+	s.fileIndex = index
+}
+
+// SetLogger ...
+func (s *Scanner) SetLogger(logger *logger.Logger) {
+	s.logger = logger
+}
+
 func (s *Scanner) ExcludedScannerNames() []string {
 	return []string{
 		android.ScannerName,
@@ -154,6 +169,7 @@ func (s *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, e
 		gradleProjectRootDirOption.AddOption(s.kmpProject.GradleProject.RootDirEntry.RelPath, moduleOption)
 
 		variantOption := models.NewOption(variantInputTitle, variantInputSummary, android.VariantInputEnvKey, models.TypeOptionalUserInput)
+		android.SetupVariantOption(variantOption)
 		moduleOption.AddOption(s.kmpProject.AndroidAppDetectResult.Modules[0].ModulePath, variantOption)
 
 		nextOption = *variantOption
@@ -189,83 +205,67 @@ func (s *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, e
 
 func (s *Scanner) DefaultOptions() models.OptionNode {
 	gradleProjectRootDirOption := models.NewOption(gradleProjectRootDirInputTitle, gradleProjectRootDirInputSummary, gradleProjectRootDirInputEnvKey, models.TypeUserInput)
-	hasAndroidAppTarget := models.NewOption(hasAndroidApplicationInputTitle, HasAndroidApplicationInputSummary, "", models.TypeSelector)
-	gradleProjectRootDirOption.AddOption(models.UserInputOptionDefaultValue, hasAndroidAppTarget)
+	applicationTargetsOption := models.NewOption(applicationTargetsInputTitle, applicationTargetsInputSummary, "", models.TypeMultiSelector)
+	gradleProjectRootDirOption.AddOption(models.UserInputOptionDefaultValue, applicationTargetsOption)
 
-	// Has Android app target
-	{
-		moduleOption := models.NewOption(moduleInputTitle, moduleInputSummary, android.ModuleInputEnvKey, models.TypeUserInput)
-		hasAndroidAppTarget.AddOption(optionValueYes, moduleOption)
-
-		variantOption := models.NewOption(variantInputTitle, variantInputSummary, android.VariantInputEnvKey, models.TypeOptionalUserInput)
-		moduleOption.AddOption("", variantOption)
-
-		hasIosAppTarget := models.NewOption(hasIOSApplicationInputTitle, hasIOSApplicationInputSummary, "", models.TypeSelector)
-		variantOption.AddOption("", hasIosAppTarget)
+	// No application target
+	applicationTargetsOption.AddConfig(models.MultiSelectKey(), models.NewConfigOption(defaultConfigName, nil))
 
-		// Has iOS app target
-		{
-			projectPathOption := models.NewOption(projectPathInputTitle, projectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeUserInput)
-			hasIosAppTarget.AddOption(optionValueYes, projectPathOption)
-
-			schemeOption := models.NewOption(schemeInputTitle, schemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
-			projectPathOption.AddOption("", schemeOption)
-
-			exportMethodOption := models.NewOption(distributionMethodInputTitle, distributionMethodInputSummary, ios.DistributionMethodEnvKey, models.TypeSelector)
-			schemeOption.AddOption("", exportMethodOption)
+	// Android application target
+	variantOption := addDefaultAndroidApplicationOptions(applicationTargetsOption, models.MultiSelectKey(applicationTargetAndroid))
+	variantOption.AddConfig("", models.NewConfigOption(defaultConfigNameWithAndroidApplication, nil))
 
-			for _, exportMethod := range ios.IosExportMethods {
-				configOption := models.NewConfigOption(defaultConfigNameWithAndroidAndIOSApplication, nil)
-				exportMethodOption.AddConfig(exportMethod, configOption)
-			}
-		}
+	// iOS application target
+	exportMethodOption := addDefaultIOSApplicationOptions(applicationTargetsOption, models.MultiSelectKey(applicationTargetIOS))
+	for _, exportMethod := range ios.IosExportMethods {
+		exportMethodOption.AddConfig(exportMethod, models.NewConfigOption(defaultConfigNameWithIOSApplication, nil))
+	}
 
-		// Has no iOS app target
-		{
-			configOption := models.NewConfigOption(defaultConfigNameWithAndroidApplication, nil)
-			hasIosAppTarget.AddConfig(optionValueNo, configOption)
-		}
+	// Android and iOS application targets
+	variantOption = addDefaultAndroidApplicationOptions(applicationTargetsOption, models.MultiSelectKey(applicationTargetAndroid, applicationTargetIOS))
+	exportMethodOption = addDefaultIOSApplicationOptions(variantOption, "")
+	for _, exportMethod := range ios.IosExportMethods {
+		exportMethodOption.AddConfig(exportMethod, models.NewConfigOption(defaultConfigNameWithAndroidAndIOSApplication, nil))
 	}
 
-	// Has no Android app target
-	{
-		hasIosAppTarget := models.NewOption(hasIOSApplicationInputTitle, hasIOSApplicationInputSummary, "", models.TypeSelector)
-		hasAndroidAppTarget.AddOption(optionValueNo, hasIosAppTarget)
+	return *gradleProjectRootDirOption
+}
 
-		// Has iOS app target
-		{
-			projectPathOption := models.NewOption(projectPathInputTitle, projectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeUserInput)
-			hasIosAppTarget.AddOption(optionValueYes, projectPathOption)
+// addDefaultAndroidApplicationOptions adds the Android application module and variant options to the parent option, returns the variant option.
+func addDefaultAndroidApplicationOptions(parent *models.OptionNode, forValue string) *models.OptionNode {
+	moduleOption := models.NewOption(moduleInputTitle, moduleInputSummary, android.ModuleInputEnvKey, models.TypeUserInput)
+	parent.AddOption(forValue, moduleOption)
 
-			schemeOption := models.NewOption(schemeInputTitle, schemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
-			projectPathOption.AddOption("", schemeOption)
+	variantOption := models.NewOption(variantInputTitle, variantInputSummary, android.VariantInputEnvKey, models.TypeOptionalUserInput)
+	android.SetupVariantOption(variantOption)
+	moduleOption.AddOption("", variantOption)
 
-			exportMethodOption := models.NewOption(distributionMethodInputTitle, distributionMethodInputSummary, ios.DistributionMethodEnvKey, models.TypeSelector)
-			schemeOption.AddOption("", exportMethodOption)
+	return variantOption
+}
 
-			for _, exportMethod := range ios.IosExportMethods {
-				configOption := models.NewConfigOption(defaultConfigNameWithIOSApplication, nil)
-				exportMethodOption.AddConfig(exportMethod, configOption)
-			}
-		}
+// addDefaultIOSApplicationOptions adds the iOS application project, scheme and distribution method options to the parent option,
+// returns the distribution method option.
+func addDefaultIOSApplicationOptions(parent *models.OptionNode, forValue string) *models.OptionNode {
+	projectPathOption := models.NewOption(projectPathInputTitle, projectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeUserInput)
+	parent.AddOption(forValue, projectPathOption)
 
-		// Has no iOS app target
-		{
-			configOption := models.NewConfigOption(defaultConfigName, nil)
-			hasIosAppTarget.AddConfig(optionValueNo, configOption)
-		}
-	}
+	schemeOption := models.NewOption(schemeInputTitle, schemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
+	projectPathOption.AddOption("", schemeOption)
 
-	return *gradleProjectRootDirOption
+	exportMethodOption := models.NewOption(distributionMethodInputTitle, distributionMethodInputSummary, ios.DistributionMethodEnvKey, models.TypeSelector)
+	schemeOption.AddOption("", exportMethodOption)
+
+	return exportMethodOption
 }
 
 func (s *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
 	bitriseDataMap := models.BitriseConfigMap{}
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 
 	// Test workflow
 	{
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
 		configBuilder.AppendStepListItemsTo(testWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: sshKeyActivation,
 		})...)
@@ -287,6 +287,7 @@ func (s *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.Bitr
 	// Android build workflow
 	if s.kmpProject.AndroidAppDetectResult != nil {
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(androidBuildWorkflowID, androidBuildWorkflowReason)
 		configBuilder.AppendStepListItemsTo(androidBuildWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: sshKeyActivation,
 		})...)
@@ -313,6 +314,7 @@ func (s *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.Bitr
 	// iOS build workflow
 	if s.kmpProject.IOSAppDetectResult != nil {
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(iosBuildWorkflowID, iosBuildWorkflowReason)
 		configBuilder.AppendStepListItemsTo(iosBuildWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: sshKeyActivation,
 		})...)
@@ -364,17 +366,25 @@ func (s *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.Bitr
 		configBuilder.SetGraphPipelineWorkflowTo(buildPipelineID, iosBuildWorkflowID, bitriseModels.GraphPipelineWorkflowModel{})
 	}
 
-	config, err := configBuilder.Generate(projectType)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
+	// Triggers
+	configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+	switch {
+	case s.kmpProject.AndroidAppDetectResult != nil && s.kmpProject.IOSAppDetectResult != nil:
+		configBuilder.AddPushTriggerToPipeline(buildPipelineID)
+	case s.kmpProject.AndroidAppDetectResult != nil:
+		configBuilder.AddPushTriggerToWorkflow(androidBuildWorkflowID)
+	case s.kmpProject.IOSAppDetectResult != nil:
+		configBuilder.AddPushTriggerToWorkflow(iosBuildWorkflowID)
+	default:
+		configBuilder.AddPushTriggerToWorkflow(testWorkflowID)
 	}
 
-	data, err := yaml.Marshal(config)
+	config, err := configBuilder.Generate(projectType)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
-	bitriseDataMap[configName] = string(data)
+	bitriseDataMap[configName] = config
 
 	return bitriseDataMap, nil
 }
@@ -384,12 +394,13 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 
 	// No Android and no iOS config
 	{
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := steps.NewConfigBuilder()
 
 		//
 		// Test workflow
 
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
 		configBuilder.AppendStepListItemsTo(testWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: models.SSHKeyActivationConditional,
 		})...)
@@ -407,27 +418,27 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		// Deploy step
 		configBuilder.AppendStepListItemsTo(testWorkflowID, steps.DefaultDeployStepList()...)
 
-		config, err := configBuilder.Generate(projectType)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		// Triggers
+		configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(testWorkflowID)
 
-		data, err := yaml.Marshal(config)
+		config, err := configBuilder.Generate(projectType)
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
 
-		bitriseDataMap[defaultConfigName] = string(data)
+		bitriseDataMap[defaultConfigName] = config
 	}
 
 	// Android and no iOS config
 	{
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := steps.NewConfigBuilder()
 
 		//
 		// Test workflow
 
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
 		configBuilder.AppendStepListItemsTo(testWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: models.SSHKeyActivationConditional,
 		})...)
@@ -449,6 +460,7 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		// Android build workflow
 
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(androidBuildWorkflowID, androidBuildWorkflowReason)
 		configBuilder.AppendStepListItemsTo(androidBuildWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: models.SSHKeyActivationConditional,
 		})...)
@@ -471,27 +483,27 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		// Deploy step
 		configBuilder.AppendStepListItemsTo(androidBuildWorkflowID, steps.DefaultDeployStepList()...)
 
-		config, err := configBuilder.Generate(projectType)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		// Triggers
+		configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(androidBuildWorkflowID)
 
-		data, err := yaml.Marshal(config)
+		config, err := configBuilder.Generate(projectType)
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
 
-		bitriseDataMap[defaultConfigNameWithAndroidApplication] = string(data)
+		bitriseDataMap[defaultConfigNameWithAndroidApplication] = config
 	}
 
 	// iOS and no Android config
 	{
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := steps.NewConfigBuilder()
 
 		//
 		// Test workflow
 
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
 		configBuilder.AppendStepListItemsTo(testWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: models.SSHKeyActivationConditional,
 		})...)
@@ -513,6 +525,7 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		// iOS build workflow
 
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(iosBuildWorkflowID, iosBuildWorkflowReason)
 		configBuilder.AppendStepListItemsTo(iosBuildWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: models.SSHKeyActivationConditional,
 		})...)
@@ -536,27 +549,27 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		// Deploy step
 		configBuilder.AppendStepListItemsTo(iosBuildWorkflowID, steps.DefaultDeployStepList()...)
 
-		config, err := configBuilder.Generate(projectType)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		// Triggers
+		configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(iosBuildWorkflowID)
 
-		data, err := yaml.Marshal(config)
+		config, err := configBuilder.Generate(projectType)
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
 
-		bitriseDataMap[defaultConfigNameWithIOSApplication] = string(data)
+		bitriseDataMap[defaultConfigNameWithIOSApplication] = config
 	}
 
 	// Android and iOS config
 	{
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := steps.NewConfigBuilder()
 
 		//
 		// Test workflow
 
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(testWorkflowID, testWorkflowReason)
 		configBuilder.AppendStepListItemsTo(testWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: models.SSHKeyActivationConditional,
 		})...)
@@ -578,6 +591,7 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		// Android build workflow
 
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(androidBuildWorkflowID, androidBuildWorkflowReason)
 		configBuilder.AppendStepListItemsTo(androidBuildWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: models.SSHKeyActivationConditional,
 		})...)
@@ -604,6 +618,7 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		// iOS build workflow
 
 		// Repository clone steps
+		configBuilder.SetWorkflowReasonTo(iosBuildWorkflowID, iosBuildWorkflowReason)
 		configBuilder.AppendStepListItemsTo(iosBuildWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: models.SSHKeyActivationConditional,
 		})...)
@@ -632,45 +647,44 @@ func (s *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 		configBuilder.SetGraphPipelineWorkflowTo(buildPipelineID, androidBuildWorkflowID, bitriseModels.GraphPipelineWorkflowModel{})
 		configBuilder.SetGraphPipelineWorkflowTo(buildPipelineID, iosBuildWorkflowID, bitriseModels.GraphPipelineWorkflowModel{})
 
-		config, err := configBuilder.Generate(projectType)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		// Triggers
+		configBuilder.AddPullRequestTriggerToWorkflow(testWorkflowID)
+		configBuilder.AddPushTriggerToPipeline(buildPipelineID)
 
-		data, err := yaml.Marshal(config)
+		config, err := configBuilder.Generate(projectType)
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
 
-		bitriseDataMap[defaultConfigNameWithAndroidAndIOSApplication] = string(data)
+		bitriseDataMap[defaultConfigNameWithAndroidAndIOSApplication] = config
 	}
 
 	return bitriseDataMap, nil
 }
 
-func printKMPProject(kmpProject kmp.Project) {
-	log.TPrintf("Project root dir: %s", kmpProject.GradleProject.RootDirEntry.RelPath)
-	log.TPrintf("Gradle wrapper script: %s", kmpProject.GradleProject.GradlewFileEntry.RelPath)
+func printKMPProject(logger *logger.Logger, kmpProject kmp.Project) {
+	logger.TPrintf("Project root dir: %s", kmpProject.GradleProject.RootDirEntry.RelPath)
+	logger.TPrintf("Gradle wrapper script: %s", kmpProject.GradleProject.GradlewFileEntry.RelPath)
 	if kmpProject.GradleProject.ConfigDirEntry != nil {
-		log.TPrintf("Gradle config dir: %s", kmpProject.GradleProject.ConfigDirEntry.RelPath)
+		logger.TPrintf("Gradle config dir: %s", kmpProject.GradleProject.ConfigDirEntry.RelPath)
 	}
 	if kmpProject.GradleProject.VersionCatalogFileEntry != nil {
-		log.TPrintf("Version catalog file: %s", kmpProject.GradleProject.VersionCatalogFileEntry.RelPath)
+		logger.TPrintf("Version catalog file: %s", kmpProject.GradleProject.VersionCatalogFileEntry.RelPath)
 	}
 	if kmpProject.GradleProject.SettingsGradleFileEntry != nil {
-		log.TPrintf("Gradle settings file: %s", kmpProject.GradleProject.SettingsGradleFileEntry.RelPath)
+		logger.TPrintf("Gradle settings file: %s", kmpProject.GradleProject.SettingsGradleFileEntry.RelPath)
 	}
 	if len(kmpProject.GradleProject.IncludedProjects) > 0 {
-		log.TPrintf("Included projects:")
+		logger.TPrintf("Included projects:")
 		for _, includedProject := range kmpProject.GradleProject.IncludedProjects {
-			log.TPrintf("- %s: %s", includedProject.Name, includedProject.BuildScriptFileEntry.RelPath)
+			logger.TPrintf("- %s: %s", includedProject.Name, includedProject.BuildScriptFileEntry.RelPath)
 		}
 	}
 
 	if kmpProject.IOSAppDetectResult != nil {
-		log.TPrintf("iOS App target: %s", kmpProject.IOSAppDetectResult.Projects[0].RelPath)
+		logger.TPrintf("iOS App target: %s", kmpProject.IOSAppDetectResult.Projects[0].RelPath)
 	}
 	if kmpProject.AndroidAppDetectResult != nil {
-		log.TPrintf("Android App target: %s", kmpProject.AndroidAppDetectResult.Modules[0].BuildScriptPth)
+		logger.TPrintf("Android App target: %s", kmpProject.AndroidAppDetectResult.Modules[0].BuildScriptPth)
 	}
 }
@@ -1,6 +1,10 @@
 package macos
 
 import (
+	"context"
+
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
 )
@@ -14,6 +18,9 @@ type Scanner struct {
 	detectResult ios.DetectResult
 
 	configDescriptors []ios.ConfigDescriptor
+
+	fileIndex *fileindex.Index
+	logger    *logger.Logger
 }
 
 // NewScanner ...
@@ -28,13 +35,18 @@ func (Scanner) Name() string {
 
 // DetectPlatform ...
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	result, err := ios.ParseProjects(ios.XcodeProjectTypeMacOS, searchDir, true, false)
+	return scanner.DetectPlatformWithContext(context.Background(), searchDir)
+}
+
+// DetectPlatformWithContext ...
+func (scanner *Scanner) DetectPlatformWithContext(ctx context.Context, searchDir string) (bool, error) {
+	result, err := ios.ParseProjectsWithContext(ctx, scanner.logger, scanner.fileIndex, ios.XcodeProjectTypeMacOS, searchDir, true, false)
 	if err != nil {
 		return false, err
 	}
 
 	if len(result.Projects) == 0 {
-		result, err = ios.ParseSPMProject(ios.XcodeProjectTypeMacOS, searchDir)
+		result, err = ios.ParseSPMProject(scanner.logger, scanner.fileIndex, ios.XcodeProjectTypeMacOS, searchDir)
 		if err != nil {
 			return false, err
 		}
@@ -45,6 +57,16 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	return detected, err
 }
 
+// SetFileIndex ...
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 // ExcludedScannerNames ...
 func (Scanner) ExcludedScannerNames() []string {
 	return []string{}
@@ -52,7 +74,16 @@ func (Scanner) ExcludedScannerNames() []string {
 
 // Options ...
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
-	options, configDescriptors, _, warnings, err := ios.GenerateOptions(ios.XcodeProjectTypeMacOS, scanner.detectResult)
+	return scanner.OptionsWithContext(context.Background())
+}
+
+// OptionsWithContext ...
+func (scanner *Scanner) OptionsWithContext(ctx context.Context) (models.OptionNode, models.Warnings, models.Icons, error) {
+	if err := ctx.Err(); err != nil {
+		return models.OptionNode{}, nil, nil, err
+	}
+
+	options, configDescriptors, _, warnings, err := ios.GenerateOptions(scanner.logger, ios.XcodeProjectTypeMacOS, scanner.detectResult)
 	if err != nil {
 		return models.OptionNode{}, warnings, nil, err
 	}
@@ -3,16 +3,20 @@ package nodejs
 import (
 	"path/filepath"
 
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 // Options
 const (
 	ScannerName = "node-js"
 
-	runTestsWorkflowID = models.WorkflowID("run_tests")
+	monorepoConfigName = "node-js-monorepo-config"
+
+	runTestsWorkflowID     = models.WorkflowID("run_tests")
+	runTestsWorkflowReason = "Installs the dependencies, then runs the lint and test scripts of the package.json."
 
 	projectDirInputTitle   = "Project Directory"
 	projectDirInputSummary = "The directory containing the package.json file"
@@ -53,7 +57,9 @@ type project struct {
 
 // Scanner implements the Scanner interface for Node.js projects
 type Scanner struct {
-	projects []project
+	projects  []project
+	fileIndex *fileindex.Index
+	logger    *logger.Logger
 }
 
 // NewScanner creates a new scanner instance.
@@ -68,31 +74,31 @@ func (scanner *Scanner) Name() string {
 
 // DetectPlatform checks if the given search directory contains a Node.js project
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	pkgJsonPaths, err := utility.CollectPackageJSONFiles(searchDir)
+	pkgJsonPaths, err := utility.CollectPackageJSONFiles(scanner.fileIndex, searchDir)
 	if err != nil {
-		log.TWarnf("%s", err)
-		log.TPrintf("Platform not detected")
+		scanner.logger.TWarnf("%s", err)
+		scanner.logger.TPrintf("Platform not detected")
 		return false, nil
 	}
 
 	for _, packageJsonPath := range pkgJsonPaths {
-		log.TPrintf("Checking: %s", packageJsonPath)
+		scanner.logger.TPrintf("Checking: %s", packageJsonPath)
 
 		// determine workdir
 		pkgJsonDir := filepath.Dir(packageJsonPath)
 
-		pkgMgr := checkPackageManager(pkgJsonDir)
-		results, err := checkPackageScripts(packageJsonPath)
+		pkgMgr := checkPackageManager(scanner.logger, scanner.fileIndex, pkgJsonDir)
+		results, err := checkPackageScripts(scanner.logger, scanner.fileIndex, packageJsonPath)
 		if err != nil {
-			log.TWarnf("Failed to check package scripts: %s", err)
+			scanner.logger.TWarnf("Failed to check package scripts: %s", err)
 			continue
 		}
-		framework := detectFramework(packageJsonPath)
-		nodeVersion := detectNodeVersion(pkgJsonDir, packageJsonPath)
+		framework := detectFramework(scanner.logger, scanner.fileIndex, packageJsonPath)
+		nodeVersion := detectNodeVersion(scanner.logger, scanner.fileIndex, pkgJsonDir, packageJsonPath)
 
 		projectRelDir, err := utility.RelPath(searchDir, pkgJsonDir)
 		if err != nil {
-			log.TWarnf("failed to get relative package.json dir path: %s", err)
+			scanner.logger.TWarnf("failed to get relative package.json dir path: %s", err)
 			continue
 		}
 
@@ -110,14 +116,24 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	}
 
 	if len(scanner.projects) == 0 {
-		log.TPrintf("Platform not detected")
+		scanner.logger.TPrintf("Platform not detected")
 		return false, nil
 	}
 
-	log.TSuccessf("Platform detected")
+	scanner.logger.TSuccessf("Platform detected")
 	return true, nil
 }
 
+// SetFileIndex sets the shared file index used to look up the package.json files
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger sets the logger of the scan
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 func (scanner *Scanner) ExcludedScannerNames() []string {
 	return []string{}
 }
@@ -137,6 +153,7 @@ func (scanner *Scanner) DefaultOptions() models.OptionNode {
 	projectRootOption := models.NewOption(projectDirInputTitle, projectDirInputSummary, projectDirInputEnvKey, models.TypeUserInput)
 	packageManagerOption := models.NewOption(packageManagerInputTitle, packageManagerInputSummary, "", models.TypeSelector)
 	nodeVersionOption := models.NewOption(nodeVersionInputTitle, nodeVersionInputSummary, nodeVersionEnvKey, models.TypeUserInput)
+	nodeVersionOption.Validation = models.ToolVersionValidation()
 
 	projectRootOption.AddOption(models.UserInputOptionDefaultValue, nodeVersionOption)
 	nodeVersionOption.AddOption(models.UserInputOptionDefaultValue, packageManagerOption)
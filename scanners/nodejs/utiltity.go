@@ -6,13 +6,11 @@ import (
 	"slices"
 	"strings"
 
-	"gopkg.in/yaml.v2"
-
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
 	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 type checkScriptResult struct {
@@ -20,26 +18,26 @@ type checkScriptResult struct {
 	hasLint, hasTest bool
 }
 
-func checkPackageManager(searchDir string) string {
-	log.TPrintf("Checking package manager lock files")
+func checkPackageManager(logger *logger.Logger, index *fileindex.Index, searchDir string) string {
+	logger.TPrintf("Checking package manager lock files")
 	for _, pkgMgr := range pkgManagers {
-		hasLockFile := utility.FileExists(filepath.Join(searchDir, pkgMgr.lockFile))
+		hasLockFile := index.FileExists(filepath.Join(searchDir, pkgMgr.lockFile))
 
 		if !hasLockFile {
-			log.TPrintf("- %s - not found", pkgMgr.lockFile)
+			logger.TPrintf("- %s - not found", pkgMgr.lockFile)
 			continue
 		}
 
-		log.TPrintf("- %s - found", pkgMgr.lockFile)
-		log.TPrintf("Package manager: %s", pkgMgr.name)
+		logger.TPrintf("- %s - found", pkgMgr.lockFile)
+		logger.TPrintf("Package manager: %s", pkgMgr.name)
 		return pkgMgr.name
 	}
 
 	return ""
 }
 
-func checkPackageScripts(packageJsonPath string) (checkScriptResult, error) {
-	log.TPrintf("Checking package scripts")
+func checkPackageScripts(logger *logger.Logger, index *fileindex.Index, packageJsonPath string) (checkScriptResult, error) {
+	logger.TPrintf("Checking package scripts")
 
 	result := checkScriptResult{
 		scripts: make([]string, 0),
@@ -47,28 +45,28 @@ func checkPackageScripts(packageJsonPath string) (checkScriptResult, error) {
 		hasTest: false,
 	}
 
-	packages, err := utility.ParsePackagesJSON(packageJsonPath)
+	packages, err := utility.ParsePackagesJSON(index, packageJsonPath)
 	if err != nil {
 		return result, err
 	}
 
 	for name := range packages.Scripts {
-		log.TDebugf("- %s", name)
+		logger.TDebugf("- %s", name)
 		result.scripts = append(result.scripts, name)
 	}
 
 	if slices.Contains(result.scripts, "lint") {
-		log.TPrintf("- lint - found")
+		logger.TPrintf("- lint - found")
 		result.hasLint = true
 	} else {
-		log.TPrintf("- lint - not found")
+		logger.TPrintf("- lint - not found")
 	}
 
 	if slices.Contains(result.scripts, "test") {
-		log.TPrintf("- test - found")
+		logger.TPrintf("- test - found")
 		result.hasTest = true
 	} else {
-		log.TPrintf("- test - not found")
+		logger.TPrintf("- test - not found")
 	}
 
 	return result, nil
@@ -76,12 +74,12 @@ func checkPackageScripts(packageJsonPath string) (checkScriptResult, error) {
 
 // detectFramework returns the JS framework detected from package.json dependencies.
 // Returns "nextjs", "nestjs", or "" if none is detected.
-func detectFramework(packageJsonPath string) string {
-	log.TPrintf("Checking framework")
+func detectFramework(logger *logger.Logger, index *fileindex.Index, packageJsonPath string) string {
+	logger.TPrintf("Checking framework")
 
-	packages, err := utility.ParsePackagesJSON(packageJsonPath)
+	packages, err := utility.ParsePackagesJSON(index, packageJsonPath)
 	if err != nil {
-		log.TPrintf("- framework - failed to parse package.json: %s", err)
+		logger.TPrintf("- framework - failed to parse package.json: %s", err)
 		return ""
 	}
 
@@ -94,66 +92,66 @@ func detectFramework(packageJsonPath string) string {
 	}
 
 	if _, ok := allDeps["next"]; ok {
-		log.TPrintf("- framework: nextjs")
+		logger.TPrintf("- framework: nextjs")
 		return "nextjs"
 	}
 	if _, ok := allDeps["@nestjs/core"]; ok {
-		log.TPrintf("- framework: nestjs")
+		logger.TPrintf("- framework: nestjs")
 		return "nestjs"
 	}
 
-	log.TPrintf("- framework - not detected")
+	logger.TPrintf("- framework - not detected")
 	return ""
 }
 
 // detectNodeVersion returns the Node.js version declared in version files or package.json engines.
 // Sources checked in order: .nvmrc, .node-version, .tool-versions, engines.node in package.json.
 // Returns an empty string if no version is found.
-func detectNodeVersion(projectDir, packageJsonPath string) string {
-	log.TPrintf("Checking Node.js version")
+func detectNodeVersion(logger *logger.Logger, index *fileindex.Index, projectDir, packageJsonPath string) string {
+	logger.TPrintf("Checking Node.js version")
 
 	// .nvmrc — single line containing the version (e.g. "22" or "22.14.0")
-	if content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, ".nvmrc")); err == nil {
+	if content, err := index.ReadStringFromFile(filepath.Join(projectDir, ".nvmrc")); err == nil {
 		version := strings.TrimSpace(content)
 		if version != "" {
-			log.TPrintf("- .nvmrc - found (%s)", version)
+			logger.TPrintf("- .nvmrc - found (%s)", version)
 			return version
 		}
 	}
 
 	// .node-version — same format as .nvmrc
-	if content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, ".node-version")); err == nil {
+	if content, err := index.ReadStringFromFile(filepath.Join(projectDir, ".node-version")); err == nil {
 		version := strings.TrimSpace(content)
 		if version != "" {
-			log.TPrintf("- .node-version - found (%s)", version)
+			logger.TPrintf("- .node-version - found (%s)", version)
 			return version
 		}
 	}
 
 	// .tool-versions — asdf/mise format: "nodejs <version>"
-	if content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, ".tool-versions")); err == nil {
+	if content, err := index.ReadStringFromFile(filepath.Join(projectDir, ".tool-versions")); err == nil {
 		for _, line := range strings.Split(content, "\n") {
 			fields := strings.Fields(line)
 			if len(fields) >= 2 && fields[0] == "nodejs" {
-				log.TPrintf("- .tool-versions - found nodejs %s", fields[1])
+				logger.TPrintf("- .tool-versions - found nodejs %s", fields[1])
 				return fields[1]
 			}
 		}
 	}
 
 	// engines.node in package.json — semver range, e.g. ">=22.0.0"
-	packages, err := utility.ParsePackagesJSON(packageJsonPath)
+	packages, err := utility.ParsePackagesJSON(index, packageJsonPath)
 	if err == nil {
 		if constraint, ok := packages.Engines["node"]; ok && constraint != "" {
 			version := parseEnginesNodeVersion(constraint)
 			if version != "" {
-				log.TPrintf("- engines.node - found (%s → %s)", constraint, version)
+				logger.TPrintf("- engines.node - found (%s → %s)", constraint, version)
 				return version
 			}
 		}
 	}
 
-	log.TPrintf("- Node.js version - not found")
+	logger.TPrintf("- Node.js version - not found")
 	return ""
 }
 
@@ -250,6 +248,9 @@ func generateOptions(projects []project) (models.OptionNode, models.Warnings, mo
 		options := generateProjectOption(project)
 		projectRootOption.AddOption(project.projectRelDir, &options)
 	}
+	if len(projects) > 1 {
+		return *models.NewMonorepoOption(projectRootOption, monorepoConfigName), nil, nil, nil
+	}
 
 	return *projectRootOption, nil, nil, nil
 }
@@ -300,56 +301,98 @@ func generateConfigs(projects []project, sshKeyActivation models.SSHKeyActivatio
 		}
 	}
 
+	if len(projects) > 1 {
+		config, err := generateMonorepoConfig(projects, sshKeyActivation)
+		if err != nil {
+			return nil, err
+		}
+		configs[monorepoConfigName] = config
+	}
+
 	return configs, nil
 }
 
-func generateConfigBasedOn(descriptor configDescriptor, sshKey models.SSHKeyActivation) (string, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func generateConfigBasedOn(descriptor configDescriptor, sshKey models.SSHKeyActivation) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
 	if descriptor.nodeVersion != "" {
 		configBuilder.AddTool("node", descriptor.nodeVersion)
 	}
 
 	prepareSteps := steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKey})
+	configBuilder.SetWorkflowReasonTo(runTestsWorkflowID, runTestsWorkflowReason)
 	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, prepareSteps...)
 
 	if descriptor.isDefault {
 		configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem("Install Node.js", nodeVersionInstallScriptContent))
 	}
 
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.RestoreNPMCache())
+	appendRunTestsSteps(configBuilder, runTestsWorkflowID, descriptor)
+
+	configBuilder.AddPullRequestTriggerToWorkflow(runTestsWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(runTestsWorkflowID)
+
+	config, err := configBuilder.Generate(ScannerName)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	return config, nil
+}
+
+// appendRunTestsSteps appends the steps installing the dependencies and running the scripts of the project to the workflow.
+func appendRunTestsSteps(configBuilder *models.ConfigBuilderModel, workflow models.WorkflowID, descriptor configDescriptor) {
+	configBuilder.AppendStepListItemsTo(workflow, steps.RestoreNPMCache())
 
 	switch descriptor.pkgManager {
 	case "yarn":
-		configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.YarnStepListItem("install", descriptor.workdir))
+		configBuilder.AppendStepListItemsTo(workflow, steps.YarnStepListItem("install", descriptor.workdir))
 		if descriptor.hasLint {
-			configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.YarnStepListItem("run lint", descriptor.workdir))
+			configBuilder.AppendStepListItemsTo(workflow, steps.YarnStepListItem("run lint", descriptor.workdir))
 		}
 		if descriptor.hasTest {
-			configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.YarnStepListItem("run test", descriptor.workdir))
+			configBuilder.AppendStepListItemsTo(workflow, steps.YarnStepListItem("run test", descriptor.workdir))
 		}
 	case "npm":
 		fallthrough
 	default:
-		configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.NpmStepListItem("install", descriptor.workdir))
+		configBuilder.AppendStepListItemsTo(workflow, steps.NpmStepListItem("install", descriptor.workdir))
 		if descriptor.hasLint {
-			configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.NpmStepListItem("run lint", descriptor.workdir))
+			configBuilder.AppendStepListItemsTo(workflow, steps.NpmStepListItem("run lint", descriptor.workdir))
 		}
 		if descriptor.hasTest {
-			configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.NpmStepListItem("run test", descriptor.workdir))
+			configBuilder.AppendStepListItemsTo(workflow, steps.NpmStepListItem("run test", descriptor.workdir))
 		}
 	}
 
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.SaveNPMCache())
+	configBuilder.AppendStepListItemsTo(workflow, steps.SaveNPMCache())
+}
 
-	config, err := configBuilder.Generate(ScannerName)
-	if err != nil {
-		return "", err
-	}
+// generateMonorepoConfig generates a config with a workflow for every project,
+// triggered by the changes of the project's files.
+func generateMonorepoConfig(projects []project, sshKey models.SSHKeyActivation) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return "", err
+	configBuilder.SetWorkflowReasonTo(models.SetupWorkflowID, models.SetupWorkflowReason)
+	configBuilder.AppendStepListItemsTo(models.SetupWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKey})...)
+
+	for _, project := range projects {
+		descriptor := createConfigDescriptor(project, false)
+		if descriptor.workdir != "" {
+			descriptor.workdir = project.projectRelDir
+		}
+
+		workflow := models.ProjectWorkflowID(runTestsWorkflowID, project.projectRelDir)
+		configBuilder.SetWorkflowReasonTo(workflow, runTestsWorkflowReason)
+		configBuilder.SetWorkflowBeforeRunTo(workflow, models.SetupWorkflowID)
+		if descriptor.nodeVersion != "" {
+			configBuilder.AddWorkflowToolTo(workflow, "node", descriptor.nodeVersion)
+		}
+		appendRunTestsSteps(configBuilder, workflow, descriptor)
+
+		changedFiles := models.ProjectChangedFiles(project.projectRelDir)
+		configBuilder.AddChangedFilesPullRequestTriggerTo(workflow, changedFiles)
+		configBuilder.AddChangedFilesPushTriggerTo(workflow, changedFiles)
 	}
 
-	return string(data), nil
+	return configBuilder.Generate(ScannerName)
 }
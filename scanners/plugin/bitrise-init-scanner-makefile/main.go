@@ -0,0 +1,133 @@
+// Command bitrise-init-scanner-makefile is the reference scanner plugin:
+// it detects a Makefile in the search dir and generates a workflow running one of its targets.
+//
+// Build it into one of the plugin directories (see the plugin package) to use it:
+//
+//	go build -o "$BITRISE_INIT_PLUGIN_PATH/bitrise-init-scanner-makefile" ./scanners/plugin/bitrise-init-scanner-makefile
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/scanners"
+	"github.com/bitrise-io/bitrise-init/scanners/plugin"
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+const (
+	scannerName = "makefile"
+	configName  = "makefile-config"
+
+	runWorkflowID = models.WorkflowID("run_make")
+
+	targetInputTitle   = "Make target"
+	targetInputSummary = "The Makefile target to run"
+	targetInputEnvKey  = "MAKE_TARGET"
+
+	defaultTarget = "all"
+
+	runMakeScriptContent = `#!/usr/bin/env bash
+set -euxo pipefail
+
+make "$MAKE_TARGET"
+`
+)
+
+var targetRegexp = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_.-]*)\s*:([^=]|$)`)
+
+type scanner struct {
+	targets []string
+}
+
+func (s *scanner) Name() string {
+	return scannerName
+}
+
+func (s *scanner) DetectPlatform(searchDir string) (bool, error) {
+	makefile, err := os.Open(filepath.Join(searchDir, "Makefile"))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := makefile.Close(); err != nil {
+			log.TWarnf("Failed to close Makefile: %s", err)
+		}
+	}()
+
+	fileScanner := bufio.NewScanner(makefile)
+	for fileScanner.Scan() {
+		if match := targetRegexp.FindStringSubmatch(fileScanner.Text()); match != nil {
+			s.targets = append(s.targets, match[1])
+		}
+	}
+	if err := fileScanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read Makefile: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *scanner) ExcludedScannerNames() []string {
+	return nil
+}
+
+func (s *scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	targets := s.targets
+	if len(targets) == 0 {
+		targets = []string{defaultTarget}
+	}
+
+	targetOption := models.NewOption(targetInputTitle, targetInputSummary, targetInputEnvKey, models.TypeOptionalSelector)
+	for _, target := range targets {
+		targetOption.AddConfig(target, models.NewConfigOption(configName, nil))
+	}
+	return *targetOption, nil, nil, nil
+}
+
+func (s *scanner) DefaultOptions() models.OptionNode {
+	targetOption := models.NewOption(targetInputTitle, targetInputSummary, targetInputEnvKey, models.TypeUserInput)
+	targetOption.AddConfig(models.UserInputOptionDefaultValue, models.NewConfigOption(configName, nil))
+	return *targetOption
+}
+
+func (s *scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
+	config, err := generateConfig(sshKeyActivation)
+	if err != nil {
+		return nil, err
+	}
+	return models.BitriseConfigMap{configName: config}, nil
+}
+
+func (s *scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
+	return s.Configs(models.SSHKeyActivationConditional)
+}
+
+func generateConfig(sshKeyActivation models.SSHKeyActivation) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
+	configBuilder.AppendStepListItemsTo(runWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKeyActivation})...)
+	configBuilder.AppendStepListItemsTo(runWorkflowID, steps.ScriptStepListItem("Run make", runMakeScriptContent))
+	configBuilder.AppendStepListItemsTo(runWorkflowID, steps.DefaultDeployStepList()...)
+
+	config, err := configBuilder.Generate(scannerName)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	return config, nil
+}
+
+func main() {
+	newScanner := func() scanners.ScannerInterface { return &scanner{} }
+	if err := plugin.Serve(newScanner, plugin.ServeOptions{Type: scanners.ProjectScannerType}); err != nil {
+		log.Errorf("%s", err)
+		os.Exit(1)
+	}
+}
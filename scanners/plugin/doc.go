@@ -0,0 +1,71 @@
+// Package plugin runs scanners implemented as standalone executables (scanner plugins).
+//
+// # Discovery
+//
+// Scanner plugins are executables named with the bitrise-init-scanner- prefix (for example bitrise-init-scanner-makefile).
+// Discover lists them in the given directories, the directories can also be set in the BITRISE_INIT_PLUGIN_PATH
+// environment variable, separated by the OS path list separator.
+// RegisterPlugins loads the discovered plugins and registers them in the scanner registry (see scanners.Register),
+// after that they run together with the built-in scanners and their outputs are merged into the scan result.
+// Loading a plugin calls its info and default_options methods, a plugin failing either of them is rejected.
+//
+// # Protocol
+//
+// Every call of the scanner interface starts the plugin executable once:
+// bitrise-init writes a single JSON request to the plugin's stdin and reads a single JSON response from its stdout.
+// The plugin can log to stderr, its logs are printed with the scanner's logs. stdout is reserved for the response.
+// A call fails if the plugin does not respond within the plugin's timeout, exits with a non-zero exit code,
+// or the response does not match the protocol (unknown fields are rejected).
+//
+// The request:
+//
+//	{
+//	  "protocol_version": 1,
+//	  "method": "detect",
+//	  "search_dir": "/absolute/path/to/the/project",
+//	  "ssh_key_activation": "none",
+//	  "detected_project_types": ["ios"]
+//	}
+//
+// Request fields:
+//   - protocol_version: the current protocol version is 1.
+//   - method: one of info, detect, options, configs, default_options and default_configs.
+//   - search_dir: set for the detect, options and configs methods.
+//   - ssh_key_activation: set for the configs method, one of none, mandatory and conditional.
+//   - detected_project_types: set for automation tool scanner plugins, the project types detected by the project scanners.
+//
+// The plugin processes do not share state: the options and configs methods are called with the same search_dir as the
+// detect method, so plugins need to repeat the detection (or cache its result) before generating the options and configs.
+//
+// The response:
+//
+//	{
+//	  "protocol_version": 1,
+//	  "error": "",
+//	  "name": "makefile",
+//	  "type": "project",
+//	  "priority": 50,
+//	  "excluded_scanners": [],
+//	  "detected": true,
+//	  "options": {"title": "...", "type": "selector", "value_map": {"...": {"config": "makefile-config"}}},
+//	  "warnings": [],
+//	  "icons": [],
+//	  "configs": {"makefile-config": "format_version: \"13\"\n..."}
+//	}
+//
+// Response fields:
+//   - protocol_version: has to match the request's protocol_version.
+//   - error: the method failed, the other fields are ignored. Errors returned by detect are reported as warnings, like for the built-in scanners.
+//   - name, type, priority and excluded_scanners: the info method's result. name is required,
+//     type is project (default) or automation_tool, see scanners.Registration for the priority.
+//   - detected: the detect method's result.
+//   - options, warnings and icons: the options and default_options methods' result,
+//     options is an OptionNode tree and every leaf has to be a config option.
+//     A multi_selector option lists its selectable values in values, its value_map keys are the sorted, comma separated selections (see models.MultiSelectKey).
+//     An option can offer a default_value and describe its values in value_infos (recommended, reason, detected_from).
+//     A user input can restrict the values typed in with validation (pattern, allowed_values, semver_constraint, hint).
+//   - configs: the configs and default_configs methods' result, a BitriseConfigMap with bitrise.yml contents.
+//     Every config referenced by the options has to be present.
+//
+// Serve implements the plugin side of the protocol for scanners written in Go.
+package plugin
@@ -0,0 +1,342 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitrise-io/bitrise-init/logger"
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanners"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+const (
+	// ExecutablePrefix is the file name prefix of the scanner plugin executables.
+	ExecutablePrefix = "bitrise-init-scanner-"
+	// PathEnvKey is the environment variable listing the plugin directories.
+	PathEnvKey = "BITRISE_INIT_PLUGIN_PATH"
+	// DefaultTimeout limits a single call of a plugin.
+	DefaultTimeout = 2 * time.Minute
+)
+
+// maxStderrLength limits the length of the plugin's stderr included in the errors.
+const maxStderrLength = 2048
+
+// Plugin is a loaded scanner plugin.
+type Plugin struct {
+	Path string
+	// Args are passed to the plugin executable.
+	Args []string
+	// Timeout limits a single call of the plugin, DefaultTimeout is used if it is 0.
+	Timeout time.Duration
+	// Info is the plugin's response to the info request.
+	Info Response
+	// DefaultOptions is the plugin's response to the default_options request.
+	DefaultOptions models.OptionNode
+}
+
+// Discover returns the scanner plugin executables found in dirs, sorted by path.
+// Not existing directories are skipped.
+func Discover(dirs ...string) ([]string, error) {
+	var executables []string
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to list plugins in %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), ExecutablePrefix) {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			if info.Mode().Perm()&0111 == 0 {
+				log.TWarnf("Skipping plugin %s: not executable", entry.Name())
+				continue
+			}
+
+			pth, err := filepath.Abs(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			executables = append(executables, pth)
+		}
+	}
+
+	sort.Strings(executables)
+	return executables, nil
+}
+
+// DirsFromEnv returns the plugin directories set in the BITRISE_INIT_PLUGIN_PATH environment variable.
+func DirsFromEnv() []string {
+	value := os.Getenv(PathEnvKey)
+	if value == "" {
+		return nil
+	}
+	return filepath.SplitList(value)
+}
+
+// Load calls the plugin's info and default_options methods.
+// The default options are loaded upfront, as the scanners can not report the failure of DefaultOptions.
+func Load(ctx context.Context, pth string, args ...string) (*Plugin, error) {
+	plugin := &Plugin{Path: pth, Args: args}
+
+	info, err := plugin.call(ctx, Request{Method: MethodInfo}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if info.Type == "" {
+		info.Type = scanners.ProjectScannerType
+	}
+	plugin.Info = info
+
+	defaultOptions, err := plugin.call(ctx, Request{Method: MethodDefaultOptions}, nil)
+	if err != nil {
+		return nil, err
+	}
+	plugin.DefaultOptions = *defaultOptions.Options
+
+	return plugin, nil
+}
+
+// Registration returns the scanner registration of the plugin.
+func (plugin *Plugin) Registration() scanners.Registration {
+	return scanners.Registration{
+		Name:     plugin.Info.Name,
+		Type:     plugin.Info.Type,
+		Priority: plugin.Info.Priority,
+		New: func() scanners.ScannerInterface {
+			return NewScanner(plugin)
+		},
+	}
+}
+
+// RegisterPlugins loads the plugins found in dirs and registers them in the default scanner registry.
+// Returns the names of the registered scanners.
+func RegisterPlugins(ctx context.Context, dirs ...string) ([]string, error) {
+	executables, err := Discover(dirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, executable := range executables {
+		plugin, err := Load(ctx, executable)
+		if err != nil {
+			return names, fmt.Errorf("failed to load plugin %s: %w", executable, err)
+		}
+
+		if err := scanners.Register(plugin.Registration()); err != nil {
+			return names, fmt.Errorf("failed to register plugin %s: %w", executable, err)
+		}
+		names = append(names, plugin.Info.Name)
+	}
+	return names, nil
+}
+
+// call runs the plugin executable with the request and returns its validated response.
+// The method's error reported in the response is returned as an error.
+// The plugin's stderr is printed with stderrLogger.
+func (plugin *Plugin) call(ctx context.Context, request Request, stderrLogger *logger.Logger) (Response, error) {
+	timeout := plugin.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	request.ProtocolVersion = ProtocolVersion
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return Response{}, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, plugin.Path, plugin.Args...)
+	cmd.Stdin = bytes.NewReader(requestData)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Do not wait for the processes started by the plugin after it was killed.
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	if pluginLog := strings.TrimSuffix(stderr.String(), "\n"); pluginLog != "" {
+		stderrLogger.Printf("%s", pluginLog)
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); errors.Is(ctxErr, context.DeadlineExceeded) {
+			return Response{}, fmt.Errorf("%s plugin %s call timed out after %s", filepath.Base(plugin.Path), request.Method, timeout)
+		} else if ctxErr != nil {
+			return Response{}, ctxErr
+		}
+		return Response{}, fmt.Errorf("%s plugin %s call failed: %w%s", filepath.Base(plugin.Path), request.Method, err, stderrSuffix(stderr.String()))
+	}
+
+	response, err := DecodeResponse(request.Method, stdout.Bytes())
+	if err != nil {
+		return Response{}, fmt.Errorf("%s plugin %s call: %w", filepath.Base(plugin.Path), request.Method, err)
+	}
+	if response.Error != "" {
+		return Response{}, errors.New(response.Error)
+	}
+	return response, nil
+}
+
+func stderrSuffix(stderr string) string {
+	stderr = strings.TrimSpace(stderr)
+	if stderr == "" {
+		return ""
+	}
+	if len(stderr) > maxStderrLength {
+		stderr = "..." + stderr[len(stderr)-maxStderrLength:]
+	}
+	return ", stderr: " + stderr
+}
+
+// Scanner implements the scanners.ScannerInterface by calling a plugin.
+type Scanner struct {
+	plugin *Plugin
+
+	searchDir            string
+	detectedProjectTypes []string
+	configNames          []string
+	logger               *logger.Logger
+}
+
+// NewScanner ...
+func NewScanner(plugin *Plugin) *Scanner {
+	return &Scanner{plugin: plugin}
+}
+
+// Name ...
+func (scanner *Scanner) Name() string {
+	return scanner.plugin.Info.Name
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
+// SetDetectedProjectTypes ...
+func (scanner *Scanner) SetDetectedProjectTypes(projectTypes []string) {
+	scanner.detectedProjectTypes = projectTypes
+}
+
+// DetectPlatform ...
+func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
+	return scanner.DetectPlatformWithContext(context.Background(), searchDir)
+}
+
+// DetectPlatformWithContext ...
+func (scanner *Scanner) DetectPlatformWithContext(ctx context.Context, searchDir string) (bool, error) {
+	scanner.searchDir = searchDir
+
+	response, err := scanner.plugin.call(ctx, Request{
+		Method:               MethodDetect,
+		SearchDir:            searchDir,
+		DetectedProjectTypes: scanner.detectedProjectTypes,
+	}, scanner.logger)
+	if err != nil {
+		return false, err
+	}
+
+	if response.Detected {
+		scanner.logger.TSuccessf("Platform detected")
+	} else {
+		scanner.logger.TPrintf("Platform not detected")
+	}
+	return response.Detected, nil
+}
+
+// ExcludedScannerNames ...
+func (scanner *Scanner) ExcludedScannerNames() []string {
+	return scanner.plugin.Info.ExcludedScanners
+}
+
+// Options ...
+func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	return scanner.OptionsWithContext(context.Background())
+}
+
+// OptionsWithContext ...
+func (scanner *Scanner) OptionsWithContext(ctx context.Context) (models.OptionNode, models.Warnings, models.Icons, error) {
+	response, err := scanner.plugin.call(ctx, Request{
+		Method:               MethodOptions,
+		SearchDir:            scanner.searchDir,
+		DetectedProjectTypes: scanner.detectedProjectTypes,
+	}, scanner.logger)
+	if err != nil {
+		return models.OptionNode{}, nil, nil, err
+	}
+
+	// Validated by the call
+	scanner.configNames, _ = optionConfigNames(response.Options)
+
+	return *response.Options, response.Warnings, response.Icons, nil
+}
+
+// DefaultOptions returns the default options loaded by Load.
+func (scanner *Scanner) DefaultOptions() models.OptionNode {
+	return scanner.plugin.DefaultOptions
+}
+
+// Configs ...
+func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
+	response, err := scanner.plugin.call(context.Background(), Request{
+		Method:               MethodConfigs,
+		SearchDir:            scanner.searchDir,
+		SSHKeyActivation:     sshKeyActivationToString(sshKeyActivation),
+		DetectedProjectTypes: scanner.detectedProjectTypes,
+	}, scanner.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := output.DecodeConfigMap(response.Configs)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConfigsForOptions(scanner.configNames, configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// DefaultConfigs ...
+func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
+	response, err := scanner.plugin.call(context.Background(), Request{Method: MethodDefaultConfigs}, scanner.logger)
+	if err != nil {
+		return nil, err
+	}
+	return output.DecodeConfigMap(response.Configs)
+}
+
+func checkConfigsForOptions(configNames []string, configs models.BitriseConfigMap) error {
+	for _, name := range configNames {
+		if _, ok := configs[name]; !ok {
+			return fmt.Errorf("config (%s) referenced by the options not provided", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitrise-io/bitrise-init/logger"
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanner"
+	"github.com/bitrise-io/bitrise-init/scanners"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePluginEnvKey makes the test binary act as a scanner plugin, its value selects the plugin's behaviour.
+const fakePluginEnvKey = "BITRISE_INIT_FAKE_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(fakePluginEnvKey); mode != "" {
+		runFakePlugin(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+const fakeConfig = `format_version: "13"
+workflows:
+  primary:
+    steps:
+    - script@1: {}
+`
+
+func newFakeConfigMap(configName string) models.BitriseConfigMap {
+	configMap, err := output.DecodeConfigMap(map[string]string{configName: fakeConfig})
+	if err != nil {
+		panic(err)
+	}
+	return configMap
+}
+
+type fakeScanner struct {
+	configs models.BitriseConfigMap
+}
+
+func (s fakeScanner) Name() string                        { return "fake" }
+func (s fakeScanner) DetectPlatform(string) (bool, error) { return true, nil }
+func (s fakeScanner) ExcludedScannerNames() []string      { return []string{"node-js"} }
+func (s fakeScanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	option := models.NewOption("Title", "Summary", "ENV_KEY", models.TypeSelector)
+	option.AddConfig("value", models.NewConfigOption("fake-config", nil))
+	return *option, models.Warnings{"fake warning"}, nil, nil
+}
+func (s fakeScanner) DefaultOptions() models.OptionNode {
+	option, _, _, _ := s.Options()
+	return option
+}
+func (s fakeScanner) Configs(models.SSHKeyActivation) (models.BitriseConfigMap, error) {
+	return s.configs, nil
+}
+func (s fakeScanner) DefaultConfigs() (models.BitriseConfigMap, error) { return s.configs, nil }
+
+func runFakePlugin(mode string) {
+	switch mode {
+	case "valid":
+		newScanner := func() scanners.ScannerInterface {
+			return fakeScanner{configs: newFakeConfigMap("fake-config")}
+		}
+		if err := Serve(newScanner, ServeOptions{Priority: 50}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "missing-config":
+		newScanner := func() scanners.ScannerInterface {
+			return fakeScanner{configs: newFakeConfigMap("other-config")}
+		}
+		if err := Serve(newScanner, ServeOptions{}); err != nil {
+			os.Exit(1)
+		}
+	case "invalid-config":
+		fmt.Println(`{"protocol_version": 1, "configs": {"fake-config": "workflows: {}"}}`)
+	case "invalid-options":
+		fmt.Println(`{"protocol_version": 1, "options": {"title": "Title", "type": "selector", "value_map": {"value": {}}}}`)
+	case "unknown-field":
+		fmt.Println(`{"protocol_version": 1, "name": "fake", "unknown": true}`)
+	case "protocol-version":
+		fmt.Println(`{"protocol_version": 2, "name": "fake"}`)
+	case "garbage":
+		fmt.Println("not json")
+	case "method-error":
+		fmt.Println(`{"protocol_version": 1, "error": "something went wrong"}`)
+	case "default-options-error":
+		var request Request
+		if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil || request.Method != MethodInfo {
+			fmt.Println(`{"protocol_version": 1, "error": "no default options"}`)
+			return
+		}
+		fmt.Println(`{"protocol_version": 1, "name": "fake"}`)
+	case "exit":
+		fmt.Fprintln(os.Stderr, "plugin crashed")
+		os.Exit(3)
+	case "slow":
+		time.Sleep(time.Minute)
+	}
+}
+
+func newFakePlugin(t *testing.T, mode string) *Plugin {
+	t.Setenv(fakePluginEnvKey, mode)
+	return &Plugin{
+		Path: os.Args[0],
+		Info: Response{Name: "fake", Type: scanners.ProjectScannerType},
+	}
+}
+
+// writeFakePluginExecutable creates a plugin executable in dir, which runs the test binary as a fake plugin.
+func writeFakePluginExecutable(t *testing.T, dir, name string) {
+	script := fmt.Sprintf("#!/bin/sh\nexec %q \"$@\"\n", os.Args[0])
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+}
+
+func TestDiscoverAndLoad(t *testing.T) {
+	t.Setenv(fakePluginEnvKey, "valid")
+
+	dir := t.TempDir()
+	writeFakePluginExecutable(t, dir, ExecutablePrefix+"fake")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ExecutablePrefix+"not-executable"), []byte{}, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other-tool"), []byte{}, 0755))
+
+	executables, err := Discover(dir, filepath.Join(dir, "not-existing"))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, ExecutablePrefix+"fake")}, executables)
+
+	plugin, err := Load(context.Background(), executables[0])
+	require.NoError(t, err)
+	require.Equal(t, Response{
+		ProtocolVersion:  ProtocolVersion,
+		Name:             "fake",
+		Type:             scanners.ProjectScannerType,
+		Priority:         50,
+		ExcludedScanners: []string{"node-js"},
+	}, plugin.Info)
+	require.Equal(t, "Title", plugin.DefaultOptions.Title)
+
+	registry := scanners.NewRegistry()
+	require.NoError(t, registry.Register(plugin.Registration()))
+	require.Len(t, registry.Scanners(scanners.ProjectScannerType), 1)
+}
+
+func TestScanner(t *testing.T) {
+	searchDir := t.TempDir()
+	pluginScanner := NewScanner(newFakePlugin(t, "valid"))
+
+	detected, err := pluginScanner.DetectPlatformWithContext(context.Background(), searchDir)
+	require.NoError(t, err)
+	require.True(t, detected)
+
+	options, warnings, _, err := pluginScanner.Options()
+	require.NoError(t, err)
+	require.Equal(t, models.Warnings{"fake warning"}, warnings)
+	require.Equal(t, "fake-config", options.ChildOptionMap["value"].Config)
+
+	configs, err := pluginScanner.Configs(models.SSHKeyActivationNone)
+	require.NoError(t, err)
+	require.Equal(t, newFakeConfigMap("fake-config"), configs)
+
+	defaultConfigs, err := pluginScanner.DefaultConfigs()
+	require.NoError(t, err)
+	require.Equal(t, configs, defaultConfigs)
+}
+
+func TestScanner_errors(t *testing.T) {
+	tests := []struct {
+		mode    string
+		call    func(scanner *Scanner) error
+		wantErr string
+	}{
+		{
+			mode: "missing-config",
+			call: func(scanner *Scanner) error {
+				if _, _, _, err := scanner.Options(); err != nil {
+					return err
+				}
+				_, err := scanner.Configs(models.SSHKeyActivationNone)
+				return err
+			},
+			wantErr: "config (fake-config) referenced by the options not provided",
+		},
+		{
+			mode: "invalid-config",
+			call: func(scanner *Scanner) error {
+				_, err := scanner.Configs(models.SSHKeyActivationNone)
+				return err
+			},
+			wantErr: "plugin.test plugin configs call: invalid response: invalid config (fake-config): format_version not set",
+		},
+		{
+			mode: "invalid-options",
+			call: func(scanner *Scanner) error {
+				_, _, _, err := scanner.Options()
+				return err
+			},
+			wantErr: "plugin.test plugin options call: invalid response: invalid options: option (Title/value) has neither title nor config",
+		},
+		{
+			mode: "unknown-field",
+			call: func(scanner *Scanner) error {
+				_, err := scanner.DetectPlatform("")
+				return err
+			},
+			wantErr: `plugin.test plugin detect call: invalid response: json: unknown field "unknown"`,
+		},
+		{
+			mode: "protocol-version",
+			call: func(scanner *Scanner) error {
+				_, err := scanner.DetectPlatform("")
+				return err
+			},
+			wantErr: "plugin.test plugin detect call: invalid response: unsupported protocol version: 2 (expected: 1)",
+		},
+		{
+			mode: "garbage",
+			call: func(scanner *Scanner) error {
+				_, err := scanner.DetectPlatform("")
+				return err
+			},
+			wantErr: "plugin.test plugin detect call: invalid response: invalid character 'o' in literal null (expecting 'u')",
+		},
+		{
+			mode: "method-error",
+			call: func(scanner *Scanner) error {
+				_, err := scanner.DetectPlatform("")
+				return err
+			},
+			wantErr: "something went wrong",
+		},
+		{
+			mode: "exit",
+			call: func(scanner *Scanner) error {
+				_, err := scanner.DetectPlatform("")
+				return err
+			},
+			wantErr: "plugin.test plugin detect call failed: exit status 3, stderr: plugin crashed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			err := tt.call(NewScanner(newFakePlugin(t, tt.mode)))
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoad_defaultOptionsError(t *testing.T) {
+	t.Setenv(fakePluginEnvKey, "default-options-error")
+
+	_, err := Load(context.Background(), os.Args[0])
+	require.EqualError(t, err, "no default options")
+}
+
+func TestScanner_stderrLogged(t *testing.T) {
+	var logs bytes.Buffer
+	pluginScanner := NewScanner(newFakePlugin(t, "exit"))
+	pluginScanner.SetLogger(logger.New(&logs))
+
+	_, err := pluginScanner.DetectPlatform("")
+	require.Error(t, err)
+	require.Contains(t, logs.String(), "plugin crashed")
+}
+
+func TestScanner_timeout(t *testing.T) {
+	plugin := newFakePlugin(t, "slow")
+	plugin.Timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	_, err := NewScanner(plugin).DetectPlatform("")
+	require.EqualError(t, err, "plugin.test plugin detect call timed out after 200ms")
+	require.Less(t, time.Since(start), 10*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewScanner(newFakePlugin(t, "slow")).DetectPlatformWithContext(ctx, "")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRegisterPlugins_mergedIntoScanResult(t *testing.T) {
+	t.Setenv(fakePluginEnvKey, "valid")
+
+	dir := t.TempDir()
+	writeFakePluginExecutable(t, dir, ExecutablePrefix+"fake")
+
+	names, err := RegisterPlugins(context.Background(), dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fake"}, names)
+	defer func() {
+		require.NoError(t, scanners.Unregister("fake"))
+	}()
+
+	searchDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(searchDir, "package.json"), []byte(`{"name": "app"}`), 0600))
+	require.NoError(t, scanners.SetPriority("fake", 10000))
+
+	result := scanner.Config(searchDir, false)
+	require.Equal(t, newFakeConfigMap("fake-config"), result.ScannerToBitriseConfigMap["fake"])
+	require.Equal(t, "Title", result.ScannerToOptionRoot["fake"].Title)
+	require.Equal(t, "fake warning", result.ScannerToWarningsWithRecommendations["fake"][0].Error)
+	// excluded by the plugin
+	require.NotContains(t, result.ScannerToBitriseConfigMap, "node-js")
+}
@@ -0,0 +1,219 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanners"
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+)
+
+// ProtocolVersion is the version of the plugin protocol implemented by this package.
+const ProtocolVersion = 1
+
+// Method ...
+type Method string
+
+// Methods of the plugin protocol, mirroring scanners.ScannerInterface
+const (
+	MethodInfo           Method = "info"
+	MethodDetect         Method = "detect"
+	MethodOptions        Method = "options"
+	MethodConfigs        Method = "configs"
+	MethodDefaultOptions Method = "default_options"
+	MethodDefaultConfigs Method = "default_configs"
+)
+
+// SSH key activation values of the plugin protocol
+const (
+	SSHKeyActivationNone        = "none"
+	SSHKeyActivationMandatory   = "mandatory"
+	SSHKeyActivationConditional = "conditional"
+)
+
+// Request is sent to the plugin's stdin.
+type Request struct {
+	ProtocolVersion      int      `json:"protocol_version"`
+	Method               Method   `json:"method"`
+	SearchDir            string   `json:"search_dir,omitempty"`
+	SSHKeyActivation     string   `json:"ssh_key_activation,omitempty"`
+	DetectedProjectTypes []string `json:"detected_project_types,omitempty"`
+}
+
+// Response is read from the plugin's stdout.
+type Response struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	Error           string `json:"error,omitempty"`
+
+	// info
+	Name             string               `json:"name,omitempty"`
+	Type             scanners.ScannerType `json:"type,omitempty"`
+	Priority         int                  `json:"priority,omitempty"`
+	ExcludedScanners []string             `json:"excluded_scanners,omitempty"`
+
+	// detect
+	Detected bool `json:"detected,omitempty"`
+
+	// options, default_options
+	Options  *models.OptionNode `json:"options,omitempty"`
+	Warnings models.Warnings    `json:"warnings,omitempty"`
+	Icons    models.Icons       `json:"icons,omitempty"`
+
+	// configs, default_configs: the bitrise.yml contents by config name, see output.EncodeConfigMap
+	Configs map[string]string `json:"configs,omitempty"`
+}
+
+func sshKeyActivationToString(sshKeyActivation models.SSHKeyActivation) string {
+	switch sshKeyActivation {
+	case models.SSHKeyActivationMandatory:
+		return SSHKeyActivationMandatory
+	case models.SSHKeyActivationConditional:
+		return SSHKeyActivationConditional
+	default:
+		return SSHKeyActivationNone
+	}
+}
+
+func sshKeyActivationFromString(sshKeyActivation string) (models.SSHKeyActivation, error) {
+	switch sshKeyActivation {
+	case SSHKeyActivationNone, "":
+		return models.SSHKeyActivationNone, nil
+	case SSHKeyActivationMandatory:
+		return models.SSHKeyActivationMandatory, nil
+	case SSHKeyActivationConditional:
+		return models.SSHKeyActivationConditional, nil
+	default:
+		return models.SSHKeyActivationNone, fmt.Errorf("unknown ssh key activation: %s", sshKeyActivation)
+	}
+}
+
+func decodeStrict(r io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after the JSON object")
+	}
+	return nil
+}
+
+// DecodeResponse parses and validates the plugin's response to a request with the given method.
+func DecodeResponse(method Method, data []byte) (Response, error) {
+	var response Response
+	if err := decodeStrict(bytes.NewReader(data), &response); err != nil {
+		return Response{}, fmt.Errorf("invalid response: %w", err)
+	}
+	if err := ValidateResponse(method, response); err != nil {
+		return Response{}, fmt.Errorf("invalid response: %w", err)
+	}
+	return response, nil
+}
+
+// ValidateResponse checks if response is a valid response to a request with the given method.
+func ValidateResponse(method Method, response Response) error {
+	if response.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("unsupported protocol version: %d (expected: %d)", response.ProtocolVersion, ProtocolVersion)
+	}
+	if response.Error != "" {
+		return nil
+	}
+
+	switch method {
+	case MethodInfo:
+		if response.Name == "" {
+			return fmt.Errorf("name not provided")
+		}
+		if response.Type != "" && response.Type != scanners.ProjectScannerType && response.Type != scanners.AutomationToolScannerType {
+			return fmt.Errorf("unknown scanner type: %s", response.Type)
+		}
+	case MethodDetect:
+	case MethodOptions, MethodDefaultOptions:
+		if response.Options == nil {
+			return fmt.Errorf("options not provided")
+		}
+		if _, err := optionConfigNames(response.Options); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+	case MethodConfigs, MethodDefaultConfigs:
+		if len(response.Configs) == 0 {
+			return fmt.Errorf("configs not provided")
+		}
+		configs, err := output.DecodeConfigMap(response.Configs)
+		if err != nil {
+			return err
+		}
+		for name, config := range configs {
+			if err := validateConfig(config.BitriseDataModel); err != nil {
+				return fmt.Errorf("invalid config (%s): %w", name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown method: %s", method)
+	}
+	return nil
+}
+
+// optionConfigNames validates the option tree and returns the config names referenced by its leaves.
+func optionConfigNames(option *models.OptionNode) ([]string, error) {
+	configNameMap := map[string]bool{}
+
+	var walk func(option *models.OptionNode, path string) error
+	walk = func(option *models.OptionNode, path string) error {
+		if option.IsConfigOption() {
+			if len(option.ChildOptionMap) > 0 {
+				return fmt.Errorf("config option (%s) has child options", path)
+			}
+			configNameMap[option.Config] = true
+			return nil
+		}
+
+		if !option.IsValueOption() {
+			return fmt.Errorf("option (%s) has neither title nor config", path)
+		}
+		switch option.Type {
+		case models.TypeSelector, models.TypeOptionalSelector, models.TypeUserInput, models.TypeOptionalUserInput, models.TypeMultiSelector:
+		default:
+			return fmt.Errorf("option (%s) has unknown type: %s", path, option.Type)
+		}
+		if len(option.ChildOptionMap) == 0 {
+			return fmt.Errorf("option (%s) has no values", path)
+		}
+
+		for value, child := range option.ChildOptionMap {
+			childPath := path + "/" + value
+			if child == nil {
+				return fmt.Errorf("option (%s) has no child option", childPath)
+			}
+			if err := walk(child, childPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(option, strings.TrimSpace(option.Title)); err != nil {
+		return nil, err
+	}
+
+	var configNames []string
+	for name := range configNameMap {
+		configNames = append(configNames, name)
+	}
+	sort.Strings(configNames)
+	return configNames, nil
+}
+
+func validateConfig(config bitriseModels.BitriseDataModel) error {
+	if config.FormatVersion == "" {
+		return fmt.Errorf("format_version not set")
+	}
+	return nil
+}
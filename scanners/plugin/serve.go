@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanners"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+// ServeOptions describes the served scanner in the info response.
+type ServeOptions struct {
+	Type     scanners.ScannerType
+	Priority int
+}
+
+// Serve handles a single protocol request, read from stdin, with a new scanner created by newScanner,
+// and writes the response to stdout. It is meant to be called from the main function of a plugin.
+// The scanner's logs are redirected to stderr.
+func Serve(newScanner scanners.Factory, opts ServeOptions) error {
+	stdout := os.Stdout
+	// Keep stdout clean for the response, even if the scanner prints to it directly.
+	os.Stdout = os.Stderr
+	log.SetOutWriter(os.Stderr)
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	return serve(os.Stdin, stdout, newScanner, opts)
+}
+
+func serve(r io.Reader, w io.Writer, newScanner scanners.Factory, opts ServeOptions) error {
+	var request Request
+	if err := decodeStrict(r, &request); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+
+	response := handle(request, newScanner(), opts)
+	response.ProtocolVersion = ProtocolVersion
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func handle(request Request, scanner scanners.ScannerInterface, opts ServeOptions) Response {
+	if request.ProtocolVersion != ProtocolVersion {
+		return Response{Error: fmt.Sprintf("unsupported protocol version: %d (expected: %d)", request.ProtocolVersion, ProtocolVersion)}
+	}
+
+	if toolScanner, ok := scanner.(scanners.AutomationToolScanner); ok {
+		toolScanner.SetDetectedProjectTypes(request.DetectedProjectTypes)
+	}
+
+	switch request.Method {
+	case MethodInfo:
+		scannerType := opts.Type
+		if scannerType == "" {
+			scannerType = scanners.ProjectScannerType
+		}
+		return Response{
+			Name:             scanner.Name(),
+			Type:             scannerType,
+			Priority:         opts.Priority,
+			ExcludedScanners: scanner.ExcludedScannerNames(),
+		}
+	case MethodDetect:
+		detected, err := scanner.DetectPlatform(request.SearchDir)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{Detected: detected}
+	case MethodOptions, MethodConfigs:
+		// Every request is served by a new process, so the detection runs again before generating the options and configs.
+		detected, err := scanner.DetectPlatform(request.SearchDir)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		if !detected {
+			return Response{Error: fmt.Sprintf("%s platform not detected in %s", scanner.Name(), request.SearchDir)}
+		}
+
+		options, warnings, icons, err := scanner.Options()
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		if request.Method == MethodOptions {
+			return Response{Options: &options, Warnings: warnings, Icons: icons}
+		}
+
+		sshKeyActivation, err := sshKeyActivationFromString(request.SSHKeyActivation)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		configs, err := scanner.Configs(sshKeyActivation)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return configsResponse(configs)
+	case MethodDefaultOptions:
+		options := scanner.DefaultOptions()
+		return Response{Options: &options}
+	case MethodDefaultConfigs:
+		configs, err := scanner.DefaultConfigs()
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return configsResponse(configs)
+	default:
+		return Response{Error: fmt.Sprintf("unknown method: %s", request.Method)}
+	}
+}
+
+func configsResponse(configs models.BitriseConfigMap) Response {
+	configStrs, err := output.EncodeConfigMap(configs)
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	return Response{Configs: configStrs}
+}
@@ -2,8 +2,7 @@ package python
 
 import (
 	"fmt"
-
-	"gopkg.in/yaml.v2"
+	"path"
 
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
@@ -11,7 +10,10 @@ import (
 )
 
 const (
-	runTestsWorkflowID = models.WorkflowID("run_tests")
+	runTestsWorkflowID     = models.WorkflowID("run_tests")
+	runTestsWorkflowReason = "Installs the dependencies, then runs the tests of the project."
+
+	monorepoConfigName = "python-monorepo-config"
 
 	pipCachePaths    = "~/.cache/pip"
 	poetryCachePaths = "~/.cache/pypoetry"
@@ -85,6 +87,8 @@ type configDescriptor struct {
 	devRequirementsFile string
 	poetryNeedsNoRoot   bool
 	isDefault           bool
+	// cacheKeyDir is the project directory in monorepo configs, where every project needs its own cache key
+	cacheKeyDir string
 }
 
 // packageManagerSetup holds the per-package-manager bits used by the run_tests
@@ -146,6 +150,9 @@ func generateOptions(projects []project) (models.OptionNode, models.Warnings, mo
 			projectRootOption.AddOption(proj.projectRelDir, pkgMgrOption)
 		}
 	}
+	if len(projects) > 1 {
+		return *models.NewMonorepoOption(projectRootOption, monorepoConfigName), nil, nil, nil
+	}
 
 	return *projectRootOption, nil, nil, nil
 }
@@ -176,6 +183,14 @@ func generateConfigs(projects []project, sshKeyActivation models.SSHKeyActivatio
 			}
 		}
 	}
+
+	if len(projects) > 1 {
+		config, err := generateMonorepoConfig(projects, sshKeyActivation)
+		if err != nil {
+			return nil, err
+		}
+		configs[monorepoConfigName] = config
+	}
 	return configs, nil
 }
 
@@ -195,42 +210,77 @@ func configName(d configDescriptor) string {
 	return name + "-config"
 }
 
-func generateConfigBasedOn(d configDescriptor, sshKey models.SSHKeyActivation) (string, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func generateConfigBasedOn(d configDescriptor, sshKey models.SSHKeyActivation) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
 
 	if d.pythonVersion != "" {
 		configBuilder.AddTool("python", d.pythonVersion)
 	}
 
 	prepareSteps := steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKey})
+	configBuilder.SetWorkflowReasonTo(runTestsWorkflowID, runTestsWorkflowReason)
 	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, prepareSteps...)
 
 	if d.isDefault {
 		configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem("Install Python", pythonVersionInstallScriptContent))
 	}
 
-	setup := packageManagerSetupFor(d)
-	key := cacheKey(setup.cacheKeyPrefix, setup.cacheLockFile)
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.RestoreCache(key))
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem("Install dependencies", setup.installScript, workdirInputs(d.workdir)...))
-	if d.hasPytest {
-		configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem("Run tests", setup.testScript, workdirInputs(d.workdir)...))
-	}
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.SaveCache(key, setup.cachePaths))
+	appendRunTestsSteps(configBuilder, runTestsWorkflowID, d)
 
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.DefaultDeployStepList()...)
+	configBuilder.AddPullRequestTriggerToWorkflow(runTestsWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(runTestsWorkflowID)
 
 	bitriseConfig, err := configBuilder.Generate(scannerName)
 	if err != nil {
-		return "", err
+		return models.Config{}, err
 	}
 
-	data, err := yaml.Marshal(bitriseConfig)
-	if err != nil {
-		return "", err
+	return bitriseConfig, nil
+}
+
+// appendRunTestsSteps appends the steps installing the dependencies and running the tests of the project to the workflow.
+func appendRunTestsSteps(configBuilder *models.ConfigBuilderModel, workflow models.WorkflowID, d configDescriptor) {
+	setup := packageManagerSetupFor(d)
+	key := cacheKey(setup.cacheKeyPrefix, path.Join(d.cacheKeyDir, setup.cacheLockFile))
+	configBuilder.AppendStepListItemsTo(workflow, steps.RestoreCache(key))
+	configBuilder.AppendStepListItemsTo(workflow, steps.ScriptStepListItem("Install dependencies", setup.installScript, workdirInputs(d.workdir)...))
+	if d.hasPytest {
+		configBuilder.AppendStepListItemsTo(workflow, steps.ScriptStepListItem("Run tests", setup.testScript, workdirInputs(d.workdir)...))
+	}
+	configBuilder.AppendStepListItemsTo(workflow, steps.SaveCache(key, setup.cachePaths))
+
+	configBuilder.AppendStepListItemsTo(workflow, steps.DefaultDeployStepList()...)
+}
+
+// generateMonorepoConfig generates a config with a workflow for every project,
+// triggered by the changes of the project's files.
+func generateMonorepoConfig(projects []project, sshKey models.SSHKeyActivation) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
+
+	configBuilder.SetWorkflowReasonTo(models.SetupWorkflowID, models.SetupWorkflowReason)
+	configBuilder.AppendStepListItemsTo(models.SetupWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKey})...)
+
+	for _, proj := range projects {
+		d := createConfigDescriptor(proj, false)
+		if d.workdir != "" {
+			d.workdir = proj.projectRelDir
+			d.cacheKeyDir = proj.projectRelDir
+		}
+
+		workflow := models.ProjectWorkflowID(runTestsWorkflowID, proj.projectRelDir)
+		configBuilder.SetWorkflowReasonTo(workflow, runTestsWorkflowReason)
+		configBuilder.SetWorkflowBeforeRunTo(workflow, models.SetupWorkflowID)
+		if d.pythonVersion != "" {
+			configBuilder.AddWorkflowToolTo(workflow, "python", d.pythonVersion)
+		}
+		appendRunTestsSteps(configBuilder, workflow, d)
+
+		changedFiles := models.ProjectChangedFiles(proj.projectRelDir)
+		configBuilder.AddChangedFilesPullRequestTriggerTo(workflow, changedFiles)
+		configBuilder.AddChangedFilesPushTriggerTo(workflow, changedFiles)
 	}
 
-	return string(data), nil
+	return configBuilder.Generate(scannerName)
 }
 
 // packageManagerSetupFor returns the cache config and scripts for the package
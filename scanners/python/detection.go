@@ -4,9 +4,8 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
@@ -28,13 +27,13 @@ var requirementsFiles = []string{
 
 type pyprojectInfo struct {
 	poetryPackageModeDisabled bool
-	poetryHasPackagesField bool
-	poetryName             string
-	projectName            string
+	poetryHasPackagesField    bool
+	poetryName                string
+	projectName               string
 }
 
-func collectPythonProjectDirs(searchDir string) ([]string, error) {
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, false)
+func collectPythonProjectDirs(index *fileindex.Index, searchDir string) ([]string, error) {
+	fileList, err := index.ListPathInDirSortedByComponents(searchDir, false)
 	if err != nil {
 		return nil, err
 	}
@@ -68,90 +67,90 @@ func collectPythonProjectDirs(searchDir string) ([]string, error) {
 	return projectDirs, nil
 }
 
-func detectPackageManager(projectDir string) string {
-	log.TPrintf("Checking package manager")
+func detectPackageManager(logger *logger.Logger, index *fileindex.Index, projectDir string) string {
+	logger.TPrintf("Checking package manager")
 
-	if utility.FileExists(filepath.Join(projectDir, "uv.lock")) {
-		log.TPrintf("- uv.lock - found")
+	if index.FileExists(filepath.Join(projectDir, "uv.lock")) {
+		logger.TPrintf("- uv.lock - found")
 		return "uv"
 	}
 
-	if utility.FileExists(filepath.Join(projectDir, "poetry.lock")) {
-		log.TPrintf("- poetry.lock - found")
+	if index.FileExists(filepath.Join(projectDir, "poetry.lock")) {
+		logger.TPrintf("- poetry.lock - found")
 		return "poetry"
 	}
 
-	if utility.FileExists(filepath.Join(projectDir, "requirements.txt")) {
-		log.TPrintf("- requirements.txt - found")
+	if index.FileExists(filepath.Join(projectDir, "requirements.txt")) {
+		logger.TPrintf("- requirements.txt - found")
 		return "pip"
 	}
 
-	log.TPrintf("- package manager - not detected")
+	logger.TPrintf("- package manager - not detected")
 	return ""
 }
 
-func detectPythonVersion(projectDir string) string {
-	log.TPrintf("Checking Python version")
+func detectPythonVersion(logger *logger.Logger, index *fileindex.Index, projectDir string) string {
+	logger.TPrintf("Checking Python version")
 
 	// .python-version — single line (e.g. "3.12")
-	if content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, ".python-version")); err == nil {
+	if content, err := index.ReadStringFromFile(filepath.Join(projectDir, ".python-version")); err == nil {
 		version := strings.TrimSpace(content)
 		if version != "" {
-			log.TPrintf("- .python-version - found (%s)", version)
+			logger.TPrintf("- .python-version - found (%s)", version)
 			return version
 		}
 	}
 
 	// .tool-versions — asdf/mise format: "python 3.12.x"
-	if content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, ".tool-versions")); err == nil {
+	if content, err := index.ReadStringFromFile(filepath.Join(projectDir, ".tool-versions")); err == nil {
 		for _, line := range strings.Split(content, "\n") {
 			fields := strings.Fields(line)
 			if len(fields) >= 2 && fields[0] == "python" {
-				log.TPrintf("- .tool-versions - found python %s", fields[1])
+				logger.TPrintf("- .tool-versions - found python %s", fields[1])
 				return fields[1]
 			}
 		}
 	}
 
 	// pyproject.toml — requires-python field (e.g. requires-python = ">=3.12")
-	if version := pyprojectRequiresPython(projectDir); version != "" {
-		log.TPrintf("- pyproject.toml requires-python - found (%s)", version)
+	if version := pyprojectRequiresPython(index, projectDir); version != "" {
+		logger.TPrintf("- pyproject.toml requires-python - found (%s)", version)
 		return version
 	}
 
-	log.TPrintf("- Python version - not found")
+	logger.TPrintf("- Python version - not found")
 	return ""
 }
 
-func detectTestRunner(projectDir string) bool {
-	log.TPrintf("Checking test runner")
+func detectTestRunner(logger *logger.Logger, index *fileindex.Index, projectDir string) bool {
+	logger.TPrintf("Checking test runner")
 
-	if utility.FileExists(filepath.Join(projectDir, "pytest.ini")) {
-		log.TPrintf("- pytest.ini - found")
+	if index.FileExists(filepath.Join(projectDir, "pytest.ini")) {
+		logger.TPrintf("- pytest.ini - found")
 		return true
 	}
 
-	if utility.FileExists(filepath.Join(projectDir, "conftest.py")) {
-		log.TPrintf("- conftest.py - found")
+	if index.FileExists(filepath.Join(projectDir, "conftest.py")) {
+		logger.TPrintf("- conftest.py - found")
 		return true
 	}
 
-	if hasPytestInPyprojectToml(projectDir) {
-		log.TPrintf("- [tool.pytest] in pyproject.toml - found")
+	if hasPytestInPyprojectToml(index, projectDir) {
+		logger.TPrintf("- [tool.pytest] in pyproject.toml - found")
 		return true
 	}
 
-	if hasPytestInRequirementsFiles(projectDir) {
-		log.TPrintf("- pytest in requirements files - found")
+	if hasPytestInRequirementsFiles(index, projectDir) {
+		logger.TPrintf("- pytest in requirements files - found")
 		return true
 	}
 
-	log.TPrintf("- test runner - not detected")
+	logger.TPrintf("- test runner - not detected")
 	return false
 }
 
 // detectDevRequirementsFile returns the first dev/test requirements file found in projectDir, or "".
-func detectDevRequirementsFile(projectDir string) string {
+func detectDevRequirementsFile(logger *logger.Logger, index *fileindex.Index, projectDir string) string {
 	devFiles := []string{
 		"requirements-dev.txt",
 		"requirements-test.txt",
@@ -159,8 +158,8 @@ func detectDevRequirementsFile(projectDir string) string {
 		"requirements_test.txt",
 	}
 	for _, name := range devFiles {
-		if utility.FileExists(filepath.Join(projectDir, name)) {
-			log.TPrintf("- dev requirements: %s - found", name)
+		if index.FileExists(filepath.Join(projectDir, name)) {
+			logger.TPrintf("- dev requirements: %s - found", name)
 			return name
 		}
 	}
@@ -170,14 +169,14 @@ func detectDevRequirementsFile(projectDir string) string {
 // detectFramework logs which Python web framework the project uses, if any.
 // The result is only surfaced through scan logs; it doesn't affect the
 // generated workflow.
-func detectFramework(projectDir string) {
-	log.TPrintf("Checking framework")
+func detectFramework(logger *logger.Logger, index *fileindex.Index, projectDir string) {
+	logger.TPrintf("Checking framework")
 
 	frameworks := []string{"fastapi", "django", "flask"}
 
-	content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, "requirements.txt"))
+	content, err := index.ReadStringFromFile(filepath.Join(projectDir, "requirements.txt"))
 	if err != nil {
-		log.TPrintf("- framework - requirements.txt not found")
+		logger.TPrintf("- framework - requirements.txt not found")
 		return
 	}
 
@@ -189,13 +188,13 @@ func detectFramework(projectDir string) {
 		pkg := strings.ToLower(packageName(line))
 		for _, fw := range frameworks {
 			if pkg == fw {
-				log.TPrintf("- framework: %s", fw)
+				logger.TPrintf("- framework: %s", fw)
 				return
 			}
 		}
 	}
 
-	log.TPrintf("- framework - not detected")
+	logger.TPrintf("- framework - not detected")
 }
 
 // detectPoetryNeedsNoRoot decides whether `poetry install` should be invoked
@@ -210,22 +209,22 @@ func detectFramework(projectDir string) {
 //  2. explicit `packages = ...` in [tool.poetry]                           -> no --no-root
 //  3. project name resolves to <dir>/__init__.py or src/<dir>/__init__.py  -> no --no-root
 //  4. otherwise                                                            -> use --no-root
-func detectPoetryNeedsNoRoot(projectDir string) bool {
-	log.TPrintf("Checking Poetry --no-root requirement")
+func detectPoetryNeedsNoRoot(logger *logger.Logger, index *fileindex.Index, projectDir string) bool {
+	logger.TPrintf("Checking Poetry --no-root requirement")
 
-	content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, "pyproject.toml"))
+	content, err := index.ReadStringFromFile(filepath.Join(projectDir, "pyproject.toml"))
 	if err != nil {
-		log.TPrintf("- pyproject.toml - not found, using --no-root")
+		logger.TPrintf("- pyproject.toml - not found, using --no-root")
 		return true
 	}
 
 	info := parsePyproject(content)
 	if info.poetryPackageModeDisabled {
-		log.TPrintf("- package-mode = false - found, plain install")
+		logger.TPrintf("- package-mode = false - found, plain install")
 		return false
 	}
 	if info.poetryHasPackagesField {
-		log.TPrintf("- [tool.poetry] packages - declared, plain install")
+		logger.TPrintf("- [tool.poetry] packages - declared, plain install")
 		return false
 	}
 
@@ -234,27 +233,27 @@ func detectPoetryNeedsNoRoot(projectDir string) bool {
 		name = info.projectName
 	}
 	if name == "" {
-		log.TPrintf("- project name - not found, using --no-root")
+		logger.TPrintf("- project name - not found, using --no-root")
 		return true
 	}
 
 	pkgDir := strings.ReplaceAll(name, "-", "_")
-	if utility.FileExists(filepath.Join(projectDir, pkgDir, "__init__.py")) {
-		log.TPrintf("- %s/__init__.py - found, plain install", pkgDir)
+	if index.FileExists(filepath.Join(projectDir, pkgDir, "__init__.py")) {
+		logger.TPrintf("- %s/__init__.py - found, plain install", pkgDir)
 		return false
 	}
-	if utility.FileExists(filepath.Join(projectDir, "src", pkgDir, "__init__.py")) {
-		log.TPrintf("- src/%s/__init__.py - found, plain install", pkgDir)
+	if index.FileExists(filepath.Join(projectDir, "src", pkgDir, "__init__.py")) {
+		logger.TPrintf("- src/%s/__init__.py - found, plain install", pkgDir)
 		return false
 	}
 
-	log.TPrintf("- installable package layout - not found, using --no-root")
+	logger.TPrintf("- installable package layout - not found, using --no-root")
 	return true
 }
 
 // pyprojectRequiresPython extracts a version string from the requires-python field in pyproject.toml.
-func pyprojectRequiresPython(projectDir string) string {
-	content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, "pyproject.toml"))
+func pyprojectRequiresPython(index *fileindex.Index, projectDir string) string {
+	content, err := index.ReadStringFromFile(filepath.Join(projectDir, "pyproject.toml"))
 	if err != nil {
 		return ""
 	}
@@ -287,17 +286,17 @@ func pyprojectRequiresPython(projectDir string) string {
 	return ""
 }
 
-func hasPytestInPyprojectToml(projectDir string) bool {
-	content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, "pyproject.toml"))
+func hasPytestInPyprojectToml(index *fileindex.Index, projectDir string) bool {
+	content, err := index.ReadStringFromFile(filepath.Join(projectDir, "pyproject.toml"))
 	if err != nil {
 		return false
 	}
 	return strings.Contains(content, "[tool.pytest")
 }
 
-func hasPytestInRequirementsFiles(projectDir string) bool {
+func hasPytestInRequirementsFiles(index *fileindex.Index, projectDir string) bool {
 	for _, name := range requirementsFiles {
-		content, err := fileutil.ReadStringFromFile(filepath.Join(projectDir, name))
+		content, err := index.ReadStringFromFile(filepath.Join(projectDir, name))
 		if err != nil {
 			continue
 		}
@@ -3,9 +3,10 @@ package python
 import (
 	"path/filepath"
 
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 const (
@@ -39,6 +40,8 @@ type Scanner struct {
 	searchDir   string
 	projectDirs []string  // relative paths, populated by DetectPlatform
 	projects    []project // populated by Options()
+	fileIndex   *fileindex.Index
+	logger      *logger.Logger
 }
 
 // NewScanner creates a new Scanner instance.
@@ -56,32 +59,42 @@ func (s *Scanner) Name() string {
 func (s *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	s.searchDir = searchDir
 
-	dirs, err := collectPythonProjectDirs(searchDir)
+	dirs, err := collectPythonProjectDirs(s.fileIndex, searchDir)
 	if err != nil {
-		log.TWarnf("%s", err)
-		log.TPrintf("Platform not detected")
+		s.logger.TWarnf("%s", err)
+		s.logger.TPrintf("Platform not detected")
 		return false, nil
 	}
 
 	for _, dir := range dirs {
 		relDir, err := utility.RelPath(searchDir, dir)
 		if err != nil {
-			log.TWarnf("failed to get relative project dir path: %s", err)
+			s.logger.TWarnf("failed to get relative project dir path: %s", err)
 			continue
 		}
-		log.TPrintf("Python project found: %s", relDir)
+		s.logger.TPrintf("Python project found: %s", relDir)
 		s.projectDirs = append(s.projectDirs, relDir)
 	}
 
 	if len(s.projectDirs) == 0 {
-		log.TPrintf("Platform not detected")
+		s.logger.TPrintf("Platform not detected")
 		return false, nil
 	}
 
-	log.TSuccessf("Platform detected")
+	s.logger.TSuccessf("Platform detected")
 	return true, nil
 }
 
+// SetFileIndex sets the shared file index used to look up the project files.
+func (s *Scanner) SetFileIndex(index *fileindex.Index) {
+	s.fileIndex = index
+}
+
+// SetLogger sets the logger of the scan.
+func (s *Scanner) SetLogger(logger *logger.Logger) {
+	s.logger = logger
+}
+
 // ExcludedScannerNames returns scanners to skip when this scanner detects.
 func (s *Scanner) ExcludedScannerNames() []string {
 	return []string{}
@@ -91,17 +104,17 @@ func (s *Scanner) ExcludedScannerNames() []string {
 func (s *Scanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
 	for _, relDir := range s.projectDirs {
 		absDir := filepath.Join(s.searchDir, relDir)
-		log.TPrintf("Checking: %s", relDir)
+		s.logger.TPrintf("Checking: %s", relDir)
 
-		pkgMgr := detectPackageManager(absDir)
-		pythonVersion := detectPythonVersion(absDir)
-		hasPytest := detectTestRunner(absDir)
-		devReqFile := detectDevRequirementsFile(absDir)
-		detectFramework(absDir)
+		pkgMgr := detectPackageManager(s.logger, s.fileIndex, absDir)
+		pythonVersion := detectPythonVersion(s.logger, s.fileIndex, absDir)
+		hasPytest := detectTestRunner(s.logger, s.fileIndex, absDir)
+		devReqFile := detectDevRequirementsFile(s.logger, s.fileIndex, absDir)
+		detectFramework(s.logger, s.fileIndex, absDir)
 
 		needsNoRoot := false
 		if pkgMgr == "poetry" {
-			needsNoRoot = detectPoetryNeedsNoRoot(absDir)
+			needsNoRoot = detectPoetryNeedsNoRoot(s.logger, s.fileIndex, absDir)
 		}
 
 		s.projects = append(s.projects, project{
@@ -126,6 +139,7 @@ func (s *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (models.Bitr
 func (s *Scanner) DefaultOptions() models.OptionNode {
 	projectDirOption := models.NewOption(projectDirInputTitle, projectDirInputSummary, projectDirInputEnvKey, models.TypeUserInput)
 	versionOption := models.NewOption(pythonVersionInputTitle, pythonVersionInputSummary, pythonVersionEnvKey, models.TypeUserInput)
+	versionOption.Validation = models.ToolVersionValidation()
 	pkgMgrOption := models.NewOption(packageManagerInputTitle, packageManagerInputSummary, "", models.TypeSelector)
 
 	projectDirOption.AddOption(models.UserInputOptionDefaultValue, versionOption)
@@ -52,3 +52,9 @@ Next steps:
 - Check out [Getting started with Expo apps](https://docs.bitrise.io/en/bitrise-ci/getting-started/quick-start-guides/getting-started-with-expo-projects.html).
 `
 )
+
+const (
+	primaryWorkflowReason    = "Verifies every pull request: installs the dependencies and runs the tests, if the project has any."
+	deployWorkflowReason     = "Builds the app for the detected platforms on every push to the default branch, the artifacts are uploaded to Bitrise."
+	expoDeployWorkflowReason = "Starts an Expo Application Services (EAS) build on every push to the default branch."
+)
@@ -3,7 +3,6 @@ package reactnative
 import (
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -49,8 +48,9 @@ func (scanner *Scanner) expoConfigs(project project, sshKeyActivation models.SSH
 		primaryDescription = expoPrimaryWorkflowNoTestsDescription
 	}
 
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 	configBuilder.SetWorkflowDescriptionTo(models.PrimaryWorkflowID, primaryDescription)
+	configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, primaryWorkflowReason)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: sshKeyActivation,
 	})...)
@@ -66,6 +66,7 @@ func (scanner *Scanner) expoConfigs(project project, sshKeyActivation models.SSH
 	}
 
 	configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployDescription)
+	configBuilder.SetWorkflowReasonTo(models.DeployWorkflowID, expoDeployWorkflowReason)
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: sshKeyActivation,
 	})...)
@@ -73,18 +74,17 @@ func (scanner *Scanner) expoConfigs(project project, sshKeyActivation models.SSH
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.RunEASBuildStepListItem(project.projectRelDir, "$"+expoPlatformInputEnvKey))
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList()...)
 
+	// triggers
+	configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(models.DeployWorkflowID)
+
 	// generate bitrise.yml
 	bitriseDataModel, err := configBuilder.Generate(scannerName)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
-	data, err := yaml.Marshal(bitriseDataModel)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
-
-	configMap[expoConfigName] = string(data)
+	configMap[expoConfigName] = bitriseDataModel
 
 	return configMap, nil
 }
@@ -108,8 +108,9 @@ func (scanner Scanner) expoDefaultConfigs() (models.BitriseConfigMap, error) {
 	configMap := models.BitriseConfigMap{}
 
 	// primary workflow
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 	configBuilder.SetWorkflowDescriptionTo(models.PrimaryWorkflowID, expoPrimaryWorkflowDescription)
+	configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, primaryWorkflowReason)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: models.SSHKeyActivationConditional,
 	})...)
@@ -120,6 +121,7 @@ func (scanner Scanner) expoDefaultConfigs() (models.BitriseConfigMap, error) {
 
 	// deploy workflow
 	configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, expoDeployWorkflowDescription)
+	configBuilder.SetWorkflowReasonTo(models.DeployWorkflowID, expoDeployWorkflowReason)
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: models.SSHKeyActivationConditional,
 	})...)
@@ -127,18 +129,17 @@ func (scanner Scanner) expoDefaultConfigs() (models.BitriseConfigMap, error) {
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.RunEASBuildStepListItem("$"+expoProjectDirInputEnvKey, "$"+expoPlatformInputEnvKey))
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList()...)
 
+	// triggers
+	configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(models.DeployWorkflowID)
+
 	// generate bitrise.yml
 	bitriseDataModel, err := configBuilder.Generate(scannerName)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
-	data, err := yaml.Marshal(bitriseDataModel)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
-
-	configMap[expoDefaultConfigName] = string(data)
+	configMap[expoDefaultConfigName] = bitriseDataModel
 
 	return configMap, nil
 }
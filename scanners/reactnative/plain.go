@@ -9,11 +9,17 @@ import (
 	"github.com/bitrise-io/bitrise-init/steps"
 	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
 	envmanModels "github.com/bitrise-io/envman/v2/models"
-	"gopkg.in/yaml.v2"
 )
 
 const (
-	defaultConfigName = "default-react-native-config"
+	defaultConfigName        = "default-react-native-config"
+	defaultAndroidConfigName = "default-react-native-android-config"
+	defaultIOSConfigName     = "default-react-native-ios-config"
+
+	platformsInputTitle   = "Platforms to build"
+	platformsInputSummary = "The native apps of the project built by the deploy workflow."
+	platformAndroid       = "android"
+	platformIOS           = "ios"
 
 	defaultModule  = "app"
 	defaultVariant = "Debug"
@@ -108,6 +114,7 @@ func (scanner *Scanner) options(project project) (models.OptionNode, models.Warn
 
 		moduleOption := models.NewOption(android.ModuleInputTitle, android.ModuleInputSummary, android.ModuleInputEnvKey, models.TypeUserInput)
 		variantOption := models.NewOption(android.VariantInputTitle, android.VariantInputSummary, android.VariantInputEnvKey, models.TypeOptionalUserInput)
+		android.SetupVariantOption(variantOption)
 
 		androidOptions.AddOption(project.androidProject.RootDirEntry.RelPath, moduleOption)
 		moduleOption.AddOption(defaultModule, variantOption)
@@ -142,27 +149,56 @@ func (scanner *Scanner) options(project project) (models.OptionNode, models.Warn
 
 // defaultOptions implements ScannerInterface.DefaultOptions function for plain React Native projects.
 func (scanner *Scanner) defaultOptions() models.OptionNode {
+	platformsOption := models.NewOption(platformsInputTitle, platformsInputSummary, "", models.TypeMultiSelector)
+
+	// Android
+	variantOption := addDefaultAndroidOptions(platformsOption, models.MultiSelectKey(platformAndroid))
+	variantOption.AddConfig(defaultVariant, models.NewConfigOption(defaultAndroidConfigName, nil))
+
+	// iOS
+	exportMethodOption := addDefaultIOSOptions(platformsOption, models.MultiSelectKey(platformIOS))
+	for _, exportMethod := range ios.IosExportMethods {
+		exportMethodOption.AddConfig(exportMethod, models.NewConfigOption(defaultIOSConfigName, nil))
+	}
+
+	// Android and iOS
+	variantOption = addDefaultAndroidOptions(platformsOption, models.MultiSelectKey(platformAndroid, platformIOS))
+	exportMethodOption = addDefaultIOSOptions(variantOption, defaultVariant)
+	for _, exportMethod := range ios.IosExportMethods {
+		exportMethodOption.AddConfig(exportMethod, models.NewConfigOption(defaultConfigName, nil))
+	}
+
+	return *platformsOption
+}
+
+// addDefaultAndroidOptions adds the Android project location, module and variant options to the parent option, returns the variant option.
+func addDefaultAndroidOptions(parent *models.OptionNode, forValue string) *models.OptionNode {
 	androidOptions := models.NewOption(android.ProjectLocationInputTitle, android.ProjectLocationInputSummary, android.ProjectLocationInputEnvKey, models.TypeUserInput)
-	moduleOption := models.NewOption(android.ModuleInputTitle, android.ModuleInputSummary, android.ModuleInputEnvKey, models.TypeUserInput)
-	variantOption := models.NewOption(android.VariantInputTitle, android.VariantInputSummary, android.VariantInputEnvKey, models.TypeOptionalUserInput)
+	parent.AddOption(forValue, androidOptions)
 
+	moduleOption := models.NewOption(android.ModuleInputTitle, android.ModuleInputSummary, android.ModuleInputEnvKey, models.TypeUserInput)
 	androidOptions.AddOption("android", moduleOption)
+
+	variantOption := models.NewOption(android.VariantInputTitle, android.VariantInputSummary, android.VariantInputEnvKey, models.TypeOptionalUserInput)
+	android.SetupVariantOption(variantOption)
 	moduleOption.AddOption(defaultModule, variantOption)
 
+	return variantOption
+}
+
+// addDefaultIOSOptions adds the iOS project path, scheme and distribution method options to the parent option,
+// returns the distribution method option.
+func addDefaultIOSOptions(parent *models.OptionNode, forValue string) *models.OptionNode {
 	projectPathOption := models.NewOption(ios.ProjectPathInputTitle, ios.ProjectPathInputSummary, ios.ProjectPathInputEnvKey, models.TypeUserInput)
-	schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
+	parent.AddOption(forValue, projectPathOption)
 
-	variantOption.AddOption(defaultVariant, projectPathOption)
+	schemeOption := models.NewOption(ios.SchemeInputTitle, ios.SchemeInputSummary, ios.SchemeInputEnvKey, models.TypeUserInput)
 	projectPathOption.AddOption(models.UserInputOptionDefaultValue, schemeOption)
 
 	exportMethodOption := models.NewOption(ios.DistributionMethodInputTitle, ios.DistributionMethodInputSummary, ios.DistributionMethodEnvKey, models.TypeSelector)
-	for _, exportMethod := range ios.IosExportMethods {
-		schemeOption.AddOption(models.UserInputOptionDefaultValue, exportMethodOption)
+	schemeOption.AddOption(models.UserInputOptionDefaultValue, exportMethodOption)
 
-		exportMethodOption.AddConfig(exportMethod, models.NewConfigOption(defaultConfigName, nil))
-	}
-
-	return *androidOptions
+	return exportMethodOption
 }
 
 func (scanner *Scanner) configs(sshKeyActivation models.SSHKeyActivation) (models.BitriseConfigMap, error) {
@@ -173,7 +209,7 @@ func (scanner *Scanner) configs(sshKeyActivation models.SSHKeyActivation) (model
 	}
 
 	for _, descriptor := range scanner.configDescriptors {
-		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder := steps.NewConfigBuilder()
 
 		testSteps := getTestSteps("$"+projectDirInputEnvKey, descriptor.hasYarnLockFile, descriptor.hasTest)
 		// ci
@@ -183,6 +219,7 @@ func (scanner *Scanner) configs(sshKeyActivation models.SSHKeyActivation) (model
 		}
 
 		configBuilder.SetWorkflowDescriptionTo(models.PrimaryWorkflowID, primaryDescription)
+		configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, primaryWorkflowReason)
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: sshKeyActivation,
 		})...)
@@ -193,6 +230,7 @@ func (scanner *Scanner) configs(sshKeyActivation models.SSHKeyActivation) (model
 
 		// cd
 		configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
+		configBuilder.SetWorkflowReasonTo(models.DeployWorkflowID, deployWorkflowReason)
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 			SSHKeyActivation: sshKeyActivation,
 		})...)
@@ -235,27 +273,45 @@ func (scanner *Scanner) configs(sshKeyActivation models.SSHKeyActivation) (model
 
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList()...)
 
+		// triggers
+		configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+		configBuilder.AddPushTriggerToWorkflow(models.DeployWorkflowID)
+
 		bitriseDataModel, err := configBuilder.Generate(scannerName)
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
 
-		data, err := yaml.Marshal(bitriseDataModel)
+		configMap[descriptor.configName()] = bitriseDataModel
+	}
+
+	return configMap, nil
+}
+
+func (scanner *Scanner) defaultConfigs() (models.BitriseConfigMap, error) {
+	configMap := models.BitriseConfigMap{}
+	for configName, descriptor := range map[string]configDescriptor{
+		defaultAndroidConfigName: {hasAndroid: true},
+		defaultIOSConfigName:     {hasIOS: true},
+		defaultConfigName:        {hasAndroid: true, hasIOS: true},
+	} {
+		config, err := generateDefaultConfig(descriptor)
 		if err != nil {
 			return models.BitriseConfigMap{}, err
 		}
-
-		configMap[descriptor.configName()] = string(data)
+		configMap[configName] = config
 	}
 
 	return configMap, nil
 }
 
-func (scanner *Scanner) defaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+// generateDefaultConfig generates the default config building the platforms of the descriptor.
+func generateDefaultConfig(descriptor configDescriptor) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
 
 	// primary
 	configBuilder.SetWorkflowDescriptionTo(models.PrimaryWorkflowID, primaryWorkflowDescription)
+	configBuilder.SetWorkflowReasonTo(models.PrimaryWorkflowID, primaryWorkflowReason)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: models.SSHKeyActivationConditional,
 	})...)
@@ -267,50 +323,44 @@ func (scanner *Scanner) defaultConfigs() (models.BitriseConfigMap, error) {
 
 	// deploy
 	configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
+	configBuilder.SetWorkflowReasonTo(models.DeployWorkflowID, deployWorkflowReason)
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: models.SSHKeyActivationConditional,
 	})...)
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, getTestSteps("", true, true)...)
 
 	// android
-	projectLocationEnv := "$" + android.ProjectLocationInputEnvKey
-
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
-		envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: "$" + android.ProjectLocationInputEnvKey + "/gradlew"},
-	))
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.AndroidBuildStepListItem(
-		envmanModels.EnvironmentItemModel{android.ProjectLocationInputKey: projectLocationEnv},
-		envmanModels.EnvironmentItemModel{android.ModuleInputKey: "$" + android.ModuleInputEnvKey},
-		envmanModels.EnvironmentItemModel{android.VariantInputKey: "$" + android.VariantInputEnvKey},
-	))
+	if descriptor.hasAndroid {
+		projectLocationEnv := "$" + android.ProjectLocationInputEnvKey
+
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
+			envmanModels.EnvironmentItemModel{android.GradlewPathInputKey: "$" + android.ProjectLocationInputEnvKey + "/gradlew"},
+		))
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.AndroidBuildStepListItem(
+			envmanModels.EnvironmentItemModel{android.ProjectLocationInputKey: projectLocationEnv},
+			envmanModels.EnvironmentItemModel{android.ModuleInputKey: "$" + android.ModuleInputEnvKey},
+			envmanModels.EnvironmentItemModel{android.VariantInputKey: "$" + android.VariantInputEnvKey},
+		))
+	}
 
 	// ios
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
-		envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
-		envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
-		envmanModels.EnvironmentItemModel{ios.DistributionMethodInputKey: "$" + ios.DistributionMethodEnvKey},
-		envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
-		envmanModels.EnvironmentItemModel{ios.AutomaticCodeSigningInputKey: ios.AutomaticCodeSigningInputAPIKeyValue},
-	))
-
-	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList()...)
-
-	bitriseDataModel, err := configBuilder.Generate(scannerName)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
+	if descriptor.hasIOS {
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
+			envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
+			envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
+			envmanModels.EnvironmentItemModel{ios.DistributionMethodInputKey: "$" + ios.DistributionMethodEnvKey},
+			envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: "Release"},
+			envmanModels.EnvironmentItemModel{ios.AutomaticCodeSigningInputKey: ios.AutomaticCodeSigningInputAPIKeyValue},
+		))
 	}
 
-	data, err := yaml.Marshal(bitriseDataModel)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList()...)
 
-	configName := defaultConfigName
-	configMap := models.BitriseConfigMap{
-		configName: string(data),
-	}
+	// triggers
+	configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(models.DeployWorkflowID)
 
-	return configMap, nil
+	return configBuilder.Generate(scannerName)
 }
 
 func getTestSteps(workDir string, hasYarnLockFile, hasTest bool) []bitriseModels.StepListItemModel {
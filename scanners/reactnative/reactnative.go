@@ -4,14 +4,15 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/bitrise-init/detectors/gradle"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/scanners/android"
 	"github.com/bitrise-io/bitrise-init/scanners/ios"
 	"github.com/bitrise-io/bitrise-init/scanners/java"
 	"github.com/bitrise-io/bitrise-init/scanners/nodejs"
 	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
@@ -43,6 +44,9 @@ type Scanner struct {
 	projects    []project
 
 	configDescriptors []configDescriptor
+
+	fileIndex *fileindex.Index
+	logger    *logger.Logger
 }
 
 // NewScanner creates a new scanner instance.
@@ -55,8 +59,8 @@ func (Scanner) Name() string {
 	return scannerName
 }
 
-func isExpoBasedProject(packageJSONPth string) (bool, error) {
-	packages, err := utility.ParsePackagesJSON(packageJSONPth)
+func isExpoBasedProject(index *fileindex.Index, packageJSONPth string) (bool, error) {
+	packages, err := utility.ParsePackagesJSON(index, packageJSONPth)
 	if err != nil {
 		return false, fmt.Errorf("failed to parse package json file (%s): %w", packageJSONPth, err)
 	}
@@ -68,7 +72,7 @@ func isExpoBasedProject(packageJSONPth string) (bool, error) {
 	expoAppConfigFiles := []string{"app.json", "app.config.js", "app.config.ts"}
 	for _, base := range expoAppConfigFiles {
 		expoAppConfigPth := filepath.Join(filepath.Dir(packageJSONPth), base)
-		exist, err := pathutil.IsPathExists(expoAppConfigPth)
+		exist, err := index.IsPathExists(expoAppConfigPth)
 		if err != nil {
 			return false, fmt.Errorf("failed to check if Expo app config exists at: %s: %w", expoAppConfigPth, err)
 		}
@@ -80,14 +84,14 @@ func isExpoBasedProject(packageJSONPth string) (bool, error) {
 	return false, nil
 }
 
-func hasNativeIOSProject(projectDir string, iosScanner *ios.Scanner) (bool, ios.DetectResult, error) {
+func hasNativeIOSProject(index *fileindex.Index, projectDir string, iosScanner *ios.Scanner) (bool, ios.DetectResult, error) {
 	absProjectDir, err := pathutil.AbsPath(projectDir)
 	if err != nil {
 		return false, ios.DetectResult{}, err
 	}
 
 	iosDir := filepath.Join(absProjectDir, "ios")
-	if exist, err := pathutil.IsDirExists(iosDir); err != nil || !exist {
+	if exist, err := index.IsDirExists(iosDir); err != nil || !exist {
 		return false, ios.DetectResult{}, err
 	}
 
@@ -96,14 +100,14 @@ func hasNativeIOSProject(projectDir string, iosScanner *ios.Scanner) (bool, ios.
 	return detected, iosScanner.DetectResult, err
 }
 
-func hasNativeAndroidProject(projectDir string, androidScanner *android.Scanner) (bool, *gradle.Project, error) {
+func hasNativeAndroidProject(index *fileindex.Index, projectDir string, androidScanner *android.Scanner) (bool, *gradle.Project, error) {
 	absProjectDir, err := pathutil.AbsPath(projectDir)
 	if err != nil {
 		return false, nil, err
 	}
 
 	androidDir := filepath.Join(absProjectDir, "android")
-	if exist, err := pathutil.IsDirExists(androidDir); err != nil || !exist {
+	if exist, err := index.IsDirExists(androidDir); err != nil || !exist {
 		return false, nil, err
 	}
 
@@ -117,26 +121,30 @@ func hasNativeAndroidProject(projectDir string, androidScanner *android.Scanner)
 	return true, &(androidScanner.Results[0].GradleProject), nil
 }
 
-func getNativeProjects(packageJSONPth, relPackageJSONDir string) (ios.DetectResult, *gradle.Project) {
+func getNativeProjects(logger *logger.Logger, fileIndex *fileindex.Index, packageJSONPth, relPackageJSONDir string) (ios.DetectResult, *gradle.Project) {
 	var (
 		iosScanner     = ios.NewScanner()
 		androidScanner = android.NewScanner()
 	)
 	iosScanner.ExcludeAppIcon = true
 	iosScanner.SuppressPodFileParseError = true
+	iosScanner.SetFileIndex(fileIndex)
+	androidScanner.SetFileIndex(fileIndex)
+	iosScanner.SetLogger(logger)
+	androidScanner.SetLogger(logger)
 
 	projectDir := filepath.Dir(packageJSONPth)
-	isIOSProject, iosProjects, err := hasNativeIOSProject(projectDir, iosScanner)
+	isIOSProject, iosProjects, err := hasNativeIOSProject(fileIndex, projectDir, iosScanner)
 	if err != nil {
-		log.TWarnf("failed to check native iOS projects: %s", err)
+		logger.TWarnf("failed to check native iOS projects: %s", err)
 	}
-	log.TPrintf("Found native ios project: %v", isIOSProject)
+	logger.TPrintf("Found native ios project: %v", isIOSProject)
 
-	isAndroidProject, androidProject, err := hasNativeAndroidProject(projectDir, androidScanner)
+	isAndroidProject, androidProject, err := hasNativeAndroidProject(fileIndex, projectDir, androidScanner)
 	if err != nil {
-		log.TWarnf("failed to check native Android projects: %s", err)
+		logger.TWarnf("failed to check native Android projects: %s", err)
 	}
-	log.TPrintf("Found native android project: %v", isAndroidProject)
+	logger.TPrintf("Found native android project: %v", isAndroidProject)
 
 	// Update native projects paths relative to search dir (otherwise would be relative to package.json dir).
 	var newIosProjects []ios.Project
@@ -155,28 +163,28 @@ func getNativeProjects(packageJSONPth, relPackageJSONDir string) (ios.DetectResu
 
 // DetectPlatform implements ScannerInterface.DetectPlatform function.
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	log.TInfof("Collecting package.json files")
+	scanner.logger.TInfof("Collecting package.json files")
 
-	packageJSONPths, err := CollectPackageJSONFiles(searchDir)
+	packageJSONPths, err := CollectPackageJSONFiles(scanner.fileIndex, searchDir)
 	if err != nil {
 		return false, err
 	}
 
-	log.TPrintf("%d package.json file detected", len(packageJSONPths))
+	scanner.logger.TPrintf("%d package.json file detected", len(packageJSONPths))
 	for _, path := range packageJSONPths {
-		log.TPrintf("- %s", path)
+		scanner.logger.TPrintf("- %s", path)
 	}
 
-	log.TPrintf("Filtering relevant package.json files")
+	scanner.logger.TPrintf("Filtering relevant package.json files")
 	for _, packageJSONPth := range packageJSONPths {
-		log.TPrintf("Checking: %s", packageJSONPth)
+		scanner.logger.TPrintf("Checking: %s", packageJSONPth)
 
-		isExpoBased, err := isExpoBasedProject(packageJSONPth)
+		isExpoBased, err := isExpoBasedProject(scanner.fileIndex, packageJSONPth)
 		if err != nil {
-			log.TWarnf("failed to determine if project is Expo based: %s", err)
+			scanner.logger.TWarnf("failed to determine if project is Expo based: %s", err)
 		}
 
-		log.TPrintf("Project uses expo: %v", isExpoBased)
+		scanner.logger.TPrintf("Project uses expo: %v", isExpoBased)
 
 		// determine workdir
 		packageJSONDir := filepath.Dir(packageJSONPth)
@@ -190,26 +198,26 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 			androidProject *gradle.Project
 		)
 		if !isExpoBased {
-			iosProjects, androidProject = getNativeProjects(packageJSONPth, relPackageJSONDir)
+			iosProjects, androidProject = getNativeProjects(scanner.logger, scanner.fileIndex, packageJSONPth, relPackageJSONDir)
 			if len(iosProjects.Projects) == 0 && androidProject == nil {
 				continue
 			}
 		}
 
 		// determine Js dependency manager
-		hasYarnLockFile, err := containsYarnLock(filepath.Dir(packageJSONPth))
+		hasYarnLockFile, err := containsYarnLock(scanner.fileIndex, filepath.Dir(packageJSONPth))
 		if err != nil {
 			return false, err
 		}
-		log.TPrintf("Js dependency manager for %s is yarn: %t", packageJSONPth, hasYarnLockFile)
+		scanner.logger.TPrintf("Js dependency manager for %s is yarn: %t", packageJSONPth, hasYarnLockFile)
 
-		packages, err := utility.ParsePackagesJSON(packageJSONPth)
+		packages, err := utility.ParsePackagesJSON(scanner.fileIndex, packageJSONPth)
 		if err != nil {
 			return false, err
 		}
 
 		_, hasTests := packages.Scripts["test"]
-		log.TPrintf("Test script found in package.json: %v", hasTests)
+		scanner.logger.TPrintf("Test script found in package.json: %v", hasTests)
 
 		result := project{
 			projectRelDir:   relPackageJSONDir,
@@ -301,6 +309,16 @@ func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 	return configMap, nil
 }
 
+// SetFileIndex implements FileIndexScanner.SetFileIndex function.
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 // ExcludedScannerNames implements ScannerInterface.ExcludedScannerNames function.
 func (Scanner) ExcludedScannerNames() []string {
 	return []string{
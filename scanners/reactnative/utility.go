@@ -4,13 +4,14 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/bitrise-init/utility"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
 // CollectPackageJSONFiles collects package.json files, with react-native dependency.
-func CollectPackageJSONFiles(searchDir string) ([]string, error) {
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, false)
+func CollectPackageJSONFiles(index *fileindex.Index, searchDir string) ([]string, error) {
+	fileList, err := index.ListPathInDirSortedByComponents(searchDir, false)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +27,7 @@ func CollectPackageJSONFiles(searchDir string) ([]string, error) {
 
 	relevantPackageFileList := []string{}
 	for _, packageFile := range packageFileList {
-		packages, err := utility.ParsePackagesJSON(packageFile)
+		packages, err := utility.ParsePackagesJSON(index, packageFile)
 		if err != nil {
 			return nil, err
 		}
@@ -40,8 +41,8 @@ func CollectPackageJSONFiles(searchDir string) ([]string, error) {
 	return relevantPackageFileList, nil
 }
 
-func containsYarnLock(absPackageJSONDir string) (bool, error) {
-	if exist, err := pathutil.IsPathExists(filepath.Join(absPackageJSONDir, "yarn.lock")); err != nil {
+func containsYarnLock(index *fileindex.Index, absPackageJSONDir string) (bool, error) {
+	if exist, err := index.IsPathExists(filepath.Join(absPackageJSONDir, "yarn.lock")); err != nil {
 		return false, fmt.Errorf("failed to check if yarn.lock file exists in the workdir: %w", err)
 	} else if exist {
 		return true, nil
@@ -0,0 +1,247 @@
+package scanners
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/bitrise-io/bitrise-init/scanners/android"
+	"github.com/bitrise-io/bitrise-init/scanners/cordova"
+	"github.com/bitrise-io/bitrise-init/scanners/fastlane"
+	"github.com/bitrise-io/bitrise-init/scanners/flutter"
+	"github.com/bitrise-io/bitrise-init/scanners/ionic"
+	"github.com/bitrise-io/bitrise-init/scanners/ios"
+	"github.com/bitrise-io/bitrise-init/scanners/java"
+	"github.com/bitrise-io/bitrise-init/scanners/kmp"
+	"github.com/bitrise-io/bitrise-init/scanners/macos"
+	"github.com/bitrise-io/bitrise-init/scanners/nodejs"
+	"github.com/bitrise-io/bitrise-init/scanners/python"
+	"github.com/bitrise-io/bitrise-init/scanners/reactnative"
+	"github.com/bitrise-io/bitrise-init/scanners/ruby"
+)
+
+// ScannerType ...
+type ScannerType string
+
+// ScannerTypes
+const (
+	// ProjectScannerType scanners detect the project type (platform) of the search dir.
+	ProjectScannerType ScannerType = "project"
+	// AutomationToolScannerType scanners detect automation tools, they run after the project scanners
+	// and have to implement the AutomationToolScanner interface.
+	AutomationToolScannerType ScannerType = "automation_tool"
+)
+
+// Factory creates a new scanner instance. Scanners store their detection results,
+// so a new instance is created for every scan.
+type Factory func() ScannerInterface
+
+// Registration describes a registered scanner.
+type Registration struct {
+	// Name has to match the name of the created scanners.
+	Name string
+	Type ScannerType
+	New  Factory
+	// Priority defines the order of the scanners with the same type: scanners with higher priority come first.
+	// A detected scanner can only exclude scanners coming after it.
+	// Scanners with the same priority keep their registration order.
+	Priority int
+	// ExcludedScanners are excluded when the scanner detects its platform, in addition to its ExcludedScannerNames().
+	ExcludedScanners []string
+}
+
+// Registry stores the available scanners. The package level functions use the default registry,
+// which is initialised with the built-in scanners.
+type Registry struct {
+	mu            sync.RWMutex
+	registrations []Registration
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Priorities of the built-in scanners, spaced to allow registering scanners in between.
+var builtinRegistrations = []Registration{
+	{Type: ProjectScannerType, Priority: 1200, New: func() ScannerInterface { return kmp.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 1100, New: func() ScannerInterface { return reactnative.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 1000, New: func() ScannerInterface { return flutter.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 900, New: func() ScannerInterface { return ionic.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 800, New: func() ScannerInterface { return cordova.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 700, New: func() ScannerInterface { return ios.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 600, New: func() ScannerInterface { return macos.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 500, New: func() ScannerInterface { return android.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 400, New: func() ScannerInterface { return nodejs.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 300, New: func() ScannerInterface { return java.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 200, New: func() ScannerInterface { return ruby.NewScanner() }},
+	{Type: ProjectScannerType, Priority: 100, New: func() ScannerInterface { return python.NewScanner() }},
+	{Type: AutomationToolScannerType, Priority: 100, New: func() ScannerInterface { return fastlane.NewScanner() }},
+}
+
+// NewBuiltinRegistry returns a registry with the built-in scanners.
+func NewBuiltinRegistry() *Registry {
+	registry := NewRegistry()
+	for _, registration := range builtinRegistrations {
+		if err := registry.Register(registration); err != nil {
+			panic(fmt.Sprintf("failed to register built-in scanner: %s", err))
+		}
+	}
+	return registry
+}
+
+// Register adds a scanner to the registry.
+// If registration.Name is empty, it is set to the name of a scanner created by registration.New.
+func (r *Registry) Register(registration Registration) error {
+	if registration.New == nil {
+		return fmt.Errorf("scanner factory not provided")
+	}
+	if registration.Type != ProjectScannerType && registration.Type != AutomationToolScannerType {
+		return fmt.Errorf("unknown scanner type: %s", registration.Type)
+	}
+
+	scanner := registration.New()
+	if registration.Name == "" {
+		registration.Name = scanner.Name()
+	} else if registration.Name != scanner.Name() {
+		return fmt.Errorf("scanner name (%s) does not match the registered name (%s)", scanner.Name(), registration.Name)
+	}
+	if registration.Type == AutomationToolScannerType {
+		if _, ok := scanner.(AutomationToolScanner); !ok {
+			return fmt.Errorf("%s scanner does not implement the AutomationToolScanner interface", registration.Name)
+		}
+	}
+	registration.ExcludedScanners = slices.Clone(registration.ExcludedScanners)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexOf(registration.Name) != -1 {
+		return fmt.Errorf("%s scanner already registered", registration.Name)
+	}
+	r.registrations = append(r.registrations, registration)
+	return nil
+}
+
+// Unregister removes a scanner from the registry.
+func (r *Registry) Unregister(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.indexOf(name)
+	if idx == -1 {
+		return fmt.Errorf("%s scanner not registered", name)
+	}
+	r.registrations = slices.Delete(r.registrations, idx, idx+1)
+	return nil
+}
+
+// SetPriority updates the priority of a registered scanner.
+func (r *Registry) SetPriority(name string, priority int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.indexOf(name)
+	if idx == -1 {
+		return fmt.Errorf("%s scanner not registered", name)
+	}
+	r.registrations[idx].Priority = priority
+	return nil
+}
+
+// SetExcludedScanners replaces the scanners excluded by a registered scanner, in addition to its ExcludedScannerNames().
+func (r *Registry) SetExcludedScanners(name string, excludedScanners ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.indexOf(name)
+	if idx == -1 {
+		return fmt.Errorf("%s scanner not registered", name)
+	}
+	r.registrations[idx].ExcludedScanners = slices.Clone(excludedScanners)
+	return nil
+}
+
+// Registrations returns the registered scanners with the given type, in priority order.
+func (r *Registry) Registrations(scannerType ScannerType) []Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var registrations []Registration
+	for _, registration := range r.registrations {
+		if registration.Type == scannerType {
+			registration.ExcludedScanners = slices.Clone(registration.ExcludedScanners)
+			registrations = append(registrations, registration)
+		}
+	}
+	sort.SliceStable(registrations, func(i, j int) bool {
+		return registrations[i].Priority > registrations[j].Priority
+	})
+	return registrations
+}
+
+// Scanners returns new instances of the registered scanners with the given type, in priority order.
+func (r *Registry) Scanners(scannerType ScannerType) []ScannerInterface {
+	var scanners []ScannerInterface
+	for _, registration := range r.Registrations(scannerType) {
+		scanners = append(scanners, registration.New())
+	}
+	return scanners
+}
+
+// ExcludedScannerNames returns the scanners excluded by the given scanner:
+// its ExcludedScannerNames() and the exclusions set in the registry.
+func (r *Registry) ExcludedScannerNames(scanner ScannerInterface) []string {
+	excludedScanners := scanner.ExcludedScannerNames()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if idx := r.indexOf(scanner.Name()); idx != -1 {
+		for _, name := range r.registrations[idx].ExcludedScanners {
+			if !slices.Contains(excludedScanners, name) {
+				excludedScanners = append(excludedScanners, name)
+			}
+		}
+	}
+	return excludedScanners
+}
+
+func (r *Registry) indexOf(name string) int {
+	return slices.IndexFunc(r.registrations, func(registration Registration) bool {
+		return registration.Name == name
+	})
+}
+
+var defaultRegistry = NewBuiltinRegistry()
+
+// Register adds a scanner to the default registry.
+func Register(registration Registration) error {
+	return defaultRegistry.Register(registration)
+}
+
+// Unregister removes a scanner from the default registry.
+func Unregister(name string) error {
+	return defaultRegistry.Unregister(name)
+}
+
+// SetPriority updates the priority of a scanner in the default registry.
+func SetPriority(name string, priority int) error {
+	return defaultRegistry.SetPriority(name, priority)
+}
+
+// SetExcludedScanners replaces the additional exclusions of a scanner in the default registry.
+func SetExcludedScanners(name string, excludedScanners ...string) error {
+	return defaultRegistry.SetExcludedScanners(name, excludedScanners...)
+}
+
+// Registrations returns the scanners with the given type from the default registry, in priority order.
+func Registrations(scannerType ScannerType) []Registration {
+	return defaultRegistry.Registrations(scannerType)
+}
+
+// ExcludedScannerNames returns the scanners excluded by the given scanner, based on the default registry.
+func ExcludedScannerNames(scanner ScannerInterface) []string {
+	return defaultRegistry.ExcludedScannerNames(scanner)
+}
@@ -0,0 +1,68 @@
+package scanners
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+)
+
+type testScanner struct {
+	name     string
+	excluded []string
+}
+
+func (s testScanner) Name() string                        { return s.name }
+func (s testScanner) DetectPlatform(string) (bool, error) { return false, nil }
+func (s testScanner) ExcludedScannerNames() []string      { return s.excluded }
+func (s testScanner) Options() (models.OptionNode, models.Warnings, models.Icons, error) {
+	return models.OptionNode{}, nil, nil, nil
+}
+func (s testScanner) DefaultOptions() models.OptionNode { return models.OptionNode{} }
+func (s testScanner) Configs(models.SSHKeyActivation) (models.BitriseConfigMap, error) {
+	return nil, nil
+}
+func (s testScanner) DefaultConfigs() (models.BitriseConfigMap, error) { return nil, nil }
+
+func newTestScanner(name string, excluded ...string) Factory {
+	return func() ScannerInterface { return testScanner{name: name, excluded: excluded} }
+}
+
+func scannerNames(scanners []ScannerInterface) (names []string) {
+	for _, scanner := range scanners {
+		names = append(names, scanner.Name())
+	}
+	return
+}
+
+func TestNewBuiltinRegistry(t *testing.T) {
+	registry := NewBuiltinRegistry()
+
+	require.Equal(t, []string{"kotlin-multiplatform", "react-native", "flutter", "ionic", "cordova", "ios", "macos", "android", "node-js", "java", "ruby", "python"}, scannerNames(registry.Scanners(ProjectScannerType)))
+	require.Equal(t, []string{"fastlane"}, scannerNames(registry.Scanners(AutomationToolScannerType)))
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	require.NoError(t, registry.Register(Registration{Type: ProjectScannerType, New: newTestScanner("low"), Priority: 10}))
+	require.NoError(t, registry.Register(Registration{Type: ProjectScannerType, New: newTestScanner("high", "low"), Priority: 100}))
+	require.NoError(t, registry.Register(Registration{Type: ProjectScannerType, New: newTestScanner("same-as-low"), Priority: 10}))
+	require.Equal(t, []string{"high", "low", "same-as-low"}, scannerNames(registry.Scanners(ProjectScannerType)))
+
+	require.EqualError(t, registry.Register(Registration{Type: ProjectScannerType, New: newTestScanner("low")}), "low scanner already registered")
+	require.EqualError(t, registry.Register(Registration{Name: "other", Type: ProjectScannerType, New: newTestScanner("low")}), "scanner name (low) does not match the registered name (other)")
+	require.EqualError(t, registry.Register(Registration{Type: AutomationToolScannerType, New: newTestScanner("tool")}), "tool scanner does not implement the AutomationToolScanner interface")
+
+	require.NoError(t, registry.SetPriority("same-as-low", 1000))
+	require.Equal(t, []string{"same-as-low", "high", "low"}, scannerNames(registry.Scanners(ProjectScannerType)))
+
+	require.NoError(t, registry.SetExcludedScanners("high", "same-as-low", "low"))
+	require.Equal(t, []string{"low", "same-as-low"}, registry.ExcludedScannerNames(testScanner{name: "high", excluded: []string{"low"}}))
+
+	require.NoError(t, registry.Unregister("high"))
+	require.EqualError(t, registry.Unregister("high"), "high scanner not registered")
+	require.EqualError(t, registry.SetPriority("high", 1), "high scanner not registered")
+	require.Equal(t, []string{"same-as-low", "low"}, scannerNames(registry.Scanners(ProjectScannerType)))
+	require.Empty(t, registry.Scanners(AutomationToolScannerType))
+}
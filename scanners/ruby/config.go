@@ -2,10 +2,9 @@ package ruby
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
-	"gopkg.in/yaml.v2"
-
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/steps"
 	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
@@ -15,10 +14,13 @@ import (
 )
 
 const (
-	runTestsWorkflowID = models.WorkflowID("run_tests")
+	runTestsWorkflowID     = models.WorkflowID("run_tests")
+	runTestsWorkflowReason = "Installs the gems, then runs the tests of the project."
+
+	gemCachePaths     = "vendor/bundle"
+	gemCacheKeyFormat = `gem-{{ checksum "%s" }}`
 
-	gemCachePaths = "vendor/bundle"
-	gemCacheKey   = `gem-{{ checksum "Gemfile.lock" }}`
+	monorepoConfigName = "ruby-monorepo-config"
 )
 
 const (
@@ -55,6 +57,8 @@ type configDescriptor struct {
 	databases      []databaseGem
 	dbYMLInfo      databaseYMLInfo
 	mongoidYMLInfo mongoidYMLInfo
+	// cacheKeyDir is the project directory in monorepo configs, where every project needs its own gem cache
+	cacheKeyDir string
 }
 
 func generateOptions(projects []project) (models.OptionNode, models.Warnings, models.Icons, error) {
@@ -68,6 +72,9 @@ func generateOptions(projects []project) (models.OptionNode, models.Warnings, mo
 		configOption := models.NewConfigOption(configName(descriptor), nil)
 		projectRootOption.AddConfig(project.projectRelDir, configOption)
 	}
+	if len(projects) > 1 {
+		return *models.NewMonorepoOption(projectRootOption, monorepoConfigName), nil, nil, nil
+	}
 
 	return *projectRootOption, nil, nil, nil
 }
@@ -88,34 +95,69 @@ func generateConfigs(projects []project, sshKeyActivation models.SSHKeyActivatio
 		configs[configName(descriptor)] = config
 	}
 
+	if len(projects) > 1 {
+		config, err := generateMonorepoConfig(projects, sshKeyActivation)
+		if err != nil {
+			return nil, err
+		}
+		configs[monorepoConfigName] = config
+	}
+
 	return configs, nil
 }
 
-func generateConfigBasedOn(descriptor configDescriptor, sshKey models.SSHKeyActivation) (string, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+func generateConfigBasedOn(descriptor configDescriptor, sshKey models.SSHKeyActivation) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
 	// Declarative Ruby version — runs before any step, no explicit install step needed
 	if descriptor.rubyVersion != "" {
 		configBuilder.AddTool("ruby", descriptor.rubyVersion)
 	}
 
 	prepareSteps := steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKey})
+	configBuilder.SetWorkflowReasonTo(runTestsWorkflowID, runTestsWorkflowReason)
 	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, prepareSteps...)
 
 	if descriptor.isDefault {
 		configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem("Install Ruby", rubyVersionInstallScriptContent))
 	}
 
+	appendRunTestsSteps(configBuilder, runTestsWorkflowID, descriptor)
+
+	configBuilder.AddPullRequestTriggerToWorkflow(runTestsWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(runTestsWorkflowID)
+
+	// Build app-level env vars for database connections
+	appEnvs := buildAppEnvs(descriptor.databases, descriptor.dbYMLInfo, descriptor.mongoidYMLInfo)
+
+	if len(descriptor.databases) > 0 {
+		containers := buildContainerDefinitions(descriptor.databases, descriptor.dbYMLInfo)
+		if len(containers) > 0 {
+			configBuilder.SetContainerDefinitions(containers)
+		}
+	}
+
+	config, err := configBuilder.Generate(scannerName, appEnvs...)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	return config, nil
+}
+
+// appendRunTestsSteps appends the steps installing the gems, setting up the database and running the tests of the project to the workflow.
+func appendRunTestsSteps(configBuilder *models.ConfigBuilderModel, workflow models.WorkflowID, descriptor configDescriptor) {
 	// Restore gem cache
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.RestoreCache(gemCacheKey))
+	key := fmt.Sprintf(gemCacheKeyFormat, path.Join(descriptor.cacheKeyDir, "Gemfile.lock"))
+	configBuilder.AppendStepListItemsTo(workflow, steps.RestoreCache(key))
 
 	// Install system dependencies (e.g. native library headers required by some gems)
 	if aptPackages := collectAptPackages(descriptor.databases); len(aptPackages) > 0 {
-		configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem(systemDepsInstallScriptStepTitle, generateSystemDepsScript(aptPackages)))
+		configBuilder.AppendStepListItemsTo(workflow, steps.ScriptStepListItem(systemDepsInstallScriptStepTitle, generateSystemDepsScript(aptPackages)))
 	}
 
 	// Install dependencies
 	if descriptor.hasBundler {
-		configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem(bundlerInstallScriptStepTitle, bundlerInstallScriptStepContent, workdirInputs(descriptor.workdir)...))
+		configBuilder.AppendStepListItemsTo(workflow, steps.ScriptStepListItem(bundlerInstallScriptStepTitle, bundlerInstallScriptStepContent, workdirInputs(descriptor.workdir)...))
 	}
 
 	serviceContainerNames := serviceContainerReferences(descriptor.databases)
@@ -125,9 +167,9 @@ func generateConfigBasedOn(descriptor configDescriptor, sshKey models.SSHKeyActi
 	if hasRelationalDB(descriptor.databases) {
 		dbSetupScript := generateDBSetupScript(descriptor)
 		if len(relationalServiceContainerNames) > 0 {
-			configBuilder.AppendStepListItemsTo(runTestsWorkflowID, scriptStepWithServiceContainers("Database setup", dbSetupScript, relationalServiceContainerNames, descriptor.workdir))
+			configBuilder.AppendStepListItemsTo(workflow, scriptStepWithServiceContainers("Database setup", dbSetupScript, relationalServiceContainerNames, descriptor.workdir))
 		} else {
-			configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem("Database setup", dbSetupScript, workdirInputs(descriptor.workdir)...))
+			configBuilder.AppendStepListItemsTo(workflow, steps.ScriptStepListItem("Database setup", dbSetupScript, workdirInputs(descriptor.workdir)...))
 		}
 	}
 
@@ -135,39 +177,60 @@ func generateConfigBasedOn(descriptor configDescriptor, sshKey models.SSHKeyActi
 	testScript := generateTestScript(descriptor)
 	if testScript != "" {
 		if len(serviceContainerNames) > 0 {
-			configBuilder.AppendStepListItemsTo(runTestsWorkflowID, scriptStepWithServiceContainers("Run tests", testScript, serviceContainerNames, descriptor.workdir))
+			configBuilder.AppendStepListItemsTo(workflow, scriptStepWithServiceContainers("Run tests", testScript, serviceContainerNames, descriptor.workdir))
 		} else {
-			configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.ScriptStepListItem("Run tests", testScript, workdirInputs(descriptor.workdir)...))
+			configBuilder.AppendStepListItemsTo(workflow, steps.ScriptStepListItem("Run tests", testScript, workdirInputs(descriptor.workdir)...))
 		}
 	}
 
 	// Save gem cache
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.SaveCache(gemCacheKey, gemCachePaths))
+	configBuilder.AppendStepListItemsTo(workflow, steps.SaveCache(key, path.Join(descriptor.cacheKeyDir, gemCachePaths)))
 
 	// Deploy steps
-	configBuilder.AppendStepListItemsTo(runTestsWorkflowID, steps.DefaultDeployStepList()...)
+	configBuilder.AppendStepListItemsTo(workflow, steps.DefaultDeployStepList()...)
+}
 
-	// Build app-level env vars for database connections
-	appEnvs := buildAppEnvs(descriptor.databases, descriptor.dbYMLInfo, descriptor.mongoidYMLInfo)
+// generateMonorepoConfig generates a config with a workflow for every project,
+// triggered by the changes of the project's files.
+// The database envs of the projects are set on their workflows, the service containers are shared.
+func generateMonorepoConfig(projects []project, sshKey models.SSHKeyActivation) (models.Config, error) {
+	configBuilder := steps.NewConfigBuilder()
 
-	if len(descriptor.databases) > 0 {
-		containers := buildContainerDefinitions(descriptor.databases, descriptor.dbYMLInfo)
-		if len(containers) > 0 {
-			configBuilder.SetContainerDefinitions(containers)
+	configBuilder.SetWorkflowReasonTo(models.SetupWorkflowID, models.SetupWorkflowReason)
+	configBuilder.AppendStepListItemsTo(models.SetupWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{SSHKeyActivation: sshKey})...)
+
+	containers := map[string]bitriseModels.Container{}
+	for _, project := range projects {
+		descriptor := createConfigDescriptor(project, false)
+		if descriptor.workdir != "" {
+			descriptor.workdir = project.projectRelDir
+			descriptor.cacheKeyDir = project.projectRelDir
 		}
-	}
 
-	config, err := configBuilder.Generate(scannerName, appEnvs...)
-	if err != nil {
-		return "", err
-	}
+		workflow := models.ProjectWorkflowID(runTestsWorkflowID, project.projectRelDir)
+		configBuilder.SetWorkflowReasonTo(workflow, runTestsWorkflowReason)
+		configBuilder.SetWorkflowBeforeRunTo(workflow, models.SetupWorkflowID)
+		if descriptor.rubyVersion != "" {
+			configBuilder.AddWorkflowToolTo(workflow, "ruby", descriptor.rubyVersion)
+		}
+		configBuilder.AppendWorkflowEnvsTo(workflow, buildAppEnvs(descriptor.databases, descriptor.dbYMLInfo, descriptor.mongoidYMLInfo)...)
+		appendRunTestsSteps(configBuilder, workflow, descriptor)
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return "", err
+		changedFiles := models.ProjectChangedFiles(project.projectRelDir)
+		configBuilder.AddChangedFilesPullRequestTriggerTo(workflow, changedFiles)
+		configBuilder.AddChangedFilesPushTriggerTo(workflow, changedFiles)
+
+		for name, container := range buildContainerDefinitions(descriptor.databases, descriptor.dbYMLInfo) {
+			if _, ok := containers[name]; !ok {
+				containers[name] = container
+			}
+		}
+	}
+	if len(containers) > 0 {
+		configBuilder.SetContainerDefinitions(containers)
 	}
 
-	return string(data), nil
+	return configBuilder.Generate(scannerName)
 }
 
 func createConfigDescriptor(project project, isDefault bool) configDescriptor {
@@ -7,6 +7,7 @@ import (
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestConfigName(t *testing.T) {
@@ -209,8 +210,7 @@ func TestGenerateConfigWithMySQLSystemDeps(t *testing.T) {
 		},
 	}
 
-	config, err := generateConfigBasedOn(descriptor, models.SSHKeyActivationConditional)
-	require.NoError(t, err)
+	config := generateConfigYAML(t, descriptor)
 
 	assert.True(t, strings.Contains(config, "Install system dependencies"), "should have system deps step")
 	assert.True(t, strings.Contains(config, "apt-get install -y libmariadb-dev"), "should install libmariadb-dev")
@@ -243,8 +243,7 @@ func TestGenerateConfigWithServices(t *testing.T) {
 		},
 	}
 
-	config, err := generateConfigBasedOn(descriptor, models.SSHKeyActivationConditional)
-	require.NoError(t, err)
+	config := generateConfigYAML(t, descriptor)
 
 	// Verify containers block
 	assert.True(t, strings.Contains(config, "containers:"), "should have containers block")
@@ -264,3 +263,12 @@ func TestGenerateConfigWithServices(t *testing.T) {
 	assert.True(t, strings.Contains(config, "DB_HOST: localhost"), "should set DB_HOST to localhost (scripts run on host, not in Docker)")
 	assert.True(t, strings.Contains(config, "DB_PASSWORD: password"), "should set DB_PASSWORD default")
 }
+
+func generateConfigYAML(t *testing.T, descriptor configDescriptor) string {
+	config, err := generateConfigBasedOn(descriptor, models.SSHKeyActivationConditional)
+	require.NoError(t, err)
+
+	data, err := yaml.Marshal(config)
+	require.NoError(t, err)
+	return string(data)
+}
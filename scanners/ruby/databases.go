@@ -8,8 +8,8 @@ import (
 
 	"gopkg.in/yaml.v2"
 
-	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 )
 
 // databaseEnvVar represents an environment variable with its name and default value.
@@ -113,11 +113,11 @@ var (
 	erbTagPattern = regexp.MustCompile(`<%[^%]*%>`)
 )
 
-func detectDatabases(searchDir string) []databaseGem {
+func detectDatabases(logger *logger.Logger, index *fileindex.Index, searchDir string) []databaseGem {
 	gemfilePath := filepath.Join(searchDir, "Gemfile")
-	content, err := fileutil.ReadStringFromFile(gemfilePath)
+	content, err := index.ReadStringFromFile(gemfilePath)
 	if err != nil {
-		log.TWarnf("Failed to read Gemfile: %s", err)
+		logger.TWarnf("Failed to read Gemfile: %s", err)
 		return nil
 	}
 
@@ -159,16 +159,16 @@ func hasRelationalDB(databases []databaseGem) bool {
 	return false
 }
 
-func parseDatabaseYML(searchDir string, databases []databaseGem) databaseYMLInfo {
+func parseDatabaseYML(logger *logger.Logger, index *fileindex.Index, searchDir string, databases []databaseGem) databaseYMLInfo {
 	ymlPath := filepath.Join(searchDir, "config", "database.yml")
-	content, err := fileutil.ReadStringFromFile(ymlPath)
+	content, err := index.ReadStringFromFile(ymlPath)
 	if err != nil {
-		log.TPrintf("- config/database.yml - not found or not readable")
+		logger.TPrintf("- config/database.yml - not found or not readable")
 		return databaseYMLInfo{}
 	}
 
-	log.TPrintf("- config/database.yml - found, parsing credentials")
-	return parseDatabaseYMLContent(content, databases)
+	logger.TPrintf("- config/database.yml - found, parsing credentials")
+	return parseDatabaseYMLContent(logger, content, databases)
 }
 
 // parseDatabaseYMLContent parses the contents of a database.yml file and extracts
@@ -176,12 +176,12 @@ func parseDatabaseYML(searchDir string, databases []databaseGem) databaseYMLInfo
 // It prefers the "test" environment section, then "default", then any other section.
 // YAML anchor merges (<<: *default) are resolved automatically by the YAML parser.
 // The adapter field is required: if absent or not matching a detected database gem, the result is empty.
-func parseDatabaseYMLContent(content string, databases []databaseGem) databaseYMLInfo {
+func parseDatabaseYMLContent(logger *logger.Logger, content string, databases []databaseGem) databaseYMLInfo {
 	preprocessed := preprocessERBForYAML(content)
 
 	var rawYML map[string]map[string]interface{}
 	if err := yaml.Unmarshal([]byte(preprocessed), &rawYML); err != nil {
-		log.TWarnf("- config/database.yml - failed to parse: %s", err)
+		logger.TWarnf("- config/database.yml - failed to parse: %s", err)
 		return databaseYMLInfo{}
 	}
 
@@ -211,7 +211,7 @@ func parseDatabaseYMLContent(content string, databases []databaseGem) databaseYM
 	}
 
 	if info.adapter == "" {
-		log.TWarnf("database.yml has no adapter field, skipping database.yml config")
+		logger.TWarnf("database.yml has no adapter field, skipping database.yml config")
 		return databaseYMLInfo{}
 	}
 
@@ -221,7 +221,7 @@ func parseDatabaseYMLContent(content string, databases []databaseGem) databaseYM
 		}
 	}
 
-	log.TWarnf("database.yml adapter %q does not match any detected database gem, skipping database.yml config", info.adapter)
+	logger.TWarnf("database.yml adapter %q does not match any detected database gem, skipping database.yml config", info.adapter)
 	return databaseYMLInfo{}
 }
 
@@ -271,15 +271,15 @@ func findMongoDBGem(databases []databaseGem) (databaseGem, bool) {
 	return databaseGem{}, false
 }
 
-func parseMongoidYML(searchDir string) mongoidYMLInfo {
+func parseMongoidYML(logger *logger.Logger, index *fileindex.Index, searchDir string) mongoidYMLInfo {
 	ymlPath := filepath.Join(searchDir, "config", "mongoid.yml")
-	content, err := fileutil.ReadStringFromFile(ymlPath)
+	content, err := index.ReadStringFromFile(ymlPath)
 	if err != nil {
-		log.TPrintf("- config/mongoid.yml - not found or not readable")
+		logger.TPrintf("- config/mongoid.yml - not found or not readable")
 		return mongoidYMLInfo{}
 	}
 
-	log.TPrintf("- config/mongoid.yml - found, parsing connection URL")
+	logger.TPrintf("- config/mongoid.yml - found, parsing connection URL")
 	return parseMongoidYMLContent(content)
 }
 
@@ -200,7 +200,7 @@ test:
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parseDatabaseYMLContent(tt.content, tt.databases)
+			result := parseDatabaseYMLContent(nil, tt.content, tt.databases)
 			assert.Equal(t, tt.want, result)
 		})
 	}
@@ -4,9 +4,8 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
@@ -20,8 +19,8 @@ var testFrameworks = []testFramework{
 	{"minitest", []string{"test/test_helper.rb"}},
 }
 
-func collectGemfiles(searchDir string) ([]string, error) {
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, false)
+func collectGemfiles(index *fileindex.Index, searchDir string) ([]string, error) {
+	fileList, err := index.ListPathInDirSortedByComponents(searchDir, false)
 	if err != nil {
 		return nil, err
 	}
@@ -36,84 +35,84 @@ func collectGemfiles(searchDir string) ([]string, error) {
 	return pathutil.FilterPaths(fileList, filters...)
 }
 
-func checkBundler(searchDir string) bool {
-	log.TPrintf("Checking for Bundler")
-	hasGemfileLock := utility.FileExists(filepath.Join(searchDir, "Gemfile.lock"))
+func checkBundler(logger *logger.Logger, index *fileindex.Index, searchDir string) bool {
+	logger.TPrintf("Checking for Bundler")
+	hasGemfileLock := index.FileExists(filepath.Join(searchDir, "Gemfile.lock"))
 
 	if !hasGemfileLock {
-		log.TPrintf("- Gemfile.lock - not found")
+		logger.TPrintf("- Gemfile.lock - not found")
 		return false
 	}
 
-	log.TPrintf("- Gemfile.lock - found")
-	log.TPrintf("Bundler: detected")
+	logger.TPrintf("- Gemfile.lock - found")
+	logger.TPrintf("Bundler: detected")
 	return true
 }
 
-func checkRakefile(searchDir string) bool {
-	log.TPrintf("Checking for Rakefile")
-	hasRakefile := utility.FileExists(filepath.Join(searchDir, "Rakefile"))
+func checkRakefile(logger *logger.Logger, index *fileindex.Index, searchDir string) bool {
+	logger.TPrintf("Checking for Rakefile")
+	hasRakefile := index.FileExists(filepath.Join(searchDir, "Rakefile"))
 
 	if !hasRakefile {
-		log.TPrintf("- Rakefile - not found")
+		logger.TPrintf("- Rakefile - not found")
 		return false
 	}
 
-	log.TPrintf("- Rakefile - found")
+	logger.TPrintf("- Rakefile - found")
 	return true
 }
 
 // readRubyVersion returns the Ruby version declared in .ruby-version or .tool-versions,
 // or an empty string if no version file is found.
-func readRubyVersion(searchDir string) string {
-	log.TPrintf("Checking for Ruby version file")
+func readRubyVersion(logger *logger.Logger, index *fileindex.Index, searchDir string) string {
+	logger.TPrintf("Checking for Ruby version file")
 
 	// .ruby-version: single line containing the version (e.g. "3.3.0" or "ruby-3.3.0")
 	rubyVersionPath := filepath.Join(searchDir, ".ruby-version")
-	if content, err := fileutil.ReadStringFromFile(rubyVersionPath); err == nil {
+	if content, err := index.ReadStringFromFile(rubyVersionPath); err == nil {
 		version := strings.TrimSpace(content)
 		version = strings.TrimPrefix(version, "ruby-")
 		if version != "" {
-			log.TPrintf("- .ruby-version - found (%s)", version)
+			logger.TPrintf("- .ruby-version - found (%s)", version)
 			return version
 		}
 	}
 
 	// .tool-versions: asdf format, one tool per line (e.g. "ruby 3.3.0")
 	toolVersionsPath := filepath.Join(searchDir, ".tool-versions")
-	if content, err := fileutil.ReadStringFromFile(toolVersionsPath); err == nil {
+	if content, err := index.ReadStringFromFile(toolVersionsPath); err == nil {
 		for _, line := range strings.Split(content, "\n") {
 			fields := strings.Fields(line)
 			if len(fields) >= 2 && fields[0] == "ruby" {
-				log.TPrintf("- .tool-versions - found ruby %s", fields[1])
+				logger.TPrintf("- .tool-versions - found ruby %s", fields[1])
 				return fields[1]
 			}
 		}
 	}
 
-	log.TPrintf("- Ruby version file - not found")
+	logger.TPrintf("- Ruby version file - not found")
 	return ""
 }
 
-func detectTestFramework(searchDir string) string {
-	log.TPrintf("Checking test framework")
+func detectTestFramework(logger *logger.Logger, index *fileindex.Index, searchDir string) string {
+	logger.TPrintf("Checking test framework")
 
 	for _, fw := range testFrameworks {
 		for _, detectionFile := range fw.detectionFiles {
-			if utility.FileExists(filepath.Join(searchDir, detectionFile)) {
-				log.TPrintf("- %s - found (%s)", fw.name, detectionFile)
+			if index.FileExists(filepath.Join(searchDir, detectionFile)) {
+				logger.TPrintf("- %s - found (%s)", fw.name, detectionFile)
 				return fw.name
 			}
 		}
 	}
 
-	log.TPrintf("- test framework - not detected")
+	logger.TPrintf("- test framework - not detected")
 	return ""
 }
 
-func detectRails(searchDir string) bool {
+func detectRails(index *fileindex.Index, searchDir string) bool {
 	gemfilePath := filepath.Join(searchDir, "Gemfile")
-	content, err := fileutil.ReadStringFromFile(gemfilePath)
+	content, err := index.ReadStringFromFile(gemfilePath)
 	if err != nil {
 		return false
 	}
@@ -3,9 +3,10 @@ package ruby
 import (
 	"path/filepath"
 
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
 	"github.com/bitrise-io/bitrise-init/utility"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 const (
@@ -28,7 +29,9 @@ type project struct {
 }
 
 type Scanner struct {
-	projects []project
+	projects  []project
+	fileIndex *fileindex.Index
+	logger    *logger.Logger
 }
 
 func NewScanner() *Scanner {
@@ -40,37 +43,37 @@ func (scanner *Scanner) Name() string {
 }
 
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
-	gemfilePaths, err := collectGemfiles(searchDir)
+	gemfilePaths, err := collectGemfiles(scanner.fileIndex, searchDir)
 	if err != nil {
-		log.TWarnf("%s", err)
-		log.TPrintf("Platform not detected")
+		scanner.logger.TWarnf("%s", err)
+		scanner.logger.TPrintf("Platform not detected")
 		return false, nil
 	}
 
 	for _, gemfilePath := range gemfilePaths {
-		log.TPrintf("Checking: %s", gemfilePath)
+		scanner.logger.TPrintf("Checking: %s", gemfilePath)
 
 		// determine workdir
 		gemfileDir := filepath.Dir(gemfilePath)
 
-		hasBundler := checkBundler(gemfileDir)
-		hasRakefile := checkRakefile(gemfileDir)
-		testFw := detectTestFramework(gemfileDir)
-		rubyVersion := readRubyVersion(gemfileDir)
-		hasRails := detectRails(gemfileDir)
-		databases := detectDatabases(gemfileDir)
+		hasBundler := checkBundler(scanner.logger, scanner.fileIndex, gemfileDir)
+		hasRakefile := checkRakefile(scanner.logger, scanner.fileIndex, gemfileDir)
+		testFw := detectTestFramework(scanner.logger, scanner.fileIndex, gemfileDir)
+		rubyVersion := readRubyVersion(scanner.logger, scanner.fileIndex, gemfileDir)
+		hasRails := detectRails(scanner.fileIndex, gemfileDir)
+		databases := detectDatabases(scanner.logger, scanner.fileIndex, gemfileDir)
 		var dbYMLInfo databaseYMLInfo
 		if hasRelationalDB(databases) {
-			dbYMLInfo = parseDatabaseYML(gemfileDir, databases)
+			dbYMLInfo = parseDatabaseYML(scanner.logger, scanner.fileIndex, gemfileDir, databases)
 		}
 		var mongoidInfo mongoidYMLInfo
 		if _, ok := findMongoDBGem(databases); ok {
-			mongoidInfo = parseMongoidYML(gemfileDir)
+			mongoidInfo = parseMongoidYML(scanner.logger, scanner.fileIndex, gemfileDir)
 		}
 
 		projectRelDir, err := utility.RelPath(searchDir, gemfileDir)
 		if err != nil {
-			log.TWarnf("failed to get relative Gemfile dir path: %s", err)
+			scanner.logger.TWarnf("failed to get relative Gemfile dir path: %s", err)
 			continue
 		}
 
@@ -90,14 +93,23 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	}
 
 	if len(scanner.projects) == 0 {
-		log.TPrintf("Platform not detected")
+		scanner.logger.TPrintf("Platform not detected")
 		return false, nil
 	}
 
-	log.TSuccessf("Platform detected")
+	scanner.logger.TSuccessf("Platform detected")
 	return true, nil
 }
 
+func (scanner *Scanner) SetFileIndex(index *fileindex.Index) {
+	scanner.fileIndex = index
+}
+
+// SetLogger ...
+func (scanner *Scanner) SetLogger(logger *logger.Logger) {
+	scanner.logger = logger
+}
+
 func (scanner *Scanner) ExcludedScannerNames() []string {
 	return []string{}
 }
@@ -113,6 +125,7 @@ func (scanner *Scanner) Configs(sshKeyActivation models.SSHKeyActivation) (model
 func (scanner *Scanner) DefaultOptions() models.OptionNode {
 	projectRootOption := models.NewOption(projectDirInputTitle, projectDirInputSummary, projectDirInputEnvKey, models.TypeUserInput)
 	rubyVersionOption := models.NewOption(rubyVersionInputTitle, rubyVersionInputSummary, rubyVersionEnvKey, models.TypeUserInput)
+	rubyVersionOption.Validation = models.ToolVersionValidation()
 
 	projectRootOption.AddOption(models.UserInputOptionDefaultValue, rubyVersionOption)
 
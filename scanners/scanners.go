@@ -1,22 +1,12 @@
 package scanners
 
 import (
+	"context"
+
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
+	"github.com/bitrise-io/bitrise-init/logger"
 	"github.com/bitrise-io/bitrise-init/models"
-	"github.com/bitrise-io/bitrise-init/scanners/android"
-	"github.com/bitrise-io/bitrise-init/scanners/cordova"
-	"github.com/bitrise-io/bitrise-init/scanners/fastlane"
-	"github.com/bitrise-io/bitrise-init/scanners/flutter"
-	"github.com/bitrise-io/bitrise-init/scanners/ionic"
-	"github.com/bitrise-io/bitrise-init/scanners/ios"
-	"github.com/bitrise-io/bitrise-init/scanners/java"
-	"github.com/bitrise-io/bitrise-init/scanners/kmp"
-	"github.com/bitrise-io/bitrise-init/scanners/macos"
-	"github.com/bitrise-io/bitrise-init/scanners/nodejs"
-	"github.com/bitrise-io/bitrise-init/scanners/python"
-	"github.com/bitrise-io/bitrise-init/scanners/reactnative"
-	"github.com/bitrise-io/bitrise-init/scanners/ruby"
 	"github.com/bitrise-io/bitrise-init/steps"
-	"gopkg.in/yaml.v2"
 )
 
 // ScannerInterface ...
@@ -72,29 +62,42 @@ type AutomationToolScanner interface {
 	SetDetectedProjectTypes(projectTypes []string)
 }
 
-// ProjectScanners ...
+// ContextScanner contains additional methods (relative to ScannerInterface)
+// implemented by scanners, which support cancellation of their long-running steps.
+// If a scanner implements this interface, these methods are called instead of DetectPlatform and Options.
+type ContextScanner interface {
+	// DetectPlatformWithContext is the context-aware variant of DetectPlatform.
+	// The scanner should stop as soon as possible and return ctx.Err() when ctx is done.
+	DetectPlatformWithContext(ctx context.Context, searchDir string) (bool, error)
+
+	// OptionsWithContext is the context-aware variant of Options.
+	OptionsWithContext(ctx context.Context) (models.OptionNode, models.Warnings, models.Icons, error)
+}
+
+// FileIndexScanner contains additional methods (relative to ScannerInterface)
+// implemented by scanners, which look up the project files in a shared file index instead of walking the search dir.
+type FileIndexScanner interface {
+	// SetFileIndex sets the index of the search dir, built once per scan.
+	// The scanner falls back to walking the search dir if the index is not set.
+	SetFileIndex(index *fileindex.Index)
+}
+
+// LoggerScanner contains additional methods (relative to ScannerInterface)
+// implemented by scanners, which log through the logger of the scan instead of the go-utils log package.
+// The scanners run in parallel, the logs written through the logger are printed in the order of the scanners.
+type LoggerScanner interface {
+	// SetLogger sets the logger of the scanner, the scanner logs with the go-utils log package if it is not set.
+	SetLogger(logger *logger.Logger)
+}
+
+// ProjectScanners returns new instances of the registered project scanners, in priority order
 func ProjectScanners() []ScannerInterface {
-	return []ScannerInterface{
-		kmp.NewScanner(),
-		reactnative.NewScanner(),
-		flutter.NewScanner(),
-		ionic.NewScanner(),
-		cordova.NewScanner(),
-		ios.NewScanner(),
-		macos.NewScanner(),
-		android.NewScanner(),
-		nodejs.NewScanner(),
-		java.NewScanner(),
-		ruby.NewScanner(),
-		python.NewScanner(),
-	}
+	return defaultRegistry.Scanners(ProjectScannerType)
 }
 
-// AutomationToolScanners returns active automation tool scanners
+// AutomationToolScanners returns new instances of the registered automation tool scanners, in priority order
 func AutomationToolScanners() []ScannerInterface {
-	return []ScannerInterface{
-		fastlane.NewScanner(),
-	}
+	return defaultRegistry.Scanners(AutomationToolScannerType)
 }
 
 // CustomProjectType ...
@@ -105,23 +108,20 @@ const CustomConfigName = "other-config"
 
 // CustomConfig ...
 func CustomConfig() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder := steps.NewConfigBuilder()
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(steps.PrepareListParams{
 		SSHKeyActivation: models.SSHKeyActivationConditional,
 	})...)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList()...)
+	configBuilder.AddPullRequestTriggerToWorkflow(models.PrimaryWorkflowID)
+	configBuilder.AddPushTriggerToWorkflow(models.PrimaryWorkflowID)
 
 	config, err := configBuilder.Generate(CustomProjectType)
 	if err != nil {
 		return models.BitriseConfigMap{}, err
 	}
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
-
 	return models.BitriseConfigMap{
-		CustomConfigName: string(data),
+		CustomConfigName: config,
 	}, nil
 }
@@ -0,0 +1,43 @@
+// Package scannertest contains test helpers for scanner authors.
+package scannertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/models"
+)
+
+// DefaultsProvider is the part of scanners.ScannerInterface providing the default options and configs.
+type DefaultsProvider interface {
+	Name() string
+	DefaultOptions() models.OptionNode
+	DefaultConfigs() (models.BitriseConfigMap, error)
+}
+
+// CheckOptionTree fails the test if the option tree doesn't match the configs (see models.CheckOptionTree),
+// the warnings of the check fail the test too.
+func CheckOptionTree(t testing.TB, options models.OptionNode, configs models.BitriseConfigMap) {
+	t.Helper()
+	if issues := optionTreeIssues(options, configs); len(issues) > 0 {
+		t.Errorf("the option tree doesn't match the configs:\n%s", strings.Join(issues, "\n"))
+	}
+}
+
+// CheckDefaults fails the test if the default option tree of the scanner doesn't match its default configs.
+func CheckDefaults(t testing.TB, scanner DefaultsProvider) {
+	t.Helper()
+	configs, err := scanner.DefaultConfigs()
+	if err != nil {
+		t.Errorf("%s: failed to generate the default configs: %s", scanner.Name(), err)
+		return
+	}
+	if issues := optionTreeIssues(scanner.DefaultOptions(), configs); len(issues) > 0 {
+		t.Errorf("%s: the default option tree doesn't match the default configs:\n%s", scanner.Name(), strings.Join(issues, "\n"))
+	}
+}
+
+func optionTreeIssues(options models.OptionNode, configs models.BitriseConfigMap) []string {
+	errs, warnings := models.CheckOptionTree(options, configs)
+	return append(errs, warnings...)
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errArchiveTooLarge = errors.New("the extracted archive exceeds the size limit")
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// extractor extracts archives into dir, the size of the extracted files is limited to remaining bytes.
+type extractor struct {
+	dir       string
+	remaining int64
+}
+
+// extractZip extracts the zip archive into dir.
+// Only regular files and directories are extracted, entries pointing outside of dir are rejected.
+// The gzipped tar archives are not extracted, they are read into memory by snapshot.Tarball.
+func extractZip(archivePth, dir string, maxSize int64) error {
+	e := &extractor{dir: dir, remaining: maxSize}
+	return e.extractZip(archivePth)
+}
+
+func (e *extractor) extractZip(archivePth string) error {
+	reader, err := zip.OpenReader(archivePth)
+	if err != nil {
+		return fmt.Errorf("invalid zip archive: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	for _, file := range reader.File {
+		mode := file.Mode()
+		if !mode.IsDir() && !mode.IsRegular() {
+			continue
+		}
+
+		pth, err := e.entryPath(file.Name)
+		if err != nil {
+			return err
+		}
+		if mode.IsDir() {
+			if err := os.MkdirAll(pth, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", file.Name, err)
+		}
+		err = e.writeFile(pth, content, mode)
+		_ = content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryPath returns the path of the archive entry in dir, or an error if the entry points outside of dir.
+func (e *extractor) entryPath(name string) (string, error) {
+	pth := filepath.Join(e.dir, filepath.FromSlash(name))
+	if pth != e.dir && !strings.HasPrefix(pth, e.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid archive entry: %s", name)
+	}
+	return pth, nil
+}
+
+func (e *extractor) writeFile(pth string, content io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(pth, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(file, io.LimitReader(content, e.remaining+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", pth, err)
+	}
+
+	e.remaining -= written
+	if e.remaining < 0 {
+		return errArchiveTooLarge
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+// Package server serves the project scanner over HTTP with a JSON API:
+//
+//   - POST /scan scans an uploaded zip or gzipped tar archive (request body), or a server-local directory
+//     (application/json request body: {"path": "...", "has_ssh_key": true}), and returns the models.ScanResultModel.
+//   - POST /resolve resolves the answers of a scan result (request body: {"scan_result": {...}, "answers": {...}}),
+//     and returns the generated bitrise.yml: {"bitrise_yml": "..."}.
+//   - POST /upgrade-steps checks the step versions of a bitrise.yml (request body: {"bitrise_yml": "..."}),
+//     and returns the outdated and deprecated steps with the upgraded bitrise.yml: {"steps": [...], "bitrise_yml": "..."}.
+//   - GET /manual-config returns the default configs of every scanner (scanner.ManualConfig).
+//
+// Failed requests return an error object: {"error": "..."}.
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanner"
+	"github.com/bitrise-io/bitrise-init/snapshot"
+	"github.com/bitrise-io/bitrise-init/steps"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+// Default options
+const (
+	DefaultMaxConcurrentScans = 4
+	DefaultMaxUploadSize      = 100 << 20
+	DefaultMaxExtractedSize   = 1 << 30
+	DefaultScannerTimeout     = 5 * time.Minute
+)
+
+// Options configures the server.
+type Options struct {
+	// LocalRoot enables scanning server-local directories inside LocalRoot, local paths are rejected if it is empty.
+	LocalRoot string
+	// TempDir is the parent of the request-scoped temp dirs, os.TempDir() is used if empty.
+	TempDir string
+	// MaxConcurrentScans limits the scans running at the same time, further scan requests are rejected.
+	MaxConcurrentScans int
+	// MaxUploadSize limits the size of the uploaded archives in bytes.
+	MaxUploadSize int64
+	// MaxExtractedSize limits the size of the extracted archives in bytes.
+	MaxExtractedSize int64
+	// ScannerTimeout limits the time a single scanner can spend on a scan (see scanner.ConfigOptions).
+	ScannerTimeout time.Duration
+}
+
+// Server handles the scan API requests.
+type Server struct {
+	opts     Options
+	scanSlot chan struct{}
+	mux      *http.ServeMux
+
+	// scanFn and scanFSFn run the scan of a directory and of an in-memory archive, replaced in tests
+	scanFn   func(ctx context.Context, searchDir string, opts scanner.ConfigOptions) models.ScanResultModel
+	scanFSFn func(ctx context.Context, fsys fs.FS, opts scanner.ConfigOptions) models.ScanResultModel
+
+	manualConfigOnce sync.Once
+	manualConfig     models.ScanResultModel
+	manualConfigErr  error
+}
+
+// New returns a server, zero options are replaced with the default values.
+func New(opts Options) (*Server, error) {
+	if opts.MaxConcurrentScans <= 0 {
+		opts.MaxConcurrentScans = DefaultMaxConcurrentScans
+	}
+	if opts.MaxUploadSize <= 0 {
+		opts.MaxUploadSize = DefaultMaxUploadSize
+	}
+	if opts.MaxExtractedSize <= 0 {
+		opts.MaxExtractedSize = DefaultMaxExtractedSize
+	}
+	if opts.ScannerTimeout <= 0 {
+		opts.ScannerTimeout = DefaultScannerTimeout
+	}
+	if opts.LocalRoot != "" {
+		localRoot, err := filepath.Abs(opts.LocalRoot)
+		if err == nil {
+			localRoot, err = filepath.EvalSymlinks(localRoot)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid local root (%s): %w", opts.LocalRoot, err)
+		}
+		opts.LocalRoot = localRoot
+	}
+
+	s := &Server{
+		opts:     opts,
+		scanSlot: make(chan struct{}, opts.MaxConcurrentScans),
+		mux:      http.NewServeMux(),
+		scanFn:   scanner.ConfigWithContext,
+		scanFSFn: scanner.ConfigFS,
+	}
+	s.mux.HandleFunc("POST /scan", s.handleScan)
+	s.mux.HandleFunc("POST /resolve", s.handleResolve)
+	s.mux.HandleFunc("POST /upgrade-steps", s.handleUpgradeSteps)
+	s.mux.HandleFunc("GET /manual-config", s.handleManualConfig)
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type scanRequest struct {
+	Path      string `json:"path"`
+	HasSSHKey bool   `json:"has_ssh_key"`
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	select {
+	case s.scanSlot <- struct{}{}:
+		defer func() { <-s.scanSlot }()
+	default:
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusServiceUnavailable, errors.New("too many concurrent scans"))
+		return
+	}
+
+	opts := scanner.ConfigOptions{ScannerTimeout: s.opts.ScannerTimeout}
+	if isJSONRequest(r) {
+		var request scanRequest
+		if err := decodeJSON(http.MaxBytesReader(w, r.Body, s.opts.MaxUploadSize), &request); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		searchDir, err := s.localPath(request.Path)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		opts.HasSSHKey = request.HasSSHKey
+
+		writeScanResult(w, s.scanFn(r.Context(), searchDir, opts))
+		return
+	}
+
+	opts.HasSSHKey = r.URL.Query().Get("has_ssh_key") == "true"
+	upload := bufio.NewReader(http.MaxBytesReader(w, r.Body, s.opts.MaxUploadSize))
+	header, err := upload.Peek(len(zipMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		status, err := s.uploadError(err)
+		writeError(w, status, err)
+		return
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		// the tar archive is read into memory, the scanners don't need it on the disk
+		fsys, err := snapshot.Tarball(upload, s.opts.MaxExtractedSize)
+		if err != nil {
+			status, err := s.uploadError(err)
+			writeError(w, status, err)
+			return
+		}
+		writeScanResult(w, s.scanFSFn(r.Context(), fsys, opts))
+	case bytes.HasPrefix(header, zipMagic):
+		s.scanZip(w, r, upload, opts)
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("unsupported archive format, upload a zip or a gzipped tar archive"))
+	}
+}
+
+// scanZip extracts the uploaded zip archive into a request-scoped temp dir and scans it.
+// The temp dir is removed when every scanner has returned, the timed out scanners may still read it after the response is written.
+func (s *Server) scanZip(w http.ResponseWriter, r *http.Request, upload io.Reader, opts scanner.ConfigOptions) {
+	tempDir, err := os.MkdirTemp(s.opts.TempDir, "bitrise-init-scan-")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to create temp dir: %w", err))
+		return
+	}
+	var runningScanners sync.WaitGroup
+	defer func() {
+		go func() {
+			runningScanners.Wait()
+			if err := os.RemoveAll(tempDir); err != nil {
+				log.Warnf("Failed to remove temp dir (%s): %s", tempDir, err)
+			}
+		}()
+	}()
+
+	searchDir := filepath.Join(tempDir, "repository")
+	if status, err := s.extractUpload(upload, tempDir, searchDir); err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	opts.RunningScanners = &runningScanners
+	writeScanResult(w, s.scanFn(r.Context(), searchDir, opts))
+}
+
+// localPath returns the absolute path of the server-local directory, it has to be inside the local root.
+func (s *Server) localPath(pth string) (string, error) {
+	if s.opts.LocalRoot == "" {
+		return "", errors.New("scanning local paths is disabled")
+	}
+	if pth == "" {
+		return "", errors.New("path not provided")
+	}
+
+	if !filepath.IsAbs(pth) {
+		pth = filepath.Join(s.opts.LocalRoot, pth)
+	}
+	// The symlinks are resolved, so that a link inside the local root can't point outside of it.
+	resolved, err := filepath.EvalSymlinks(pth)
+	if err != nil {
+		return "", fmt.Errorf("path (%s) is not a directory", pth)
+	}
+	pth = resolved
+
+	rootPrefix := s.opts.LocalRoot
+	if !strings.HasSuffix(rootPrefix, string(filepath.Separator)) {
+		rootPrefix += string(filepath.Separator)
+	}
+	if pth != s.opts.LocalRoot && !strings.HasPrefix(pth, rootPrefix) {
+		return "", fmt.Errorf("path (%s) is outside of the local root", pth)
+	}
+
+	if info, err := os.Stat(pth); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("path (%s) is not a directory", pth)
+	}
+	return pth, nil
+}
+
+// extractUpload saves the uploaded zip archive into tempDir and extracts it into dir,
+// returns the response status code on failure.
+func (s *Server) extractUpload(upload io.Reader, tempDir, dir string) (int, error) {
+	archivePth := filepath.Join(tempDir, "upload.zip")
+	archive, err := os.Create(archivePth)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	_, err = io.Copy(archive, upload)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return s.uploadError(fmt.Errorf("failed to read the uploaded archive: %w", err))
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if err := extractZip(archivePth, dir, s.opts.MaxExtractedSize); err != nil {
+		return s.uploadError(err)
+	}
+	return http.StatusOK, nil
+}
+
+// uploadError returns the response status code and the error of a failed upload.
+func (s *Server) uploadError(err error) (int, error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge, fmt.Errorf("the uploaded archive exceeds the size limit (%d bytes)", s.opts.MaxUploadSize)
+	case errors.Is(err, errArchiveTooLarge), errors.Is(err, snapshot.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge, errArchiveTooLarge
+	default:
+		return http.StatusBadRequest, err
+	}
+}
+
+type resolveRequest struct {
+	ScanResult output.ScanResult `json:"scan_result"`
+	Answers    scanner.Answers   `json:"answers"`
+}
+
+type resolveResponse struct {
+	BitriseYML string `json:"bitrise_yml"`
+}
+
+type errorResponse struct {
+	Error  string               `json:"error"`
+	Answer *scanner.AnswerError `json:"answer,omitempty"`
+}
+
+func (s *Server) handleResolve(w http.ResponseWriter, r *http.Request) {
+	var request resolveRequest
+	if err := decodeJSON(http.MaxBytesReader(w, r.Body, s.opts.MaxUploadSize), &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	scanResult, err := request.ScanResult.ScanResultModel()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	config, err := scanner.ResolveConfig(scanResult, request.Answers)
+	if err != nil {
+		response := errorResponse{Error: err.Error()}
+		if errors.As(err, &response.Answer) {
+			writeJSON(w, http.StatusUnprocessableEntity, response)
+		} else {
+			writeJSON(w, http.StatusBadRequest, response)
+		}
+		return
+	}
+
+	content, err := models.MarshalConfig(config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to marshal config: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, resolveResponse{BitriseYML: string(content)})
+}
+
+type upgradeStepsRequest struct {
+	BitriseYML string `json:"bitrise_yml"`
+}
+
+type upgradeStepsResponse struct {
+	Steps      []steps.StepAdvice `json:"steps"`
+	BitriseYML string             `json:"bitrise_yml"`
+}
+
+func (s *Server) handleUpgradeSteps(w http.ResponseWriter, r *http.Request) {
+	var request upgradeStepsRequest
+	if err := decodeJSON(http.MaxBytesReader(w, r.Body, s.opts.MaxUploadSize), &request); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	upgraded, advices, err := steps.UpgradeSteps([]byte(request.BitriseYML))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if advices == nil {
+		advices = []steps.StepAdvice{}
+	}
+	writeJSON(w, http.StatusOK, upgradeStepsResponse{Steps: advices, BitriseYML: string(upgraded)})
+}
+
+func (s *Server) handleManualConfig(w http.ResponseWriter, _ *http.Request) {
+	s.manualConfigOnce.Do(func() {
+		s.manualConfig, s.manualConfigErr = scanner.ManualConfig()
+	})
+	if s.manualConfigErr != nil {
+		writeError(w, http.StatusInternalServerError, s.manualConfigErr)
+		return
+	}
+	writeScanResult(w, s.manualConfig)
+}
+
+// writeScanResult writes the scan result in its serialized form (output.ScanResult).
+func writeScanResult(w http.ResponseWriter, result models.ScanResultModel) {
+	serialized, err := output.NewScanResult(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to serialize scan result: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, serialized)
+}
+
+func isJSONRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+func decodeJSON(r io.Reader, v interface{}) error {
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warnf("Failed to write response: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
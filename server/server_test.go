@@ -0,0 +1,323 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bitrise-io/bitrise-init/models"
+	"github.com/bitrise-io/bitrise-init/output"
+	"github.com/bitrise-io/bitrise-init/scanner"
+	"github.com/bitrise-io/bitrise-init/steps"
+	bitriseModels "github.com/bitrise-io/bitrise/v2/models"
+)
+
+var nodeJSProjectFiles = map[string]string{
+	"app/package.json":      `{"name": "app", "scripts": {"test": "jest"}}`,
+	"app/package-lock.json": `{}`,
+}
+
+func newZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func newTarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	writer := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func newTestServer(t *testing.T, opts Options) (*Server, *httptest.Server) {
+	s, err := New(opts)
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(s)
+	t.Cleanup(httpServer.Close)
+	return s, httpServer
+}
+
+// post sends the request and decodes the JSON response into out, returns the status code.
+func post(t *testing.T, url, contentType string, body []byte, out interface{}) int {
+	resp, err := http.Post(url, contentType, bytes.NewReader(body))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	return resp.StatusCode
+}
+
+func TestScan_upload(t *testing.T) {
+	tempDir := t.TempDir()
+	_, httpServer := newTestServer(t, Options{TempDir: tempDir})
+
+	for name, archive := range map[string][]byte{
+		"zip":    newZip(t, nodeJSProjectFiles),
+		"tar.gz": newTarGz(t, nodeJSProjectFiles),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var result output.ScanResult
+			status := post(t, httpServer.URL+"/scan", "application/octet-stream", archive, &result)
+			require.Equal(t, http.StatusOK, status)
+			require.Contains(t, result.Configs["node-js"], "node-js-npm-test-config")
+
+			// the request-scoped temp dir is removed
+			require.Eventually(t, func() bool {
+				entries, err := os.ReadDir(tempDir)
+				return err == nil && len(entries) == 0
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestScan_uploadKeptForRunningScanners(t *testing.T) {
+	tempDir := t.TempDir()
+	s, httpServer := newTestServer(t, Options{TempDir: tempDir, ScannerTimeout: time.Minute})
+
+	release := make(chan struct{})
+	s.scanFn = func(_ context.Context, searchDir string, opts scanner.ConfigOptions) models.ScanResultModel {
+		require.Equal(t, time.Minute, opts.ScannerTimeout)
+		// a timed out scanner left running in the background
+		opts.RunningScanners.Add(1)
+		go func() {
+			defer opts.RunningScanners.Done()
+			<-release
+		}()
+		return models.ScanResultModel{}
+	}
+
+	var result output.ScanResult
+	status := post(t, httpServer.URL+"/scan", "application/octet-stream", newZip(t, nodeJSProjectFiles), &result)
+	require.Equal(t, http.StatusOK, status)
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		entries, err := os.ReadDir(tempDir)
+		return err == nil && len(entries) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestScan_invalidUpload(t *testing.T) {
+	_, httpServer := newTestServer(t, Options{MaxUploadSize: 1024, MaxExtractedSize: 100})
+
+	tests := []struct {
+		name       string
+		archive    []byte
+		wantStatus int
+		wantErr    string
+	}{
+		{
+			name:       "unsupported format",
+			archive:    []byte("plain text"),
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "unsupported archive format, upload a zip or a gzipped tar archive",
+		},
+		{
+			name:       "entry outside of the extract dir",
+			archive:    newZip(t, map[string]string{"../evil.sh": "rm -rf /"}),
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid archive entry: ../evil.sh",
+		},
+		{
+			name:       "upload too large",
+			archive:    append([]byte("PK\x03\x04"), bytes.Repeat([]byte{0}, 2048)...),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantErr:    "the uploaded archive exceeds the size limit (1024 bytes)",
+		},
+		{
+			name:       "extracted archive too large",
+			archive:    newTarGz(t, map[string]string{"large.txt": strings.Repeat("a", 200)}),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantErr:    "the extracted archive exceeds the size limit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response errorResponse
+			status := post(t, httpServer.URL+"/scan", "application/zip", tt.archive, &response)
+			require.Equal(t, tt.wantStatus, status)
+			require.Equal(t, tt.wantErr, response.Error)
+		})
+	}
+}
+
+func TestScan_localPath(t *testing.T) {
+	localRoot := t.TempDir()
+	for pth, content := range nodeJSProjectFiles {
+		require.NoError(t, os.MkdirAll(filepath.Join(localRoot, filepath.Dir(pth)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(localRoot, pth), []byte(content), 0644))
+	}
+
+	_, httpServer := newTestServer(t, Options{LocalRoot: localRoot})
+
+	var result output.ScanResult
+	status := post(t, httpServer.URL+"/scan", "application/json", []byte(`{"path": "app"}`), &result)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, result.Configs["node-js"], "node-js-npm-root-test-config")
+
+	var response errorResponse
+	status = post(t, httpServer.URL+"/scan", "application/json", []byte(`{"path": "../"}`), &response)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, response.Error, "is outside of the local root")
+
+	_, disabledServer := newTestServer(t, Options{})
+	status = post(t, disabledServer.URL+"/scan", "application/json", []byte(`{"path": "app"}`), &response)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "scanning local paths is disabled", response.Error)
+}
+
+func TestScan_localPathSymlinks(t *testing.T) {
+	parentDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	localRoot := filepath.Join(parentDir, "root")
+	require.NoError(t, os.MkdirAll(filepath.Join(localRoot, "app"), 0755))
+	// a sibling sharing the prefix of the local root
+	require.NoError(t, os.MkdirAll(filepath.Join(parentDir, "root-other"), 0755))
+	require.NoError(t, os.Symlink(filepath.Join(parentDir, "root-other"), filepath.Join(localRoot, "outside")))
+	require.NoError(t, os.Symlink(filepath.Join(localRoot, "app"), filepath.Join(localRoot, "inside")))
+
+	// the local root itself is a symlink
+	linkedRoot := filepath.Join(parentDir, "linked-root")
+	require.NoError(t, os.Symlink(localRoot, linkedRoot))
+
+	s, err := New(Options{LocalRoot: linkedRoot})
+	require.NoError(t, err)
+
+	pth, err := s.localPath("inside")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(localRoot, "app"), pth)
+
+	pth, err = s.localPath(filepath.Join(linkedRoot, "app"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(localRoot, "app"), pth)
+
+	_, err = s.localPath("outside")
+	require.EqualError(t, err, fmt.Sprintf("path (%s) is outside of the local root", filepath.Join(parentDir, "root-other")))
+
+	_, err = s.localPath("../root-other")
+	require.ErrorContains(t, err, "is outside of the local root")
+}
+
+func TestScan_concurrencyLimit(t *testing.T) {
+	s, httpServer := newTestServer(t, Options{LocalRoot: t.TempDir(), MaxConcurrentScans: 1})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.scanFn = func(context.Context, string, scanner.ConfigOptions) models.ScanResultModel {
+		close(started)
+		<-release
+		return models.ScanResultModel{}
+	}
+
+	done := make(chan int)
+	go func() {
+		resp, err := http.Post(httpServer.URL+"/scan", "application/json", strings.NewReader(`{"path": "."}`))
+		if err != nil {
+			done <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+	<-started
+
+	var response errorResponse
+	status := post(t, httpServer.URL+"/scan", "application/json", []byte(`{"path": "."}`), &response)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, "too many concurrent scans", response.Error)
+
+	close(release)
+	require.Equal(t, http.StatusOK, <-done)
+}
+
+func TestResolve(t *testing.T) {
+	_, httpServer := newTestServer(t, Options{})
+
+	option := models.NewOption("Project Directory", "", "PROJECT_DIR", models.TypeSelector)
+	option.AddConfig("app", models.NewConfigOption("app-config", nil))
+	scanResult, err := output.NewScanResult(models.ScanResultModel{
+		ScannerToOptionRoot:       map[string]models.OptionNode{"node-js": *option},
+		ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{"node-js": {"app-config": models.NewConfig(bitriseModels.BitriseDataModel{FormatVersion: "13"})}},
+	})
+	require.NoError(t, err)
+
+	body, err := json.Marshal(resolveRequest{ScanResult: scanResult, Answers: scanner.Answers{"PROJECT_DIR": "app"}})
+	require.NoError(t, err)
+	var response resolveResponse
+	require.Equal(t, http.StatusOK, post(t, httpServer.URL+"/resolve", "application/json", body, &response))
+	require.Contains(t, response.BitriseYML, "PROJECT_DIR: app")
+
+	body, err = json.Marshal(resolveRequest{ScanResult: scanResult, Answers: scanner.Answers{"PROJECT_DIR": "web"}})
+	require.NoError(t, err)
+	var errResponse errorResponse
+	require.Equal(t, http.StatusUnprocessableEntity, post(t, httpServer.URL+"/resolve", "application/json", body, &errResponse))
+	require.Equal(t, &scanner.AnswerError{Title: "Project Directory", EnvKey: "PROJECT_DIR", Value: "web", AvailableValues: []string{"app"}}, errResponse.Answer)
+}
+
+func TestUpgradeSteps(t *testing.T) {
+	_, httpServer := newTestServer(t, Options{})
+
+	body, err := json.Marshal(upgradeStepsRequest{BitriseYML: "workflows:\n  primary:\n    steps:\n    - git-clone@6: {}\n"})
+	require.NoError(t, err)
+	var response upgradeStepsResponse
+	require.Equal(t, http.StatusOK, post(t, httpServer.URL+"/upgrade-steps", "application/json", body, &response))
+	require.Equal(t, []steps.StepAdvice{{Location: "workflows.primary", Line: 4, Step: "git-clone@6", UpgradeTo: "git-clone@" + steps.GitCloneVersion}}, response.Steps)
+	require.Equal(t, "workflows:\n  primary:\n    steps:\n    - git-clone@"+steps.GitCloneVersion+": {}\n", response.BitriseYML)
+
+	body, err = json.Marshal(upgradeStepsRequest{BitriseYML: "workflows: ["})
+	require.NoError(t, err)
+	var errResponse errorResponse
+	require.Equal(t, http.StatusBadRequest, post(t, httpServer.URL+"/upgrade-steps", "application/json", body, &errResponse))
+	require.Contains(t, errResponse.Error, "failed to parse config")
+}
+
+func TestManualConfig(t *testing.T) {
+	_, httpServer := newTestServer(t, Options{})
+
+	resp, err := http.Get(httpServer.URL + "/manual-config")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result output.ScanResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Contains(t, result.Options, "android")
+
+	resp, err = http.Get(httpServer.URL + "/scan")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
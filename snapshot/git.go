@@ -0,0 +1,73 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GitTree returns the tree of the commit in the git repository at repoDir (a bare repository or a work tree) as an fs.FS.
+// commit can be any revision resolving to a commit (a commit hash, a branch or a tag).
+// The tree is listed when GitTree is called, the file contents are read from the repository on first read.
+// Symlinks and submodules are not part of the fs.FS.
+func GitTree(repoDir, commit string) (fs.FS, error) {
+	if strings.HasPrefix(commit, "-") {
+		return nil, fmt.Errorf("invalid commit: %s", commit)
+	}
+
+	out, err := git(repoDir, "ls-tree", "-r", "-l", "-z", "--full-tree", commit+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the tree of %s: %w", commit, err)
+	}
+
+	fsys := newTreeFS()
+	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		if line == "" {
+			continue
+		}
+
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		meta, pth, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, fmt.Errorf("unexpected ls-tree output: %s", line)
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected ls-tree output: %s", line)
+		}
+		mode, objectType, object, sizeStr := fields[0], fields[1], fields[2], fields[3]
+		if objectType != "blob" || (mode != "100644" && mode != "100755") {
+			continue
+		}
+		if !fs.ValidPath(pth) {
+			return nil, fmt.Errorf("invalid path in the tree of %s: %s", commit, pth)
+		}
+
+		size, err := strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected ls-tree output: %s", line)
+		}
+		perm := fs.FileMode(0644)
+		if mode == "100755" {
+			perm = 0755
+		}
+
+		fsys.addFile(pth, perm, size, func() ([]byte, error) {
+			return git(repoDir, "cat-file", "blob", object)
+		})
+	}
+	return fsys, nil
+}
+
+// git runs the git command in repoDir and returns its output, the error contains the stderr of the command.
+func git(repoDir string, args ...string) ([]byte, error) {
+	out, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return out, err
+}
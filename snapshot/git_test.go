@@ -0,0 +1,77 @@
+package snapshot
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newBareRepo commits the files to a new repository, and returns the path of its bare clone and the commit hash.
+func newBareRepo(t *testing.T, files map[string]string) (string, string) {
+	workDir := filepath.Join(t.TempDir(), "work")
+	bareDir := filepath.Join(t.TempDir(), "repo.git")
+
+	runGit := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	runGit("init", "-q", workDir)
+	for pth, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(workDir, filepath.Dir(pth)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, pth), []byte(content), 0644))
+	}
+	require.NoError(t, os.Chmod(filepath.Join(workDir, "android/gradlew"), 0755))
+	require.NoError(t, os.Symlink("package.json", filepath.Join(workDir, "web/link.json")))
+	runGit("-C", workDir, "add", "-A")
+	runGit("-C", workDir, "commit", "-q", "-m", "initial")
+	commit := runGit("-C", workDir, "rev-parse", "HEAD")
+
+	// the work tree is changed after the commit
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "web/package.json"), []byte("{}"), 0644))
+	runGit("-C", workDir, "commit", "-q", "-a", "-m", "update")
+
+	runGit("clone", "-q", "--bare", workDir, bareDir)
+	return bareDir, commit
+}
+
+func TestGitTree(t *testing.T) {
+	files := map[string]string{
+		"web/package.json":      `{"name": "web"}`,
+		"web/package-lock.json": `{}`,
+		"android/gradlew":       "#!/bin/sh\n",
+		"README.md":             "# app\n",
+	}
+	repoDir, commit := newBareRepo(t, files)
+
+	fsys, err := GitTree(repoDir, commit)
+	require.NoError(t, err)
+	require.NoError(t, fstest.TestFS(fsys, "web/package.json", "web/package-lock.json", "android/gradlew", "README.md"))
+
+	for pth, content := range files {
+		data, err := fs.ReadFile(fsys, pth)
+		require.NoError(t, err)
+		require.Equal(t, content, string(data))
+	}
+
+	info, err := fs.Stat(fsys, "android/gradlew")
+	require.NoError(t, err)
+	require.Equal(t, fs.FileMode(0755), info.Mode())
+
+	// symlinks are not part of the tree
+	_, err = fs.Stat(fsys, "web/link.json")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = GitTree(repoDir, "0000000000000000000000000000000000000000")
+	require.Error(t, err)
+	_, err = GitTree(repoDir, "--output=/tmp/x")
+	require.ErrorContains(t, err, "invalid commit")
+}
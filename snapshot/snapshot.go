@@ -0,0 +1,154 @@
+// Package snapshot provides read-only fs.FS implementations of directory tree snapshots (a git commit or a tarball),
+// to scan a repository without checking it out (see scanner.ConfigFS).
+package snapshot
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// treeFS is a read-only fs.FS of a directory tree snapshot, the file contents are loaded on first read.
+// Only regular files and directories are part of the snapshot.
+type treeFS struct {
+	entries map[string]*treeEntry
+}
+
+type treeEntry struct {
+	name     string
+	mode     fs.FileMode
+	size     int64
+	children []string
+	content  func() ([]byte, error)
+}
+
+func newTreeFS() *treeFS {
+	return &treeFS{entries: map[string]*treeEntry{".": {name: ".", mode: fs.ModeDir | 0755}}}
+}
+
+// addDir adds the directory and its missing parent directories.
+func (fsys *treeFS) addDir(name string) {
+	if _, ok := fsys.entries[name]; ok {
+		return
+	}
+
+	parent := path.Dir(name)
+	fsys.addDir(parent)
+	fsys.entries[parent].children = append(fsys.entries[parent].children, name)
+	fsys.entries[name] = &treeEntry{name: path.Base(name), mode: fs.ModeDir | 0755}
+}
+
+// addFile adds the regular file and its missing parent directories, content is called at most once.
+func (fsys *treeFS) addFile(name string, perm fs.FileMode, size int64, content func() ([]byte, error)) {
+	if _, ok := fsys.entries[name]; ok {
+		return
+	}
+
+	parent := path.Dir(name)
+	fsys.addDir(parent)
+	fsys.entries[parent].children = append(fsys.entries[parent].children, name)
+	fsys.entries[name] = &treeEntry{name: path.Base(name), mode: perm.Perm(), size: size, content: sync.OnceValues(content)}
+}
+
+// Open implements fs.FS.
+func (fsys *treeFS) Open(name string) (fs.File, error) {
+	entry, err := fsys.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.mode.IsDir() {
+		return &treeDir{entry: entry, children: fsys.dirEntries(entry)}, nil
+	}
+
+	content, err := entry.content()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &treeFile{entry: entry, Reader: bytes.NewReader(content)}, nil
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (fsys *treeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entry, err := fsys.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return fsys.dirEntries(entry), nil
+}
+
+// Stat implements fs.StatFS.
+func (fsys *treeFS) Stat(name string) (fs.FileInfo, error) {
+	return fsys.lookup("stat", name)
+}
+
+func (fsys *treeFS) lookup(op, name string) (*treeEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	entry, ok := fsys.entries[name]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return entry, nil
+}
+
+// dirEntries returns the children of the directory sorted by name.
+func (fsys *treeFS) dirEntries(dir *treeEntry) []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(dir.children))
+	for _, child := range dir.children {
+		entries = append(entries, fs.FileInfoToDirEntry(fsys.entries[child]))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries
+}
+
+// treeEntry implements fs.FileInfo.
+func (entry *treeEntry) Name() string       { return entry.name }
+func (entry *treeEntry) Size() int64        { return entry.size }
+func (entry *treeEntry) Mode() fs.FileMode  { return entry.mode }
+func (entry *treeEntry) ModTime() time.Time { return time.Time{} }
+func (entry *treeEntry) IsDir() bool        { return entry.mode.IsDir() }
+func (entry *treeEntry) Sys() any           { return nil }
+
+type treeFile struct {
+	entry *treeEntry
+	*bytes.Reader
+}
+
+func (file *treeFile) Stat() (fs.FileInfo, error) { return file.entry, nil }
+func (file *treeFile) Close() error               { return nil }
+
+type treeDir struct {
+	entry    *treeEntry
+	children []fs.DirEntry
+	offset   int
+}
+
+func (dir *treeDir) Stat() (fs.FileInfo, error) { return dir.entry, nil }
+func (dir *treeDir) Close() error               { return nil }
+
+func (dir *treeDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: dir.entry.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (dir *treeDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := dir.children[dir.offset:]
+	if n > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > 0 && n < len(remaining) {
+		remaining = remaining[:n]
+	}
+	dir.offset += len(remaining)
+	return remaining, nil
+}
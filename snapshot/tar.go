@@ -0,0 +1,79 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ErrTooLarge is returned by Tarball if the file contents of the archive exceed the size limit.
+var ErrTooLarge = errors.New("the archive exceeds the size limit")
+
+// Tarball reads the tar archive (optionally gzip compressed) into an in-memory fs.FS, without extracting it to the disk.
+// The size of the read file contents is limited to maxSize bytes.
+// Only regular files and directories are part of the fs.FS, entries pointing outside of the archive root are rejected.
+func Tarball(r io.Reader, maxSize int64) (fs.FS, error) {
+	bufReader := bufio.NewReader(r)
+	header, err := bufReader.Peek(len(gzipMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	var reader io.Reader = bufReader
+	if bytes.Equal(header, gzipMagic) {
+		gzipReader, err := gzip.NewReader(bufReader)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip archive: %w", err)
+		}
+		defer func() { _ = gzipReader.Close() }()
+		reader = gzipReader
+	}
+
+	fsys := newTreeFS()
+	remaining := maxSize
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return fsys, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid tar archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeDir && header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if !fs.ValidPath(name) {
+			return nil, fmt.Errorf("invalid archive entry: %s", header.Name)
+		}
+		if name == "." {
+			continue
+		}
+		if header.Typeflag == tar.TypeDir {
+			fsys.addDir(name)
+			continue
+		}
+
+		content, err := io.ReadAll(io.LimitReader(tarReader, remaining+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		remaining -= int64(len(content))
+		if remaining < 0 {
+			return nil, ErrTooLarge
+		}
+
+		fsys.addFile(name, header.FileInfo().Mode(), int64(len(content)), func() ([]byte, error) { return content, nil })
+	}
+}
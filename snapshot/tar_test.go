@@ -0,0 +1,82 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTar(t *testing.T, headers []tar.Header, contents map[string]string) []byte {
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	for _, header := range headers {
+		content := contents[header.Name]
+		header.Size = int64(len(content))
+		require.NoError(t, writer.WriteHeader(&header))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestTarball(t *testing.T) {
+	contents := map[string]string{
+		"./web/package.json": `{"name": "web"}`,
+		"android/gradlew":    "#!/bin/sh\n",
+	}
+	archive := newTar(t, []tar.Header{
+		{Name: "./", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "./web/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "./web/package.json", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "android/gradlew", Typeflag: tar.TypeReg, Mode: 0755},
+		{Name: "android/link", Typeflag: tar.TypeSymlink, Linkname: "gradlew"},
+		{Name: "empty/", Typeflag: tar.TypeDir, Mode: 0755},
+	}, contents)
+
+	for name, data := range map[string][]byte{"tar": archive, "tar.gz": gzipped(t, archive)} {
+		t.Run(name, func(t *testing.T) {
+			fsys, err := Tarball(bytes.NewReader(data), 1<<20)
+			require.NoError(t, err)
+			require.NoError(t, fstest.TestFS(fsys, "web/package.json", "android/gradlew", "empty"))
+
+			content, err := fs.ReadFile(fsys, "web/package.json")
+			require.NoError(t, err)
+			require.Equal(t, `{"name": "web"}`, string(content))
+
+			info, err := fs.Stat(fsys, "android/gradlew")
+			require.NoError(t, err)
+			require.Equal(t, fs.FileMode(0755), info.Mode())
+
+			_, err = fs.Stat(fsys, "android/link")
+			require.ErrorIs(t, err, fs.ErrNotExist)
+		})
+	}
+}
+
+func TestTarball_invalid(t *testing.T) {
+	archive := newTar(t, []tar.Header{{Name: "../outside.txt", Typeflag: tar.TypeReg, Mode: 0644}}, map[string]string{"../outside.txt": "x"})
+	_, err := Tarball(bytes.NewReader(archive), 1<<20)
+	require.ErrorContains(t, err, "invalid archive entry: ../outside.txt")
+
+	archive = newTar(t, []tar.Header{{Name: "large.txt", Typeflag: tar.TypeReg, Mode: 0644}}, map[string]string{"large.txt": "0123456789"})
+	_, err = Tarball(bytes.NewReader(gzipped(t, archive)), 9)
+	require.ErrorContains(t, err, "exceeds the size limit")
+
+	_, err = Tarball(bytes.NewReader([]byte("not an archive")), 1<<20)
+	require.ErrorContains(t, err, "invalid tar archive")
+}
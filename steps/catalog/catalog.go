@@ -0,0 +1,189 @@
+// Package catalog is an offline catalog of the step.yml input specs of the steps the scanners generate,
+// used to catch the typos in the input keys and values of the generated steps.
+package catalog
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+)
+
+//go:embed catalog.yml
+var embeddedCatalog []byte
+
+const header = `# Input specs of the steps in steps/const.go, taken from the step.yml of the major versions the scanners generate.
+# Refresh it after updating steps/const.go:
+#   go run ./cmd/bitrise-init step-catalog -steplib path/to/bitrise-steplib -output steps/catalog/catalog.yml
+`
+
+// Catalog maps the step IDs to their specs.
+type Catalog struct {
+	Steps map[string]StepSpec `yaml:"steps"`
+}
+
+// StepSpec is the spec of a step version.
+type StepSpec struct {
+	// Version is the version of the step.yml the spec was read from, its major version at least.
+	Version string      `yaml:"version"`
+	Inputs  []InputSpec `yaml:"inputs,omitempty"`
+}
+
+// InputSpec is the spec of a step input.
+type InputSpec struct {
+	Key          string   `yaml:"key"`
+	ValueOptions []string `yaml:"value_options,omitempty,flow"`
+}
+
+var (
+	defaultCatalog     Catalog
+	defaultCatalogOnce sync.Once
+)
+
+// Default returns the embedded catalog.
+func Default() Catalog {
+	defaultCatalogOnce.Do(func() {
+		if err := yaml.Unmarshal(embeddedCatalog, &defaultCatalog); err != nil {
+			panic(fmt.Sprintf("invalid embedded step catalog: %s", err))
+		}
+	})
+	return defaultCatalog
+}
+
+// Major returns the major version of the spec.
+func (spec StepSpec) Major() string {
+	major, _, _ := strings.Cut(spec.Version, ".")
+	return major
+}
+
+func (spec StepSpec) input(key string) (InputSpec, bool) {
+	for _, input := range spec.Inputs {
+		if input.Key == key {
+			return input, true
+		}
+	}
+	return InputSpec{}, false
+}
+
+// ValidateStep checks the inputs of a step against the spec of the step's major version.
+// Steps missing from the catalog, steps of other major versions and values referencing env vars are not checked.
+func (catalog Catalog) ValidateStep(reference string, step stepmanModels.StepModel) error {
+	id, version, _ := strings.Cut(reference, "@")
+	spec, ok := catalog.Steps[id]
+	if !ok || version == "" {
+		return nil
+	}
+	if major, _, _ := strings.Cut(version, "."); major != spec.Major() {
+		return nil
+	}
+
+	for _, env := range step.Inputs {
+		key, value, err := env.GetKeyValuePair()
+		if err != nil {
+			return fmt.Errorf("invalid input of step %s: %w", reference, err)
+		}
+
+		input, ok := spec.input(key)
+		if !ok {
+			return fmt.Errorf("step %s has no input %s", reference, key)
+		}
+		if value == "" || strings.Contains(value, "$") || len(input.ValueOptions) == 0 {
+			continue
+		}
+		if !slices.Contains(input.ValueOptions, value) {
+			return fmt.Errorf("invalid value of input %s of step %s: %s, available values: %s", key, reference, value, strings.Join(input.ValueOptions, ", "))
+		}
+	}
+	return nil
+}
+
+// Build reads the specs of the steps from a local steplib checkout (steps/<id>/<version>/step.yml),
+// using the latest version of the major version given for every step.
+func Build(steplibDir string, majors map[string]string) (Catalog, error) {
+	catalog := Catalog{Steps: map[string]StepSpec{}}
+	for id, major := range majors {
+		version, err := latestVersion(filepath.Join(steplibDir, "steps", id), major)
+		if err != nil {
+			return Catalog{}, fmt.Errorf("step %s: %w", id, err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(steplibDir, "steps", id, version, "step.yml"))
+		if err != nil {
+			return Catalog{}, fmt.Errorf("step %s: %w", id, err)
+		}
+		var step stepmanModels.StepModel
+		if err := yaml.Unmarshal(content, &step); err != nil {
+			return Catalog{}, fmt.Errorf("step %s: failed to parse step.yml: %w", id, err)
+		}
+
+		spec := StepSpec{Version: version}
+		for _, env := range step.Inputs {
+			input, err := inputSpec(env)
+			if err != nil {
+				return Catalog{}, fmt.Errorf("step %s: %w", id, err)
+			}
+			spec.Inputs = append(spec.Inputs, input)
+		}
+		catalog.Steps[id] = spec
+	}
+	return catalog, nil
+}
+
+// Marshal returns the catalog in the format of the embedded catalog.
+func (catalog Catalog) Marshal() ([]byte, error) {
+	content, err := yaml.Marshal(catalog)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header), content...), nil
+}
+
+func inputSpec(env envmanModels.EnvironmentItemModel) (InputSpec, error) {
+	key, _, err := env.GetKeyValuePair()
+	if err != nil {
+		return InputSpec{}, err
+	}
+	options, err := env.GetOptions()
+	if err != nil {
+		return InputSpec{}, err
+	}
+	return InputSpec{Key: key, ValueOptions: options.ValueOptions}, nil
+}
+
+// latestVersion returns the latest version in the step's dir with the major version.
+func latestVersion(stepDir, major string) (string, error) {
+	entries, err := os.ReadDir(stepDir)
+	if err != nil {
+		return "", err
+	}
+
+	var versions []stepmanModels.Semver
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		version, err := stepmanModels.ParseSemver(entry.Name())
+		if err != nil || strconv.FormatUint(version.Major, 10) != major {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no %s.x.x version found in %s", major, stepDir)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return stepmanModels.CmpSemver(versions[i], versions[j]) > 0
+	})
+	return versions[0].String(), nil
+}
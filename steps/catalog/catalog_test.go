@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	envmanModels "github.com/bitrise-io/envman/v2/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCatalog_ValidateStep(t *testing.T) {
+	catalog := Catalog{Steps: map[string]StepSpec{
+		"android-build": {Version: "1.2.0", Inputs: []InputSpec{
+			{Key: "project_location"},
+			{Key: "build_type", ValueOptions: []string{"apk", "aab"}},
+		}},
+	}}
+	step := func(inputs ...envmanModels.EnvironmentItemModel) stepmanModels.StepModel {
+		return stepmanModels.StepModel{Inputs: inputs}
+	}
+
+	require.NoError(t, catalog.ValidateStep("android-build@1", step(
+		envmanModels.EnvironmentItemModel{"project_location": "."},
+		envmanModels.EnvironmentItemModel{"build_type": "aab"},
+	)))
+	require.NoError(t, catalog.ValidateStep("android-build@1", step(envmanModels.EnvironmentItemModel{"build_type": "$BUILD_TYPE"})))
+	require.EqualError(t, catalog.ValidateStep("android-build@1.2", step(envmanModels.EnvironmentItemModel{"project_locaton": "."})),
+		"step android-build@1.2 has no input project_locaton")
+	require.EqualError(t, catalog.ValidateStep("android-build@1", step(envmanModels.EnvironmentItemModel{"build_type": "ipa"})),
+		"invalid value of input build_type of step android-build@1: ipa, available values: apk, aab")
+
+	// other majors and unknown steps are not checked
+	require.NoError(t, catalog.ValidateStep("android-build@2", step(envmanModels.EnvironmentItemModel{"project_locaton": "."})))
+	require.NoError(t, catalog.ValidateStep("custom-step@1", step(envmanModels.EnvironmentItemModel{"any": "value"})))
+}
+
+func TestDefault(t *testing.T) {
+	catalog := Default()
+	require.Equal(t, "8", catalog.Steps["git-clone"].Major())
+	require.NotEmpty(t, catalog.Steps["git-clone"].Inputs)
+}
+
+func TestBuild(t *testing.T) {
+	steplibDir := t.TempDir()
+	writeStep := func(id, version, stepYML string) {
+		dir := filepath.Join(steplibDir, "steps", id, version)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "step.yml"), []byte(stepYML), 0600))
+	}
+	writeStep("script", "1.1.5", "inputs:\n- content: \"\"\n")
+	writeStep("script", "1.2.0", `inputs:
+- content: ""
+- is_debug: "no"
+  opts:
+    value_options:
+    - "yes"
+    - "no"
+`)
+	writeStep("script", "2.0.0", "inputs:\n- script: \"\"\n")
+
+	catalog, err := Build(steplibDir, map[string]string{"script": "1"})
+	require.NoError(t, err)
+	require.Equal(t, Catalog{Steps: map[string]StepSpec{
+		"script": {Version: "1.2.0", Inputs: []InputSpec{{Key: "content"}, {Key: "is_debug", ValueOptions: []string{"yes", "no"}}}},
+	}}, catalog)
+
+	content, err := catalog.Marshal()
+	require.NoError(t, err)
+	require.Contains(t, string(content), "# Refresh it after updating steps/const.go:")
+	var parsed Catalog
+	require.NoError(t, yaml.Unmarshal(content, &parsed))
+	require.Equal(t, catalog, parsed)
+
+	_, err = Build(steplibDir, map[string]string{"script": "3"})
+	require.ErrorContains(t, err, "step script: no 3.x.x version found")
+}
@@ -0,0 +1,39 @@
+package steps
+
+import "github.com/bitrise-io/bitrise-init/models"
+
+// reasons explain why the non-obvious steps are in the generated configs,
+// they are written as comments to the bitrise.yml on request.
+var reasons = map[string]string{
+	ActivateSSHKeyID:                         "Makes the SSH key available to clone private repositories and submodules.",
+	CacheRestoreGradleID:                     "Restores the Gradle dependencies and build cache of a previous build to speed up this one.",
+	CacheSaveGradleID:                        "Saves the Gradle dependencies and build cache for the next builds.",
+	CacheRestoreCocoapodsID:                  "Restores the CocoaPods dependencies of a previous build to speed up this one.",
+	CacheSaveCocoapodsID:                     "Saves the CocoaPods dependencies for the next builds.",
+	CacheRestoreCarthageID:                   "Restores the Carthage dependencies of a previous build to speed up this one.",
+	CacheSaveCarthageID:                      "Saves the Carthage dependencies for the next builds.",
+	CacheRestoreNPMID:                        "Restores the node_modules of a previous build to speed up this one.",
+	CacheSaveNPMID:                           "Saves the node_modules for the next builds.",
+	CacheRestoreSPMID:                        "Restores the Swift Package Manager dependencies of a previous build to speed up this one.",
+	CacheSaveSPMID:                           "Saves the Swift Package Manager dependencies for the next builds.",
+	CacheRestoreDartID:                       "Restores the Dart and Flutter dependencies of a previous build to speed up this one.",
+	CacheSaveDartID:                          "Saves the Dart and Flutter dependencies for the next builds.",
+	CacheRestoreID:                           "Restores the cached files of a previous build with the same cache key.",
+	CacheSaveID:                              "Saves the listed paths for the next builds, under the cache key.",
+	CertificateAndProfileInstallerID:         "Installs the code signing certificates and provisioning profiles uploaded to Bitrise.",
+	ChangeAndroidVersionCodeAndVersionNameID: "Sets the version code of the app to the build number, so every build can be uploaded to the stores.",
+	InstallMissingAndroidToolsID:             "Installs the Android SDK components required by the project.",
+	DeployToBitriseIoID:                      "Uploads the build artifacts and test results to Bitrise.",
+	XcodeTestShardCalculationID:              "Splits the tests into shards, so that the test_without_building workflow can run them in parallel.",
+	PullIntermediateFilesID:                  "Downloads the test bundle built by the build_for_testing workflow.",
+	AvdManagerID:                             "Creates and starts an Android emulator for the instrumented tests.",
+	WaitForAndroidEmulatorID:                 "Waits for the emulator to boot before the tests start.",
+	GenerateCordovaBuildConfigID:             "Generates the build configuration from the code signing files uploaded to Bitrise.",
+}
+
+// NewConfigBuilder returns a config builder which explains the steps of the generated config with their reasons.
+func NewConfigBuilder() *models.ConfigBuilderModel {
+	builder := models.NewDefaultConfigBuilder()
+	builder.SetStepReasons(reasons)
+	return builder
+}
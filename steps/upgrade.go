@@ -0,0 +1,232 @@
+package steps
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// StepLibSource is the step library the versions in const.go are released to.
+const StepLibSource = "https://github.com/bitrise-io/bitrise-steplib.git"
+
+// LatestVersions maps the step IDs to the major versions the scanners generate, keep it in sync with const.go.
+var LatestVersions = map[string]string{
+	ActivateSSHKeyID:                         ActivateSSHKeyVersion,
+	AndroidLintID:                            AndroidLintVersion,
+	AndroidUnitTestID:                        AndroidUnitTestVersion,
+	AndroidBuildID:                           AndroidBuildVersion,
+	GradleRunnerID:                           GradleRunnerVersion,
+	GradleUnitTestID:                         GradleUnitTestVersion,
+	GitCloneID:                               GitCloneVersion,
+	CacheRestoreGradleID:                     CacheRestoreGradleVersion,
+	CacheRestoreCocoapodsID:                  CacheRestoreCocoapodsVersion,
+	CacheRestoreCarthageID:                   CacheRestoreCarthageVersion,
+	CacheRestoreNPMID:                        CacheRestoreNPMVersion,
+	CacheRestoreSPMID:                        CacheRestoreSPMVersion,
+	CacheRestoreDartID:                       CacheRestoreDartVersion,
+	CacheRestoreID:                           CacheRestoreVersion,
+	CacheSaveGradleID:                        CacheSaveGradleVersion,
+	CacheSaveCocoapodsID:                     CacheSaveCocoapodsVersion,
+	CacheSaveCarthageID:                      CacheSaveCarthageVersion,
+	CacheSaveNPMID:                           CacheSaveNPMVersion,
+	CacheSaveSPMID:                           CacheSaveSPMVersion,
+	CacheSaveDartID:                          CacheSaveDartVersion,
+	CacheSaveID:                              CacheSaveVersion,
+	ActivateBuildCacheForGradleID:            ActivateBuildCacheForGradleVersion,
+	CertificateAndProfileInstallerID:         CertificateAndProfileInstallerVersion,
+	ChangeAndroidVersionCodeAndVersionNameID: ChangeAndroidVersionCodeAndVersionNameVersion,
+	DeployToBitriseIoID:                      DeployToBitriseIoVersion,
+	SignAPKID:                                SignAPKVersion,
+	InstallMissingAndroidToolsID:             InstallMissingAndroidToolsVersion,
+	FastlaneID:                               FastlaneVersion,
+	CocoapodsInstallID:                       CocoapodsInstallVersion,
+	CarthageID:                               CarthageVersion,
+	XcodeArchiveID:                           XcodeArchiveVersion,
+	XcodeTestID:                              XcodeTestVersion,
+	XcodeBuildForTestID:                      XcodeBuildForTestVersion,
+	XcodeArchiveMacID:                        XcodeArchiveMacVersion,
+	ExportXCArchiveID:                        ExportXCArchiveVersion,
+	XcodeTestMacID:                           XcodeTestMacVersion,
+	CordovaArchiveID:                         CordovaArchiveVersion,
+	IonicArchiveID:                           IonicArchiveVersion,
+	GenerateCordovaBuildConfigID:             GenerateCordovaBuildConfigVersion,
+	JasmineTestRunnerID:                      JasmineTestRunnerVersion,
+	KarmaJasmineTestRunnerID:                 KarmaJasmineTestRunnerVersion,
+	ScriptID:                                 ScriptVersion,
+	NpmID:                                    NpmVersion,
+	RunEASBuildID:                            RunEASBuildVersion,
+	YarnID:                                   YarnVersion,
+	FlutterInstallID:                         FlutterInstallVersion,
+	FlutterTestID:                            FlutterTestVersion,
+	FlutterAnalyzeID:                         FlutterAnalyzeVersion,
+	FlutterBuildID:                           FlutterBuildVersion,
+	XcodeTestShardCalculationID:              XcodeTestShardCalculationVersion,
+	PullIntermediateFilesID:                  PullIntermediateFilesVersion,
+	XcodeTestWithoutBuildingID:               XcodeTestWithoutBuildingVersion,
+	AvdManagerID:                             AvdManagerVersion,
+	WaitForAndroidEmulatorID:                 WaitForAndroidEmulatorVersion,
+}
+
+// DeprecatedSteps maps the deprecated step IDs to the steps replacing them.
+// The replacements take different inputs, so the deprecated steps are reported, but not replaced by UpgradeSteps.
+var DeprecatedSteps = map[string]string{
+	"cache-pull": CacheRestoreID,
+	"cache-push": CacheSaveID,
+}
+
+// StepAdvice is an outdated or deprecated step reference of a bitrise.yml.
+type StepAdvice struct {
+	// Location is the workflow or step bundle using the step, for example workflows.primary.
+	Location string `json:"location" yaml:"location"`
+	Line     int    `json:"line" yaml:"line"`
+	Step     string `json:"step" yaml:"step"`
+	// UpgradeTo is the step reference with the latest major version, set if the step's major version is outdated.
+	UpgradeTo string `json:"upgrade_to,omitempty" yaml:"upgrade_to,omitempty"`
+	// ReplacedBy is the ID of the step replacing the deprecated step.
+	ReplacedBy string `json:"replaced_by,omitempty" yaml:"replaced_by,omitempty"`
+}
+
+func (advice StepAdvice) String() string {
+	if advice.ReplacedBy != "" {
+		return fmt.Sprintf("%s (line %d): %s is deprecated, use %s instead", advice.Location, advice.Line, advice.Step, advice.ReplacedBy)
+	}
+	return fmt.Sprintf("%s (line %d): %s can be upgraded to %s", advice.Location, advice.Line, advice.Step, advice.UpgradeTo)
+}
+
+// AdviseStepUpgrades compares the step references of the bitrise.yml with the versions in const.go.
+// It reports the steps pinned to an older major version, and the deprecated steps.
+// Steps from other step libraries, local and git steps and the steps without a version are skipped.
+func AdviseStepUpgrades(config []byte) ([]StepAdvice, error) {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(config, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	root := document.Content[0]
+
+	defaultSource := StepLibSource
+	if source := mappingValue(root, "default_step_lib_source"); source != nil && source.Value != "" {
+		defaultSource = source.Value
+	}
+
+	var advices []StepAdvice
+	for _, section := range []string{"workflows", "step_bundles"} {
+		items := mappingValue(root, section)
+		if items == nil || items.Kind != yamlv3.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(items.Content); i += 2 {
+			location := section + "." + items.Content[i].Value
+			for _, stepKey := range stepKeys(items.Content[i+1]) {
+				if advice, ok := adviseStep(stepKey.Value, defaultSource); ok {
+					advice.Location = location
+					advice.Line = stepKey.Line
+					advices = append(advices, advice)
+				}
+			}
+		}
+	}
+	return advices, nil
+}
+
+// UpgradeSteps upgrades the outdated step references of the bitrise.yml to the latest major versions.
+// Only the step references are changed, the formatting and the comments of the config are kept.
+func UpgradeSteps(config []byte) ([]byte, []StepAdvice, error) {
+	advices, err := AdviseStepUpgrades(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lines := bytes.SplitAfter(config, []byte("\n"))
+	for _, advice := range advices {
+		if advice.UpgradeTo == "" {
+			continue
+		}
+		line := lines[advice.Line-1]
+		idx := bytes.Index(line, []byte(advice.Step))
+		if idx == -1 {
+			return nil, nil, fmt.Errorf("step %s not found in line %d", advice.Step, advice.Line)
+		}
+		lines[advice.Line-1] = append(append(append([]byte{}, line[:idx]...), advice.UpgradeTo...), line[idx+len(advice.Step):]...)
+	}
+	return bytes.Join(lines, nil), advices, nil
+}
+
+// adviseStep returns the advice for a step reference, like git-clone@6, git-clone@6.2.1 or https://github.com/bitrise-io/bitrise-steplib.git::git-clone@6.
+func adviseStep(reference, defaultSource string) (StepAdvice, bool) {
+	source, idVersion, found := strings.Cut(reference, "::")
+	if !found {
+		source, idVersion = defaultSource, reference
+	}
+	if normalizedSource(source) != normalizedSource(StepLibSource) {
+		return StepAdvice{}, false
+	}
+
+	id, version, _ := strings.Cut(idVersion, "@")
+	if replacement, ok := DeprecatedSteps[id]; ok {
+		return StepAdvice{Step: reference, ReplacedBy: replacement}, true
+	}
+
+	latest, ok := LatestVersions[id]
+	if !ok || version == "" {
+		return StepAdvice{}, false
+	}
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return StepAdvice{}, false
+	}
+	if latestMajor, err := strconv.Atoi(latest); err != nil || major >= latestMajor {
+		return StepAdvice{}, false
+	}
+
+	upgraded := id + "@" + latest
+	if found {
+		upgraded = source + "::" + upgraded
+	}
+	return StepAdvice{Step: reference, UpgradeTo: upgraded}, true
+}
+
+func normalizedSource(source string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+}
+
+// stepKeys returns the step reference keys of a workflow or step bundle, including the steps of its with groups.
+func stepKeys(node *yamlv3.Node) []*yamlv3.Node {
+	stepList := mappingValue(node, "steps")
+	if stepList == nil || stepList.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+
+	var keys []*yamlv3.Node
+	for _, item := range stepList.Content {
+		if item.Kind != yamlv3.MappingNode || len(item.Content) < 2 {
+			continue
+		}
+		key := item.Content[0]
+		switch {
+		case key.Value == "with":
+			keys = append(keys, stepKeys(item.Content[1])...)
+		case strings.HasPrefix(key.Value, "bundle::"):
+		default:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package steps
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/bitrise-init/steps/catalog"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradeTestConfig = `format_version: "13"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  primary:
+    steps:
+    # Clones the repository.
+    - git-clone@6.2.1: {}
+    - cache-pull@2: {}
+    - script: {}
+    - path::./steps/custom@1: {}
+    - https://github.com/bitrise-io/bitrise-steplib.git::npm@1:
+        inputs:
+        - command: test
+    - with:
+        container: node
+        steps:
+        - 'xcode-test@4': {}
+    - bundle::setup: {}
+    - deploy-to-bitrise-io@2: {}
+step_bundles:
+  setup:
+    steps:
+    - activate-ssh-key@3: {}
+`
+
+func TestAdviseStepUpgrades(t *testing.T) {
+	advices, err := AdviseStepUpgrades([]byte(upgradeTestConfig))
+	require.NoError(t, err)
+	require.Equal(t, []StepAdvice{
+		{Location: "workflows.primary", Line: 7, Step: "git-clone@6.2.1", UpgradeTo: "git-clone@" + GitCloneVersion},
+		{Location: "workflows.primary", Line: 8, Step: "cache-pull@2", ReplacedBy: CacheRestoreID},
+		{Location: "workflows.primary", Line: 11, Step: "https://github.com/bitrise-io/bitrise-steplib.git::npm@1", UpgradeTo: "https://github.com/bitrise-io/bitrise-steplib.git::npm@" + NpmVersion},
+		{Location: "workflows.primary", Line: 17, Step: "xcode-test@4", UpgradeTo: "xcode-test@" + XcodeTestVersion},
+		{Location: "step_bundles.setup", Line: 23, Step: "activate-ssh-key@3", UpgradeTo: "activate-ssh-key@" + ActivateSSHKeyVersion},
+	}, advices)
+
+	// the steps of other step libraries are not checked
+	advices, err = AdviseStepUpgrades([]byte(strings.Replace(upgradeTestConfig, "bitrise-io/bitrise-steplib.git\n", "acme/steplib.git\n", 1)))
+	require.NoError(t, err)
+	require.Len(t, advices, 1)
+	require.Equal(t, "workflows.primary (line 11): https://github.com/bitrise-io/bitrise-steplib.git::npm@1 can be upgraded to https://github.com/bitrise-io/bitrise-steplib.git::npm@"+NpmVersion, advices[0].String())
+}
+
+func TestUpgradeSteps(t *testing.T) {
+	upgraded, advices, err := UpgradeSteps([]byte(upgradeTestConfig))
+	require.NoError(t, err)
+	require.Len(t, advices, 5)
+
+	expected := strings.NewReplacer(
+		"git-clone@6.2.1:", "git-clone@"+GitCloneVersion+":",
+		"::npm@1:", "::npm@"+NpmVersion+":",
+		"'xcode-test@4'", "'xcode-test@"+XcodeTestVersion+"'",
+		"activate-ssh-key@3:", "activate-ssh-key@"+ActivateSSHKeyVersion+":",
+	).Replace(upgradeTestConfig)
+	require.Equal(t, expected, string(upgraded))
+
+	// the upgraded config is up to date, except the deprecated step
+	advices, err = AdviseStepUpgrades(upgraded)
+	require.NoError(t, err)
+	require.Equal(t, []StepAdvice{{Location: "workflows.primary", Line: 8, Step: "cache-pull@2", ReplacedBy: CacheRestoreID}}, advices)
+}
+
+func TestLatestVersions(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "const.go", nil, 0)
+	require.NoError(t, err)
+
+	// every step of const.go is checked by AdviseStepUpgrades
+	var stepIDs []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if ok && strings.HasSuffix(spec.Names[0].Name, "ID") && len(spec.Values) == 1 {
+			if lit, ok := spec.Values[0].(*ast.BasicLit); ok {
+				stepIDs = append(stepIDs, strings.Trim(lit.Value, `"`))
+			}
+		}
+		return true
+	})
+	require.NotEmpty(t, stepIDs)
+	for _, id := range stepIDs {
+		require.Contains(t, LatestVersions, id)
+	}
+	require.Len(t, LatestVersions, len(stepIDs))
+}
+
+func TestCatalogVersions(t *testing.T) {
+	// the step catalog has the inputs of the major versions the scanners generate
+	stepCatalog := catalog.Default()
+	for id, version := range LatestVersions {
+		spec, ok := stepCatalog.Steps[id]
+		require.True(t, ok, id)
+		require.Equal(t, version, spec.Major(), id)
+	}
+}
@@ -146,33 +146,33 @@ func TestAddProjectTypeToConfig(t *testing.T) {
 	const title = "abcd"
 	type args struct {
 		configName           string
-		config               bitriseModels.BitriseDataModel
+		config               models.Config
 		detectedProjectTypes []string
 	}
 	tests := []struct {
 		name string
 		args args
-		want map[string]bitriseModels.BitriseDataModel
+		want map[string]models.Config
 	}{
 		{
 			name: "2 project types",
 			args: args{
 				configName: "fastlane-config",
-				config: bitriseModels.BitriseDataModel{
+				config: models.NewConfig(bitriseModels.BitriseDataModel{
 					Title:       title,
 					ProjectType: "other",
-				},
+				}),
 				detectedProjectTypes: []string{"ios", "android"},
 			},
-			want: map[string]bitriseModels.BitriseDataModel{
-				"fastlane-config_ios": bitriseModels.BitriseDataModel{
+			want: map[string]models.Config{
+				"fastlane-config_ios": models.NewConfig(bitriseModels.BitriseDataModel{
 					Title:       title,
 					ProjectType: "ios",
-				},
-				"fastlane-config_android": bitriseModels.BitriseDataModel{
+				}),
+				"fastlane-config_android": models.NewConfig(bitriseModels.BitriseDataModel{
 					Title:       title,
 					ProjectType: "android",
-				},
+				}),
 			},
 		},
 	}
@@ -6,7 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/bitrise-init/detectors/fileindex"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
@@ -26,9 +26,9 @@ func parsePackagesJSONContent(content string) (PackagesModel, error) {
 	return packages, nil
 }
 
-// ParsePackagesJSON ...
-func ParsePackagesJSON(packagesJSONPth string) (PackagesModel, error) {
-	content, err := fileutil.ReadStringFromFile(packagesJSONPth)
+// ParsePackagesJSON parses the package.json file at packagesJSONPth, read from the file index.
+func ParsePackagesJSON(index *fileindex.Index, packagesJSONPth string) (PackagesModel, error) {
+	content, err := index.ReadStringFromFile(packagesJSONPth)
 	if err != nil {
 		return PackagesModel{}, err
 	}
@@ -36,8 +36,8 @@ func ParsePackagesJSON(packagesJSONPth string) (PackagesModel, error) {
 }
 
 // CollectPackageJSONFiles ...
-func CollectPackageJSONFiles(searchDir string) ([]string, error) {
-	fileList, err := pathutil.ListPathInDirSortedByComponents(searchDir, false)
+func CollectPackageJSONFiles(index *fileindex.Index, searchDir string) ([]string, error) {
+	fileList, err := index.ListPathInDirSortedByComponents(searchDir, false)
 	if err != nil {
 		return nil, err
 	}